@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnicnb"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnicsb"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnsb"
+	"github.com/dave-tucker/ariadne/internal/mcp/vswitch"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var (
+	port    = flag.Int("port", 8085, "MCP server port")
+	host    = flag.String("host", "localhost", "MCP server host")
+	verbose = flag.Bool("verbose", false, "Enable verbose logging")
+
+	nbEndpoint      = flag.String("nb-endpoint", "", "OVN NB database endpoint, or the comma-separated endpoints of a clustered database, defaults to the local NB socket")
+	sbEndpoint      = flag.String("sb-endpoint", "", "OVN SB database endpoint, or the comma-separated endpoints of a clustered database, defaults to the local SB socket")
+	icNBEndpoint    = flag.String("ic-nb-endpoint", "", "OVN IC NB database endpoint, or the comma-separated endpoints of a clustered database, defaults to the local IC NB socket")
+	icSBEndpoint    = flag.String("ic-sb-endpoint", "", "OVN IC SB database endpoint, or the comma-separated endpoints of a clustered database, defaults to the local IC SB socket")
+	vswitchEndpoint = flag.String("vswitch-endpoint", "", "Open vSwitch database endpoint, defaults to the local Open vSwitch socket")
+
+	rowHistory = flag.Int("row-history", 0, "Number of row changes to record per table for row_history, 0 disables recording")
+
+	caCert = flag.String("ca-cert", "", "CA certificate to verify ssl: database endpoints with")
+	cert   = flag.String("cert", "", "Client certificate for ssl: database endpoints")
+	key    = flag.String("key", "", "Client private key for ssl: database endpoints")
+
+	leaderOnly = flag.Bool("leader-only", false, "Only connect to the leader of clustered databases")
+
+	callTimeout = flag.Duration("call-timeout", mcp.DefaultCallTimeout, "Maximum duration of a tool call, 0 disables the timeout")
+
+	metrics = flag.Bool("metrics", false, "Serve Prometheus metrics of tool calls on /metrics")
+
+	nbCache      = flag.String("nb-cache", "", "Comma-separated OVN NB tables to serve list tools from a monitored cache, e.g. Logical_Switch,Logical_Router")
+	sbCache      = flag.String("sb-cache", "", "Comma-separated OVN SB tables to serve list tools from a monitored cache, e.g. Chassis,Datapath_Binding")
+	icNBCache    = flag.String("ic-nb-cache", "", "Comma-separated OVN IC NB tables to serve list tools from a monitored cache, e.g. Transit_Switch")
+	icSBCache    = flag.String("ic-sb-cache", "", "Comma-separated OVN IC SB tables to serve list tools from a monitored cache, e.g. Availability_Zone")
+	vswitchCache = flag.String("vswitch-cache", "", "Comma-separated Open vSwitch tables to serve list tools from a monitored cache, e.g. Bridge,Port")
+)
+
+// serverOptions returns the options of the server for a database, connecting
+// to endpoint when it is set and caching the comma-separated tables in cache
+func serverOptions(logger *slog.Logger, endpoint, cache string) []mcp.Option {
+	opts := []mcp.Option{mcp.WithLogger(logger), mcp.WithRowHistory(*rowHistory), mcp.WithCertPaths(*cert, *key, *caCert), mcp.WithCallTimeout(*callTimeout), mcp.WithLeaderOnly(*leaderOnly), mcp.WithCache(strings.Split(cache, ",")...)}
+	if *metrics {
+		opts = append(opts, mcp.WithMetrics())
+	}
+	if endpoint != "" {
+		opts = append(opts, mcp.WithEndpoint(endpoint))
+	}
+	return opts
+}
+
+// newEntries creates the server for each database, with the prefix of its
+// tool names
+func newEntries(logger *slog.Logger) ([]mcp.MuxEntry, error) {
+	nb, err := ovnnb.NewServer(*host, *port, serverOptions(logger, *nbEndpoint, *nbCache)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OVN NB server: %w", err)
+	}
+	sb, err := ovnsb.NewServer(*host, *port, serverOptions(logger, *sbEndpoint, *sbCache)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OVN SB server: %w", err)
+	}
+	icNB, err := ovnicnb.NewServer(*host, *port, serverOptions(logger, *icNBEndpoint, *icNBCache)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OVN IC NB server: %w", err)
+	}
+	icSB, err := ovnicsb.NewServer(*host, *port, serverOptions(logger, *icSBEndpoint, *icSBCache)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OVN IC SB server: %w", err)
+	}
+	ovs, err := vswitch.NewServer(*host, *port, serverOptions(logger, *vswitchEndpoint, *vswitchCache)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Open vSwitch server: %w", err)
+	}
+	return []mcp.MuxEntry{
+		{Prefix: "nb", Server: nb.BaseServer},
+		{Prefix: "sb", Server: sb.BaseServer},
+		{Prefix: "ic_nb", Server: icNB.BaseServer},
+		{Prefix: "ic_sb", Server: icSB.BaseServer},
+		{Prefix: "vswitch", Server: ovs.BaseServer},
+	}, nil
+}
+
+func main() {
+	flag.Parse()
+
+	// Setup logging
+	logLevel := slog.LevelInfo
+	if *verbose {
+		logLevel = slog.LevelDebug
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: logLevel,
+	}))
+
+	logger.Info("Starting ariadne-mcp server",
+		"host", *host,
+		"port", *port)
+
+	entries, err := newEntries(logger)
+	if err != nil {
+		logger.Error("Failed to create server", "error", err)
+		os.Exit(1)
+	}
+	server, err := mcp.NewMuxServer(&mcpsdk.Implementation{
+		Name:    "ariadne-mcp",
+		Title:   "OVN and Open vSwitch MCP Server",
+		Version: "1.0.0",
+	}, logger, entries...)
+	if err != nil {
+		logger.Error("Failed to create server", "error", err)
+		os.Exit(1)
+	}
+
+	// Start the MCP server
+	addr := fmt.Sprintf("%s:%d", *host, *port)
+	if err := server.Start(context.Background(), addr); err != nil {
+		logger.Error("Failed to start MCP server", "error", err)
+		os.Exit(1)
+	}
+
+	// Wait for shutdown signal
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+
+	logger.Info("Shutting down...")
+
+	// Stop the server gracefully
+	if err := server.Stop(context.Background()); err != nil {
+		logger.Error("Error stopping MCP server", "error", err)
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnicnb"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnicsb"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnsb"
+	"github.com/dave-tucker/ariadne/internal/mcp/vswitch"
+)
+
+// server is the part of each package's server used to run it
+type server interface {
+	Start(ctx context.Context, addr string) error
+	Stop(ctx context.Context) error
+}
+
+// database is an MCP server that can be started, with its own port and
+// endpoint flags
+type database struct {
+	name      string
+	port      *int
+	endpoint  *string
+	cache     *string
+	newServer func(host string, port int, opts ...mcp.Option) (server, error)
+}
+
+// databases are the servers that can be started, using the same default
+// ports as their own binaries
+var databases = []database{
+	{
+		name:     "vswitch",
+		port:     flag.Int("vswitch-port", 8080, "Open vSwitch MCP server port"),
+		endpoint: flag.String("vswitch-endpoint", "", "Open vSwitch database endpoint, defaults to the local Open vSwitch socket"),
+		cache:    flag.String("vswitch-cache", "", "Comma-separated Open vSwitch tables to serve list tools from a monitored cache, e.g. Bridge,Port"),
+		newServer: func(host string, port int, opts ...mcp.Option) (server, error) {
+			return vswitch.NewServer(host, port, opts...)
+		},
+	},
+	{
+		name:     "nb",
+		port:     flag.Int("nb-port", 8081, "OVN NB MCP server port"),
+		endpoint: flag.String("nb-endpoint", "", "OVN NB database endpoint, or the comma-separated endpoints of a clustered database, defaults to the local NB socket"),
+		cache:    flag.String("nb-cache", "", "Comma-separated OVN NB tables to serve list tools from a monitored cache, e.g. Logical_Switch,Logical_Router"),
+		newServer: func(host string, port int, opts ...mcp.Option) (server, error) {
+			return ovnnb.NewServer(host, port, opts...)
+		},
+	},
+	{
+		name:     "sb",
+		port:     flag.Int("sb-port", 8082, "OVN SB MCP server port"),
+		endpoint: flag.String("sb-endpoint", "", "OVN SB database endpoint, or the comma-separated endpoints of a clustered database, defaults to the local SB socket"),
+		cache:    flag.String("sb-cache", "", "Comma-separated OVN SB tables to serve list tools from a monitored cache, e.g. Chassis,Datapath_Binding"),
+		newServer: func(host string, port int, opts ...mcp.Option) (server, error) {
+			return ovnsb.NewServer(host, port, opts...)
+		},
+	},
+	{
+		name:     "ic-nb",
+		port:     flag.Int("ic-nb-port", 8083, "OVN IC NB MCP server port"),
+		endpoint: flag.String("ic-nb-endpoint", "", "OVN IC NB database endpoint, or the comma-separated endpoints of a clustered database, defaults to the local IC NB socket"),
+		cache:    flag.String("ic-nb-cache", "", "Comma-separated OVN IC NB tables to serve list tools from a monitored cache, e.g. Transit_Switch"),
+		newServer: func(host string, port int, opts ...mcp.Option) (server, error) {
+			return ovnicnb.NewServer(host, port, opts...)
+		},
+	},
+	{
+		name:     "ic-sb",
+		port:     flag.Int("ic-sb-port", 8084, "OVN IC SB MCP server port"),
+		endpoint: flag.String("ic-sb-endpoint", "", "OVN IC SB database endpoint, or the comma-separated endpoints of a clustered database, defaults to the local IC SB socket"),
+		cache:    flag.String("ic-sb-cache", "", "Comma-separated OVN IC SB tables to serve list tools from a monitored cache, e.g. Availability_Zone"),
+		newServer: func(host string, port int, opts ...mcp.Option) (server, error) {
+			return ovnicsb.NewServer(host, port, opts...)
+		},
+	},
+}
+
+var (
+	host     = flag.String("host", "localhost", "MCP server host")
+	verbose  = flag.Bool("verbose", false, "Enable verbose logging")
+	selected = flag.String("databases", "nb,sb,vswitch", "Comma-separated databases to start MCP servers for: nb, sb, ic-nb, ic-sb and vswitch")
+
+	rowHistory = flag.Int("row-history", 0, "Number of row changes to record per table for row_history, 0 disables recording")
+
+	caCert = flag.String("ca-cert", "", "CA certificate to verify ssl: database endpoints with")
+	cert   = flag.String("cert", "", "Client certificate for ssl: database endpoints")
+	key    = flag.String("key", "", "Client private key for ssl: database endpoints")
+
+	leaderOnly = flag.Bool("leader-only", false, "Only connect to the leader of clustered databases")
+
+	callTimeout = flag.Duration("call-timeout", mcp.DefaultCallTimeout, "Maximum duration of a tool call, 0 disables the timeout")
+
+	metrics = flag.Bool("metrics", false, "Serve Prometheus metrics of tool calls on /metrics")
+)
+
+// selectDatabases returns the databases named in names, in the order of
+// databases
+func selectDatabases(names string) ([]database, error) {
+	var valid []string
+	for _, db := range databases {
+		valid = append(valid, db.name)
+	}
+	var requested []string
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(valid, name) {
+			return nil, fmt.Errorf("unknown database %q, valid databases are: %s", name, strings.Join(valid, ", "))
+		}
+		requested = append(requested, name)
+	}
+	if len(requested) == 0 {
+		return nil, fmt.Errorf("no databases selected, valid databases are: %s", strings.Join(valid, ", "))
+	}
+	var result []database
+	for _, db := range databases {
+		if slices.Contains(requested, db.name) {
+			result = append(result, db)
+		}
+	}
+	return result, nil
+}
+
+// stopAll stops servers in the reverse of the order they were started
+func stopAll(logger *slog.Logger, started map[string]server, order []string) {
+	for i := len(order) - 1; i >= 0; i-- {
+		name := order[i]
+		if err := started[name].Stop(context.Background()); err != nil {
+			logger.Error("Error stopping MCP server", "database", name, "error", err)
+		}
+	}
+}
+
+func main() {
+	flag.Parse()
+
+	// Setup logging
+	logLevel := slog.LevelInfo
+	if *verbose {
+		logLevel = slog.LevelDebug
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: logLevel,
+	}))
+
+	toStart, err := selectDatabases(*selected)
+	if err != nil {
+		logger.Error("Invalid -databases", "error", err)
+		os.Exit(1)
+	}
+
+	started := make(map[string]server, len(toStart))
+	var order []string
+	for _, db := range toStart {
+		logger.Info("Starting MCP server",
+			"database", db.name,
+			"host", *host,
+			"port", *db.port)
+
+		opts := []mcp.Option{mcp.WithLogger(logger), mcp.WithRowHistory(*rowHistory), mcp.WithCertPaths(*cert, *key, *caCert), mcp.WithCallTimeout(*callTimeout), mcp.WithLeaderOnly(*leaderOnly), mcp.WithCache(strings.Split(*db.cache, ",")...)}
+		if *metrics {
+			opts = append(opts, mcp.WithMetrics())
+		}
+		if *db.endpoint != "" {
+			opts = append(opts, mcp.WithEndpoint(*db.endpoint))
+		}
+		server, err := db.newServer(*host, *db.port, opts...)
+		if err != nil {
+			logger.Error("Failed to create server", "database", db.name, "error", err)
+			stopAll(logger, started, order)
+			os.Exit(1)
+		}
+
+		addr := fmt.Sprintf("%s:%d", *host, *db.port)
+		if err := server.Start(context.Background(), addr); err != nil {
+			logger.Error("Failed to start MCP server", "database", db.name, "error", err)
+			stopAll(logger, started, order)
+			os.Exit(1)
+		}
+		started[db.name] = server
+		order = append(order, db.name)
+	}
+
+	// Wait for shutdown signal
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+
+	logger.Info("Shutting down...")
+
+	// Stop every server gracefully
+	stopAll(logger, started, order)
+}
@@ -7,8 +7,10 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
+	"github.com/dave-tucker/ariadne/internal/mcp"
 	"github.com/dave-tucker/ariadne/internal/mcp/ovnicsb"
 )
 
@@ -16,9 +18,34 @@ var (
 	port    = flag.Int("port", 8084, "MCP server port")
 	host    = flag.String("host", "localhost", "MCP server host")
 	verbose = flag.Bool("verbose", false, "Enable verbose logging")
+
+	endpoint = flag.String("endpoint", "", "OVN IC SB database endpoint, or the comma-separated endpoints of a clustered database, defaults to the local IC SB socket")
+
+	rowHistory = flag.Int("row-history", 0, "Number of row changes to record per table for row_history, 0 disables recording")
+
+	caCert = flag.String("ca-cert", "", "CA certificate to verify ssl: database endpoints with")
+	cert   = flag.String("cert", "", "Client certificate for ssl: database endpoints")
+	key    = flag.String("key", "", "Client private key for ssl: database endpoints")
+
+	leaderOnly = flag.Bool("leader-only", false, "Only connect to the leader of clustered databases")
+
+	callTimeout = flag.Duration("call-timeout", mcp.DefaultCallTimeout, "Maximum duration of a tool call, 0 disables the timeout")
+
+	metrics = flag.Bool("metrics", false, "Serve Prometheus metrics of tool calls on /metrics")
+
+	cache = flag.String("cache", "", "Comma-separated OVN IC SB tables to serve list tools from a monitored cache, e.g. Availability_Zone")
 )
 
 func main() {
+	var targets []mcp.Option
+	flag.Func("target", "Named database endpoint as name=endpoint, tools take a target argument selecting one, can be repeated, the first is the default", func(value string) error {
+		name, endpoint, ok := strings.Cut(value, "=")
+		if !ok {
+			return fmt.Errorf("must be name=endpoint")
+		}
+		targets = append(targets, mcp.WithTarget(name, endpoint))
+		return nil
+	})
 	flag.Parse()
 
 	// Setup logging
@@ -36,7 +63,15 @@ func main() {
 		"port", *port)
 
 	// Create server using the new package
-	server, err := ovnicsb.NewServer(*host, *port)
+	opts := []mcp.Option{mcp.WithLogger(logger), mcp.WithRowHistory(*rowHistory), mcp.WithCertPaths(*cert, *key, *caCert), mcp.WithCallTimeout(*callTimeout), mcp.WithLeaderOnly(*leaderOnly), mcp.WithCache(strings.Split(*cache, ",")...)}
+	if *metrics {
+		opts = append(opts, mcp.WithMetrics())
+	}
+	if *endpoint != "" {
+		opts = append(opts, mcp.WithEndpoint(*endpoint))
+	}
+	opts = append(opts, targets...)
+	server, err := ovnicsb.NewServer(*host, *port, opts...)
 	if err != nil {
 		logger.Error("Failed to create server", "error", err)
 		os.Exit(1)
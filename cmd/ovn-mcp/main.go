@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovn"
+)
+
+var (
+	port    = flag.Int("port", 8087, "MCP server port")
+	host    = flag.String("host", "localhost", "MCP server host")
+	verbose = flag.Bool("verbose", false, "Enable verbose logging")
+
+	nbEndpoint = flag.String("nb-endpoint", "", "OVN NB database endpoint, or the comma-separated endpoints of a clustered database, defaults to the local NB socket")
+	sbEndpoint = flag.String("sb-endpoint", "", "OVN SB database endpoint, or the comma-separated endpoints of a clustered database, defaults to the local SB socket")
+
+	caCert = flag.String("ca-cert", "", "CA certificate to verify ssl: database endpoints with")
+	cert   = flag.String("cert", "", "Client certificate for ssl: database endpoints")
+	key    = flag.String("key", "", "Client private key for ssl: database endpoints")
+
+	leaderOnly = flag.Bool("leader-only", false, "Only connect to the leader of clustered databases")
+
+	callTimeout = flag.Duration("call-timeout", mcp.DefaultCallTimeout, "Maximum duration of a tool call, 0 disables the timeout")
+
+	metrics = flag.Bool("metrics", false, "Serve Prometheus metrics of tool calls on /metrics")
+)
+
+func main() {
+	flag.Parse()
+
+	// Setup logging
+	logLevel := slog.LevelInfo
+	if *verbose {
+		logLevel = slog.LevelDebug
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: logLevel,
+	}))
+
+	logger.Info("Starting ovn-mcp server",
+		"host", *host,
+		"port", *port)
+
+	// Create server using the new package
+	opts := []mcp.Option{mcp.WithLogger(logger), mcp.WithCertPaths(*cert, *key, *caCert), mcp.WithCallTimeout(*callTimeout), mcp.WithLeaderOnly(*leaderOnly)}
+	if *metrics {
+		opts = append(opts, mcp.WithMetrics())
+	}
+	if *nbEndpoint != "" {
+		opts = append(opts, mcp.WithEndpoint(*nbEndpoint))
+	}
+	server, err := ovn.NewServer(*host, *port, *sbEndpoint, opts...)
+	if err != nil {
+		logger.Error("Failed to create server", "error", err)
+		os.Exit(1)
+	}
+
+	// Start the MCP server
+	addr := fmt.Sprintf("%s:%d", *host, *port)
+	if err := server.Start(context.Background(), addr); err != nil {
+		logger.Error("Failed to start MCP server", "error", err)
+		os.Exit(1)
+	}
+
+	// Wait for shutdown signal
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+
+	logger.Info("Shutting down...")
+
+	// Stop the server gracefully
+	if err := server.Stop(context.Background()); err != nil {
+		logger.Error("Error stopping MCP server", "error", err)
+	}
+}
@@ -0,0 +1,205 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/model"
+)
+
+// cacheStartTimeout bounds connecting to OVSDB and receiving the initial
+// contents of the cache's monitor
+const cacheStartTimeout = 30 * time.Second
+
+// cacheReconnectTimeout bounds each attempt to reconnect the cache's
+// connection after it is lost
+const cacheReconnectTimeout = 10 * time.Second
+
+// tableCache is a long-lived connection monitoring some of the database's
+// tables, so that list tools read them from the client's cache rather than
+// selecting them from the database on every call
+type tableCache struct {
+	tables []string
+	types  map[reflect.Type]bool
+
+	mu     sync.RWMutex
+	client *cachedClient
+	cancel context.CancelFunc
+}
+
+// cachedClient is the connection of a tableCache. It is shared by every
+// tool call, so closing it does nothing, and it reconnects by itself when
+// its connection is lost.
+type cachedClient struct {
+	client.Client
+	types map[reflect.Type]bool
+}
+
+// Close does nothing, the connection is closed when the server stops
+func (c *cachedClient) Close() {}
+
+// Connect does not connect the client again, as its own reconnection
+// restarts the monitor
+func (c *cachedClient) Connect(ctx context.Context) error {
+	if !c.Connected() {
+		return client.ErrNotConnected
+	}
+	return nil
+}
+
+// caches reports whether the table of m is monitored
+func (c *cachedClient) caches(m any) bool {
+	return c.types[reflect.TypeOf(m)]
+}
+
+// newTableCache returns the cache of tables, which must be tables of dbModel
+func newTableCache(dbModel model.ClientDBModel, tables []string) (*tableCache, error) {
+	cache := &tableCache{types: make(map[reflect.Type]bool)}
+	for _, table := range tables {
+		table = strings.TrimSpace(table)
+		if table == "" {
+			continue
+		}
+		t, ok := dbModel.Types()[table]
+		if !ok {
+			return nil, fmt.Errorf("unknown table %q to cache in database %s", table, dbModel.Name())
+		}
+		cache.tables = append(cache.tables, table)
+		cache.types[t] = true
+	}
+	return cache, nil
+}
+
+// connected returns the cache's client while it is connected and its
+// monitor has received the tables' contents, or nil
+func (t *tableCache) connected() *cachedClient {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.client == nil || !t.client.Connected() {
+		return nil
+	}
+	return t.client
+}
+
+// startCaching starts monitoring the cached tables in the background. Until
+// their contents have been received, tools select them from the database.
+func (s *BaseServer) startCaching() {
+	if len(s.cache.tables) == 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cache.cancel = cancel
+	go func() {
+		if err := s.startCache(ctx); err != nil {
+			s.Logger.Warn("Failed to start the table cache, tables are selected from the database", "endpoint", s.endpoint, "tables", s.cache.tables, "error", err)
+		}
+	}()
+}
+
+// stopCaching stops monitoring the cached tables
+func (s *BaseServer) stopCaching() {
+	if s.cache.cancel != nil {
+		s.cache.cancel()
+	}
+}
+
+// startCache connects to OVSDB and monitors the cached tables, keeping the
+// connection until ctx is done
+func (s *BaseServer) startCache(ctx context.Context) error {
+	startCtx, cancel := context.WithTimeout(ctx, cacheStartTimeout)
+	defer cancel()
+
+	opts := append(s.clientOptions(s.endpoint), client.WithReconnect(cacheReconnectTimeout, backoff.NewExponentialBackOff()))
+	c, err := client.NewOVSDBClient(s.dbModel, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	if err := c.Connect(startCtx); err != nil {
+		c.Close()
+		return fmt.Errorf("failed to connect to OVSDB: %w", err)
+	}
+
+	// libovsdb applies the updates to the cache before calling its event
+	// handlers, so they only have to be counted
+	if s.metrics != nil {
+		c.Cache().AddEventHandler(cacheUpdateCounter{s.metrics})
+	}
+
+	var monitorOpts []client.MonitorOption
+	for _, table := range s.cache.tables {
+		m := reflect.New(s.dbModel.Types()[table].Elem()).Interface().(model.Model)
+		monitorOpts = append(monitorOpts, client.WithTable(m))
+	}
+	if _, err := c.Monitor(startCtx, c.NewMonitor(monitorOpts...)); err != nil {
+		c.Close()
+		return fmt.Errorf("failed to monitor tables: %w", err)
+	}
+
+	s.cache.mu.Lock()
+	s.cache.client = &cachedClient{Client: c, types: s.cache.types}
+	s.cache.mu.Unlock()
+	s.Logger.Info("Serving tables from the cache", "tables", s.cache.tables)
+
+	go func() {
+		<-ctx.Done()
+		s.cache.mu.Lock()
+		s.cache.client = nil
+		s.cache.mu.Unlock()
+		c.Close()
+	}()
+	return nil
+}
+
+// cacheUpdateCounter counts the updates applied to the cache by table. It
+// only increments a counter, so it never holds up the libovsdb callback.
+type cacheUpdateCounter struct {
+	metrics *serverMetrics
+}
+
+func (c cacheUpdateCounter) OnAdd(table string, _ model.Model) {
+	c.metrics.cacheUpdate(table)
+}
+
+func (c cacheUpdateCounter) OnUpdate(table string, _, _ model.Model) {
+	c.metrics.cacheUpdate(table)
+}
+
+func (c cacheUpdateCounter) OnDelete(table string, _ model.Model) {
+	c.metrics.cacheUpdate(table)
+}
+
+// listCached reads the rows of m's table matching conditions from the cache
+// when c is the client of a tableCache monitoring the table. ok is false
+// when the rows must be selected from the database instead. The rows are
+// matched against all of the conditions when matchAll is set, and against
+// any of them otherwise, and are sorted by UUID so that pages are stable.
+func listCached[T any](ctx context.Context, c client.Client, m *T, matchAll bool, conditions ...model.Condition) (results []T, ok bool, err error) {
+	cached, isCached := c.(*cachedClient)
+	if !isCached || !cached.caches(m) {
+		return nil, false, nil
+	}
+
+	switch {
+	case len(conditions) == 0:
+		err = cached.List(ctx, &results)
+	case matchAll:
+		err = cached.WhereAll(m, conditions...).List(ctx, &results)
+	default:
+		err = cached.WhereAny(m, conditions...).List(ctx, &results)
+	}
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to list cached rows: %w", err)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return rowUUID(&results[i]) < rowUUID(&results[j])
+	})
+	return results, true, nil
+}
@@ -0,0 +1,170 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type CountByArgs struct {
+	Table   string `json:"table" jsonschema:"the table to count the rows of, e.g. Logical_Switch_Port"`
+	GroupBy string `json:"group_by" jsonschema:"the column whose values the rows are grouped by, e.g. type or up"`
+}
+
+// noValue is the group of rows whose column is an empty string, set or map,
+// e.g. an optional column that is not set
+const noValue = "(none)"
+
+// groupKey formats a value in OVSDB notation as a group name. The elements
+// of sets and maps are sorted, so rows with the same values are counted in
+// the same group.
+func groupKey(value any) string {
+	switch v := value.(type) {
+	case ovsdb.OvsSet:
+		if len(v.GoSet) == 0 {
+			return noValue
+		}
+		elems := make([]string, 0, len(v.GoSet))
+		for _, e := range v.GoSet {
+			elems = append(elems, groupKey(e))
+		}
+		sort.Strings(elems)
+		return strings.Join(elems, ", ")
+	case ovsdb.OvsMap:
+		if len(v.GoMap) == 0 {
+			return noValue
+		}
+		elems := make([]string, 0, len(v.GoMap))
+		for key, val := range v.GoMap {
+			elems = append(elems, groupKey(key)+"="+groupKey(val))
+		}
+		sort.Strings(elems)
+		return strings.Join(elems, ", ")
+	case ovsdb.UUID:
+		return v.GoUUID
+	case nil:
+		// Columns with their default value may be left out of the row
+		return noValue
+	default:
+		if key := fmt.Sprint(v); key != "" {
+			return key
+		}
+		return noValue
+	}
+}
+
+// CountBy counts the rows of any table of the database grouped by the value
+// of a column, for summaries that don't need the rows themselves
+func (s *BaseServer) CountBy(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[CountByArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	if args.Table == "" {
+		return nil, fmt.Errorf("table is required")
+	}
+	if args.GroupBy == "" {
+		return nil, fmt.Errorf("group_by is required")
+	}
+
+	c, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	table, err := lookupTable(c.Schema(), args.Table)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := table.Columns[args.GroupBy]; !ok {
+		return nil, fmt.Errorf("unknown column %q, valid columns are: %s", args.GroupBy, strings.Join(columnNames(table), ", "))
+	}
+
+	// Only the column is selected, as the rows themselves aren't returned
+	ops := []ovsdb.Operation{{
+		Op:      ovsdb.OperationSelect,
+		Table:   args.Table,
+		Where:   []ovsdb.Condition{},
+		Columns: []string{args.GroupBy},
+	}}
+	reply, err := Transact(ctx, c, ops...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+	if _, err := ovsdb.CheckOperationResults(reply, ops); err != nil {
+		return nil, fmt.Errorf("failed to select rows: %w", err)
+	}
+
+	groups := make(map[string]int)
+	for _, row := range reply[0].Rows {
+		groups[groupKey(row[args.GroupBy])]++
+	}
+
+	return NewResult(map[string]interface{}{
+		"table":    args.Table,
+		"group_by": args.GroupBy,
+		"groups":   groups,
+		"total":    len(reply[0].Rows),
+		"context":  fmt.Sprintf("The number of rows of the %s table with each value of %s. Rows whose %s is an empty string, set or map, such as an optional column that is not set, are counted under %s. Set and map values are grouped by all of their elements, joined with commas. Use ovsdb_select with a condition on the column to get the rows of a group.", args.Table, args.GroupBy, args.GroupBy, noValue),
+	})
+}
+
+// countOnlyContext is the context of the result of a list tool called with
+// count_only
+const countOnlyContext = "Only the number of matching rows is returned as count_only was set. Call the tool again without count_only, using limit and offset to page through them, for the rows themselves."
+
+// CountRows counts the rows of m's table matching all of conditions. Only
+// the rows' UUIDs are selected, so large tables are counted without
+// transferring or decoding their rows. Cached tables are counted in the
+// cache.
+func CountRows[T any](ctx context.Context, c client.Client, m *T, conditions ...model.Condition) (int, error) {
+	if rows, ok, err := listCached(ctx, c, m, true, conditions...); ok {
+		return len(rows), err
+	}
+
+	var ops []ovsdb.Operation
+	var err error
+	if len(conditions) > 0 {
+		ops, _, err = c.WhereAll(m, conditions...).Select()
+	} else {
+		ops, _, err = c.Where(m).Select()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to create select operation: %w", err)
+	}
+	for i := range ops {
+		ops[i].Columns = []string{"_uuid"}
+	}
+
+	reply, err := Transact(ctx, c, ops...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+	if _, err := ovsdb.CheckOperationResults(reply, ops); err != nil {
+		return 0, fmt.Errorf("failed to select rows: %w", err)
+	}
+
+	// A query can be split into several selects, whose rows may overlap
+	uuids := make(map[any]bool)
+	for _, r := range reply {
+		for _, row := range r.Rows {
+			uuids[row["_uuid"]] = true
+		}
+	}
+	return len(uuids), nil
+}
+
+// CountResult is the result of a list tool called with count_only, holding
+// the number of matching rows without the rows
+func CountResult(count int) (*mcpsdk.CallToolResultFor[ListResult], error) {
+	return NewResult(ListResult{
+		Count:   count,
+		Total:   count,
+		Context: countOnlyContext,
+	})
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+// dryRunArgument is the argument of the write tools that returns the
+// operations a call would run instead of running them
+const dryRunArgument = "dry_run"
+
+// errDryRun is returned by TransactWrite during a dry run in place of
+// running the transaction, so the tool stops before using its reply
+var errDryRun = errors.New("dry run, the transaction was not run")
+
+// DryRunResult is the result of a write tool called with dry_run set: the
+// OVSDB operations it would have run, in one transaction
+type DryRunResult struct {
+	DryRun     bool              `json:"dry_run"`
+	Database   string            `json:"database"`
+	Operations []ovsdb.Operation `json:"operations"`
+	Context    string            `json:"context"`
+}
+
+// Summary describes the result in a line of text
+func (r DryRunResult) Summary() string {
+	return fmt.Sprintf("Dry run, %d operations on %s were not run.", len(r.Operations), r.Database)
+}
+
+// dryRun collects the transaction of a dry run
+type dryRun struct {
+	result *DryRunResult
+}
+
+type dryRunKey struct{}
+
+// contextWithDryRun returns a context in which TransactWrite records its
+// transaction in plan instead of running it
+func contextWithDryRun(ctx context.Context, plan *dryRun) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, plan)
+}
+
+// planTransaction records the operations of a write transaction when ctx is
+// a dry run's, and reports whether it was
+func planTransaction(ctx context.Context, database string, ops []ovsdb.Operation) bool {
+	plan, ok := ctx.Value(dryRunKey{}).(*dryRun)
+	if !ok {
+		return false
+	}
+	plan.result = &DryRunResult{
+		DryRun:     true,
+		Database:   database,
+		Operations: ops,
+		Context:    "Nothing was written. These are the OVSDB operations the call would run, in one transaction that is applied entirely or not at all. Named UUIDs such as new_acl stand for the rows the transaction inserts, and the where clauses select the rows it updates, mutates or deletes; they are evaluated when the transaction runs, so the result may differ if the database changes first. Call the tool again without dry_run to run it.",
+	}
+	return true
+}
+
+// AddWriteTool registers a tool that writes to the database like AddTool,
+// adding a dry_run argument. With dry_run set the tool runs as usual until
+// it calls TransactWrite, which returns its operations as the result
+// instead of running them. The tool has no output schema, as its result is
+// either Out or a DryRunResult.
+func AddWriteTool[In, Out any](s *BaseServer, t *mcpsdk.Tool, h mcpsdk.ToolHandlerFor[In, Out]) {
+	addTool(s, t, h, s.callTimeout, true)
+}
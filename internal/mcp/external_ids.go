@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+// externalIDsColumn is the column in which OVN and OVS clients tag rows
+const externalIDsColumn = "external_ids"
+
+type FindByExternalIDKeyArgs struct {
+	Key   string `json:"key" jsonschema:"the external_ids key rows must have, e.g. k8s.ovn.org/owner"`
+	Value string `json:"value,omitempty" jsonschema:"only match rows whose value for the key contains this substring"`
+}
+
+// externalIDsTables returns the tables of schema with an external_ids map
+// column, sorted by name
+func externalIDsTables(schema ovsdb.DatabaseSchema) []string {
+	var tables []string
+	for name, table := range schema.Tables {
+		column, ok := table.Columns[externalIDsColumn]
+		if !ok || column.Type != ovsdb.TypeMap {
+			continue
+		}
+		tables = append(tables, name)
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// FindByExternalIDKey returns the rows of every table that have an
+// external_ids key, whatever its value. OVSDB can only match a map column
+// against whole key-value pairs, so every row is selected and the keys are
+// matched here.
+func (s *BaseServer) FindByExternalIDKey(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[FindByExternalIDKeyArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	if args.Key == "" {
+		return nil, fmt.Errorf("key is required")
+	}
+
+	c, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	tables := externalIDsTables(c.Schema())
+	ops := make([]ovsdb.Operation, 0, len(tables))
+	for _, table := range tables {
+		ops = append(ops, ovsdb.Operation{
+			Op:    ovsdb.OperationSelect,
+			Table: table,
+		})
+	}
+	reply, err := Transact(ctx, c, ops...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+	if _, err := ovsdb.CheckOperationResults(reply, ops); err != nil {
+		return nil, fmt.Errorf("failed to select rows: %w", err)
+	}
+
+	matches := map[string][]ovsdb.Row{}
+	count := 0
+	for i, result := range reply {
+		for _, row := range result.Rows {
+			externalIDs, ok := row[externalIDsColumn].(ovsdb.OvsMap)
+			if !ok {
+				continue
+			}
+			value, ok := externalIDs.GoMap[args.Key].(string)
+			if !ok || !strings.Contains(value, args.Value) {
+				continue
+			}
+			matches[tables[i]] = append(matches[tables[i]], row)
+			count++
+		}
+	}
+
+	result := map[string]interface{}{
+		"key":     args.Key,
+		"matches": matches,
+		"count":   count,
+		"tables":  len(matches),
+		"context": "Rows of every table with an external_ids column that have the key, in OVSDB notation and grouped by table. Clients such as ovn-kubernetes tag the rows they own with external_ids keys, so this finds everything a client or controller owns regardless of the key's value. When value is set, only rows whose value contains it are returned.",
+	}
+
+	return NewResult(result)
+}
@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Match modes supported by the name filters
+const (
+	MatchExact     = "exact"
+	MatchPrefix    = "prefix"
+	MatchSubstring = "substring"
+	MatchRegex     = "regex"
+)
+
+// NameMatcher matches names against a name filter. OVSDB conditions only
+// support equality, so non-exact modes are evaluated in Go after the select.
+type NameMatcher struct {
+	filter string
+	mode   string
+	re     *regexp.Regexp
+}
+
+// NewNameMatcher creates a NameMatcher for filter. An empty mode defaults to
+// MatchExact. Prefix and substring matches are case-insensitive, regex
+// filters use RE2 syntax and are unanchored.
+func NewNameMatcher(filter string, mode string) (*NameMatcher, error) {
+	if mode == "" {
+		mode = MatchExact
+	}
+	m := &NameMatcher{
+		filter: filter,
+		mode:   mode,
+	}
+	switch mode {
+	case MatchExact, MatchPrefix, MatchSubstring:
+	case MatchRegex:
+		re, err := regexp.Compile(filter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex name filter: %w", err)
+		}
+		m.re = re
+	default:
+		return nil, fmt.Errorf("invalid match mode %q, must be one of %s, %s, %s or %s", mode, MatchExact, MatchPrefix, MatchSubstring, MatchRegex)
+	}
+	return m, nil
+}
+
+// Exact reports whether the filter can be evaluated by OVSDB as an equality
+// condition
+func (m *NameMatcher) Exact() bool {
+	return m.filter != "" && m.mode == MatchExact
+}
+
+// Match reports whether name matches the filter. An empty filter matches
+// everything.
+func (m *NameMatcher) Match(name string) bool {
+	if m.filter == "" {
+		return true
+	}
+	switch m.mode {
+	case MatchPrefix:
+		return strings.HasPrefix(strings.ToLower(name), strings.ToLower(m.filter))
+	case MatchSubstring:
+		return strings.Contains(strings.ToLower(name), strings.ToLower(m.filter))
+	case MatchRegex:
+		return m.re.MatchString(name)
+	default:
+		return name == m.filter
+	}
+}
+
+// FilterByName returns the results whose name, as returned by name, matches
+func FilterByName[T any](results []T, m *NameMatcher, name func(T) string) []T {
+	if m.filter == "" {
+		return results
+	}
+	var filtered []T
+	for _, result := range results {
+		if m.Match(name(result)) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,115 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// healthTTL is how long the result of a health check is reused, so that
+// frequent load balancer probes and pings don't each connect to the
+// databases
+const healthTTL = 5 * time.Second
+
+// HTTP paths served alongside the MCP handler
+const (
+	// MCPPath serves MCP over Streamable HTTP, which is also served on / for
+	// existing clients
+	MCPPath = "/mcp"
+	// HealthPath reports that the process is up, for liveness probes
+	HealthPath = "/healthz"
+	// ReadyPath reports whether the server's databases are reachable, with
+	// 200 when they all are and 503 otherwise, for readiness probes
+	ReadyPath = "/readyz"
+)
+
+// healthCache holds the result of the last health check
+type healthCache struct {
+	mu       sync.Mutex
+	checked  time.Time
+	statuses []DatabaseStatus
+}
+
+// HealthResult is the result of a health check
+type HealthResult struct {
+	Healthy   bool             `json:"healthy"`
+	Databases []DatabaseStatus `json:"databases"`
+	CheckedAt time.Time        `json:"checked_at"`
+}
+
+// CheckHealth connects to each of the server's databases, reusing the
+// result of a check made in the last few seconds
+func (s *BaseServer) CheckHealth(ctx context.Context) HealthResult {
+	s.health.mu.Lock()
+	defer s.health.mu.Unlock()
+
+	if s.health.statuses == nil || time.Since(s.health.checked) > healthTTL {
+		statuses := make([]DatabaseStatus, 0, len(s.statusDatabases))
+		for _, db := range s.statusDatabases {
+			status := s.probe(ctx, db)
+			if !status.Connected {
+				s.Logger.Warn("Database is unreachable", "database", status.Database, "endpoint", status.Endpoint, "error", status.Error)
+			}
+			statuses = append(statuses, status)
+		}
+		s.health.statuses = statuses
+		s.health.checked = time.Now()
+	}
+
+	return newHealthResult(s.health.checked, s.health.statuses)
+}
+
+func newHealthResult(checked time.Time, statuses []DatabaseStatus) HealthResult {
+	healthy := true
+	for _, status := range statuses {
+		healthy = healthy && status.Connected
+	}
+	return HealthResult{
+		Healthy:   healthy,
+		Databases: statuses,
+		CheckedAt: checked,
+	}
+}
+
+// healthHandler reports that the process is up without checking the
+// databases, so that a database outage doesn't get the server restarted
+func healthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("ok\n"))
+	})
+}
+
+// readyHandler serves the result of check as JSON, with 503 when a
+// database is unreachable
+func readyHandler(check func(ctx context.Context) HealthResult) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result := check(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		if !result.Healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	})
+}
+
+type PingDatabaseArgs struct{}
+
+// PingDatabase reports whether the server's databases are reachable, and
+// the version of the schema each one serves
+func (s *BaseServer) PingDatabase(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[PingDatabaseArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	health := s.CheckHealth(ctx)
+
+	result := map[string]interface{}{
+		"healthy":    health.Healthy,
+		"databases":  health.Databases,
+		"checked_at": health.CheckedAt,
+		"context":    "Each database is reached with a fresh connection, the same way tools connect, and reports its latency in milliseconds and the version of the schema it serves. Results are reused for a few seconds, so checked_at may be slightly in the past. When a database is unreachable, tools using it will fail until it is back; check that its server is running and the endpoint is correct.",
+	}
+
+	return NewResult(result)
+}
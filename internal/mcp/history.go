@@ -0,0 +1,356 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/mapper"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+// Operations recorded in a ChangeLog
+const (
+	RowInsert = "insert"
+	RowUpdate = "update"
+	RowDelete = "delete"
+)
+
+// RowChange is a recorded change to a row. Before is nil for an insert and
+// After is nil for a delete.
+type RowChange struct {
+	Time   time.Time      `json:"time"`
+	Table  string         `json:"table"`
+	UUID   string         `json:"uuid"`
+	Op     string         `json:"op"`
+	Before map[string]any `json:"before,omitempty"`
+	After  map[string]any `json:"after,omitempty"`
+}
+
+// ChangeLog keeps the most recent changes to the rows of each table of a
+// monitored database, up to size changes per table. Changes are only
+// recorded once the rows present before the monitor started have been
+// recorded as the baseline with SetBaseline.
+type ChangeLog struct {
+	schema ovsdb.DatabaseSchema
+	mapper mapper.Mapper
+	size   int
+
+	mu       sync.RWMutex
+	tables   map[string][]RowChange
+	baseline map[string]bool
+	ready    bool
+}
+
+// NewChangeLog creates a ChangeLog for the database described by schema
+func NewChangeLog(schema ovsdb.DatabaseSchema, size int) *ChangeLog {
+	return &ChangeLog{
+		schema: schema,
+		mapper: mapper.NewMapper(schema),
+		size:   size,
+		tables: make(map[string][]RowChange),
+	}
+}
+
+// SetBaseline selects the rows of tables from the server as existing before
+// the monitor starts, so the inserts for its initial contents are not
+// recorded as changes, and starts recording. It must be called before the
+// monitor is started, as its initial contents are delivered to the event
+// handlers after Monitor returns, when the cache may also hold later
+// changes.
+func (l *ChangeLog) SetBaseline(ctx context.Context, c client.Client, tables ...string) error {
+	baseline, err := selectUUIDs(ctx, c, tables...)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	l.baseline = baseline
+	l.ready = true
+	l.mu.Unlock()
+	return nil
+}
+
+// selectUUIDs returns the UUIDs of the rows of tables
+func selectUUIDs(ctx context.Context, c client.Client, tables ...string) (map[string]bool, error) {
+	uuids := make(map[string]bool)
+	if len(tables) == 0 {
+		return uuids, nil
+	}
+	ops := make([]ovsdb.Operation, 0, len(tables))
+	for _, table := range tables {
+		ops = append(ops, ovsdb.Operation{
+			Op:      ovsdb.OperationSelect,
+			Table:   table,
+			Columns: []string{"_uuid"},
+		})
+	}
+	reply, err := Transact(ctx, c, ops...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+	if _, err := ovsdb.CheckOperationResults(reply, ops); err != nil {
+		return nil, fmt.Errorf("failed to select rows: %w", err)
+	}
+	for _, result := range reply {
+		for _, row := range result.Rows {
+			if uuid, ok := row["_uuid"].(ovsdb.UUID); ok {
+				uuids[uuid.GoUUID] = true
+			}
+		}
+	}
+	return uuids, nil
+}
+
+// Ready reports whether changes are being recorded
+func (l *ChangeLog) Ready() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.ready
+}
+
+func (l *ChangeLog) row(table string, m model.Model) (map[string]any, error) {
+	if m == nil {
+		return nil, nil
+	}
+	info, err := mapper.NewInfo(table, l.schema.Table(table), m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create info: %w", err)
+	}
+	return l.mapper.NewRow(info)
+}
+
+// Record adds an update from the monitor to the log
+func (l *ChangeLog) Record(u Update) error {
+	if !l.Ready() {
+		return nil
+	}
+	change := RowChange{
+		Time:  u.Queued,
+		Table: u.Table,
+		UUID:  u.UUID,
+	}
+	switch {
+	case u.Old == nil:
+		change.Op = RowInsert
+	case u.New == nil:
+		change.Op = RowDelete
+	default:
+		change.Op = RowUpdate
+	}
+	var err error
+	if change.Before, err = l.row(u.Table, u.Old); err != nil {
+		return err
+	}
+	if change.After, err = l.row(u.Table, u.New); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if change.Op == RowInsert && l.baseline[u.UUID] {
+		// Part of the initial contents of the monitor
+		delete(l.baseline, u.UUID)
+		return nil
+	}
+	delete(l.baseline, u.UUID)
+	changes := append(l.tables[u.Table], change)
+	if len(changes) > l.size {
+		changes = slices.Delete(changes, 0, len(changes)-l.size)
+	}
+	l.tables[u.Table] = changes
+	return nil
+}
+
+// History returns the recorded changes to the row with uuid, oldest first
+func (l *ChangeLog) History(uuid string) []RowChange {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	history := []RowChange{}
+	for _, changes := range l.tables {
+		for _, c := range changes {
+			if c.UUID == uuid {
+				history = append(history, c)
+			}
+		}
+	}
+	sort.SliceStable(history, func(i, j int) bool { return history[i].Time.Before(history[j].Time) })
+	return history
+}
+
+// Changes returns the recorded changes to the rows of table made at or
+// after since, oldest first. complete is false when older changes to the
+// table have been dropped from the log, so some changes made since then
+// may be missing.
+func (l *ChangeLog) Changes(table string, since time.Time) (changes []RowChange, complete bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	recorded := l.tables[table]
+	changes = []RowChange{}
+	for _, c := range recorded {
+		if !c.Time.Before(since) {
+			changes = append(changes, c)
+		}
+	}
+	complete = len(recorded) < l.size || recorded[0].Time.Before(since)
+	return changes, complete
+}
+
+// historyStartTimeout bounds connecting to OVSDB and receiving the initial
+// contents of the monitor when starting to record row history
+const historyStartTimeout = 30 * time.Second
+
+// startHistory monitors the whole database and records its changes in the
+// server's ChangeLog until ctx is done
+func (s *BaseServer) startHistory(ctx context.Context) error {
+	startCtx, cancel := context.WithTimeout(ctx, historyStartTimeout)
+	defer cancel()
+
+	c, err := client.NewOVSDBClient(s.dbModel, s.clientOptions(s.endpoint)...)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	if err := c.Connect(startCtx); err != nil {
+		c.Close()
+		return fmt.Errorf("failed to connect to OVSDB: %w", err)
+	}
+
+	history := NewChangeLog(c.Schema(), s.historySize)
+	if err := history.SetBaseline(startCtx, c, c.Cache().Tables()...); err != nil {
+		c.Close()
+		return fmt.Errorf("failed to select the rows present before monitoring: %w", err)
+	}
+	processor := NewUpdateProcessor(1, func(u Update) {
+		if err := history.Record(u); err != nil {
+			s.Logger.Warn("Failed to record row change", "table", u.Table, "uuid", u.UUID, "error", err)
+		}
+	})
+	processor.Start(ctx)
+	s.metrics.observeUpdates("history", processor)
+	c.Cache().AddEventHandler(processor)
+
+	if _, err := c.MonitorAll(startCtx); err != nil {
+		c.Close()
+		return fmt.Errorf("failed to monitor database: %w", err)
+	}
+	s.history = history
+
+	go func() {
+		<-ctx.Done()
+		c.Close()
+	}()
+	return nil
+}
+
+// ChangeLog returns the server's row history, or nil if it is not
+// recording changes
+func (s *BaseServer) ChangeLog() *ChangeLog {
+	if s.history == nil || !s.history.Ready() {
+		return nil
+	}
+	return s.history
+}
+
+type RowHistoryArgs struct {
+	UUID  string `json:"uuid" jsonschema:"the UUID of the row"`
+	Table string `json:"table,omitempty" jsonschema:"the table of the row, all tables are searched when empty"`
+}
+
+// RowHistory returns the current value of a row and, when the server records
+// row history, its recent changes
+func (s *BaseServer) RowHistory(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[RowHistoryArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	if err := ovsdb.ValidateUUID(args.UUID); err != nil {
+		return nil, fmt.Errorf("invalid uuid %q: %w", args.UUID, err)
+	}
+
+	c, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	schema := c.Schema()
+	tables := make([]string, 0, len(schema.Tables))
+	if args.Table != "" {
+		if _, ok := schema.Tables[args.Table]; !ok {
+			return nil, fmt.Errorf("unknown table %q", args.Table)
+		}
+		tables = append(tables, args.Table)
+	} else {
+		for table := range schema.Tables {
+			tables = append(tables, table)
+		}
+		sort.Strings(tables)
+	}
+
+	ops := make([]ovsdb.Operation, 0, len(tables))
+	for _, table := range tables {
+		ops = append(ops, ovsdb.Operation{
+			Op:    ovsdb.OperationSelect,
+			Table: table,
+			Where: []ovsdb.Condition{ovsdb.NewCondition("_uuid", ovsdb.ConditionEqual, ovsdb.UUID{GoUUID: args.UUID})},
+		})
+	}
+	reply, err := Transact(ctx, c, ops...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+	if _, err := ovsdb.CheckOperationResults(reply, ops); err != nil {
+		return nil, fmt.Errorf("failed to select row: %w", err)
+	}
+
+	var table string
+	var current ovsdb.Row
+	for i, result := range reply {
+		if len(result.Rows) > 0 {
+			table = tables[i]
+			current = result.Rows[0]
+			break
+		}
+	}
+
+	// History is only recorded for the default endpoint
+	historyAvailable := s.history != nil && s.history.Ready() && s.Endpoint(ctx) == s.endpoint
+	history := []RowChange{}
+	if historyAvailable {
+		history = s.history.History(args.UUID)
+		if table == "" && len(history) > 0 {
+			table = history[len(history)-1].Table
+		}
+	}
+
+	if current == nil && len(history) == 0 {
+		return &mcpsdk.CallToolResultFor[map[string]any]{
+			IsError: true,
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: fmt.Sprintf("No row found with UUID %s", args.UUID),
+				},
+			},
+		}, nil
+	}
+
+	result := map[string]interface{}{
+		"uuid":              args.UUID,
+		"table":             table,
+		"exists":            current != nil,
+		"current":           current,
+		"history_available": historyAvailable,
+		"history":           history,
+		"count":             len(history),
+	}
+	if historyAvailable {
+		result["context"] = fmt.Sprintf("The current value of the row and the changes to it recorded since the server started monitoring the database, oldest first, with the row before and after each change in OVSDB notation. Up to %d changes are kept per table, so older changes to busy tables are dropped.", s.historySize)
+	} else {
+		result["context"] = "The current value of the row in OVSDB notation. OVSDB servers do not keep the history of rows, and this server is not recording changes, so no history is available. Start the server with row history enabled to record changes from then on."
+	}
+
+	return NewResult(result)
+}
@@ -0,0 +1,162 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+// ParentFilter restricts a list tool to the rows of a parent, e.g. the
+// ports of a logical switch. The parents are the rows of Model whose Field
+// equals Value.
+type ParentFilter[P, C any] struct {
+	// Field must point into Model
+	Model *P
+	Field any
+	Value any
+	// Kind names the parent in the result context when no parent matches,
+	// e.g. "logical switch"
+	Kind string
+	// Keep reports whether child belongs to parent, e.g. whether the
+	// switch's ports column references the port. Rows belonging to any of
+	// the parents are kept.
+	Keep func(parent P, child C) bool
+}
+
+// ListQuery is the query of a list tool whose rows can be filtered by a
+// parent
+type ListQuery[P, C any] struct {
+	Model  *C
+	Table  string
+	Schema ovsdb.DatabaseSchema
+	// Key is the key of the rows in the result's Data, e.g. "acls"
+	Key         string
+	Context     string
+	Limit       int
+	Offset      int
+	ResolveRefs bool
+	// Conditions restrict the rows selected, whether or not they are
+	// filtered by a parent
+	Conditions []model.Condition
+	// Filter, when set, is applied to the selected rows for conditions
+	// that can't be expressed as a model.Condition
+	Filter func(C) bool
+	// Parent is nil when the tool was called without a parent filter
+	Parent *ParentFilter[P, C]
+	// CountOnly returns the number of rows without the rows themselves
+	CountOnly bool
+}
+
+// SelectWithParentFilter selects the rows of m's table matching all of
+// conditions, keeping only those belonging to the parents matched by parent
+// when it is not nil. found is
+// false when no parent matched, in which case no rows are selected.
+// The rows are filtered here rather than by the select, as the parent and
+// child are linked by reference columns that OVSDB can't join on.
+func SelectWithParentFilter[P, C any](ctx context.Context, c client.Client, m *C, parent *ParentFilter[P, C], conditions ...model.Condition) (results []C, found bool, err error) {
+	results, _, found, err = selectWithParentFilter(ctx, c, m, parent, false, conditions...)
+	return results, found, err
+}
+
+// selectWithParentFilter is SelectWithParentFilter, selecting the rows with
+// ExecuteSelectQueryLenient when lenient is set and returning its warnings
+func selectWithParentFilter[P, C any](ctx context.Context, c client.Client, m *C, parent *ParentFilter[P, C], lenient bool, conditions ...model.Condition) (results []C, warnings []string, found bool, err error) {
+	var parents []P
+	if parent != nil {
+		parents, err = ExecuteSelectQuery(ctx, c, parent.Model, model.Condition{
+			Field:    parent.Field,
+			Function: ovsdb.ConditionEqual,
+			Value:    parent.Value,
+		})
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("failed to select %s: %w", parent.Kind, err)
+		}
+		if len(parents) == 0 {
+			return nil, nil, false, nil
+		}
+	}
+
+	if lenient {
+		results, warnings, err = ExecuteSelectQueryLenient(ctx, c, m, conditions...)
+	} else {
+		results, err = ExecuteSelectQuery(ctx, c, m, conditions...)
+	}
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	if parent != nil {
+		kept := []C{}
+		for _, child := range results {
+			if slices.ContainsFunc(parents, func(p P) bool { return parent.Keep(p, child) }) {
+				kept = append(kept, child)
+			}
+		}
+		results = kept
+	}
+	return results, warnings, true, nil
+}
+
+// NoParentResult is the result of a list tool when no parent matched its
+// filter
+func NoParentResult(key, kind string) (*mcpsdk.CallToolResultFor[ListResult], error) {
+	return NewResult(ListResult{
+		Data:    map[string]any{key: []map[string]any{}},
+		Count:   0,
+		Context: fmt.Sprintf("No %s found with the specified filter.", kind),
+	})
+}
+
+// ListWithParentFilter runs q and returns a page of its rows, for list
+// tools that do nothing more than filter by a parent. Rows that can't be
+// decoded are skipped and reported in the result's warnings.
+func ListWithParentFilter[P, C any](ctx context.Context, c client.Client, q ListQuery[P, C]) (*mcpsdk.CallToolResultFor[ListResult], error) {
+	if q.CountOnly && q.Parent == nil && q.Filter == nil {
+		// Every selected row is counted, so OVSDB can count them
+		count, err := CountRows(ctx, c, q.Model, q.Conditions...)
+		if err != nil {
+			return nil, err
+		}
+		return CountResult(count)
+	}
+
+	results, warnings, found, err := selectWithParentFilter(ctx, c, q.Model, q.Parent, true, q.Conditions...)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return NoParentResult(q.Key, q.Parent.Kind)
+	}
+	if q.Filter != nil {
+		results = slices.DeleteFunc(results, func(row C) bool { return !q.Filter(row) })
+	}
+	if q.CountOnly {
+		return CountResult(len(results))
+	}
+
+	results, page := Paginate(results, q.Limit, q.Offset)
+
+	rows, err := MapRows(q.Table, q.Schema, results)
+	if err != nil {
+		return nil, err
+	}
+	if q.ResolveRefs {
+		if err := ResolveRefs(ctx, c, q.Schema, q.Table, rows); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewResult(ListResult{
+		Data:       map[string]any{q.Key: rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Warnings:   warnings,
+		Context:    q.Context,
+	})
+}
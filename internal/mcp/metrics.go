@@ -0,0 +1,178 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsPath serves the server's Prometheus metrics when they are enabled
+const MetricsPath = "/metrics"
+
+// Statuses of a tool call in the tool_calls_total metric
+const (
+	callSuccess = "success"
+	callError   = "error"
+)
+
+// serverMetrics are the Prometheus metrics of a server. A nil
+// *serverMetrics records nothing, so servers without metrics enabled don't
+// create or update them.
+type serverMetrics struct {
+	registry      *prometheus.Registry
+	toolCalls     *prometheus.CounterVec
+	toolDuration  *prometheus.HistogramVec
+	connectErrors *prometheus.CounterVec
+	cacheUpdates  *prometheus.CounterVec
+	updates       *updateCollector
+}
+
+// newServerMetrics registers the metrics of the server named server in a
+// registry of their own, labelled with the server's name so that the
+// metrics of several servers can be gathered together
+func newServerMetrics(server string) *serverMetrics {
+	m := &serverMetrics{
+		registry: prometheus.NewRegistry(),
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tool_calls_total",
+			Help: "Number of tool calls by tool and status, success or error.",
+		}, []string{"tool", "status"}),
+		toolDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "tool_call_duration_seconds",
+			Help:    "Duration of tool calls by tool.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"tool"}),
+		connectErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ovsdb_connect_errors_total",
+			Help: "Number of failed connections to OVSDB by database.",
+		}, []string{"database"}),
+		cacheUpdates: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ovsdb_cache_updates_total",
+			Help: "Number of row updates applied to the table cache by table.",
+		}, []string{"table"}),
+		updates: newUpdateCollector(),
+	}
+	registerer := prometheus.WrapRegistererWith(prometheus.Labels{"server": server}, m.registry)
+	registerer.MustRegister(m.toolCalls, m.toolDuration, m.connectErrors, m.cacheUpdates, m.updates)
+	return m
+}
+
+// observeCall records a tool call that took duration
+func (m *serverMetrics) observeCall(tool, status string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.toolCalls.WithLabelValues(tool, status).Inc()
+	m.toolDuration.WithLabelValues(tool).Observe(duration.Seconds())
+}
+
+// connectError records a failure to connect to database
+func (m *serverMetrics) connectError(database string) {
+	if m == nil {
+		return
+	}
+	m.connectErrors.WithLabelValues(database).Inc()
+}
+
+// cacheUpdate records an update to a row of table processed by the cache
+func (m *serverMetrics) cacheUpdate(table string) {
+	if m == nil {
+		return
+	}
+	m.cacheUpdates.WithLabelValues(table).Inc()
+}
+
+// observeUpdates reports the lag and backlog of p, which processes the
+// updates of the monitor named monitor. A processor observed under the same
+// name replaces it, as when the server is restarted.
+func (m *serverMetrics) observeUpdates(monitor string, p *UpdateProcessor) {
+	if m == nil {
+		return
+	}
+	m.updates.mu.Lock()
+	defer m.updates.mu.Unlock()
+	m.updates.processors[monitor] = p
+}
+
+// gatherer returns the registry of the metrics, or nil
+func (m *serverMetrics) gatherer() prometheus.Gatherer {
+	if m == nil {
+		return nil
+	}
+	return m.registry
+}
+
+// updateCollector collects the lag and number of pending updates of the
+// UpdateProcessors of the server's monitors that process their updates,
+// such as the row history, when the metrics are gathered
+type updateCollector struct {
+	lag     *prometheus.Desc
+	pending *prometheus.Desc
+
+	mu         sync.Mutex
+	processors map[string]*UpdateProcessor
+}
+
+func newUpdateCollector() *updateCollector {
+	return &updateCollector{
+		lag: prometheus.NewDesc("ovsdb_update_lag_seconds",
+			"How long the oldest monitor update waiting to be processed has been queued by monitor, zero when it has caught up.",
+			[]string{"monitor"}, nil),
+		pending: prometheus.NewDesc("ovsdb_pending_updates",
+			"Number of monitor updates waiting to be processed by monitor.",
+			[]string{"monitor"}, nil),
+		processors: make(map[string]*UpdateProcessor),
+	}
+}
+
+func (c *updateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.lag
+	ch <- c.pending
+}
+
+func (c *updateCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for monitor, p := range c.processors {
+		ch <- prometheus.MustNewConstMetric(c.lag, prometheus.GaugeValue, p.Lag().Seconds(), monitor)
+		ch <- prometheus.MustNewConstMetric(c.pending, prometheus.GaugeValue, float64(p.Pending()), monitor)
+	}
+}
+
+// instrumentTool wraps h to record each call in m. Calls that fail or
+// return an error result are counted as errors.
+func instrumentTool[In, Out any](m *serverMetrics, tool string, h mcpsdk.ToolHandlerFor[In, Out]) mcpsdk.ToolHandlerFor[In, Out] {
+	if m == nil {
+		return h
+	}
+	return func(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[In]) (*mcpsdk.CallToolResultFor[Out], error) {
+		start := time.Now()
+		res, err := h(ctx, ss, params)
+		status := callSuccess
+		if err != nil || (res != nil && res.IsError) {
+			status = callError
+		}
+		m.observeCall(tool, status, time.Since(start))
+		return res, err
+	}
+}
+
+// metricsHandler serves the metrics gathered from gatherers, or nil when
+// there are none
+func metricsHandler(gatherers ...prometheus.Gatherer) http.Handler {
+	var enabled prometheus.Gatherers
+	for _, g := range gatherers {
+		if g != nil {
+			enabled = append(enabled, g)
+		}
+	}
+	if len(enabled) == 0 {
+		return nil
+	}
+	return promhttp.HandlerFor(enabled, promhttp.HandlerOpts{})
+}
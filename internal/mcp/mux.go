@@ -0,0 +1,134 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MuxEntry is a server whose tools are served by a MuxServer, with the
+// prefix added to their names, e.g. nb for nb_list_logical_switches
+type MuxEntry struct {
+	Prefix string
+	Server *BaseServer
+}
+
+// MuxServer serves the tools of several servers from one MCP server, so a
+// client can use every database through one endpoint. The servers are not
+// started themselves, their tools are called through the MuxServer.
+type MuxServer struct {
+	*mcpsdk.Server
+	Logger     *slog.Logger
+	entries    []MuxEntry
+	httpServer *http.Server
+}
+
+// NewMuxServer creates an MCP server serving the tools of each entry's
+// server, prefixed with the entry's prefix and an underscore. The status
+// resource reports every server's databases.
+func NewMuxServer(impl *mcpsdk.Implementation, logger *slog.Logger, entries ...MuxEntry) (*MuxServer, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no servers to serve")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	m := &MuxServer{
+		Server:  mcpsdk.NewServer(impl, nil),
+		Logger:  logger.With("server", impl.Name),
+		entries: entries,
+	}
+
+	prefixes := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		if e.Prefix == "" {
+			return nil, fmt.Errorf("every server must have a prefix")
+		}
+		if prefixes[e.Prefix] {
+			return nil, fmt.Errorf("duplicate prefix %q", e.Prefix)
+		}
+		prefixes[e.Prefix] = true
+		for _, register := range e.Server.registrations {
+			register(m.Server, e.Prefix+"_")
+		}
+	}
+
+	m.AddResource(&mcpsdk.Resource{
+		URI:         StatusURI,
+		Name:        "status",
+		Title:       "Database status",
+		Description: "Whether the server can connect to each of its OVSDB databases, with the endpoint, the time taken to connect in milliseconds, the schema version and the error if it failed. Reading it makes a fresh connection attempt, so it can be used to check the databases before running tools.",
+		MIMEType:    "application/json",
+	}, m.ReadStatus)
+
+	return m, nil
+}
+
+// ReadStatus reads the status resource of every server
+func (m *MuxServer) ReadStatus(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.ReadResourceParams) (*mcpsdk.ReadResourceResult, error) {
+	result := &mcpsdk.ReadResourceResult{}
+	for _, e := range m.entries {
+		status, err := e.Server.ReadStatus(ctx, ss, params)
+		if err != nil {
+			return nil, err
+		}
+		result.Contents = append(result.Contents, status.Contents...)
+	}
+	return result, nil
+}
+
+// CheckHealth checks the databases of every server
+func (m *MuxServer) CheckHealth(ctx context.Context) HealthResult {
+	var statuses []DatabaseStatus
+	var checked time.Time
+	for _, e := range m.entries {
+		health := e.Server.CheckHealth(ctx)
+		statuses = append(statuses, health.Databases...)
+		// Report the oldest check, as that is when the result dates from
+		if checked.IsZero() || health.CheckedAt.Before(checked) {
+			checked = health.CheckedAt
+		}
+	}
+	return newHealthResult(checked, statuses)
+}
+
+// Start starts the MCP server on the specified address, and row history
+// recording and table caches for the servers they are enabled on. The
+// metrics of the servers that have them enabled are served together. The listener is bound before
+// Start returns so that bind errors are reported to the caller.
+func (m *MuxServer) Start(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	for _, e := range m.entries {
+		e.Server.startRecording()
+		e.Server.startCaching()
+	}
+	var gatherers []prometheus.Gatherer
+	for _, e := range m.entries {
+		gatherers = append(gatherers, e.Server.metrics.gatherer())
+	}
+	m.httpServer = serve(m.Logger, m.Server, m.CheckHealth, metricsHandler(gatherers...), listener, addr)
+
+	return nil
+}
+
+// Stop stops the MCP server
+func (m *MuxServer) Stop(ctx context.Context) error {
+	for _, e := range m.entries {
+		e.Server.stopRecording()
+		e.Server.stopCaching()
+	}
+	if m.httpServer != nil {
+		return m.httpServer.Shutdown(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// DefaultCallTimeout bounds each tool call unless overridden with
+// WithCallTimeout
+const DefaultCallTimeout = 30 * time.Second
+
+// Options holds the configuration shared by all of the MCP servers
+type Options struct {
+	Logger *slog.Logger
+	// Endpoint is the OVSDB endpoint, or the comma-separated endpoints of
+	// the servers of a clustered database
+	Endpoint string
+	// LeaderOnly only connects to the leader of a clustered database
+	LeaderOnly bool
+	// Targets are named endpoints tool calls select with their target
+	// argument, the first is used when a call doesn't name one
+	Targets []Target
+	// RowHistory is the number of row changes recorded per table for the
+	// row_history tool, history is not recorded when it is zero
+	RowHistory int
+	// TLSConfig is used to connect to ssl: endpoints. When it is nil it is
+	// loaded from CertFile, KeyFile and CAFile if they are set.
+	TLSConfig *tls.Config
+	CertFile  string
+	KeyFile   string
+	CAFile    string
+	// CallTimeout bounds each tool call, calls are not bounded when it is
+	// zero
+	CallTimeout time.Duration
+	// CacheTables are served to list tools from a monitored cache rather
+	// than selected from the database on every call
+	CacheTables []string
+	// Metrics enables the Prometheus metrics served on /metrics
+	Metrics bool
+}
+
+// Option configures an MCP server
+type Option func(*Options)
+
+// WithLogger sets the logger used for server and tool call logging
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *Options) {
+		o.Logger = logger
+	}
+}
+
+// WithEndpoint overrides the default OVSDB endpoint of the server
+func WithEndpoint(endpoint string) Option {
+	return func(o *Options) {
+		o.Endpoint = endpoint
+	}
+}
+
+// WithEndpoints overrides the default OVSDB endpoint of the server with the
+// endpoints of the servers of a clustered database. The first endpoint that
+// can be reached is connected to, so calls keep working while a server of
+// the cluster is down.
+func WithEndpoints(endpoints ...string) Option {
+	return func(o *Options) {
+		o.Endpoint = strings.Join(endpoints, ",")
+	}
+}
+
+// WithTarget adds a named endpoint, so one server can query the databases
+// of several deployments. Tools take a target argument naming the endpoint
+// to query, calls without one use the first target added. It can't be
+// combined with WithEndpoint, the first target replaces the server's
+// endpoint.
+func WithTarget(name, endpoint string) Option {
+	return func(o *Options) {
+		o.Targets = append(o.Targets, Target{Name: name, Endpoint: endpoint})
+	}
+}
+
+// WithLeaderOnly only connects to the endpoint that is the leader of a
+// clustered database, so reads are never stale and writes aren't forwarded.
+// Connections made after the leader changes connect to the new leader, and
+// the cache's connection reconnects to it.
+func WithLeaderOnly(leaderOnly bool) Option {
+	return func(o *Options) {
+		o.LeaderOnly = leaderOnly
+	}
+}
+
+// WithRowHistory records up to size changes per table while the server is
+// running, so row_history can return the recent changes to a row
+func WithRowHistory(size int) Option {
+	return func(o *Options) {
+		o.RowHistory = size
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used to connect to ssl: endpoints
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(o *Options) {
+		o.TLSConfig = cfg
+	}
+}
+
+// WithCertPaths connects to ssl: endpoints with the client certificate and
+// private key in certFile and keyFile, verifying the server's certificate
+// against the CA certificates in caFile. The system's CAs are used when
+// caFile is empty.
+func WithCertPaths(certFile, keyFile, caFile string) Option {
+	return func(o *Options) {
+		o.CertFile = certFile
+		o.KeyFile = keyFile
+		o.CAFile = caFile
+	}
+}
+
+// WithCallTimeout bounds each tool call to timeout, so a call to an
+// unresponsive database fails rather than blocking until the client gives
+// up. A timeout of zero disables it.
+func WithCallTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.CallTimeout = timeout
+	}
+}
+
+// WithCache monitors tables while the server is running and serves them to
+// the list tools from the client's cache, rather than selecting them on
+// every call. It suits tables that are queried often and change rarely,
+// such as bridges, logical switches and chassis. Tools select the tables
+// from the database until the monitor has received their contents, and
+// while its connection is lost. Empty table names are ignored.
+func WithCache(tables ...string) Option {
+	return func(o *Options) {
+		o.CacheTables = append(o.CacheTables, tables...)
+	}
+}
+
+// WithMetrics records the server's tool calls, their durations and failed
+// OVSDB connections as Prometheus metrics, served on /metrics
+func WithMetrics() Option {
+	return func(o *Options) {
+		o.Metrics = true
+	}
+}
+
+// NewOptions applies opts on top of the default options
+func NewOptions(opts ...Option) *Options {
+	o := &Options{CallTimeout: DefaultCallTimeout}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.Logger == nil {
+		o.Logger = slog.Default()
+	}
+	return o
+}
@@ -0,0 +1,763 @@
+package ovn
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type PodToPodReportArgs struct {
+	Source      string `json:"source" jsonschema:"the source pod as namespace/name, or the name of its logical switch port"`
+	Destination string `json:"destination" jsonschema:"the destination pod as namespace/name, or the name of its logical switch port"`
+}
+
+// Statuses of a report stage
+const (
+	stagePass    = "pass"
+	stageWarn    = "warn"
+	stageFail    = "fail"
+	stageSkipped = "skipped"
+)
+
+// reportStage is the verdict and findings of one step of a report
+type reportStage struct {
+	Stage    string      `json:"stage"`
+	Status   string      `json:"status"`
+	Findings []string    `json:"findings"`
+	Details  interface{} `json:"details,omitempty"`
+	failures []string
+	warnings []string
+}
+
+func newStage(name string) *reportStage {
+	return &reportStage{Stage: name, Status: stagePass, Findings: []string{}}
+}
+
+func (st *reportStage) info(format string, a ...interface{}) {
+	st.Findings = append(st.Findings, fmt.Sprintf(format, a...))
+}
+
+func (st *reportStage) warn(format string, a ...interface{}) {
+	st.info(format, a...)
+	st.warnings = append(st.warnings, st.Findings[len(st.Findings)-1])
+	if st.Status == stagePass {
+		st.Status = stageWarn
+	}
+}
+
+func (st *reportStage) fail(format string, a ...interface{}) {
+	st.info(format, a...)
+	st.failures = append(st.failures, st.Findings[len(st.Findings)-1])
+	st.Status = stageFail
+}
+
+func (st *reportStage) skip(reason string) {
+	st.Status = stageSkipped
+	st.Findings = []string{reason}
+}
+
+// nbTopology is an indexed snapshot of the parts of the NB database needed
+// to follow traffic between two logical switch ports
+type nbTopology struct {
+	ports          []ovnnb.LogicalSwitchPort
+	switchByPort   map[string]*ovnnb.LogicalSwitch
+	switches       map[string]*ovnnb.LogicalSwitch
+	routers        map[string]*ovnnb.LogicalRouter
+	routerByPort   map[string]*ovnnb.LogicalRouter
+	routerPorts    map[string]*ovnnb.LogicalRouterPort
+	routerPortName map[string]*ovnnb.LogicalRouterPort
+	portGroups     []ovnnb.PortGroup
+	acls           map[string]*ovnnb.ACL
+	policies       map[string]*ovnnb.LogicalRouterPolicy
+}
+
+func loadNBTopology(ctx context.Context, c client.Client) (*nbTopology, error) {
+	ports, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.LogicalSwitchPort{})
+	if err != nil {
+		return nil, err
+	}
+	switches, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.LogicalSwitch{})
+	if err != nil {
+		return nil, err
+	}
+	routers, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.LogicalRouter{})
+	if err != nil {
+		return nil, err
+	}
+	routerPorts, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.LogicalRouterPort{})
+	if err != nil {
+		return nil, err
+	}
+	portGroups, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.PortGroup{})
+	if err != nil {
+		return nil, err
+	}
+	acls, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.ACL{})
+	if err != nil {
+		return nil, err
+	}
+	policies, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.LogicalRouterPolicy{})
+	if err != nil {
+		return nil, err
+	}
+
+	t := &nbTopology{
+		ports:          ports,
+		switchByPort:   make(map[string]*ovnnb.LogicalSwitch),
+		switches:       make(map[string]*ovnnb.LogicalSwitch),
+		routers:        make(map[string]*ovnnb.LogicalRouter),
+		routerByPort:   make(map[string]*ovnnb.LogicalRouter),
+		routerPorts:    make(map[string]*ovnnb.LogicalRouterPort),
+		routerPortName: make(map[string]*ovnnb.LogicalRouterPort),
+		portGroups:     portGroups,
+		acls:           make(map[string]*ovnnb.ACL),
+		policies:       make(map[string]*ovnnb.LogicalRouterPolicy),
+	}
+	for i := range switches {
+		ls := &switches[i]
+		t.switches[ls.UUID] = ls
+		for _, p := range ls.Ports {
+			t.switchByPort[p] = ls
+		}
+	}
+	for i := range routers {
+		lr := &routers[i]
+		t.routers[lr.UUID] = lr
+		for _, p := range lr.Ports {
+			t.routerByPort[p] = lr
+		}
+	}
+	for i := range routerPorts {
+		lrp := &routerPorts[i]
+		t.routerPorts[lrp.UUID] = lrp
+		t.routerPortName[lrp.Name] = lrp
+	}
+	for i := range acls {
+		t.acls[acls[i].UUID] = &acls[i]
+	}
+	for i := range policies {
+		t.policies[policies[i].UUID] = &policies[i]
+	}
+	return t, nil
+}
+
+// resolvePort finds the logical switch port of a pod given as namespace/name,
+// following the ovn-kubernetes naming of namespace_name for the default
+// network and prefix_namespace_name for secondary networks. Any other
+// identifier is treated as a logical switch port name.
+func (t *nbTopology) resolvePort(id string) (*ovnnb.LogicalSwitchPort, error) {
+	namespace, name, isPod := strings.Cut(id, "/")
+	if !isPod {
+		for i := range t.ports {
+			if t.ports[i].Name == id {
+				return &t.ports[i], nil
+			}
+		}
+		return nil, fmt.Errorf("no logical switch port named %s", id)
+	}
+
+	portName := namespace + "_" + name
+	var candidates []*ovnnb.LogicalSwitchPort
+	for i := range t.ports {
+		lsp := &t.ports[i]
+		if lsp.Name == portName {
+			return lsp, nil
+		}
+		if strings.HasSuffix(lsp.Name, "_"+portName) && lsp.ExternalIDs["namespace"] == namespace {
+			candidates = append(candidates, lsp)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("no logical switch port found for pod %s, expected a port named %s", id, portName)
+	case 1:
+		return candidates[0], nil
+	}
+	var names []string
+	for _, c := range candidates {
+		names = append(names, c.Name)
+	}
+	return nil, fmt.Errorf("pod %s has a port on several networks, use one of the logical switch port names: %s", id, strings.Join(names, ", "))
+}
+
+// portIPs returns the IP addresses assigned to a logical switch port
+func portIPs(lsp *ovnnb.LogicalSwitchPort) []string {
+	var ips []string
+	for _, address := range lsp.Addresses {
+		if address == "dynamic" && lsp.DynamicAddresses != nil {
+			address = *lsp.DynamicAddresses
+		}
+		fields := strings.Fields(address)
+		if len(fields) > 1 {
+			ips = append(ips, fields[1:]...)
+		}
+	}
+	return ips
+}
+
+// pathHop is a logical switch or router on the path between two ports
+type pathHop struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	UUID string `json:"uuid"`
+	Via  string `json:"via,omitempty"`
+}
+
+type pathEdge struct {
+	to  string
+	via string
+}
+
+// logicalPath returns the shortest chain of switches and routers from one
+// switch to another, following router ports and router-to-router peers
+func (t *nbTopology) logicalPath(from, to *ovnnb.LogicalSwitch) []pathHop {
+	edges := make(map[string][]pathEdge)
+	link := func(a, b, via string) {
+		edges[a] = append(edges[a], pathEdge{to: b, via: via})
+		edges[b] = append(edges[b], pathEdge{to: a, via: via})
+	}
+	for _, lsp := range t.ports {
+		if lsp.Type != "router" {
+			continue
+		}
+		ls := t.switchByPort[lsp.UUID]
+		lrp := t.routerPortName[lsp.Options["router-port"]]
+		if ls == nil || lrp == nil {
+			continue
+		}
+		if lr := t.routerByPort[lrp.UUID]; lr != nil {
+			link("switch:"+ls.UUID, "router:"+lr.UUID, lrp.Name)
+		}
+	}
+	for _, lrp := range t.routerPorts {
+		if lrp.Peer == nil {
+			continue
+		}
+		peer := t.routerPortName[*lrp.Peer]
+		if peer == nil {
+			continue
+		}
+		a, b := t.routerByPort[lrp.UUID], t.routerByPort[peer.UUID]
+		if a != nil && b != nil {
+			link("router:"+a.UUID, "router:"+b.UUID, lrp.Name)
+		}
+	}
+
+	start, goal := "switch:"+from.UUID, "switch:"+to.UUID
+	parent := map[string]pathEdge{start: {}}
+	queue := []string{start}
+	for len(queue) > 0 && !hasKey(parent, goal) {
+		node := queue[0]
+		queue = queue[1:]
+		for _, e := range edges[node] {
+			if hasKey(parent, e.to) {
+				continue
+			}
+			parent[e.to] = pathEdge{to: node, via: e.via}
+			queue = append(queue, e.to)
+		}
+	}
+	if !hasKey(parent, goal) {
+		return nil
+	}
+
+	var path []pathHop
+	for node := goal; ; node = parent[node].to {
+		kind, uuid, _ := strings.Cut(node, ":")
+		hop := pathHop{Type: kind, UUID: uuid, Via: parent[node].via}
+		if kind == "switch" {
+			hop.Type = "logical_switch"
+			hop.Name = t.switches[uuid].Name
+		} else {
+			hop.Type = "logical_router"
+			hop.Name = t.routers[uuid].Name
+		}
+		path = append(path, hop)
+		if node == start {
+			break
+		}
+	}
+	slices.Reverse(path)
+	// Via is recorded on the hop reached through a port, shift it so
+	// each hop names the port it is left through
+	for i := 0; i < len(path)-1; i++ {
+		path[i].Via = path[i+1].Via
+	}
+	path[len(path)-1].Via = ""
+	return path
+}
+
+func hasKey[K comparable, V any](m map[K]V, k K) bool {
+	_, ok := m[k]
+	return ok
+}
+
+// appliedACL is an ACL that applies to one of the two ports
+type appliedACL struct {
+	UUID      string `json:"uuid"`
+	Name      string `json:"name,omitempty"`
+	Side      string `json:"side"`
+	AppliedTo string `json:"applied_to"`
+	Direction string `json:"direction"`
+	Priority  int    `json:"priority"`
+	Tier      int    `json:"tier"`
+	Action    string `json:"action"`
+	Match     string `json:"match"`
+}
+
+// aclsFor returns the ACLs in direction that apply to lsp, either through
+// its logical switch or through a port group it belongs to
+func (t *nbTopology) aclsFor(lsp *ovnnb.LogicalSwitchPort, side, direction string) []appliedACL {
+	var applied []appliedACL
+	add := func(uuids []string, appliedTo string) {
+		for _, uuid := range uuids {
+			acl := t.acls[uuid]
+			if acl == nil || acl.Direction != direction {
+				continue
+			}
+			a := appliedACL{
+				UUID:      acl.UUID,
+				Side:      side,
+				AppliedTo: appliedTo,
+				Direction: acl.Direction,
+				Priority:  acl.Priority,
+				Tier:      acl.Tier,
+				Action:    acl.Action,
+				Match:     acl.Match,
+			}
+			if acl.Name != nil {
+				a.Name = *acl.Name
+			}
+			applied = append(applied, a)
+		}
+	}
+	if ls := t.switchByPort[lsp.UUID]; ls != nil {
+		add(ls.ACLs, "logical_switch "+ls.Name)
+	}
+	for _, pg := range t.portGroups {
+		if slices.Contains(pg.Ports, lsp.UUID) {
+			add(pg.ACLs, "port_group "+pg.Name)
+		}
+	}
+	sort.Slice(applied, func(i, j int) bool {
+		if applied[i].Tier != applied[j].Tier {
+			return applied[i].Tier < applied[j].Tier
+		}
+		return applied[i].Priority > applied[j].Priority
+	})
+	return applied
+}
+
+func isBlocking(action string) bool {
+	return action == ovnnb.ACLActionDrop || action == ovnnb.ACLActionReject
+}
+
+// mentionsAny reports whether match refers to any of the addresses. An
+// address is only found as a whole token, so 10.0.0.1 is not mentioned by
+// a match on 10.0.0.10.
+func mentionsAny(match string, addresses []string) bool {
+	for _, address := range addresses {
+		if address != "" && mentions(match, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// mentions reports whether address appears in match between characters
+// that can't be part of an address
+func mentions(match, address string) bool {
+	for start := 0; ; {
+		i := strings.Index(match[start:], address)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(address)
+		if (i == 0 || !isAddressChar(match[i-1])) && (end == len(match) || !isAddressChar(match[end])) {
+			return true
+		}
+		start = i + 1
+	}
+}
+
+// isAddressChar reports whether c can be part of an IPv4 or IPv6 address,
+// or continue a longer name containing one
+func isAddressChar(c byte) bool {
+	return c == '.' || c == ':' || c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// checkACLs reports the blocking ACLs of one side. ACL matches are not
+// evaluated, an ACL is only treated as certain to drop the traffic when its
+// match is unconditional or names the peer's address.
+func checkACLs(st *reportStage, side string, acls []appliedACL, peerIPs []string) {
+	for _, acl := range acls {
+		if !isBlocking(acl.Action) {
+			continue
+		}
+		overridden := slices.ContainsFunc(acls, func(a appliedACL) bool {
+			return !isBlocking(a.Action) && a.Tier == acl.Tier && a.Priority > acl.Priority
+		})
+		switch {
+		case overridden:
+			st.info("%s ACL %s (priority %d, %s) may be overridden by a higher priority allow ACL: %s", side, acl.Action, acl.Priority, acl.AppliedTo, acl.Match)
+		case acl.Match == "1" || mentionsAny(acl.Match, peerIPs):
+			st.fail("%s ACL %s (priority %d, %s) drops this traffic: %s", side, acl.Action, acl.Priority, acl.AppliedTo, acl.Match)
+		default:
+			st.warn("%s ACL %s (priority %d, %s) drops traffic it matches and no higher priority ACL allows it: %s", side, acl.Action, acl.Priority, acl.AppliedTo, acl.Match)
+		}
+	}
+}
+
+// portBinding returns the SB port binding of a logical port, or nil
+func portBinding(ctx context.Context, c client.Client, logicalPort string) (*ovnsb.PortBinding, error) {
+	pb := &ovnsb.PortBinding{}
+	results, err := mcp.ExecuteSelectQuery(ctx, c, pb, model.Condition{
+		Field:    &pb.LogicalPort,
+		Function: ovsdb.ConditionEqual,
+		Value:    logicalPort,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+	return &results[0], nil
+}
+
+func (s *Server) PodToPodReport(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[PodToPodReportArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	if args.Source == "" || args.Destination == "" {
+		return nil, fmt.Errorf("source and destination are required")
+	}
+
+	nbClient, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer nbClient.Close()
+
+	sbClient, err := s.ConnectSB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer sbClient.Close()
+
+	topo, err := loadNBTopology(ctx, nbClient)
+	if err != nil {
+		return nil, err
+	}
+
+	resolve := newStage("resolve_ports")
+	portsStage := newStage("port_state")
+	pathStage := newStage("logical_path")
+	policyStage := newStage("acls_and_policies")
+	chassisStage := newStage("chassis")
+	tunnelStage := newStage("tunnel")
+	stages := []*reportStage{resolve, portsStage, pathStage, policyStage, chassisStage, tunnelStage}
+
+	src, srcErr := topo.resolvePort(args.Source)
+	if srcErr != nil {
+		resolve.fail("source: %v", srcErr)
+	}
+	dst, dstErr := topo.resolvePort(args.Destination)
+	if dstErr != nil {
+		resolve.fail("destination: %v", dstErr)
+	}
+	if resolve.Status == stageFail {
+		for _, st := range stages[1:] {
+			st.skip("the logical switch ports could not be resolved")
+		}
+		return reportResult(args, stages)
+	}
+
+	// Resolve ports
+	srcSwitch, dstSwitch := topo.switchByPort[src.UUID], topo.switchByPort[dst.UUID]
+	srcIPs, dstIPs := portIPs(src), portIPs(dst)
+	for _, p := range []struct {
+		side string
+		lsp  *ovnnb.LogicalSwitchPort
+		ls   *ovnnb.LogicalSwitch
+	}{{"source", src, srcSwitch}, {"destination", dst, dstSwitch}} {
+		if p.ls == nil {
+			resolve.fail("%s port %s is not attached to any logical switch", p.side, p.lsp.Name)
+			continue
+		}
+		resolve.info("%s port %s is on logical switch %s", p.side, p.lsp.Name, p.ls.Name)
+	}
+	resolve.Details = map[string]interface{}{
+		"source":      portSummary(src, srcSwitch),
+		"destination": portSummary(dst, dstSwitch),
+	}
+
+	// Port state
+	for _, p := range []struct {
+		side string
+		lsp  *ovnnb.LogicalSwitchPort
+		ips  []string
+	}{{"source", src, srcIPs}, {"destination", dst, dstIPs}} {
+		if p.lsp.Enabled != nil && !*p.lsp.Enabled {
+			portsStage.fail("%s port %s is administratively disabled", p.side, p.lsp.Name)
+		}
+		switch {
+		case p.lsp.Up == nil:
+			portsStage.warn("%s port %s has not reported its up state", p.side, p.lsp.Name)
+		case !*p.lsp.Up:
+			portsStage.fail("%s port %s is down, ovn-controller has not finished binding it", p.side, p.lsp.Name)
+		}
+		if len(p.ips) == 0 {
+			portsStage.warn("%s port %s has no IP address in its addresses column", p.side, p.lsp.Name)
+		}
+	}
+	if len(portsStage.Findings) == 0 {
+		portsStage.info("both ports are enabled and up")
+	}
+
+	// Logical path
+	var pathRouters []*ovnnb.LogicalRouter
+	switch {
+	case srcSwitch == nil || dstSwitch == nil:
+		pathStage.skip("one of the ports is not attached to a logical switch")
+	case srcSwitch.UUID == dstSwitch.UUID:
+		pathStage.info("both ports are on logical switch %s, traffic is switched at layer 2", srcSwitch.Name)
+		pathStage.Details = []pathHop{{Type: "logical_switch", Name: srcSwitch.Name, UUID: srcSwitch.UUID}}
+	default:
+		path := topo.logicalPath(srcSwitch, dstSwitch)
+		if path == nil {
+			pathStage.fail("no chain of logical routers connects logical switch %s to %s", srcSwitch.Name, dstSwitch.Name)
+			break
+		}
+		var names []string
+		for _, hop := range path {
+			names = append(names, hop.Name)
+			if hop.Type != "logical_router" {
+				continue
+			}
+			lr := topo.routers[hop.UUID]
+			pathRouters = append(pathRouters, lr)
+			if lr.Enabled != nil && !*lr.Enabled {
+				pathStage.fail("logical router %s is disabled", lr.Name)
+			}
+		}
+		for _, hop := range path {
+			if lrp := topo.routerPortName[hop.Via]; lrp != nil && lrp.Enabled != nil && !*lrp.Enabled {
+				pathStage.fail("logical router port %s is disabled", lrp.Name)
+			}
+		}
+		pathStage.info("traffic is routed through %s", strings.Join(names, " -> "))
+		pathStage.Details = path
+	}
+
+	// ACLs and router policies
+	srcACLs := topo.aclsFor(src, "source", ovnnb.ACLDirectionFromLport)
+	dstACLs := topo.aclsFor(dst, "destination", ovnnb.ACLDirectionToLport)
+	checkACLs(policyStage, "source", srcACLs, dstIPs)
+	checkACLs(policyStage, "destination", dstACLs, srcIPs)
+	var appliedPolicies []map[string]interface{}
+	for _, lr := range pathRouters {
+		for _, uuid := range lr.Policies {
+			policy := topo.policies[uuid]
+			if policy == nil {
+				continue
+			}
+			appliedPolicies = append(appliedPolicies, map[string]interface{}{
+				"router":   lr.Name,
+				"uuid":     policy.UUID,
+				"priority": policy.Priority,
+				"action":   policy.Action,
+				"match":    policy.Match,
+				"nexthops": policy.Nexthops,
+			})
+			relevant := mentionsAny(policy.Match, srcIPs) || mentionsAny(policy.Match, dstIPs)
+			switch {
+			case policy.Action == ovnnb.LogicalRouterPolicyActionDrop && relevant:
+				policyStage.fail("router %s policy (priority %d) drops this traffic: %s", lr.Name, policy.Priority, policy.Match)
+			case policy.Action == ovnnb.LogicalRouterPolicyActionDrop:
+				policyStage.warn("router %s policy (priority %d) drops traffic it matches: %s", lr.Name, policy.Priority, policy.Match)
+			case policy.Action == ovnnb.LogicalRouterPolicyActionReroute && relevant:
+				policyStage.info("router %s policy (priority %d) reroutes this traffic to %s: %s", lr.Name, policy.Priority, strings.Join(policy.Nexthops, ", "), policy.Match)
+			}
+		}
+	}
+	if len(policyStage.Findings) == 0 {
+		policyStage.info("no drop or reject ACLs or router policies apply")
+	}
+	policyStage.Details = map[string]interface{}{
+		"acls":            append(srcACLs, dstACLs...),
+		"router_policies": appliedPolicies,
+	}
+
+	// Chassis
+	chassisRows, err := mcp.ExecuteSelectQuery(ctx, sbClient, &ovnsb.Chassis{})
+	if err != nil {
+		return nil, err
+	}
+	chassisByUUID := make(map[string]*ovnsb.Chassis)
+	for i := range chassisRows {
+		chassisByUUID[chassisRows[i].UUID] = &chassisRows[i]
+	}
+
+	var bound [2]*ovnsb.Chassis
+	var bindings [2]*ovnsb.PortBinding
+	chassisDetails := map[string]interface{}{}
+	for i, p := range []struct {
+		side string
+		lsp  *ovnnb.LogicalSwitchPort
+	}{{"source", src}, {"destination", dst}} {
+		pb, err := portBinding(ctx, sbClient, p.lsp.Name)
+		if err != nil {
+			return nil, err
+		}
+		bindings[i] = pb
+		if pb == nil {
+			chassisStage.fail("%s port %s has no port binding in SB, ovn-northd has not processed it", p.side, p.lsp.Name)
+			continue
+		}
+		if pb.Chassis == nil {
+			chassisStage.fail("%s port %s is not bound to any chassis, no ovn-controller has claimed its interface", p.side, p.lsp.Name)
+			continue
+		}
+		ch := chassisByUUID[*pb.Chassis]
+		if ch == nil {
+			chassisStage.fail("%s port %s is bound to chassis %s which does not exist", p.side, p.lsp.Name, *pb.Chassis)
+			continue
+		}
+		bound[i] = ch
+		chassisStage.info("%s port %s is bound to chassis %s (%s)", p.side, p.lsp.Name, ch.Name, ch.Hostname)
+		if pb.RequestedChassis != nil && *pb.RequestedChassis != ch.UUID {
+			if requested := chassisByUUID[*pb.RequestedChassis]; requested != nil {
+				chassisStage.warn("%s port %s is requested on chassis %s but bound to %s", p.side, p.lsp.Name, requested.Name, ch.Name)
+			}
+		}
+		if pb.Up != nil && !*pb.Up {
+			chassisStage.warn("%s port binding %s is not up", p.side, p.lsp.Name)
+		}
+		chassisDetails[p.side] = map[string]interface{}{
+			"chassis":    ch.Name,
+			"hostname":   ch.Hostname,
+			"tunnel_key": pb.TunnelKey,
+			"datapath":   pb.Datapath,
+		}
+	}
+	chassisStage.Details = chassisDetails
+
+	// Tunnel
+	switch {
+	case bound[0] == nil || bound[1] == nil:
+		tunnelStage.skip("both ports must be bound to a chassis")
+	case bound[0].UUID == bound[1].UUID:
+		tunnelStage.info("both ports are on chassis %s, no tunnel is needed", bound[0].Name)
+	default:
+		encaps, err := mcp.ExecuteSelectQuery(ctx, sbClient, &ovnsb.Encap{})
+		if err != nil {
+			return nil, err
+		}
+		encapByUUID := make(map[string]*ovnsb.Encap)
+		for i := range encaps {
+			encapByUUID[encaps[i].UUID] = &encaps[i]
+		}
+		chassisEncaps := func(ch *ovnsb.Chassis, pb *ovnsb.PortBinding) []*ovnsb.Encap {
+			uuids := ch.Encaps
+			if pb.Encap != nil {
+				uuids = []string{*pb.Encap}
+			}
+			var result []*ovnsb.Encap
+			for _, uuid := range uuids {
+				if e := encapByUUID[uuid]; e != nil {
+					result = append(result, e)
+				}
+			}
+			return result
+		}
+		srcEncaps, dstEncaps := chassisEncaps(bound[0], bindings[0]), chassisEncaps(bound[1], bindings[1])
+		var tunnel map[string]interface{}
+		for _, se := range srcEncaps {
+			for _, de := range dstEncaps {
+				if se.Type == de.Type && tunnel == nil {
+					tunnel = map[string]interface{}{
+						"type":   se.Type,
+						"src_ip": se.IP,
+						"dst_ip": de.IP,
+					}
+				}
+			}
+		}
+		switch {
+		case len(srcEncaps) == 0 || len(dstEncaps) == 0:
+			tunnelStage.fail("chassis %s or %s has no tunnel encapsulation", bound[0].Name, bound[1].Name)
+		case tunnel == nil:
+			tunnelStage.fail("chassis %s and %s have no tunnel encapsulation type in common", bound[0].Name, bound[1].Name)
+		default:
+			tunnelStage.info("traffic is tunnelled over %s from %s on %s to %s on %s", tunnel["type"], tunnel["src_ip"], bound[0].Name, tunnel["dst_ip"], bound[1].Name)
+			tunnelStage.Details = tunnel
+		}
+		if bound[1].OtherConfig["is-remote"] == "true" {
+			tunnelStage.info("chassis %s is in a remote availability zone, traffic crosses the interconnect transit switch", bound[1].Name)
+		}
+	}
+
+	return reportResult(args, stages)
+}
+
+func portSummary(lsp *ovnnb.LogicalSwitchPort, ls *ovnnb.LogicalSwitch) map[string]interface{} {
+	summary := map[string]interface{}{
+		"logical_port": lsp.Name,
+		"uuid":         lsp.UUID,
+		"addresses":    lsp.Addresses,
+		"ips":          portIPs(lsp),
+		"external_ids": lsp.ExternalIDs,
+	}
+	if ls != nil {
+		summary["logical_switch"] = ls.Name
+	}
+	return summary
+}
+
+// reportResult concludes the report from its stages. Connectivity should
+// work when no stage failed, warnings are returned as caveats.
+func reportResult(args PodToPodReportArgs, stages []*reportStage) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	reasons := []string{}
+	caveats := []string{}
+	for _, st := range stages {
+		for _, finding := range st.failures {
+			reasons = append(reasons, st.Stage+": "+finding)
+		}
+		for _, finding := range st.warnings {
+			caveats = append(caveats, st.Stage+": "+finding)
+		}
+	}
+
+	shouldWork := len(reasons) == 0
+	conclusion := fmt.Sprintf("Connectivity from %s to %s should work.", args.Source, args.Destination)
+	switch {
+	case !shouldWork:
+		conclusion = fmt.Sprintf("Connectivity from %s to %s is not expected to work.", args.Source, args.Destination)
+	case len(caveats) > 0:
+		conclusion = fmt.Sprintf("Connectivity from %s to %s should work, subject to the caveats.", args.Source, args.Destination)
+	}
+
+	result := map[string]interface{}{
+		"source":      args.Source,
+		"destination": args.Destination,
+		"stages":      stages,
+		"should_work": shouldWork,
+		"conclusion":  conclusion,
+		"reasons":     reasons,
+		"caveats":     caveats,
+		"context":     "The report follows traffic from the source pod's logical switch port to the destination's: port state, the logical switches and routers between them, the from-lport ACLs of the source and to-lport ACLs of the destination, router policies on the path, the chassis each port is bound to and the tunnel between them. ACL and policy matches are not evaluated, so warnings need checking against the traffic in question.",
+	}
+
+	return mcp.NewResult(result)
+}
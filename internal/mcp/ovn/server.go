@@ -0,0 +1,95 @@
+package ovn
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/model"
+)
+
+const (
+	defaultNBEndpoint = "unix:/var/run/ovn/ovnnb_db.sock"
+	defaultSBEndpoint = "unix:/var/run/ovn/ovnsb_db.sock"
+)
+
+// Server is an MCP server for tools that correlate the OVN NB and SB
+// databases. The embedded BaseServer connects to the NB database.
+type Server struct {
+	*mcp.BaseServer
+	sbModel    model.ClientDBModel
+	sbEndpoint string
+}
+
+// ConnectSB returns a client connected to the OVN SB database.
+// The caller is responsible for closing the client.
+func (s *Server) ConnectSB(ctx context.Context) (client.Client, error) {
+	return s.ConnectTo(ctx, s.sbModel, s.sbEndpoint)
+}
+
+// NewServer creates a new OVN MCP server spanning the NB and SB databases.
+// The NB endpoint is overridden with mcp.WithEndpoint and the SB endpoint
+// with sbEndpoint, an empty sbEndpoint uses the default SB socket.
+func NewServer(host string, port int, sbEndpoint string, opts ...mcp.Option) (*Server, error) {
+
+	// Use the OVSDB client models shared by every server in the process
+	nbModel, err := ovnnb.DatabaseModel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create NB database model: %w", err)
+	}
+	sbModel, err := ovnsb.DatabaseModel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SB database model: %w", err)
+	}
+
+	if sbEndpoint == "" {
+		sbEndpoint = defaultSBEndpoint
+	}
+
+	base, err := mcp.NewBaseServer(&mcpsdk.Implementation{
+		Name:    "ovn-mcp",
+		Title:   "OVN MCP Server",
+		Version: "0.1.0",
+	}, nbModel, defaultNBEndpoint, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	s := Server{
+		BaseServer: base,
+		sbModel:    sbModel,
+		sbEndpoint: sbEndpoint,
+	}
+	s.AddStatusDatabase(sbModel, sbEndpoint)
+
+	// Register tools inline
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "pod_to_pod_report",
+		Description: "Diagnose connectivity between two pods using OVN NB and SB. Resolves each pod's logical switch port and reports on port state, the logical path between them, the ACLs and router policies that apply, the hosting chassis and the tunnel path, with a verdict per stage and an overall conclusion.",
+	}, s.PodToPodReport)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "trace_packet",
+		Description: "Trace a packet from a pod to a destination IP, with an optional protocol and port, across OVN NB and SB. Builds the packet from the source port's addresses, traces it through the SB logical flows of its switch and the routers and switches it reaches, and ties each flow to the NB ACL, load balancer or router policy it was generated for. Returns the ACLs, load balancer VIPs and port bindings involved, the verdict and an ordered explanation.",
+	}, s.TracePacket)
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "find_stale_chassis_refs",
+		Description: "Find references to a chassis across OVN NB and SB, grouped by database and table. Use after removing a node to find the Gateway_Chassis, HA_Chassis, port binding and requested-chassis references that still point at it.",
+	}, s.FindStaleChassisRefs)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "ping_database",
+		Description: "Check that the OVN NB and SB databases can be reached, with the connection latency and the schema version it serves. Use it before running other tools when the database may be down. Results are cached for a few seconds.",
+	}, s.PingDatabase)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "server_info",
+		Description: "Get the schema version of the OVN NB and SB databases and, from the ovsdb-server _Server database, whether it is standalone, clustered or a relay, whether the server is the cluster leader and connected to the cluster, and its cluster and server IDs. Use it to find the leader of a clustered database and the version to quote in bug reports.",
+	}, s.ServerInfo)
+
+	return &s, nil
+}
@@ -0,0 +1,292 @@
+package ovn
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type FindStaleChassisRefsArgs struct {
+	Chassis string `json:"chassis" jsonschema:"the name (system-id) of the removed chassis"`
+}
+
+// chassisRef is a row that refers to a chassis
+type chassisRef struct {
+	UUID   string `json:"uuid"`
+	Column string `json:"column"`
+	Owner  string `json:"owner,omitempty"`
+}
+
+// chassisRefs groups references to a chassis by table
+type chassisRefs map[string][]chassisRef
+
+func (r chassisRefs) add(table string, ref chassisRef) {
+	r[table] = append(r[table], ref)
+}
+
+// hasChassisOption reports whether a comma separated chassis list option,
+// such as requested-chassis, names any of names
+func hasChassisOption(options map[string]string, key string, names []string) bool {
+	value, ok := options[key]
+	if !ok {
+		return false
+	}
+	for _, v := range strings.Split(value, ",") {
+		if slices.Contains(names, strings.TrimSpace(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) FindStaleChassisRefs(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[FindStaleChassisRefsArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	if args.Chassis == "" {
+		return nil, fmt.Errorf("chassis is required")
+	}
+
+	nbClient, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer nbClient.Close()
+
+	sbClient, err := s.ConnectSB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer sbClient.Close()
+
+	// Options refer to a chassis by name or, for requested-chassis, by
+	// hostname, so include the hostname if the chassis is still in SB
+	chassisModel := &ovnsb.Chassis{}
+	chassis, err := mcp.ExecuteSelectQuery(ctx, sbClient, chassisModel, model.Condition{
+		Field:    &chassisModel.Name,
+		Function: ovsdb.ConditionEqual,
+		Value:    args.Chassis,
+	})
+	if err != nil {
+		return nil, err
+	}
+	names := []string{args.Chassis}
+	var chassisUUID string
+	if len(chassis) > 0 {
+		chassisUUID = chassis[0].UUID
+		if chassis[0].Hostname != "" && chassis[0].Hostname != args.Chassis {
+			names = append(names, chassis[0].Hostname)
+		}
+	}
+
+	nbRefs, err := findNBChassisRefs(ctx, nbClient, args.Chassis, names)
+	if err != nil {
+		return nil, err
+	}
+	sbRefs, err := findSBChassisRefs(ctx, sbClient, args.Chassis, chassisUUID, names)
+	if err != nil {
+		return nil, err
+	}
+
+	count := 0
+	for _, refs := range []chassisRefs{nbRefs, sbRefs} {
+		for _, r := range refs {
+			count += len(r)
+		}
+	}
+
+	result := map[string]interface{}{
+		"chassis":        args.Chassis,
+		"chassis_exists": len(chassis) > 0,
+		"nb":             nbRefs,
+		"sb":             sbRefs,
+		"count":          count,
+		"context":        "References to the chassis grouped by database and table. NB Gateway_Chassis and HA_Chassis rows keep scheduling gateway ports onto the chassis, and requested-chassis options pin ports to it, until they are removed. In SB, a Chassis row that still exists after the node is gone should be deleted (ovn-sbctl chassis-del), which also clears the port bindings and HA references that point at it.",
+	}
+
+	return mcp.NewResult(result)
+}
+
+func findNBChassisRefs(ctx context.Context, c client.Client, chassisName string, names []string) (chassisRefs, error) {
+	refs := chassisRefs{}
+
+	gatewayChassisModel := &ovnnb.GatewayChassis{}
+	gatewayChassis, err := mcp.ExecuteSelectQuery(ctx, c, gatewayChassisModel, model.Condition{
+		Field:    &gatewayChassisModel.ChassisName,
+		Function: ovsdb.ConditionEqual,
+		Value:    chassisName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(gatewayChassis) > 0 {
+		routerPorts, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.LogicalRouterPort{})
+		if err != nil {
+			return nil, err
+		}
+		for _, gc := range gatewayChassis {
+			ref := chassisRef{UUID: gc.UUID, Column: "chassis_name"}
+			for _, lrp := range routerPorts {
+				if slices.Contains(lrp.GatewayChassis, gc.UUID) {
+					ref.Owner = "Logical_Router_Port " + lrp.Name
+				}
+			}
+			refs.add(ovnnb.GatewayChassisTable, ref)
+		}
+	}
+
+	haChassisModel := &ovnnb.HAChassis{}
+	haChassis, err := mcp.ExecuteSelectQuery(ctx, c, haChassisModel, model.Condition{
+		Field:    &haChassisModel.ChassisName,
+		Function: ovsdb.ConditionEqual,
+		Value:    chassisName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(haChassis) > 0 {
+		groups, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.HAChassisGroup{})
+		if err != nil {
+			return nil, err
+		}
+		for _, hc := range haChassis {
+			ref := chassisRef{UUID: hc.UUID, Column: "chassis_name"}
+			for _, group := range groups {
+				if slices.Contains(group.HaChassis, hc.UUID) {
+					ref.Owner = "HA_Chassis_Group " + group.Name
+				}
+			}
+			refs.add(ovnnb.HAChassisTable, ref)
+		}
+	}
+
+	ports, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.LogicalSwitchPort{})
+	if err != nil {
+		return nil, err
+	}
+	for _, lsp := range ports {
+		if hasChassisOption(lsp.Options, "requested-chassis", names) {
+			refs.add(ovnnb.LogicalSwitchPortTable, chassisRef{UUID: lsp.UUID, Column: "options:requested-chassis", Owner: lsp.Name})
+		}
+	}
+
+	routers, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.LogicalRouter{})
+	if err != nil {
+		return nil, err
+	}
+	for _, lr := range routers {
+		// Gateway routers are bound to a chassis with options:chassis
+		if hasChassisOption(lr.Options, "chassis", names) {
+			refs.add(ovnnb.LogicalRouterTable, chassisRef{UUID: lr.UUID, Column: "options:chassis", Owner: lr.Name})
+		}
+	}
+
+	return refs, nil
+}
+
+func findSBChassisRefs(ctx context.Context, c client.Client, chassisName, chassisUUID string, names []string) (chassisRefs, error) {
+	refs := chassisRefs{}
+
+	if chassisUUID != "" {
+		refs.add(ovnsb.ChassisTable, chassisRef{UUID: chassisUUID, Column: "name"})
+	}
+
+	privateModel := &ovnsb.ChassisPrivate{}
+	private, err := mcp.ExecuteSelectQuery(ctx, c, privateModel, model.Condition{
+		Field:    &privateModel.Name,
+		Function: ovsdb.ConditionEqual,
+		Value:    chassisName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, cp := range private {
+		refs.add(ovnsb.ChassisPrivateTable, chassisRef{UUID: cp.UUID, Column: "name"})
+	}
+
+	encapModel := &ovnsb.Encap{}
+	encaps, err := mcp.ExecuteSelectQuery(ctx, c, encapModel, model.Condition{
+		Field:    &encapModel.ChassisName,
+		Function: ovsdb.ConditionEqual,
+		Value:    chassisName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range encaps {
+		refs.add(ovnsb.EncapTable, chassisRef{UUID: e.UUID, Column: "chassis_name", Owner: fmt.Sprintf("%s %s", e.Type, e.IP)})
+	}
+
+	bindings, err := mcp.ExecuteSelectQuery(ctx, c, &ovnsb.PortBinding{})
+	if err != nil {
+		return nil, err
+	}
+	isChassis := func(uuid *string) bool {
+		return chassisUUID != "" && uuid != nil && *uuid == chassisUUID
+	}
+	for _, pb := range bindings {
+		var columns []string
+		if isChassis(pb.Chassis) {
+			columns = append(columns, "chassis")
+		}
+		if isChassis(pb.RequestedChassis) {
+			columns = append(columns, "requested_chassis")
+		}
+		if chassisUUID != "" && slices.Contains(pb.AdditionalChassis, chassisUUID) {
+			columns = append(columns, "additional_chassis")
+		}
+		if chassisUUID != "" && slices.Contains(pb.RequestedAdditionalChassis, chassisUUID) {
+			columns = append(columns, "requested_additional_chassis")
+		}
+		if hasChassisOption(pb.Options, "requested-chassis", names) {
+			columns = append(columns, "options:requested-chassis")
+		}
+		for _, column := range columns {
+			refs.add(ovnsb.PortBindingTable, chassisRef{UUID: pb.UUID, Column: column, Owner: pb.LogicalPort})
+		}
+	}
+
+	if chassisUUID == "" {
+		return refs, nil
+	}
+
+	gatewayChassis, err := mcp.ExecuteSelectQuery(ctx, c, &ovnsb.GatewayChassis{})
+	if err != nil {
+		return nil, err
+	}
+	for _, gc := range gatewayChassis {
+		if isChassis(gc.Chassis) {
+			refs.add(ovnsb.GatewayChassisTable, chassisRef{UUID: gc.UUID, Column: "chassis", Owner: gc.Name})
+		}
+	}
+
+	haChassis, err := mcp.ExecuteSelectQuery(ctx, c, &ovnsb.HAChassis{})
+	if err != nil {
+		return nil, err
+	}
+	for _, hc := range haChassis {
+		if isChassis(hc.Chassis) {
+			refs.add(ovnsb.HAChassisTable, chassisRef{UUID: hc.UUID, Column: "chassis"})
+		}
+	}
+
+	groups, err := mcp.ExecuteSelectQuery(ctx, c, &ovnsb.HAChassisGroup{})
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		if slices.Contains(group.RefChassis, chassisUUID) {
+			refs.add(ovnsb.HAChassisGroupTable, chassisRef{UUID: group.UUID, Column: "ref_chassis", Owner: group.Name})
+		}
+	}
+
+	return refs, nil
+}
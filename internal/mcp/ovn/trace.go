@@ -0,0 +1,470 @@
+package ovn
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	ovnsbmcp "github.com/dave-tucker/ariadne/internal/mcp/ovnsb"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type TracePacketArgs struct {
+	Source          string `json:"source" jsonschema:"the source pod as namespace/name, or the name of its logical switch port"`
+	DestinationIP   string `json:"destination_ip" jsonschema:"the IP address the packet is sent to, a pod, a load balancer VIP or an external address"`
+	Protocol        string `json:"protocol,omitempty" jsonschema:"tcp, udp, sctp or icmp, any IP packet when not set"`
+	DestinationPort int    `json:"destination_port,omitempty" jsonschema:"the TCP, UDP or SCTP port the packet is sent to"`
+}
+
+// nbRow is the northbound row a logical flow was generated for, found from
+// the stage-hint northd sets on the flow
+type nbRow struct {
+	Table   string `json:"table"`
+	UUID    string `json:"uuid"`
+	Name    string `json:"name,omitempty"`
+	Summary string `json:"summary"`
+}
+
+// tracedFlow is a logical flow the packet matched, with the northbound row
+// it implements
+type tracedFlow struct {
+	ovnsbmcp.TraceStep
+	NBRow *nbRow `json:"nb_row,omitempty"`
+}
+
+// vipMatch is a load balancer VIP the packet is sent to
+type vipMatch struct {
+	UUID       string   `json:"uuid"`
+	Name       string   `json:"name"`
+	VIP        string   `json:"vip"`
+	Backends   []string `json:"backends"`
+	Protocol   string   `json:"protocol,omitempty"`
+	AppliedTo  []string `json:"applied_to"`
+	OnSwitch   bool     `json:"on_source_switch"`
+	protocolOK bool
+}
+
+// splitHostPort splits a VIP or backend into its IP and optional port,
+// which IPv6 addresses put in brackets
+func splitHostPort(address string) (string, string) {
+	if host, port, err := net.SplitHostPort(address); err == nil {
+		return host, port
+	}
+	return strings.Trim(address, "[]"), ""
+}
+
+// portMAC returns the MAC address of a logical switch port, or ""
+func portMAC(lsp *ovnnb.LogicalSwitchPort) string {
+	for _, address := range lsp.Addresses {
+		if address == "dynamic" && lsp.DynamicAddresses != nil {
+			address = *lsp.DynamicAddresses
+		}
+		fields := strings.Fields(address)
+		if len(fields) > 0 {
+			if _, err := net.ParseMAC(fields[0]); err == nil {
+				return fields[0]
+			}
+		}
+	}
+	return ""
+}
+
+// sameFamily returns the first of ips in the family of ip
+func sameFamily(ips []string, ip net.IP) string {
+	for _, candidate := range ips {
+		if parsed := net.ParseIP(candidate); parsed != nil && (parsed.To4() != nil) == (ip.To4() != nil) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// gatewayMAC returns the MAC address of the router port attached to the
+// switch, which packets leaving the switch are sent to
+func (t *nbTopology) gatewayMAC(ls *ovnnb.LogicalSwitch) (string, string) {
+	for _, lsp := range t.ports {
+		if lsp.Type != "router" || t.switchByPort[lsp.UUID] != ls {
+			continue
+		}
+		if lrp := t.routerPortName[lsp.Options["router-port"]]; lrp != nil {
+			return lrp.MAC, lrp.Name
+		}
+	}
+	return "", ""
+}
+
+// loadBalancerVIPs returns the load balancer VIPs matching the destination,
+// with the switches and routers they are applied to
+func loadBalancerVIPs(topo *nbTopology, lbs []ovnnb.LoadBalancer, groups []ovnnb.LoadBalancerGroup, srcSwitch *ovnnb.LogicalSwitch, ip, port, protocol string) []vipMatch {
+	groupLBs := make(map[string][]string, len(groups))
+	for _, g := range groups {
+		groupLBs[g.UUID] = g.LoadBalancer
+	}
+	appliedTo := make(map[string][]string)
+	onSwitch := make(map[string]bool)
+	apply := func(name string, lbs, lbGroups []string, isSource bool) {
+		for _, group := range lbGroups {
+			lbs = append(lbs, groupLBs[group]...)
+		}
+		for _, lb := range lbs {
+			appliedTo[lb] = append(appliedTo[lb], name)
+			onSwitch[lb] = onSwitch[lb] || isSource
+		}
+	}
+	for _, ls := range topo.switches {
+		apply("logical_switch "+ls.Name, ls.LoadBalancer, ls.LoadBalancerGroup, ls == srcSwitch)
+	}
+	for _, lr := range topo.routers {
+		apply("logical_router "+lr.Name, lr.LoadBalancer, lr.LoadBalancerGroup, false)
+	}
+
+	var matches []vipMatch
+	for _, lb := range lbs {
+		lbProtocol := ovnnb.LoadBalancerProtocolTCP
+		if lb.Protocol != nil {
+			lbProtocol = *lb.Protocol
+		}
+		for vip, backends := range lb.Vips {
+			vipIP, vipPort := splitHostPort(vip)
+			if vipIP != ip || port != "" && vipPort != "" && vipPort != port {
+				continue
+			}
+			applied := appliedTo[lb.UUID]
+			sort.Strings(applied)
+			m := vipMatch{
+				UUID:       lb.UUID,
+				Name:       lb.Name,
+				VIP:        vip,
+				Backends:   strings.Split(backends, ","),
+				AppliedTo:  applied,
+				OnSwitch:   onSwitch[lb.UUID],
+				protocolOK: vipPort == "" || protocol == "" || protocol == lbProtocol,
+			}
+			if vipPort != "" {
+				m.Protocol = lbProtocol
+			}
+			matches = append(matches, m)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Name != matches[j].Name {
+			return matches[i].Name < matches[j].Name
+		}
+		return matches[i].VIP < matches[j].VIP
+	})
+	return matches
+}
+
+// nbRows indexes the northbound rows that logical flows refer to in their
+// stage-hint, the first 8 hex digits of the row's UUID
+func nbRows(topo *nbTopology, lbs []ovnnb.LoadBalancer) map[string]*nbRow {
+	rows := make(map[string]*nbRow)
+	add := func(row *nbRow) {
+		if len(row.UUID) >= 8 {
+			rows[row.UUID[:8]] = row
+		}
+	}
+	for _, acl := range topo.acls {
+		row := &nbRow{Table: ovnnb.ACLTable, UUID: acl.UUID, Summary: fmt.Sprintf("%s %s priority %d: %s", acl.Direction, acl.Action, acl.Priority, acl.Match)}
+		if acl.Name != nil {
+			row.Name = *acl.Name
+		}
+		add(row)
+	}
+	for _, policy := range topo.policies {
+		add(&nbRow{Table: ovnnb.LogicalRouterPolicyTable, UUID: policy.UUID, Summary: fmt.Sprintf("%s priority %d: %s", policy.Action, policy.Priority, policy.Match)})
+	}
+	for _, lb := range lbs {
+		add(&nbRow{Table: ovnnb.LoadBalancerTable, UUID: lb.UUID, Name: lb.Name, Summary: fmt.Sprintf("%d VIPs", len(lb.Vips))})
+	}
+	for _, lsp := range topo.ports {
+		add(&nbRow{Table: ovnnb.LogicalSwitchPortTable, UUID: lsp.UUID, Name: lsp.Name, Summary: "logical switch port " + lsp.Name})
+	}
+	for _, lrp := range topo.routerPorts {
+		add(&nbRow{Table: ovnnb.LogicalRouterPortTable, UUID: lrp.UUID, Name: lrp.Name, Summary: "logical router port " + lrp.Name})
+	}
+	return rows
+}
+
+// boundTo describes the chassis a logical port is bound to
+func boundTo(pb *ovnsb.PortBinding, chassis map[string]*ovnsb.Chassis) string {
+	switch {
+	case pb == nil:
+		return "has no port binding in SB"
+	case pb.Chassis == nil:
+		return "is not bound to any chassis"
+	case chassis[*pb.Chassis] == nil:
+		return fmt.Sprintf("is bound to chassis %s which does not exist", *pb.Chassis)
+	default:
+		ch := chassis[*pb.Chassis]
+		return fmt.Sprintf("is bound to chassis %s (%s)", ch.Name, ch.Hostname)
+	}
+}
+
+func (s *Server) TracePacket(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[TracePacketArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	if args.Source == "" {
+		return nil, fmt.Errorf("source must not be empty")
+	}
+	dstIP := net.ParseIP(args.DestinationIP)
+	if dstIP == nil {
+		return nil, fmt.Errorf("invalid destination_ip %q", args.DestinationIP)
+	}
+	switch args.Protocol {
+	case "", "tcp", "udp", "sctp", "icmp":
+	default:
+		return nil, fmt.Errorf("invalid protocol %q, must be one of tcp, udp, sctp or icmp", args.Protocol)
+	}
+	if args.DestinationPort != 0 {
+		if args.Protocol != "tcp" && args.Protocol != "udp" && args.Protocol != "sctp" {
+			return nil, fmt.Errorf("destination_port needs protocol tcp, udp or sctp")
+		}
+		if args.DestinationPort < 1 || args.DestinationPort > 65535 {
+			return nil, fmt.Errorf("invalid destination_port %d, must be from 1 to 65535", args.DestinationPort)
+		}
+	}
+	ip := dstIP.String()
+	port := ""
+	if args.DestinationPort != 0 {
+		port = strconv.Itoa(args.DestinationPort)
+	}
+
+	nbClient, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer nbClient.Close()
+
+	sbClient, err := s.ConnectSB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer sbClient.Close()
+
+	topo, err := loadNBTopology(ctx, nbClient)
+	if err != nil {
+		return nil, err
+	}
+	lbs, err := mcp.ExecuteSelectQuery(ctx, nbClient, &ovnnb.LoadBalancer{})
+	if err != nil {
+		return nil, err
+	}
+	lbGroups, err := mcp.ExecuteSelectQuery(ctx, nbClient, &ovnnb.LoadBalancerGroup{})
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := topo.resolvePort(args.Source)
+	if err != nil {
+		return &mcpsdk.CallToolResultFor[map[string]any]{
+			IsError: true,
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{Text: err.Error()},
+			},
+		}, nil
+	}
+	srcSwitch := topo.switchByPort[src.UUID]
+	if srcSwitch == nil {
+		return nil, fmt.Errorf("source port %s is not attached to any logical switch", src.Name)
+	}
+
+	chassisRows, err := mcp.ExecuteSelectQuery(ctx, sbClient, &ovnsb.Chassis{})
+	if err != nil {
+		return nil, err
+	}
+	chassisByUUID := make(map[string]*ovnsb.Chassis, len(chassisRows))
+	for i := range chassisRows {
+		chassisByUUID[chassisRows[i].UUID] = &chassisRows[i]
+	}
+
+	explanation, caveats := []string{}, []string{}
+
+	// Source
+	srcIP, srcMAC := sameFamily(portIPs(src), dstIP), portMAC(src)
+	srcBinding, err := portBinding(ctx, sbClient, src.Name)
+	if err != nil {
+		return nil, err
+	}
+	source := portSummary(src, srcSwitch)
+	explanation = append(explanation, fmt.Sprintf("The packet leaves port %s (%s) on logical switch %s, which %s.", src.Name, strings.Join(portIPs(src), ", "), srcSwitch.Name, boundTo(srcBinding, chassisByUUID)))
+	if srcIP == "" {
+		caveats = append(caveats, fmt.Sprintf("source port %s has no address in the family of %s, the packet is traced with a zero source IP", src.Name, ip))
+	}
+
+	// Destination
+	destination := map[string]interface{}{"ip": ip}
+	if port != "" {
+		destination["port"] = args.DestinationPort
+	}
+	var dst *ovnnb.LogicalSwitchPort
+	for i := range topo.ports {
+		if topo.ports[i].Type != "" {
+			continue
+		}
+		for _, candidate := range portIPs(&topo.ports[i]) {
+			if net.ParseIP(candidate).Equal(dstIP) {
+				dst = &topo.ports[i]
+			}
+		}
+	}
+	vips := loadBalancerVIPs(topo, lbs, lbGroups, srcSwitch, ip, port, args.Protocol)
+	var dstSwitch *ovnnb.LogicalSwitch
+	switch {
+	case dst != nil:
+		dstSwitch = topo.switchByPort[dst.UUID]
+		dstBinding, err := portBinding(ctx, sbClient, dst.Name)
+		if err != nil {
+			return nil, err
+		}
+		destination["type"] = "logical_switch_port"
+		destination["port_details"] = portSummary(dst, dstSwitch)
+		switchName := ""
+		if dstSwitch != nil {
+			switchName = dstSwitch.Name
+		}
+		explanation = append(explanation, fmt.Sprintf("%s belongs to port %s on logical switch %s, which %s.", ip, dst.Name, switchName, boundTo(dstBinding, chassisByUUID)))
+		if dstSwitch != nil && dstSwitch != srcSwitch {
+			if path := topo.logicalPath(srcSwitch, dstSwitch); path != nil {
+				var names []string
+				for _, hop := range path {
+					names = append(names, hop.Name)
+				}
+				destination["logical_path"] = path
+				explanation = append(explanation, fmt.Sprintf("The packet is routed through %s.", strings.Join(names, " -> ")))
+			} else {
+				explanation = append(explanation, fmt.Sprintf("No chain of logical routers connects logical switch %s to %s.", srcSwitch.Name, dstSwitch.Name))
+			}
+		}
+	case len(vips) > 0:
+		destination["type"] = "load_balancer_vip"
+	default:
+		destination["type"] = "external"
+		explanation = append(explanation, fmt.Sprintf("No logical switch port or load balancer VIP has %s, so the packet is routed out of the cluster if a router on its path has a route for it.", ip))
+	}
+	for _, vip := range vips {
+		switch {
+		case !vip.protocolOK:
+			explanation = append(explanation, fmt.Sprintf("Load balancer %s has VIP %s for %s only, so it doesn't apply to %s.", vip.Name, vip.VIP, vip.Protocol, args.Protocol))
+		case len(vip.AppliedTo) == 0:
+			explanation = append(explanation, fmt.Sprintf("Load balancer %s has VIP %s but is not applied to any switch or router, so it has no effect.", vip.Name, vip.VIP))
+		default:
+			explanation = append(explanation, fmt.Sprintf("Load balancer %s (applied to %s) sends VIP %s to one of %s.", vip.Name, strings.Join(vip.AppliedTo, ", "), vip.VIP, strings.Join(vip.Backends, ", ")))
+			if !vip.OnSwitch {
+				caveats = append(caveats, fmt.Sprintf("load balancer %s is not applied to the source's logical switch %s, so the packet is only load balanced by a router on its path", vip.Name, srcSwitch.Name))
+			}
+		}
+	}
+
+	// ACLs, evaluated against the destination address only
+	acls := newStage("acls")
+	srcACLs := topo.aclsFor(src, "source", ovnnb.ACLDirectionFromLport)
+	checkACLs(acls, "source", srcACLs, []string{ip})
+	applied := srcACLs
+	if dst != nil {
+		dstACLs := topo.aclsFor(dst, "destination", ovnnb.ACLDirectionToLport)
+		srcIPs := []string{}
+		if srcIP != "" {
+			srcIPs = append(srcIPs, srcIP)
+		}
+		checkACLs(acls, "destination", dstACLs, srcIPs)
+		applied = append(applied, dstACLs...)
+	}
+	for _, finding := range acls.Findings {
+		explanation = append(explanation, strings.ToUpper(finding[:1])+finding[1:]+".")
+	}
+
+	// The logical flows of the source's switch, and of the datapaths the
+	// packet reaches through patch ports
+	ethDst, via := srcMAC, ""
+	switch {
+	case dst != nil && dstSwitch == srcSwitch:
+		ethDst = portMAC(dst)
+	default:
+		ethDst, via = topo.gatewayMAC(srcSwitch)
+		if ethDst == "" {
+			caveats = append(caveats, fmt.Sprintf("logical switch %s has no router port, the packet is traced with a zero destination MAC", srcSwitch.Name))
+		}
+	}
+	terms := []string{}
+	if srcMAC != "" {
+		terms = append(terms, "eth.src == "+srcMAC)
+	}
+	if ethDst != "" {
+		terms = append(terms, "eth.dst == "+ethDst)
+	}
+	family := "ip4"
+	if dstIP.To4() == nil {
+		family = "ip6"
+	}
+	terms = append(terms, family)
+	if srcIP != "" {
+		terms = append(terms, family+".src == "+srcIP)
+	}
+	terms = append(terms, family+".dst == "+ip)
+	switch args.Protocol {
+	case "icmp":
+		terms = append(terms, "icmp"+strings.TrimPrefix(family, "ip"))
+	case "":
+	default:
+		terms = append(terms, args.Protocol)
+		if port != "" {
+			terms = append(terms, args.Protocol+".dst == "+port)
+		}
+	}
+	flow := strings.Join(terms, " && ")
+
+	result := map[string]interface{}{
+		"source":         source,
+		"destination":    destination,
+		"load_balancers": vips,
+		"acls":           applied,
+		"microflow":      flow,
+	}
+	trace, err := ovnsbmcp.Trace(ctx, sbClient, srcSwitch.Name, src.Name, flow)
+	if err != nil {
+		return nil, err
+	}
+	if trace == nil {
+		caveats = append(caveats, fmt.Sprintf("logical switch %s has no datapath in SB, ovn-northd has not processed it", srcSwitch.Name))
+	} else {
+		if via != "" {
+			explanation = append(explanation, fmt.Sprintf("The packet is addressed to router port %s, the gateway of logical switch %s.", via, srcSwitch.Name))
+		}
+		rows := nbRows(topo, lbs)
+		flows := make([]tracedFlow, 0, len(trace.Steps))
+		for _, step := range trace.Steps {
+			flow := tracedFlow{TraceStep: step}
+			if step.StageHint != "" {
+				flow.NBRow = rows[step.StageHint]
+			}
+			flows = append(flows, flow)
+			if flow.NBRow != nil && (flow.NBRow.Table != ovnnb.LogicalSwitchPortTable && flow.NBRow.Table != ovnnb.LogicalRouterPortTable) {
+				explanation = append(explanation, fmt.Sprintf("In %s %s table %d (%s), the packet matches the flow for %s %s: %s.", step.Datapath, step.Pipeline, step.Table, step.Stage, flow.NBRow.Table, flow.NBRow.UUID, flow.NBRow.Summary))
+			}
+			caveats = append(caveats, step.Notes...)
+			if len(step.Uncertain) > 0 {
+				caveats = append(caveats, fmt.Sprintf("%s %s table %d has higher priority flows that could not be evaluated", step.Datapath, step.Pipeline, step.Table))
+			}
+		}
+		explanation = append(explanation, fmt.Sprintf("The logical flows give the verdict %s: %s.", trace.Verdict, trace.Reason))
+		result["logical_flows"] = flows
+		result["verdict"] = trace.Verdict
+		result["reason"] = trace.Reason
+		if trace.Outport != "" {
+			result["outport"] = trace.Outport
+		}
+	}
+
+	result["explanation"] = explanation
+	result["caveats"] = caveats
+	result["context"] = "The packet is built from the source port's MAC and IP addresses and traced through the southbound logical flows of its switch with the built-in tracer, following patch ports into routers and other switches. Flows generated for northbound ACLs, load balancers and router policies are tied to their rows through the flow's stage-hint, in nb_row. The ACLs and load balancer VIPs that apply are listed from the northbound database. Connection tracking is not simulated, so load balancer VIPs are not translated to a backend in the trace."
+
+	return mcp.NewResult(result)
+}
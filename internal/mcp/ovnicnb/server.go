@@ -2,14 +2,11 @@ package ovnicnb
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
 
 	"github.com/dave-tucker/ariadne/internal/mcp"
 	"github.com/dave-tucker/ariadne/internal/schema/ovnicnb"
 	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
-	"github.com/ovn-kubernetes/libovsdb/client"
 	"github.com/ovn-kubernetes/libovsdb/model"
 	"github.com/ovn-kubernetes/libovsdb/ovsdb"
 )
@@ -17,254 +14,283 @@ import (
 const defaultEndpoint = "unix:/var/run/ovn/ovn_ic_nb_db.sock"
 
 type Server struct {
-	*mcpsdk.Server
-	dbModel    model.ClientDBModel
-	httpServer *http.Server
+	*mcp.BaseServer
 }
 
 type ListTransitSwitchesArgs struct {
 	NameFilter string `json:"name_filter" jsonschema:"the name of the transit switch to filter by"`
+	MatchMode  string `json:"match,omitempty" jsonschema:"how name_filter is matched: exact (default), prefix, substring or regex, prefix and substring matches are case-insensitive"`
+	CountOnly  bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset     int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListICNBGlobalsArgs struct {
+	CountOnly bool `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit     int  `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset    int  `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListConnectionsArgs struct {
+	CountOnly bool `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit     int  `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset    int  `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListSSLConfigsArgs struct {
+	CountOnly bool `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit     int  `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset    int  `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
-func (s *Server) ListTransitSwitches(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListTransitSwitchesArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListTransitSwitches(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListTransitSwitchesArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	nameFilter := args.NameFilter
+	matcher, err := mcp.NewNameMatcher(args.NameFilter, args.MatchMode)
+	if err != nil {
+		return nil, err
+	}
+
+	transitSwitch := &ovnicnb.TransitSwitch{}
 	var conditions []model.Condition
-	if nameFilter != "" {
+	if matcher.Exact() {
 		conditions = append(conditions, model.Condition{
-			Field:    &(&ovnicnb.TransitSwitch{}).Name,
+			Field:    &transitSwitch.Name,
 			Function: ovsdb.ConditionEqual,
-			Value:    nameFilter,
+			Value:    args.NameFilter,
 		})
 	}
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
-	}
-
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnicnb.TransitSwitch{}, conditions...)
+	results, err := mcp.ExecuteSelectQuery(ctx, client, transitSwitch, conditions...)
 	if err != nil {
 		return nil, err
 	}
+	results = mcp.FilterByName(results, matcher, func(r ovnicnb.TransitSwitch) string { return r.Name })
 
-	result := map[string]interface{}{
-		"transit_switches": results,
-		"count":            len(results),
-		"context":          "Transit switches are logical switches that connect different availability zones in OVN Interconnection.",
+	if args.CountOnly {
+		return mcp.CountResult(len(results))
 	}
 
-	json, err := json.Marshal(result)
+	results, page := mcp.Paginate(results, args.Limit, args.Offset)
+
+	rows, err := mcp.MapRows(ovnicnb.TransitSwitchTable, ovnicnb.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	result := mcp.ListResult{
+		Data:       map[string]any{"transit_switches": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Transit switches are logical switches that connect different availability zones in OVN Interconnection.",
+	}
+
+	return mcp.NewResult(result)
 }
 
-func (s *Server) ListICNBGlobals(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListICNBGlobalsArgs]) (*mcpsdk.CallToolResult, error) {
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+func (s *Server) ListICNBGlobals(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListICNBGlobalsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+	if args.CountOnly {
+		count, err := mcp.CountRows(ctx, client, &ovnicnb.ICNBGlobal{})
+		if err != nil {
+			return nil, err
+		}
+		return mcp.CountResult(count)
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnicnb.ICNBGlobal{})
+	results, page, err := mcp.ExecuteSelectQueryPaged(ctx, client, &ovnicnb.ICNBGlobal{}, args.Limit, args.Offset)
 	if err != nil {
 		return nil, err
 	}
 
-	result := map[string]interface{}{
-		"ic_nb_globals": results,
-		"count":         len(results),
-		"context":       "IC NB Globals contain global configuration settings for OVN Interconnection Northbound database.",
-	}
-
-	json, err := json.Marshal(result)
+	rows, err := mcp.MapRows(ovnicnb.ICNBGlobalTable, ovnicnb.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	result := mcp.ListResult{
+		Data:       map[string]any{"ic_nb_globals": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "IC NB Globals contain global configuration settings for OVN Interconnection Northbound database.",
+	}
+
+	return mcp.NewResult(result)
 }
 
-func (s *Server) ListConnections(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListConnectionsArgs]) (*mcpsdk.CallToolResult, error) {
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+func (s *Server) ListConnections(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListConnectionsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+	if args.CountOnly {
+		count, err := mcp.CountRows(ctx, client, &ovnicnb.Connection{})
+		if err != nil {
+			return nil, err
+		}
+		return mcp.CountResult(count)
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnicnb.Connection{})
+	results, page, err := mcp.ExecuteSelectQueryPaged(ctx, client, &ovnicnb.Connection{}, args.Limit, args.Offset)
 	if err != nil {
 		return nil, err
 	}
 
-	result := map[string]interface{}{
-		"connections": results,
-		"count":       len(results),
-		"context":     "Connections define the network connections between different availability zones in OVN Interconnection.",
-	}
-
-	json, err := json.Marshal(result)
+	rows, err := mcp.MapRows(ovnicnb.ConnectionTable, ovnicnb.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	result := mcp.ListResult{
+		Data:       map[string]any{"connections": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Connections define the network connections between different availability zones in OVN Interconnection.",
+	}
+
+	return mcp.NewResult(result)
 }
 
-func (s *Server) ListSSLConfigs(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListSSLConfigsArgs]) (*mcpsdk.CallToolResult, error) {
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+func (s *Server) ListSSLConfigs(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListSSLConfigsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+	if args.CountOnly {
+		count, err := mcp.CountRows(ctx, client, &ovnicnb.SSL{})
+		if err != nil {
+			return nil, err
+		}
+		return mcp.CountResult(count)
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnicnb.SSL{})
+	results, page, err := mcp.ExecuteSelectQueryPaged(ctx, client, &ovnicnb.SSL{}, args.Limit, args.Offset)
 	if err != nil {
 		return nil, err
 	}
 
-	result := map[string]interface{}{
-		"ssl_configs": results,
-		"count":       len(results),
-		"context":     "SSL configurations define TLS settings for secure connections in OVN Interconnection.",
-	}
-
-	json, err := json.Marshal(result)
+	rows, err := mcp.MapRows(ovnicnb.SSLTable, ovnicnb.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	result := mcp.ListResult{
+		Data:       map[string]any{"ssl_configs": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "SSL configurations define TLS settings for secure connections in OVN Interconnection.",
+	}
+
+	return mcp.NewResult(result)
 }
 
 // NewServer creates a new OVN IC NB MCP server
-func NewServer(host string, port int) (*Server, error) {
+func NewServer(host string, port int, opts ...mcp.Option) (*Server, error) {
 
-	// Create OVSDB client model using generated code
-	dbModel, err := ovnicnb.FullDatabaseModel()
+	// Use the OVSDB client model shared by every server in the process
+	dbModel, err := ovnicnb.DatabaseModel()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create database model: %w", err)
 	}
 
-	server := mcpsdk.NewServer(&mcpsdk.Implementation{
+	base, err := mcp.NewBaseServer(&mcpsdk.Implementation{
 		Name:    "ovn-ic-nb-mcp",
 		Title:   "OVN IC NB MCP Server",
 		Version: "0.1.0",
-	}, nil)
+	}, dbModel, defaultEndpoint, opts...)
+	if err != nil {
+		return nil, err
+	}
 
 	s := Server{
-		Server:  server,
-		dbModel: dbModel,
+		BaseServer: base,
 	}
 
 	// Register tools inline
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_transit_switches",
-		Description: "List all transit switches in OVN IC NB database. Transit switches connect different availability zones.",
+		Description: "List all transit switches in OVN IC NB database. Transit switches connect different availability zones. Set count_only to only get the number of matching rows.",
 	}, s.ListTransitSwitches)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_ic_nb_globals",
-		Description: "List all IC NB globals in OVN IC NB database. IC NB globals contain global configuration settings.",
+		Description: "List all IC NB globals in OVN IC NB database. IC NB globals contain global configuration settings. Set count_only to only get the number of matching rows.",
 	}, s.ListICNBGlobals)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_connections",
-		Description: "List all connections in OVN IC NB database. Connections define network links between availability zones.",
+		Description: "List all connections in OVN IC NB database. Connections define network links between availability zones. Set count_only to only get the number of matching rows.",
 	}, s.ListConnections)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_ssl_configs",
-		Description: "List all SSL configurations in OVN IC NB database. SSL configs define TLS settings for secure connections.",
+		Description: "List all SSL configurations in OVN IC NB database. SSL configs define TLS settings for secure connections. Set count_only to only get the number of matching rows.",
 	}, s.ListSSLConfigs)
 
-	return &s, nil
-}
-
-// Start starts the MCP server on the specified address
-func (s *Server) Start(ctx context.Context, addr string) error {
-	// Create HTTP server using Streamable HTTP handler
-	streamableHandler := mcpsdk.NewStreamableHTTPHandler(func(request *http.Request) *mcpsdk.Server {
-		return s.Server
-	}, nil)
-
-	s.httpServer = &http.Server{
-		Addr:    addr,
-		Handler: streamableHandler,
-	}
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "ovsdb_select",
+		Description: "Run a select on any table of the OVN IC NB database with [column, function, value] conditions, which rows must all match unless match_all is false, optionally returning only some columns. An escape hatch for querying columns the other tools don't filter on; use get_schema to find the tables and columns. Unknown tables and columns are rejected with the valid names.",
+	}, s.OVSDBSelect)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "get_schema",
+		Description: "Get the schema of the OVN IC NB database: its tables, and each table's columns with their types, whether they are sets, maps or references to other tables, and the values allowed for enums. Optionally describe a single table. Useful for finding which columns exist before filtering on them. Needs no database connection.",
+	}, mcp.GetSchema(ovnicnb.DatabaseSchema()))
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "describe_schema",
+		Description: "List the tables of the OVN IC NB database with the type of each of their columns, as compact JSON keyed by table. A quick overview of what can be queried before choosing a tool; use get_schema for the details of a table. Needs no database connection.",
+	}, mcp.DescribeSchema(ovnicnb.DatabaseSchema()))
+
+	mcp.AddLongRunningTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "watch_table",
+		Description: "Watch one table of the OVN IC NB database (e.g. Transit_Switch) for a while and report its row inserts, updates and deletes as they happen. Each change is streamed to the client as a progress notification when the call has a progress token, and all of them are returned when the watch ends after duration_seconds (30 by default, at most 300) or max_events changes. Optionally only watch rows whose name matches a filter. The monitor is removed when the watch ends or the session closes.",
+	}, s.WatchTable)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "find_by_external_id_key",
+		Description: "Find the rows of every table in the OVN IC NB database that have an external_ids key, whatever its value, grouped by table. Optionally only match rows whose value for the key contains a substring. Useful for finding every object owned or labelled by a controller, e.g. by the k8s.ovn.org/owner key.",
+	}, s.FindByExternalIDKey)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "row_history",
+		Description: "Get the current value of any row in the OVN IC NB database by UUID, with its recent changes as timestamped before and after snapshots when the server is recording row history. OVSDB does not keep history itself, so only changes made since the server started recording are available.",
+	}, s.RowHistory)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "ping_database",
+		Description: "Check that the OVN IC NB database can be reached, with the connection latency and the schema version it serves. Use it before running other tools when the database may be down. Results are cached for a few seconds.",
+	}, s.PingDatabase)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "server_info",
+		Description: "Get the schema version of the OVN IC NB database and, from the ovsdb-server _Server database, whether it is standalone, clustered or a relay, whether the server is the cluster leader and connected to the cluster, and its cluster and server IDs. Use it to find the leader of a clustered database and the version to quote in bug reports.",
+	}, s.ServerInfo)
 
-	// Start server in a goroutine
-	go func() {
-		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			// Log error if we had a logger
-		}
-	}()
-
-	return nil
-}
-
-// Stop stops the MCP server
-func (s *Server) Stop(ctx context.Context) error {
-	if s.httpServer != nil {
-		return s.httpServer.Shutdown(ctx)
-	}
-	return nil
+	return &s, nil
 }
@@ -2,644 +2,444 @@ package ovnicsb
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
+	"slices"
 
 	"github.com/dave-tucker/ariadne/internal/mcp"
 	"github.com/dave-tucker/ariadne/internal/schema/ovnicsb"
 	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
-	"github.com/ovn-kubernetes/libovsdb/client"
 	"github.com/ovn-kubernetes/libovsdb/model"
 	"github.com/ovn-kubernetes/libovsdb/ovsdb"
 )
 
-const defaultEndpoint = "unix:/var/run/ovn/ovn_ic_nb_db.sock"
+const defaultEndpoint = "unix:/var/run/ovn/ovn_ic_sb_db.sock"
 
 type Server struct {
-	*mcpsdk.Server
-	dbModel    model.ClientDBModel
-	httpServer *http.Server
+	*mcp.BaseServer
 }
 
 type ListAvailabilityZonesArgs struct {
 	NameFilter string `json:"name_filter" jsonschema:"the name of the availability zone to filter by"`
+	MatchMode  string `json:"match,omitempty" jsonschema:"how name_filter is matched: exact (default), prefix, substring or regex, prefix and substring matches are case-insensitive"`
+	CountOnly  bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset     int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListDatapathBindingsArgs struct {
-	ZoneFilter string `json:"zone_filter" jsonschema:"the name of the availability zone to filter by"`
+	ZoneFilter string `json:"zone_filter" jsonschema:"only return the datapaths of transit switches with ports in the availability zone with this name"`
+	CountOnly  bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset     int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListPortBindingsArgs struct {
-	DatapathFilter string `json:"datapath_filter" jsonschema:"the name of the datapath to filter by"`
+	DatapathFilter string `json:"datapath_filter" jsonschema:"the name of the transit switch of the datapath to filter by"`
+	CountOnly      bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit          int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset         int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListGatewaysArgs struct {
 	ZoneFilter string `json:"zone_filter" jsonschema:"the name of the availability zone to filter by"`
+	CountOnly  bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset     int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListRoutesArgs struct {
-	GatewayFilter string `json:"gateway_filter" jsonschema:"the name of the gateway to filter by"`
+	GatewayFilter string `json:"gateway_filter" jsonschema:"only return the routes of the availability zone of the gateway with this name"`
+	CountOnly     bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit         int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset        int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListEncapsArgs struct {
 	GatewayFilter string `json:"gateway_filter" jsonschema:"the name of the gateway to filter by"`
+	CountOnly     bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit         int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset        int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListICSBGlobalsArgs struct {
+	CountOnly bool `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit     int  `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset    int  `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
-func (s *Server) ListAvailabilityZones(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListAvailabilityZonesArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListAvailabilityZones(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListAvailabilityZonesArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	nameFilter := args.NameFilter
+	matcher, err := mcp.NewNameMatcher(args.NameFilter, args.MatchMode)
+	if err != nil {
+		return nil, err
+	}
+
+	availabilityZone := &ovnicsb.AvailabilityZone{}
 	var conditions []model.Condition
-	if nameFilter != "" {
+	if matcher.Exact() {
 		conditions = append(conditions, model.Condition{
-			Field:    &(&ovnicsb.AvailabilityZone{}).Name,
+			Field:    &availabilityZone.Name,
 			Function: ovsdb.ConditionEqual,
-			Value:    nameFilter,
+			Value:    args.NameFilter,
 		})
 	}
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
-	}
-
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnicsb.AvailabilityZone{}, conditions...)
+	results, err := mcp.ExecuteSelectQuery(ctx, client, availabilityZone, conditions...)
 	if err != nil {
 		return nil, err
 	}
+	results = mcp.FilterByName(results, matcher, func(r ovnicsb.AvailabilityZone) string { return r.Name })
 
-	result := map[string]interface{}{
-		"availability_zones": results,
-		"count":              len(results),
-		"context":            "Availability zones represent different geographical or logical regions in OVN Interconnection.",
+	if args.CountOnly {
+		return mcp.CountResult(len(results))
 	}
 
-	json, err := json.Marshal(result)
+	results, page := mcp.Paginate(results, args.Limit, args.Offset)
+
+	rows, err := mcp.MapRows(ovnicsb.AvailabilityZoneTable, ovnicsb.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	result := mcp.ListResult{
+		Data:       map[string]any{"availability_zones": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Availability zones represent different geographical or logical regions in OVN Interconnection.",
+	}
+
+	return mcp.NewResult(result)
 }
 
-func (s *Server) ListDatapathBindings(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListDatapathBindingsArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListDatapathBindings(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListDatapathBindingsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
-	}
-
-	zoneFilter := args.ZoneFilter
-	var conditions []model.Condition
-	if zoneFilter != "" {
-		// First, get the availability zone UUID
-		var zones []ovnicsb.AvailabilityZone
-		zoneCondition := model.Condition{
-			Field:    &(&ovnicsb.AvailabilityZone{}).Name,
-			Function: ovsdb.ConditionEqual,
-			Value:    zoneFilter,
-		}
-		zoneSelectOps, zoneQueryID, zoneSelectErr := client.WhereAll(&ovnicsb.AvailabilityZone{}, zoneCondition).Select()
-		if zoneSelectErr != nil {
-			return nil, fmt.Errorf("failed to create availability zone select operation: %w", zoneSelectErr)
-		}
-
-		zoneReply, err := client.Transact(ctx, zoneSelectOps...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute availability zone transaction: %w", err)
-		}
-
-		err = client.GetSelectResults(zoneSelectOps, zoneReply, map[string]interface{}{zoneQueryID: &zones})
+	query := mcp.ListQuery[ovnicsb.AvailabilityZone, ovnicsb.DatapathBinding]{
+		Model:     &ovnicsb.DatapathBinding{},
+		Table:     ovnicsb.DatapathBindingTable,
+		Schema:    ovnicsb.DatabaseSchema(),
+		Key:       "datapath_bindings",
+		Context:   "Datapath bindings represent the physical or virtual switches that implement transit switches in OVN Interconnection.",
+		Limit:     args.Limit,
+		Offset:    args.Offset,
+		CountOnly: args.CountOnly,
+	}
+	if args.ZoneFilter != "" {
+		// Datapaths don't belong to a zone, they are the transit switches
+		// that the zone's port bindings are attached to
+		portBindings, err := mcp.ExecuteSelectQuery(ctx, client, &ovnicsb.PortBinding{})
 		if err != nil {
-			return nil, fmt.Errorf("failed to get availability zone select results: %w", err)
+			return nil, err
 		}
 
-		if len(zones) == 0 {
-			result := map[string]interface{}{
-				"datapath_bindings": []ovnicsb.DatapathBinding{},
-				"count":             0,
-				"context":           "No availability zone found with the specified filter.",
-			}
-			json, err := json.Marshal(result)
-			if err != nil {
-				return nil, err
-			}
-			return &mcpsdk.CallToolResult{
-				Content: []mcpsdk.Content{
-					&mcpsdk.TextContent{
-						Text: string(json),
-					},
-				},
-			}, nil
+		availabilityZone := &ovnicsb.AvailabilityZone{}
+		query.Parent = &mcp.ParentFilter[ovnicsb.AvailabilityZone, ovnicsb.DatapathBinding]{
+			Model: availabilityZone,
+			Field: &availabilityZone.Name,
+			Value: args.ZoneFilter,
+			Kind:  "availability zone",
+			Keep: func(az ovnicsb.AvailabilityZone, dp ovnicsb.DatapathBinding) bool {
+				return slices.ContainsFunc(portBindings, func(pb ovnicsb.PortBinding) bool {
+					return pb.AvailabilityZone == az.UUID && pb.TransitSwitch == dp.TransitSwitch
+				})
+			},
 		}
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnicsb.DatapathBinding{}, conditions...)
-	if err != nil {
-		return nil, err
-	}
-
-	result := map[string]interface{}{
-		"datapath_bindings": results,
-		"count":             len(results),
-		"context":           "Datapath bindings represent the physical or virtual switches that implement transit switches in OVN Interconnection.",
-	}
-
-	json, err := json.Marshal(result)
-	if err != nil {
-		return nil, err
-	}
-
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	return mcp.ListWithParentFilter(ctx, client, query)
 }
 
-func (s *Server) ListPortBindings(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListPortBindingsArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListPortBindings(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListPortBindingsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
-	}
-
-	datapathFilter := args.DatapathFilter
-	var conditions []model.Condition
-	if datapathFilter != "" {
-		// First, get the datapath UUID
-		var datapaths []ovnicsb.DatapathBinding
-		datapathCondition := model.Condition{
-			Field:    &(&ovnicsb.DatapathBinding{}).ExternalIDs,
-			Function: ovsdb.ConditionEqual,
-			Value:    map[string]string{"name": datapathFilter},
-		}
-		datapathSelectOps, datapathQueryID, datapathSelectErr := client.WhereAll(&ovnicsb.DatapathBinding{}, datapathCondition).Select()
-		if datapathSelectErr != nil {
-			return nil, fmt.Errorf("failed to create datapath select operation: %w", datapathSelectErr)
-		}
-
-		datapathReply, err := client.Transact(ctx, datapathSelectOps...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute datapath transaction: %w", err)
-		}
-
-		err = client.GetSelectResults(datapathSelectOps, datapathReply, map[string]interface{}{datapathQueryID: &datapaths})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get datapath select results: %w", err)
-		}
-
-		if len(datapaths) == 0 {
-			result := map[string]interface{}{
-				"port_bindings": []ovnicsb.PortBinding{},
-				"count":         0,
-				"context":       "No datapath found with the specified filter.",
-			}
-			json, err := json.Marshal(result)
-			if err != nil {
-				return nil, err
-			}
-			return &mcpsdk.CallToolResult{
-				Content: []mcpsdk.Content{
-					&mcpsdk.TextContent{
-						Text: string(json),
-					},
-				},
-			}, nil
+	query := mcp.ListQuery[ovnicsb.DatapathBinding, ovnicsb.PortBinding]{
+		Model:     &ovnicsb.PortBinding{},
+		Table:     ovnicsb.PortBindingTable,
+		Schema:    ovnicsb.DatabaseSchema(),
+		Key:       "port_bindings",
+		Context:   "Port bindings map logical ports to physical ports on datapaths in OVN Interconnection.",
+		Limit:     args.Limit,
+		Offset:    args.Offset,
+		CountOnly: args.CountOnly,
+	}
+	if args.DatapathFilter != "" {
+		datapathBinding := &ovnicsb.DatapathBinding{}
+		query.Parent = &mcp.ParentFilter[ovnicsb.DatapathBinding, ovnicsb.PortBinding]{
+			Model: datapathBinding,
+			Field: &datapathBinding.TransitSwitch,
+			Value: args.DatapathFilter,
+			Kind:  "datapath",
+			Keep: func(dp ovnicsb.DatapathBinding, pb ovnicsb.PortBinding) bool {
+				return pb.TransitSwitch == dp.TransitSwitch
+			},
 		}
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnicsb.PortBinding{}, conditions...)
-	if err != nil {
-		return nil, err
-	}
-
-	result := map[string]interface{}{
-		"port_bindings": results,
-		"count":         len(results),
-		"context":       "Port bindings map logical ports to physical ports on datapaths in OVN Interconnection.",
-	}
-
-	json, err := json.Marshal(result)
-	if err != nil {
-		return nil, err
-	}
-
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	return mcp.ListWithParentFilter(ctx, client, query)
 }
 
-func (s *Server) ListGateways(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListGatewaysArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListGateways(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListGatewaysArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
-	}
-
-	zoneFilter := args.ZoneFilter
-	var conditions []model.Condition
-	if zoneFilter != "" {
-		// First, get the availability zone UUID
-		var zones []ovnicsb.AvailabilityZone
-		zoneCondition := model.Condition{
-			Field:    &(&ovnicsb.AvailabilityZone{}).Name,
-			Function: ovsdb.ConditionEqual,
-			Value:    zoneFilter,
-		}
-		zoneSelectOps, zoneQueryID, zoneSelectErr := client.WhereAll(&ovnicsb.AvailabilityZone{}, zoneCondition).Select()
-		if zoneSelectErr != nil {
-			return nil, fmt.Errorf("failed to create availability zone select operation: %w", zoneSelectErr)
-		}
-
-		zoneReply, err := client.Transact(ctx, zoneSelectOps...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute availability zone transaction: %w", err)
-		}
-
-		err = client.GetSelectResults(zoneSelectOps, zoneReply, map[string]interface{}{zoneQueryID: &zones})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get availability zone select results: %w", err)
-		}
-
-		if len(zones) == 0 {
-			result := map[string]interface{}{
-				"gateways": []ovnicsb.Gateway{},
-				"count":    0,
-				"context":  "No availability zone found with the specified filter.",
-			}
-			json, err := json.Marshal(result)
-			if err != nil {
-				return nil, err
-			}
-			return &mcpsdk.CallToolResult{
-				Content: []mcpsdk.Content{
-					&mcpsdk.TextContent{
-						Text: string(json),
-					},
-				},
-			}, nil
+	query := mcp.ListQuery[ovnicsb.AvailabilityZone, ovnicsb.Gateway]{
+		Model:     &ovnicsb.Gateway{},
+		Table:     ovnicsb.GatewayTable,
+		Schema:    ovnicsb.DatabaseSchema(),
+		Key:       "gateways",
+		Context:   "Gateways provide routing and connectivity between availability zones in OVN Interconnection.",
+		Limit:     args.Limit,
+		Offset:    args.Offset,
+		CountOnly: args.CountOnly,
+	}
+	if args.ZoneFilter != "" {
+		availabilityZone := &ovnicsb.AvailabilityZone{}
+		query.Parent = &mcp.ParentFilter[ovnicsb.AvailabilityZone, ovnicsb.Gateway]{
+			Model: availabilityZone,
+			Field: &availabilityZone.Name,
+			Value: args.ZoneFilter,
+			Kind:  "availability zone",
+			Keep: func(az ovnicsb.AvailabilityZone, gw ovnicsb.Gateway) bool {
+				return gw.AvailabilityZone == az.UUID
+			},
 		}
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnicsb.Gateway{}, conditions...)
-	if err != nil {
-		return nil, err
-	}
-
-	result := map[string]interface{}{
-		"gateways": results,
-		"count":    len(results),
-		"context":  "Gateways provide routing and connectivity between availability zones in OVN Interconnection.",
-	}
-
-	json, err := json.Marshal(result)
-	if err != nil {
-		return nil, err
-	}
-
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	return mcp.ListWithParentFilter(ctx, client, query)
 }
 
-func (s *Server) ListRoutes(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListRoutesArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListRoutes(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListRoutesArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
-	}
-
-	gatewayFilter := args.GatewayFilter
-	var conditions []model.Condition
-	if gatewayFilter != "" {
-		// First, get the gateway UUID
-		var gateways []ovnicsb.Gateway
-		gatewayCondition := model.Condition{
-			Field:    &(&ovnicsb.Gateway{}).Name,
-			Function: ovsdb.ConditionEqual,
-			Value:    gatewayFilter,
-		}
-		gatewaySelectOps, gatewayQueryID, gatewaySelectErr := client.WhereAll(&ovnicsb.Gateway{}, gatewayCondition).Select()
-		if gatewaySelectErr != nil {
-			return nil, fmt.Errorf("failed to create gateway select operation: %w", gatewaySelectErr)
-		}
-
-		gatewayReply, err := client.Transact(ctx, gatewaySelectOps...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute gateway transaction: %w", err)
-		}
-
-		err = client.GetSelectResults(gatewaySelectOps, gatewayReply, map[string]interface{}{gatewayQueryID: &gateways})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get gateway select results: %w", err)
-		}
-
-		if len(gateways) == 0 {
-			result := map[string]interface{}{
-				"routes":  []ovnicsb.Route{},
-				"count":   0,
-				"context": "No gateway found with the specified filter.",
-			}
-			json, err := json.Marshal(result)
-			if err != nil {
-				return nil, err
-			}
-			return &mcpsdk.CallToolResult{
-				Content: []mcpsdk.Content{
-					&mcpsdk.TextContent{
-						Text: string(json),
-					},
-				},
-			}, nil
+	query := mcp.ListQuery[ovnicsb.Gateway, ovnicsb.Route]{
+		Model:     &ovnicsb.Route{},
+		Table:     ovnicsb.RouteTable,
+		Schema:    ovnicsb.DatabaseSchema(),
+		Key:       "routes",
+		Context:   "Routes define the network paths between availability zones in OVN Interconnection.",
+		Limit:     args.Limit,
+		Offset:    args.Offset,
+		CountOnly: args.CountOnly,
+	}
+	if args.GatewayFilter != "" {
+		gateway := &ovnicsb.Gateway{}
+		query.Parent = &mcp.ParentFilter[ovnicsb.Gateway, ovnicsb.Route]{
+			Model: gateway,
+			Field: &gateway.Name,
+			Value: args.GatewayFilter,
+			Kind:  "gateway",
+			// Routes are advertised by availability zones, so keep the routes of the gateway's zone
+			Keep: func(gw ovnicsb.Gateway, route ovnicsb.Route) bool {
+				return route.AvailabilityZone == gw.AvailabilityZone
+			},
 		}
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnicsb.Route{}, conditions...)
-	if err != nil {
-		return nil, err
-	}
+	return mcp.ListWithParentFilter(ctx, client, query)
+}
 
-	result := map[string]interface{}{
-		"routes":  results,
-		"count":   len(results),
-		"context": "Routes define the network paths between availability zones in OVN Interconnection.",
-	}
+func (s *Server) ListEncaps(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListEncapsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
 
-	json, err := json.Marshal(result)
+	client, err := s.Connect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer client.Close()
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
+	query := mcp.ListQuery[ovnicsb.Gateway, ovnicsb.Encap]{
+		Model:     &ovnicsb.Encap{},
+		Table:     ovnicsb.EncapTable,
+		Schema:    ovnicsb.DatabaseSchema(),
+		Key:       "encaps",
+		Context:   "Encapsulations define the tunneling protocols used to connect gateways in OVN Interconnection.",
+		Limit:     args.Limit,
+		Offset:    args.Offset,
+		CountOnly: args.CountOnly,
+	}
+	if args.GatewayFilter != "" {
+		gateway := &ovnicsb.Gateway{}
+		query.Parent = &mcp.ParentFilter[ovnicsb.Gateway, ovnicsb.Encap]{
+			Model: gateway,
+			Field: &gateway.Name,
+			Value: args.GatewayFilter,
+			Kind:  "gateway",
+			// Only keep the encaps referenced by the gateway's encaps column
+			Keep: func(gw ovnicsb.Gateway, encap ovnicsb.Encap) bool {
+				return slices.Contains(gw.Encaps, encap.UUID)
 			},
-		},
-	}, nil
+		}
+	}
+
+	return mcp.ListWithParentFilter(ctx, client, query)
 }
 
-func (s *Server) ListEncaps(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListEncapsArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListICSBGlobals(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListICSBGlobalsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
-	}
-
-	gatewayFilter := args.GatewayFilter
-	var conditions []model.Condition
-	if gatewayFilter != "" {
-		// First, get the gateway UUID
-		var gateways []ovnicsb.Gateway
-		gatewayCondition := model.Condition{
-			Field:    &(&ovnicsb.Gateway{}).Name,
-			Function: ovsdb.ConditionEqual,
-			Value:    gatewayFilter,
-		}
-		gatewaySelectOps, gatewayQueryID, gatewaySelectErr := client.WhereAll(&ovnicsb.Gateway{}, gatewayCondition).Select()
-		if gatewaySelectErr != nil {
-			return nil, fmt.Errorf("failed to create gateway select operation: %w", gatewaySelectErr)
-		}
-
-		gatewayReply, err := client.Transact(ctx, gatewaySelectOps...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute gateway transaction: %w", err)
-		}
-
-		err = client.GetSelectResults(gatewaySelectOps, gatewayReply, map[string]interface{}{gatewayQueryID: &gateways})
+	if args.CountOnly {
+		count, err := mcp.CountRows(ctx, client, &ovnicsb.ICSBGlobal{})
 		if err != nil {
-			return nil, fmt.Errorf("failed to get gateway select results: %w", err)
-		}
-
-		if len(gateways) == 0 {
-			result := map[string]interface{}{
-				"encaps":  []ovnicsb.Encap{},
-				"count":   0,
-				"context": "No gateway found with the specified filter.",
-			}
-			json, err := json.Marshal(result)
-			if err != nil {
-				return nil, err
-			}
-			return &mcpsdk.CallToolResult{
-				Content: []mcpsdk.Content{
-					&mcpsdk.TextContent{
-						Text: string(json),
-					},
-				},
-			}, nil
+			return nil, err
 		}
+		return mcp.CountResult(count)
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnicsb.Encap{}, conditions...)
+	results, page, err := mcp.ExecuteSelectQueryPaged(ctx, client, &ovnicsb.ICSBGlobal{}, args.Limit, args.Offset)
 	if err != nil {
 		return nil, err
 	}
 
-	result := map[string]interface{}{
-		"encaps":  results,
-		"count":   len(results),
-		"context": "Encapsulations define the tunneling protocols used to connect gateways in OVN Interconnection.",
-	}
-
-	json, err := json.Marshal(result)
+	rows, err := mcp.MapRows(ovnicsb.ICSBGlobalTable, ovnicsb.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
-}
-
-func (s *Server) ListICSBGlobals(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListICSBGlobalsArgs]) (*mcpsdk.CallToolResult, error) {
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
-	}
-	defer client.Close()
-
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+	result := mcp.ListResult{
+		Data:       map[string]any{"ic_sb_globals": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "IC SB Globals contain global configuration settings for OVN Interconnection Southbound database.",
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnicsb.ICSBGlobal{})
-	if err != nil {
-		return nil, err
-	}
-
-	result := map[string]interface{}{
-		"ic_sb_globals": results,
-		"count":         len(results),
-		"context":       "IC SB Globals contain global configuration settings for OVN Interconnection Southbound database.",
-	}
-
-	json, err := json.Marshal(result)
-	if err != nil {
-		return nil, err
-	}
-
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	return mcp.NewResult(result)
 }
 
 // NewServer creates a new OVN IC SB MCP server
-func NewServer(host string, port int) (*Server, error) {
+func NewServer(host string, port int, opts ...mcp.Option) (*Server, error) {
 
-	// Create OVSDB client model using generated code
-	dbModel, err := ovnicsb.FullDatabaseModel()
+	// Use the OVSDB client model shared by every server in the process
+	dbModel, err := ovnicsb.DatabaseModel()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create database model: %w", err)
 	}
 
-	server := mcpsdk.NewServer(&mcpsdk.Implementation{
+	base, err := mcp.NewBaseServer(&mcpsdk.Implementation{
 		Name:    "ovn-ic-sb-mcp",
 		Title:   "OVN IC SB MCP Server",
 		Version: "0.1.0",
-	}, nil)
+	}, dbModel, defaultEndpoint, opts...)
+	if err != nil {
+		return nil, err
+	}
 
 	s := Server{
-		Server:  server,
-		dbModel: dbModel,
+		BaseServer: base,
 	}
 
 	// Register tools inline
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_availability_zones",
-		Description: "List all availability zones in OVN IC SB database. Availability zones represent different regions.",
+		Description: "List all availability zones in OVN IC SB database. Availability zones represent different regions. Set count_only to only get the number of matching rows.",
 	}, s.ListAvailabilityZones)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_datapath_bindings",
-		Description: "List all datapath bindings in OVN IC SB database. Datapath bindings represent physical or virtual switches.",
+		Description: "List all datapath bindings in OVN IC SB database. Datapath bindings represent physical or virtual switches. Set count_only to only get the number of matching rows.",
 	}, s.ListDatapathBindings)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_port_bindings",
-		Description: "List all port bindings in OVN IC SB database. Port bindings map logical ports to physical ports.",
+		Description: "List all port bindings in OVN IC SB database. Port bindings map logical ports to physical ports. Set count_only to only get the number of matching rows.",
 	}, s.ListPortBindings)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_gateways",
-		Description: "List all gateways in OVN IC SB database. Gateways provide routing between availability zones.",
+		Description: "List all gateways in OVN IC SB database. Gateways provide routing between availability zones. Set count_only to only get the number of matching rows.",
 	}, s.ListGateways)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_routes",
-		Description: "List all routes in OVN IC SB database. Routes define network paths between availability zones.",
+		Description: "List all routes in OVN IC SB database. Routes define network paths between availability zones. Set count_only to only get the number of matching rows.",
 	}, s.ListRoutes)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_encaps",
-		Description: "List all encapsulations in OVN IC SB database. Encapsulations define tunneling protocols for gateways.",
+		Description: "List all encapsulations in OVN IC SB database. Encapsulations define tunneling protocols for gateways. Set count_only to only get the number of matching rows.",
 	}, s.ListEncaps)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_ic_sb_globals",
-		Description: "List all IC SB globals in OVN IC SB database. IC SB globals contain global configuration settings.",
+		Description: "List all IC SB globals in OVN IC SB database. IC SB globals contain global configuration settings. Set count_only to only get the number of matching rows.",
 	}, s.ListICSBGlobals)
 
-	return &s, nil
-}
-
-// Start starts the MCP server on the specified address
-func (s *Server) Start(ctx context.Context, addr string) error {
-	// Create HTTP server using Streamable HTTP handler
-	streamableHandler := mcpsdk.NewStreamableHTTPHandler(func(request *http.Request) *mcpsdk.Server {
-		return s.Server
-	}, nil)
-
-	s.httpServer = &http.Server{
-		Addr:    addr,
-		Handler: streamableHandler,
-	}
-
-	// Start server in a goroutine
-	go func() {
-		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			// Log error if we had a logger
-		}
-	}()
-
-	return nil
-}
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "ovsdb_select",
+		Description: "Run a select on any table of the OVN IC SB database with [column, function, value] conditions, which rows must all match unless match_all is false, optionally returning only some columns. An escape hatch for querying columns the other tools don't filter on; use get_schema to find the tables and columns. Unknown tables and columns are rejected with the valid names.",
+	}, s.OVSDBSelect)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "get_schema",
+		Description: "Get the schema of the OVN IC SB database: its tables, and each table's columns with their types, whether they are sets, maps or references to other tables, and the values allowed for enums. Optionally describe a single table. Useful for finding which columns exist before filtering on them. Needs no database connection.",
+	}, mcp.GetSchema(ovnicsb.DatabaseSchema()))
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "describe_schema",
+		Description: "List the tables of the OVN IC SB database with the type of each of their columns, as compact JSON keyed by table. A quick overview of what can be queried before choosing a tool; use get_schema for the details of a table. Needs no database connection.",
+	}, mcp.DescribeSchema(ovnicsb.DatabaseSchema()))
+
+	mcp.AddLongRunningTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "watch_table",
+		Description: "Watch one table of the OVN IC SB database (e.g. Gateway) for a while and report its row inserts, updates and deletes as they happen. Each change is streamed to the client as a progress notification when the call has a progress token, and all of them are returned when the watch ends after duration_seconds (30 by default, at most 300) or max_events changes. Optionally only watch rows whose name matches a filter. The monitor is removed when the watch ends or the session closes.",
+	}, s.WatchTable)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "find_by_external_id_key",
+		Description: "Find the rows of every table in the OVN IC SB database that have an external_ids key, whatever its value, grouped by table. Optionally only match rows whose value for the key contains a substring. Useful for finding every object owned or labelled by a controller, e.g. by the k8s.ovn.org/owner key.",
+	}, s.FindByExternalIDKey)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "row_history",
+		Description: "Get the current value of any row in the OVN IC SB database by UUID, with its recent changes as timestamped before and after snapshots when the server is recording row history. OVSDB does not keep history itself, so only changes made since the server started recording are available.",
+	}, s.RowHistory)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "ping_database",
+		Description: "Check that the OVN IC SB database can be reached, with the connection latency and the schema version it serves. Use it before running other tools when the database may be down. Results are cached for a few seconds.",
+	}, s.PingDatabase)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "server_info",
+		Description: "Get the schema version of the OVN IC SB database and, from the ovsdb-server _Server database, whether it is standalone, clustered or a relay, whether the server is the cluster leader and connected to the cluster, and its cluster and server IDs. Use it to find the leader of a clustered database and the version to quote in bug reports.",
+	}, s.ServerInfo)
 
-// Stop stops the MCP server
-func (s *Server) Stop(ctx context.Context) error {
-	if s.httpServer != nil {
-		return s.httpServer.Shutdown(ctx)
-	}
-	return nil
+	return &s, nil
 }
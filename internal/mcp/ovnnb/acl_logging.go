@@ -0,0 +1,168 @@
+package ovnnb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/ovn-kubernetes/libovsdb/client"
+)
+
+// ACLLogging is the decoded logging and sampling configuration of an ACL
+type ACLLogging struct {
+	Enabled   bool       `json:"enabled"`
+	Name      string     `json:"name,omitempty"`
+	Severity  string     `json:"severity,omitempty"`
+	Meter     string     `json:"meter,omitempty"`
+	MeterRate string     `json:"meter_rate,omitempty"`
+	Sampling  bool       `json:"sampling"`
+	SampleNew *ACLSample `json:"sample_new,omitempty"`
+	SampleEst *ACLSample `json:"sample_est,omitempty"`
+	Warnings  []string   `json:"warnings,omitempty"`
+}
+
+// ACLSample is a decoded Sample row and its collectors
+type ACLSample struct {
+	Metadata   int               `json:"metadata"`
+	Collectors []SampleCollector `json:"collectors"`
+}
+
+// SampleCollector is a decoded Sample_Collector row
+type SampleCollector struct {
+	Name        string  `json:"name"`
+	SetID       int     `json:"set_id"`
+	Probability float64 `json:"probability_percent"`
+}
+
+// ACLWithLogging is an ACL along with its decoded logging configuration
+type ACLWithLogging struct {
+	ovnnb.ACL
+	Logging ACLLogging `json:"logging"`
+}
+
+// defaultACLSeverity is the severity OVN logs at when severity is unset
+var defaultACLSeverity = ovnnb.ACLSeverityInfo
+
+// aclLoggingDecoder resolves the meters and samples referenced by ACLs
+type aclLoggingDecoder struct {
+	meters     map[string]ovnnb.Meter
+	bands      map[string]ovnnb.MeterBand
+	samples    map[string]ovnnb.Sample
+	collectors map[string]ovnnb.SampleCollector
+}
+
+func newACLLoggingDecoder(ctx context.Context, c client.Client) (*aclLoggingDecoder, error) {
+	meters, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.Meter{})
+	if err != nil {
+		return nil, err
+	}
+	bands, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.MeterBand{})
+	if err != nil {
+		return nil, err
+	}
+	samples, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.Sample{})
+	if err != nil {
+		return nil, err
+	}
+	collectors, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.SampleCollector{})
+	if err != nil {
+		return nil, err
+	}
+
+	d := &aclLoggingDecoder{
+		meters:     make(map[string]ovnnb.Meter, len(meters)),
+		bands:      make(map[string]ovnnb.MeterBand, len(bands)),
+		samples:    make(map[string]ovnnb.Sample, len(samples)),
+		collectors: make(map[string]ovnnb.SampleCollector, len(collectors)),
+	}
+	// ACLs reference meters by name rather than UUID
+	for _, m := range meters {
+		d.meters[m.Name] = m
+	}
+	for _, b := range bands {
+		d.bands[b.UUID] = b
+	}
+	for _, s := range samples {
+		d.samples[s.UUID] = s
+	}
+	for _, c := range collectors {
+		d.collectors[c.UUID] = c
+	}
+	return d, nil
+}
+
+// decode explains an ACL's log, severity, meter and sample columns, flagging
+// configurations that silently disable or throttle logging
+func (d *aclLoggingDecoder) decode(acl ovnnb.ACL) ACLLogging {
+	l := ACLLogging{
+		Enabled: acl.Log,
+	}
+	if acl.Name != nil {
+		l.Name = *acl.Name
+	}
+	if acl.Log {
+		l.Severity = defaultACLSeverity
+		if acl.Severity != nil {
+			l.Severity = *acl.Severity
+		}
+	}
+
+	if acl.Meter != nil {
+		l.Meter = *acl.Meter
+		meter, ok := d.meters[l.Meter]
+		switch {
+		case !acl.Log:
+			l.Warnings = append(l.Warnings, "meter has no effect while logging is disabled")
+		case !ok:
+			l.Warnings = append(l.Warnings, fmt.Sprintf("meter %s does not exist, log messages are not rate limited", l.Meter))
+		case len(meter.Bands) == 0:
+			l.Warnings = append(l.Warnings, fmt.Sprintf("meter %s has no bands", l.Meter))
+		default:
+			if band, ok := d.bands[meter.Bands[0]]; ok {
+				l.MeterRate = fmt.Sprintf("%d %s, burst %d, %s", band.Rate, meter.Unit, band.BurstSize, band.Action)
+			}
+		}
+	} else if acl.Log {
+		l.Warnings = append(l.Warnings, "logging is not rate limited by a meter")
+	}
+
+	l.SampleNew = d.decodeSample(acl.SampleNew, &l)
+	l.SampleEst = d.decodeSample(acl.SampleEst, &l)
+	l.Sampling = l.SampleNew != nil || l.SampleEst != nil
+	return l
+}
+
+func (d *aclLoggingDecoder) decodeSample(uuid *string, l *ACLLogging) *ACLSample {
+	if uuid == nil {
+		return nil
+	}
+	sample, ok := d.samples[*uuid]
+	if !ok {
+		return nil
+	}
+	s := &ACLSample{
+		Metadata:   sample.Metadata,
+		Collectors: []SampleCollector{},
+	}
+	for _, c := range sample.Collectors {
+		collector, ok := d.collectors[c]
+		if !ok {
+			continue
+		}
+		// Sample_Collector probability is out of 65535
+		probability := float64(collector.Probability) * 100 / 65535
+		if collector.Probability == 0 {
+			l.Warnings = append(l.Warnings, fmt.Sprintf("sample collector %s has a probability of 0 and never samples", collector.Name))
+		}
+		s.Collectors = append(s.Collectors, SampleCollector{
+			Name:        collector.Name,
+			SetID:       collector.SetID,
+			Probability: probability,
+		})
+	}
+	if len(s.Collectors) == 0 {
+		l.Warnings = append(l.Warnings, "sampling is configured without any collectors")
+	}
+	return s
+}
@@ -0,0 +1,274 @@
+package ovnnb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type CreateACLArgs struct {
+	SwitchOrPortGroup string `json:"switch_or_portgroup" jsonschema:"the name of the logical switch or port group to add the ACL to"`
+	Direction         string `json:"direction" jsonschema:"from-lport for traffic sent by the ports, to-lport for traffic sent to them"`
+	Priority          int    `json:"priority" jsonschema:"the priority of the ACL, from 0 to 32767, higher priorities are matched first"`
+	Match             string `json:"match" jsonschema:"the OVN match expression of the packets the ACL applies to, e.g. ip4.src == 10.0.0.0/24 && tcp.dst == 80"`
+	Action            string `json:"action" jsonschema:"what to do with matching packets: allow, allow-related, allow-stateless, drop, reject or pass"`
+}
+
+type DeleteACLArgs struct {
+	UUID string `json:"uuid" jsonschema:"the UUID of the ACL to delete"`
+}
+
+// ACLResult is an ACL that was created or deleted, and the switches and port
+// groups it was added to or removed from
+type ACLResult struct {
+	UUID    string      `json:"uuid"`
+	Parents []ACLParent `json:"parents"`
+	Context string      `json:"context"`
+}
+
+// ACLParent is a logical switch or port group holding an ACL
+type ACLParent struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+var (
+	aclDirections = []string{ovnnb.ACLDirectionFromLport, ovnnb.ACLDirectionToLport}
+	aclActions    = []string{
+		ovnnb.ACLActionAllow,
+		ovnnb.ACLActionAllowRelated,
+		ovnnb.ACLActionAllowStateless,
+		ovnnb.ACLActionDrop,
+		ovnnb.ACLActionReject,
+		ovnnb.ACLActionPass,
+	}
+)
+
+// validateCreateACL checks the arguments of create_acl before anything is
+// sent to the database
+func validateCreateACL(args CreateACLArgs) error {
+	if args.SwitchOrPortGroup == "" {
+		return fmt.Errorf("switch_or_portgroup must not be empty")
+	}
+	if !slices.Contains(aclDirections, args.Direction) {
+		return fmt.Errorf("invalid direction %q, must be one of %s", args.Direction, strings.Join(aclDirections, ", "))
+	}
+	if !slices.Contains(aclActions, args.Action) {
+		return fmt.Errorf("invalid action %q, must be one of %s", args.Action, strings.Join(aclActions, ", "))
+	}
+	if args.Priority < 0 || args.Priority > maxACLPriority {
+		return fmt.Errorf("invalid priority %d, must be from 0 to %d", args.Priority, maxACLPriority)
+	}
+	if strings.TrimSpace(args.Match) == "" {
+		return fmt.Errorf("match must not be empty")
+	}
+	return nil
+}
+
+// aclParentMutation returns the operation adding the ACL to or removing it
+// from the acls column of the switch or port group
+func aclParentMutation(c client.Client, parent ACLParent, mutator ovsdb.Mutator, aclUUID string) ([]ovsdb.Operation, error) {
+	if parent.Type == "port_group" {
+		pg := &ovnnb.PortGroup{UUID: parent.UUID}
+		return c.Where(pg).Mutate(pg, model.Mutation{
+			Field:   &pg.ACLs,
+			Mutator: mutator,
+			Value:   []string{aclUUID},
+		})
+	}
+	ls := &ovnnb.LogicalSwitch{UUID: parent.UUID}
+	return c.Where(ls).Mutate(ls, model.Mutation{
+		Field:   &ls.ACLs,
+		Mutator: mutator,
+		Value:   []string{aclUUID},
+	})
+}
+
+func (s *Server) CreateACL(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[CreateACLArgs]) (*mcpsdk.CallToolResultFor[ACLResult], error) {
+	args := params.Arguments
+
+	if err := validateCreateACL(args); err != nil {
+		return nil, err
+	}
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	var parents []ACLParent
+	lsModel := &ovnnb.LogicalSwitch{}
+	switches, err := mcp.ExecuteSelectQuery(ctx, client, lsModel, model.Condition{
+		Field:    &lsModel.Name,
+		Function: ovsdb.ConditionEqual,
+		Value:    args.SwitchOrPortGroup,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, ls := range switches {
+		parents = append(parents, ACLParent{UUID: ls.UUID, Name: ls.Name, Type: "logical_switch"})
+	}
+	pgModel := &ovnnb.PortGroup{}
+	portGroups, err := mcp.ExecuteSelectQuery(ctx, client, pgModel, model.Condition{
+		Field:    &pgModel.Name,
+		Function: ovsdb.ConditionEqual,
+		Value:    args.SwitchOrPortGroup,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, pg := range portGroups {
+		parents = append(parents, ACLParent{UUID: pg.UUID, Name: pg.Name, Type: "port_group"})
+	}
+
+	switch len(parents) {
+	case 0:
+		return &mcpsdk.CallToolResultFor[ACLResult]{
+			IsError: true,
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: fmt.Sprintf("No logical switch or port group found with name %s", args.SwitchOrPortGroup),
+				},
+			},
+		}, nil
+	case 1:
+	default:
+		// Switch names are not unique, and a port group can share a name
+		// with a switch
+		return nil, fmt.Errorf("%d logical switches and port groups are named %s, ACLs can only be added to one", len(parents), args.SwitchOrPortGroup)
+	}
+	parent := parents[0]
+
+	acl := ovnnb.ACL{
+		UUID:      "new_acl",
+		Direction: args.Direction,
+		Priority:  args.Priority,
+		Match:     args.Match,
+		Action:    args.Action,
+	}
+	insertOps, err := client.Create(&acl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ACL insert operation: %w", err)
+	}
+	mutateOps, err := aclParentMutation(client, parent, ovsdb.MutateOperationInsert, acl.UUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s mutate operation: %w", parent.Type, err)
+	}
+
+	operations := append(insertOps, mutateOps...)
+	reply, err := mcp.TransactWrite(ctx, client, operations...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+	if _, err := ovsdb.CheckOperationResults(reply, operations); err != nil {
+		return nil, fmt.Errorf("failed to create ACL on %s: %w", args.SwitchOrPortGroup, err)
+	}
+
+	return mcp.NewResult(ACLResult{
+		UUID:    reply[0].UUID.GoUUID,
+		Parents: []ACLParent{parent},
+		Context: "The ACL was created and added to the acls column of the logical switch or port group. northd translates it into logical flows in the ACL stages of the switches it applies to, after which ovn-controller installs them; check the result with list_acls or the southbound logical flows. Delete it with delete_acl and its UUID.",
+	})
+}
+
+func (s *Server) DeleteACL(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[DeleteACLArgs]) (*mcpsdk.CallToolResultFor[ACLResult], error) {
+	args := params.Arguments
+
+	if args.UUID == "" {
+		return nil, fmt.Errorf("uuid must not be empty")
+	}
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	aclModel := &ovnnb.ACL{}
+	acls, err := mcp.ExecuteSelectQuery(ctx, client, aclModel, model.Condition{
+		Field:    &aclModel.UUID,
+		Function: ovsdb.ConditionEqual,
+		Value:    args.UUID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(acls) == 0 {
+		return &mcpsdk.CallToolResultFor[ACLResult]{
+			IsError: true,
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: fmt.Sprintf("No ACL found with UUID %s", args.UUID),
+				},
+			},
+		}, nil
+	}
+	acl := acls[0]
+
+	// An ACL can be referenced by several switches and port groups, each
+	// reference must be removed for the row to be deleted
+	lsModel := &ovnnb.LogicalSwitch{}
+	switches, err := mcp.ExecuteSelectQuery(ctx, client, lsModel, model.Condition{
+		Field:    &lsModel.ACLs,
+		Function: ovsdb.ConditionIncludes,
+		Value:    []string{acl.UUID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	pgModel := &ovnnb.PortGroup{}
+	portGroups, err := mcp.ExecuteSelectQuery(ctx, client, pgModel, model.Condition{
+		Field:    &pgModel.ACLs,
+		Function: ovsdb.ConditionIncludes,
+		Value:    []string{acl.UUID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	parents := []ACLParent{}
+	for _, ls := range switches {
+		parents = append(parents, ACLParent{UUID: ls.UUID, Name: ls.Name, Type: "logical_switch"})
+	}
+	for _, pg := range portGroups {
+		parents = append(parents, ACLParent{UUID: pg.UUID, Name: pg.Name, Type: "port_group"})
+	}
+
+	var operations []ovsdb.Operation
+	for _, parent := range parents {
+		mutateOps, err := aclParentMutation(client, parent, ovsdb.MutateOperationDelete, acl.UUID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s mutate operation: %w", parent.Type, err)
+		}
+		operations = append(operations, mutateOps...)
+	}
+	deleteOps, err := client.Where(&acl).Delete()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ACL delete operation: %w", err)
+	}
+	operations = append(operations, deleteOps...)
+
+	reply, err := mcp.TransactWrite(ctx, client, operations...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+	if _, err := ovsdb.CheckOperationResults(reply, operations); err != nil {
+		return nil, fmt.Errorf("failed to delete ACL %s: %w", acl.UUID, err)
+	}
+
+	return mcp.NewResult(ACLResult{
+		UUID:    acl.UUID,
+		Parents: parents,
+		Context: "The ACL was deleted and removed from the logical switches and port groups in parents. northd removes its logical flows, so the traffic it matched falls through to lower priority ACLs or the default verdict.",
+	})
+}
@@ -0,0 +1,277 @@
+package ovnnb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ExternalReachablePortsArgs struct {
+	Switch       string `json:"switch,omitempty" jsonschema:"only report the ports of the logical switch with this name"`
+	IsolatedOnly bool   `json:"isolated_only,omitempty" jsonschema:"only return the isolated ports, reachable ports are still counted"`
+}
+
+// ReachablePort is a logical switch port with a path to an external network
+type ReachablePort struct {
+	Port   string `json:"port"`
+	Switch string `json:"switch"`
+	// Path is the switches and routers from the port's switch to the
+	// switch with the localnet port
+	Path       []string `json:"path"`
+	ExitSwitch string   `json:"exit_switch"`
+	// Gateway is the first gateway router or distributed gateway port on the
+	// path, empty when the path has none
+	Gateway string `json:"gateway,omitempty"`
+}
+
+// IsolatedPort is a logical switch port with no path to an external network
+type IsolatedPort struct {
+	Port   string `json:"port"`
+	Switch string `json:"switch"`
+}
+
+// topologyNode is a logical switch or router in the topology graph
+type topologyNode struct {
+	name string
+	// gateway is set for gateway routers
+	gateway bool
+}
+
+// topologyEdge connects a switch and a router, or two routers through peer
+// ports. gatewayPort is set when the router port is a distributed gateway
+// port.
+type topologyEdge struct {
+	to          string
+	gatewayPort string
+}
+
+// endpointPortTypes are the logical switch port types of workloads, as
+// opposed to ports that connect the switch to something else
+var endpointPortTypes = []string{"", "virtual", "external"}
+
+func (s *Server) ExternalReachablePorts(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ExternalReachablePortsArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	switches, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.LogicalSwitch{})
+	if err != nil {
+		return nil, err
+	}
+	switchPorts, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.LogicalSwitchPort{})
+	if err != nil {
+		return nil, err
+	}
+	routers, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.LogicalRouter{})
+	if err != nil {
+		return nil, err
+	}
+	routerPorts, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.LogicalRouterPort{})
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Switch != "" && !slices.ContainsFunc(switches, func(ls ovnnb.LogicalSwitch) bool { return ls.Name == args.Switch }) {
+		return &mcpsdk.CallToolResultFor[map[string]any]{
+			IsError: true,
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: fmt.Sprintf("No logical switch found with name %s", args.Switch),
+				},
+			},
+		}, nil
+	}
+
+	lspByUUID := make(map[string]ovnnb.LogicalSwitchPort, len(switchPorts))
+	for _, lsp := range switchPorts {
+		lspByUUID[lsp.UUID] = lsp
+	}
+	nodes := make(map[string]*topologyNode)
+	// The router owning each router port, keyed by port name
+	lrpRouter := make(map[string]string)
+	lrpByName := make(map[string]ovnnb.LogicalRouterPort, len(routerPorts))
+	lrpByUUID := make(map[string]ovnnb.LogicalRouterPort, len(routerPorts))
+	for _, lrp := range routerPorts {
+		lrpByName[lrp.Name] = lrp
+		lrpByUUID[lrp.UUID] = lrp
+	}
+	for _, lr := range routers {
+		nodes[lr.UUID] = &topologyNode{
+			name:    lr.Name,
+			gateway: lr.Options["chassis"] != "",
+		}
+		for _, u := range lr.Ports {
+			if lrp, ok := lrpByUUID[u]; ok {
+				lrpRouter[lrp.Name] = lr.UUID
+			}
+		}
+	}
+	isGatewayPort := func(lrp ovnnb.LogicalRouterPort) bool {
+		return len(lrp.GatewayChassis) > 0 || lrp.HaChassisGroup != nil || lrp.Options["redirect-chassis"] != ""
+	}
+
+	edges := make(map[string][]topologyEdge)
+	connect := func(a, b, gatewayPort string) {
+		edges[a] = append(edges[a], topologyEdge{to: b, gatewayPort: gatewayPort})
+		edges[b] = append(edges[b], topologyEdge{to: a, gatewayPort: gatewayPort})
+	}
+
+	var exits []string
+	for _, ls := range switches {
+		nodes[ls.UUID] = &topologyNode{name: ls.Name}
+		external := false
+		for _, u := range ls.Ports {
+			lsp, ok := lspByUUID[u]
+			if !ok {
+				continue
+			}
+			switch lsp.Type {
+			case "localnet":
+				external = true
+			case "router":
+				name := lsp.Options["router-port"]
+				lr, ok := lrpRouter[name]
+				if !ok {
+					continue
+				}
+				gatewayPort := ""
+				if isGatewayPort(lrpByName[name]) {
+					gatewayPort = name
+				}
+				connect(ls.UUID, lr, gatewayPort)
+			}
+		}
+		if external {
+			exits = append(exits, ls.UUID)
+		}
+	}
+	for _, lrp := range routerPorts {
+		if lrp.Peer == nil {
+			continue
+		}
+		// Peer router ports usually name each other, only add the edge once
+		if peer, ok := lrpByName[*lrp.Peer]; ok && peer.Peer != nil && *peer.Peer == lrp.Name && lrp.Name > peer.Name {
+			continue
+		}
+		a, aok := lrpRouter[lrp.Name]
+		b, bok := lrpRouter[*lrp.Peer]
+		if aok && bok {
+			connect(a, b, "")
+		}
+	}
+
+	// Search outwards from the switches with localnet ports, recording the
+	// next hop towards the nearest exit for every node reached
+	sort.Slice(exits, func(i, j int) bool { return nodes[exits[i]].name < nodes[exits[j]].name })
+	next := make(map[string]topologyEdge)
+	exitOf := make(map[string]string)
+	queue := make([]string, 0, len(exits))
+	for _, u := range exits {
+		exitOf[u] = u
+		queue = append(queue, u)
+	}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, e := range edges[u] {
+			if _, seen := exitOf[e.to]; seen {
+				continue
+			}
+			exitOf[e.to] = exitOf[u]
+			next[e.to] = topologyEdge{to: u, gatewayPort: e.gatewayPort}
+			queue = append(queue, e.to)
+		}
+	}
+
+	pathFrom := func(u string) ([]string, string) {
+		path := []string{nodes[u].name}
+		gateway := ""
+		for {
+			if gateway == "" && nodes[u].gateway {
+				gateway = nodes[u].name
+			}
+			e, ok := next[u]
+			if !ok {
+				return path, gateway
+			}
+			if gateway == "" && e.gatewayPort != "" {
+				gateway = e.gatewayPort
+			}
+			u = e.to
+			path = append(path, nodes[u].name)
+		}
+	}
+
+	reachable := []ReachablePort{}
+	isolated := []IsolatedPort{}
+	reachableCount := 0
+	for _, ls := range switches {
+		if args.Switch != "" && ls.Name != args.Switch {
+			continue
+		}
+		_, ok := exitOf[ls.UUID]
+		var path []string
+		var gateway string
+		if ok {
+			path, gateway = pathFrom(ls.UUID)
+		}
+		for _, u := range ls.Ports {
+			lsp, found := lspByUUID[u]
+			if !found || !slices.Contains(endpointPortTypes, lsp.Type) {
+				continue
+			}
+			if !ok {
+				isolated = append(isolated, IsolatedPort{Port: lsp.Name, Switch: ls.Name})
+				continue
+			}
+			reachableCount++
+			if args.IsolatedOnly {
+				continue
+			}
+			reachable = append(reachable, ReachablePort{
+				Port:       lsp.Name,
+				Switch:     ls.Name,
+				Path:       path,
+				ExitSwitch: nodes[exitOf[ls.UUID]].name,
+				Gateway:    gateway,
+			})
+		}
+	}
+	sort.Slice(reachable, func(i, j int) bool {
+		if reachable[i].Switch != reachable[j].Switch {
+			return reachable[i].Switch < reachable[j].Switch
+		}
+		return reachable[i].Port < reachable[j].Port
+	})
+	sort.Slice(isolated, func(i, j int) bool {
+		if isolated[i].Switch != isolated[j].Switch {
+			return isolated[i].Switch < isolated[j].Switch
+		}
+		return isolated[i].Port < isolated[j].Port
+	})
+
+	exitNames := make([]string, 0, len(exits))
+	for _, u := range exits {
+		exitNames = append(exitNames, nodes[u].name)
+	}
+
+	result := map[string]interface{}{
+		"reachable":         reachable,
+		"isolated":          isolated,
+		"external_switches": exitNames,
+		"reachable_count":   reachableCount,
+		"isolated_count":    len(isolated),
+		"context":           "A port can reach the external network if its logical switch is connected, directly or through logical routers and the switches between them, to a switch with a localnet port. The path is the shortest chain of switches and routers to that switch and gateway is the first gateway router or distributed gateway port on it; a path without a gateway relies on the routers being distributed onto the localnet network. Only the topology is checked: routes, NAT, ACLs and port security can still block traffic on a reachable path. Isolated ports are on switches with no path to a localnet port at all, which is why workloads on them cannot reach outside OVN; connect the switch to a router with a gateway to fix this. Only workload ports (VIF, virtual and external ports) are reported.",
+	}
+
+	return mcp.NewResult(result)
+}
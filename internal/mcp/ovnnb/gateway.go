@@ -0,0 +1,277 @@
+package ovnnb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type FindDistributedGatewayPortsArgs struct {
+	Router string `json:"router,omitempty" jsonschema:"only check the logical router with this name"`
+}
+
+// Ways a gateway port's NAT rules are handled
+const (
+	gatewayModeCentralized = "centralized"
+	gatewayModeDistributed = "distributed"
+	gatewayModeMixed       = "mixed"
+)
+
+// GatewayChassisPriority is a chassis that a gateway port can be scheduled on
+type GatewayChassisPriority struct {
+	Chassis  string `json:"chassis"`
+	Priority int    `json:"priority"`
+}
+
+// DistributedGatewayPort is a logical router port that connects its router to
+// an external network through a gateway chassis
+type DistributedGatewayPort struct {
+	Router          string                   `json:"router"`
+	Port            string                   `json:"port"`
+	UUID            string                   `json:"uuid"`
+	MAC             string                   `json:"mac"`
+	Networks        []string                 `json:"networks"`
+	GatewayChassis  []GatewayChassisPriority `json:"gateway_chassis,omitempty"`
+	HAChassisGroup  string                   `json:"ha_chassis_group,omitempty"`
+	HAChassis       []GatewayChassisPriority `json:"ha_chassis,omitempty"`
+	RedirectChassis string                   `json:"redirect_chassis,omitempty"`
+	Mode            string                   `json:"mode"`
+	CentralizedNAT  int                      `json:"centralized_nat"`
+	DistributedNAT  int                      `json:"distributed_nat"`
+	Issues          []string                 `json:"issues"`
+}
+
+// GatewayRouter is a logical router bound to a single chassis
+type GatewayRouter struct {
+	Router  string `json:"router"`
+	UUID    string `json:"uuid"`
+	Chassis string `json:"chassis"`
+}
+
+// FlaggedRouter is a router whose gateway configuration is broken
+type FlaggedRouter struct {
+	Router string `json:"router"`
+	UUID   string `json:"uuid"`
+	Issue  string `json:"issue"`
+}
+
+func sortByPriority(chassis []GatewayChassisPriority) {
+	sort.SliceStable(chassis, func(i, j int) bool { return chassis[i].Priority > chassis[j].Priority })
+}
+
+// natDistributed reports whether a NAT rule is handled on the chassis of its
+// logical port rather than on the gateway chassis
+func natDistributed(nat ovnnb.NAT) bool {
+	return nat.LogicalPort != nil && *nat.LogicalPort != "" && nat.ExternalMAC != nil && *nat.ExternalMAC != ""
+}
+
+func (s *Server) FindDistributedGatewayPorts(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[FindDistributedGatewayPortsArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	routers, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.LogicalRouter{})
+	if err != nil {
+		return nil, err
+	}
+	if args.Router != "" {
+		routers = slices.DeleteFunc(routers, func(lr ovnnb.LogicalRouter) bool { return lr.Name != args.Router })
+		if len(routers) == 0 {
+			return &mcpsdk.CallToolResultFor[map[string]any]{
+				IsError: true,
+				Content: []mcpsdk.Content{
+					&mcpsdk.TextContent{
+						Text: fmt.Sprintf("No logical router found with name %s", args.Router),
+					},
+				},
+			}, nil
+		}
+	}
+	sort.Slice(routers, func(i, j int) bool { return routers[i].Name < routers[j].Name })
+
+	routerPorts, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.LogicalRouterPort{})
+	if err != nil {
+		return nil, err
+	}
+	gatewayChassis, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.GatewayChassis{})
+	if err != nil {
+		return nil, err
+	}
+	groups, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.HAChassisGroup{})
+	if err != nil {
+		return nil, err
+	}
+	haChassis, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.HAChassis{})
+	if err != nil {
+		return nil, err
+	}
+	nats, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.NAT{})
+	if err != nil {
+		return nil, err
+	}
+
+	lrpByUUID := make(map[string]ovnnb.LogicalRouterPort, len(routerPorts))
+	for _, lrp := range routerPorts {
+		lrpByUUID[lrp.UUID] = lrp
+	}
+	gcByUUID := make(map[string]ovnnb.GatewayChassis, len(gatewayChassis))
+	for _, gc := range gatewayChassis {
+		gcByUUID[gc.UUID] = gc
+	}
+	groupByUUID := make(map[string]ovnnb.HAChassisGroup, len(groups))
+	for _, g := range groups {
+		groupByUUID[g.UUID] = g
+	}
+	hcByUUID := make(map[string]ovnnb.HAChassis, len(haChassis))
+	for _, hc := range haChassis {
+		hcByUUID[hc.UUID] = hc
+	}
+	natByUUID := make(map[string]ovnnb.NAT, len(nats))
+	for _, nat := range nats {
+		natByUUID[nat.UUID] = nat
+	}
+
+	gatewayPorts := []DistributedGatewayPort{}
+	gatewayRouters := []GatewayRouter{}
+	flagged := []FlaggedRouter{}
+	for _, lr := range routers {
+		var dgps []*DistributedGatewayPort
+		for _, portUUID := range lr.Ports {
+			lrp, ok := lrpByUUID[portUUID]
+			if !ok {
+				continue
+			}
+			redirect := lrp.Options["redirect-chassis"]
+			if len(lrp.GatewayChassis) == 0 && lrp.HaChassisGroup == nil && redirect == "" {
+				continue
+			}
+			dgp := &DistributedGatewayPort{
+				Router:          lr.Name,
+				Port:            lrp.Name,
+				UUID:            lrp.UUID,
+				MAC:             lrp.MAC,
+				Networks:        lrp.Networks,
+				RedirectChassis: redirect,
+				Issues:          []string{},
+			}
+			for _, u := range lrp.GatewayChassis {
+				if gc, ok := gcByUUID[u]; ok {
+					dgp.GatewayChassis = append(dgp.GatewayChassis, GatewayChassisPriority{Chassis: gc.ChassisName, Priority: gc.Priority})
+				}
+			}
+			sortByPriority(dgp.GatewayChassis)
+			if lrp.HaChassisGroup != nil {
+				if g, ok := groupByUUID[*lrp.HaChassisGroup]; ok {
+					dgp.HAChassisGroup = g.Name
+					for _, u := range g.HaChassis {
+						if hc, ok := hcByUUID[u]; ok {
+							dgp.HAChassis = append(dgp.HAChassis, GatewayChassisPriority{Chassis: hc.ChassisName, Priority: hc.Priority})
+						}
+					}
+					sortByPriority(dgp.HAChassis)
+					if len(dgp.HAChassis) == 0 {
+						dgp.Issues = append(dgp.Issues, fmt.Sprintf("HA chassis group %s has no chassis, so the port is not bound anywhere", g.Name))
+					}
+				}
+			}
+			if len(lrp.GatewayChassis) > 0 && lrp.HaChassisGroup != nil {
+				dgp.Issues = append(dgp.Issues, "both gateway_chassis and ha_chassis_group are set, only one should be configured")
+			}
+			if redirect != "" {
+				dgp.Issues = append(dgp.Issues, "options:redirect-chassis is deprecated, use gateway_chassis or ha_chassis_group instead")
+			}
+			if len(lrp.Networks) == 0 {
+				dgp.Issues = append(dgp.Issues, "the port has no networks, so there is no external subnet")
+			}
+			dgps = append(dgps, dgp)
+		}
+
+		chassis := lr.Options["chassis"]
+		if chassis != "" {
+			gatewayRouters = append(gatewayRouters, GatewayRouter{Router: lr.Name, UUID: lr.UUID, Chassis: chassis})
+			for _, dgp := range dgps {
+				dgp.Issues = append(dgp.Issues, "the router is a gateway router (options:chassis is set), so northd ignores its distributed gateway ports")
+			}
+		}
+
+		// With several gateway ports, each NAT rule must say which one it uses
+		for _, natUUID := range lr.Nat {
+			nat, ok := natByUUID[natUUID]
+			if !ok || len(dgps) == 0 {
+				continue
+			}
+			var dgp *DistributedGatewayPort
+			if nat.GatewayPort != nil {
+				for _, d := range dgps {
+					if d.UUID == *nat.GatewayPort {
+						dgp = d
+					}
+				}
+			} else if len(dgps) == 1 {
+				dgp = dgps[0]
+			}
+			if dgp == nil {
+				flagged = append(flagged, FlaggedRouter{
+					Router: lr.Name,
+					UUID:   lr.UUID,
+					Issue:  fmt.Sprintf("NAT rule %s (%s %s) does not set gateway_port to one of the router's distributed gateway ports", nat.UUID, nat.Type, nat.ExternalIP),
+				})
+				continue
+			}
+			if natDistributed(nat) {
+				dgp.DistributedNAT++
+			} else {
+				dgp.CentralizedNAT++
+			}
+		}
+
+		for _, dgp := range dgps {
+			switch {
+			case dgp.DistributedNAT > 0 && dgp.CentralizedNAT > 0:
+				dgp.Mode = gatewayModeMixed
+			case dgp.DistributedNAT > 0:
+				dgp.Mode = gatewayModeDistributed
+			default:
+				dgp.Mode = gatewayModeCentralized
+			}
+			gatewayPorts = append(gatewayPorts, *dgp)
+		}
+
+		if len(dgps) == 0 && chassis == "" {
+			var uses []string
+			if len(lr.Nat) > 0 {
+				uses = append(uses, "NAT rules")
+			}
+			if len(lr.LoadBalancer) > 0 || len(lr.LoadBalancerGroup) > 0 {
+				uses = append(uses, "load balancers")
+			}
+			if len(uses) > 0 {
+				flagged = append(flagged, FlaggedRouter{
+					Router: lr.Name,
+					UUID:   lr.UUID,
+					Issue:  fmt.Sprintf("router has %s but no distributed gateway port and is not a gateway router, so they will not work for external traffic", strings.Join(uses, " and ")),
+				})
+			}
+		}
+	}
+
+	result := map[string]interface{}{
+		"gateway_ports":   gatewayPorts,
+		"gateway_routers": gatewayRouters,
+		"flagged_routers": flagged,
+		"count":           len(gatewayPorts),
+		"context":         "Distributed gateway ports are logical router ports with gateway_chassis, an ha_chassis_group or the deprecated options:redirect-chassis. Traffic to and from the external network (the port's networks) is redirected to the active chassis, the highest priority one that is up. NAT rules with both logical_port and external_mac are distributed and handled on the chassis hosting the logical port, all other NAT rules and load balancers are centralized on the gateway chassis. Gateway routers are instead bound entirely to the chassis in options:chassis. Flagged routers have NAT or load balancers without any gateway, or NAT rules that cannot be assigned to a gateway port.",
+	}
+
+	return mcp.NewResult(result)
+}
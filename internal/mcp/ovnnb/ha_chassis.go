@@ -0,0 +1,112 @@
+package ovnnb
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type ListHAChassisGroupsArgs struct {
+	NameFilter string `json:"name_filter" jsonschema:"the name of the HA chassis group to filter by"`
+	MatchMode  string `json:"match,omitempty" jsonschema:"how name_filter is matched: exact (default), prefix, substring or regex, prefix and substring matches are case-insensitive"`
+	Resolve    bool   `json:"resolve,omitempty" jsonschema:"replace the UUIDs in ha_chassis with the HA_Chassis rows, highest priority first, this costs an extra query"`
+	CountOnly  bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset     int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+func (s *Server) ListHAChassisGroups(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListHAChassisGroupsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	matcher, err := mcp.NewNameMatcher(args.NameFilter, args.MatchMode)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &ovnnb.HAChassisGroup{}
+	var conditions []model.Condition
+	if matcher.Exact() {
+		conditions = append(conditions, model.Condition{
+			Field:    &group.Name,
+			Function: ovsdb.ConditionEqual,
+			Value:    args.NameFilter,
+		})
+	}
+
+	c, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	results, err := mcp.ExecuteSelectQuery(ctx, c, group, conditions...)
+	if err != nil {
+		return nil, err
+	}
+	results = mcp.FilterByName(results, matcher, func(r ovnnb.HAChassisGroup) string { return r.Name })
+
+	if args.CountOnly {
+		return mcp.CountResult(len(results))
+	}
+
+	results, page := mcp.Paginate(results, args.Limit, args.Offset)
+
+	rows, err := mcp.MapRows(ovnnb.HAChassisGroupTable, ovnnb.DatabaseSchema(), results)
+	if err != nil {
+		return nil, err
+	}
+	if args.Resolve {
+		if err := s.resolveHAChassis(ctx, c, results, rows); err != nil {
+			return nil, err
+		}
+	}
+
+	result := mcp.ListResult{
+		Data:       map[string]any{"ha_chassis_groups": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "HA chassis groups are the chassis, in priority order, that a gateway is made highly available across. They are referenced from the ha_chassis_group column of logical router ports and of external or localnet switch ports. The highest priority chassis in the group that is up, as detected by BFD between chassis, is active; if it fails, the next highest takes over. Set resolve to get each chassis's name and priority.",
+	}
+
+	return mcp.NewResult(result)
+}
+
+// resolveHAChassis replaces the ha_chassis column of each group's row with
+// its HA_Chassis rows, highest priority first, selecting the rows of every
+// group at once
+func (s *Server) resolveHAChassis(ctx context.Context, c client.Client, groups []ovnnb.HAChassisGroup, rows []map[string]any) error {
+	var uuids []string
+	for _, group := range groups {
+		uuids = append(uuids, group.HaChassis...)
+	}
+	haChassis := &ovnnb.HAChassis{}
+	chassis, err := mcp.ExecuteSelectByUUIDs(ctx, c, haChassis, &haChassis.UUID, uuids)
+	if err != nil {
+		return err
+	}
+	byUUID := make(map[string]ovnnb.HAChassis, len(chassis))
+	for _, hc := range chassis {
+		byUUID[hc.UUID] = hc
+	}
+
+	for i, group := range groups {
+		members := make([]ovnnb.HAChassis, 0, len(group.HaChassis))
+		for _, uuid := range group.HaChassis {
+			if hc, ok := byUUID[uuid]; ok {
+				members = append(members, hc)
+			}
+		}
+		sort.SliceStable(members, func(a, b int) bool { return members[a].Priority > members[b].Priority })
+		if rows[i]["ha_chassis"], err = mcp.MapRows(ovnnb.HAChassisTable, ovnnb.DatabaseSchema(), members); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+package ovnnb
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type CreateLogicalSwitchPortArgs struct {
+	Switch    string   `json:"switch" jsonschema:"the name of the logical switch to add the port to"`
+	Name      string   `json:"name" jsonschema:"the name of the new port, which must be unique across all logical switches"`
+	Addresses []string `json:"addresses,omitempty" jsonschema:"the port's addresses, each a MAC address followed by its IP addresses, e.g. 0a:58:0a:00:00:05 10.0.0.5, or one of router, unknown or dynamic"`
+	Type      string   `json:"type,omitempty" jsonschema:"the port type, empty for a VM or container port, or one of router, localnet, localport, l2gateway, vtep, external, virtual or remote"`
+}
+
+type SetLogicalSwitchPortAddressesArgs struct {
+	Name      string   `json:"name" jsonschema:"the name of the logical switch port"`
+	Addresses []string `json:"addresses" jsonschema:"the port's new addresses, replacing its current ones, each a MAC address followed by its IP addresses, or one of router, unknown or dynamic; empty to clear them"`
+}
+
+// LogicalSwitchPortResult is a logical switch port that was created or
+// updated
+type LogicalSwitchPortResult struct {
+	UUID       string   `json:"uuid"`
+	Name       string   `json:"name"`
+	Switch     string   `json:"switch"`
+	SwitchUUID string   `json:"switch_uuid"`
+	Type       string   `json:"type"`
+	Addresses  []string `json:"addresses"`
+	Context    string   `json:"context"`
+}
+
+var lspTypes = []string{"", "router", "localnet", "localport", "l2gateway", "vtep", "external", "virtual", "remote"}
+
+// validateLSPAddresses checks that each address is a keyword or a MAC
+// address followed by IP addresses, as northd ignores the ones it can't
+// parse
+func validateLSPAddresses(addresses []string) error {
+	for _, address := range addresses {
+		fields := strings.Fields(address)
+		if len(fields) == 0 {
+			return fmt.Errorf("addresses must not contain empty entries")
+		}
+		switch fields[0] {
+		case "router", "unknown":
+			if len(fields) > 1 {
+				return fmt.Errorf("invalid address %q, %s takes no IP addresses", address, fields[0])
+			}
+			continue
+		case "dynamic":
+		default:
+			if _, err := net.ParseMAC(fields[0]); err != nil {
+				return fmt.Errorf("invalid address %q, must start with a MAC address or be one of router, unknown or dynamic", address)
+			}
+		}
+		for _, ip := range fields[1:] {
+			if ip == "dynamic" && fields[0] != "dynamic" {
+				continue
+			}
+			if net.ParseIP(ip) == nil {
+				if _, _, err := net.ParseCIDR(ip); err != nil {
+					return fmt.Errorf("invalid address %q, %s is not an IP address", address, ip)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateCreateLogicalSwitchPort checks the arguments of
+// create_logical_switch_port before anything is sent to the database
+func validateCreateLogicalSwitchPort(args CreateLogicalSwitchPortArgs) error {
+	if args.Switch == "" {
+		return fmt.Errorf("switch must not be empty")
+	}
+	if args.Name == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+	if !slices.Contains(lspTypes, args.Type) {
+		return fmt.Errorf("invalid type %q, must be empty or one of %s", args.Type, strings.Join(lspTypes[1:], ", "))
+	}
+	return validateLSPAddresses(args.Addresses)
+}
+
+func (s *Server) CreateLogicalSwitchPort(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[CreateLogicalSwitchPortArgs]) (*mcpsdk.CallToolResultFor[LogicalSwitchPortResult], error) {
+	args := params.Arguments
+
+	if err := validateCreateLogicalSwitchPort(args); err != nil {
+		return nil, err
+	}
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	lsModel := &ovnnb.LogicalSwitch{}
+	switches, err := mcp.ExecuteSelectQuery(ctx, client, lsModel, model.Condition{
+		Field:    &lsModel.Name,
+		Function: ovsdb.ConditionEqual,
+		Value:    args.Switch,
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch len(switches) {
+	case 0:
+		return &mcpsdk.CallToolResultFor[LogicalSwitchPortResult]{
+			IsError: true,
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: fmt.Sprintf("No logical switch found with name %s", args.Switch),
+				},
+			},
+		}, nil
+	case 1:
+	default:
+		// Switch names are not unique
+		return nil, fmt.Errorf("%d logical switches are named %s, use one with a unique name", len(switches), args.Switch)
+	}
+	ls := switches[0]
+
+	// Port names are unique, the database would reject the insert, but
+	// saying which switch holds the port is more useful
+	lspModel := &ovnnb.LogicalSwitchPort{}
+	existing, err := mcp.ExecuteSelectQuery(ctx, client, lspModel, model.Condition{
+		Field:    &lspModel.Name,
+		Function: ovsdb.ConditionEqual,
+		Value:    args.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("a logical switch port named %s already exists with UUID %s", args.Name, existing[0].UUID)
+	}
+
+	lsp := ovnnb.LogicalSwitchPort{
+		UUID:      "new_lsp",
+		Name:      args.Name,
+		Type:      args.Type,
+		Addresses: args.Addresses,
+	}
+	insertOps, err := client.Create(&lsp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create logical switch port insert operation: %w", err)
+	}
+	parent := &ovnnb.LogicalSwitch{UUID: ls.UUID}
+	mutateOps, err := client.Where(parent).Mutate(parent, model.Mutation{
+		Field:   &parent.Ports,
+		Mutator: ovsdb.MutateOperationInsert,
+		Value:   []string{lsp.UUID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create logical switch mutate operation: %w", err)
+	}
+
+	operations := append(insertOps, mutateOps...)
+	reply, err := mcp.TransactWrite(ctx, client, operations...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+	if _, err := ovsdb.CheckOperationResults(reply, operations); err != nil {
+		return nil, fmt.Errorf("failed to create logical switch port %s on %s: %w", args.Name, args.Switch, err)
+	}
+
+	addresses := args.Addresses
+	if addresses == nil {
+		addresses = []string{}
+	}
+	return mcp.NewResult(LogicalSwitchPortResult{
+		UUID:       reply[0].UUID.GoUUID,
+		Name:       args.Name,
+		Switch:     ls.Name,
+		SwitchUUID: ls.UUID,
+		Type:       args.Type,
+		Addresses:  addresses,
+		Context:    "The logical switch port was created and added to the ports column of the logical switch. northd creates its port binding in the southbound database; for a VM or container port, the ovn-controller of the chassis whose OVS interface has external_ids:iface-id set to the port's name claims it. Change its addresses with set_logical_switch_port_addresses.",
+	})
+}
+
+func (s *Server) SetLogicalSwitchPortAddresses(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[SetLogicalSwitchPortAddressesArgs]) (*mcpsdk.CallToolResultFor[LogicalSwitchPortResult], error) {
+	args := params.Arguments
+
+	if args.Name == "" {
+		return nil, fmt.Errorf("name must not be empty")
+	}
+	if err := validateLSPAddresses(args.Addresses); err != nil {
+		return nil, err
+	}
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	lspModel := &ovnnb.LogicalSwitchPort{}
+	ports, err := mcp.ExecuteSelectQuery(ctx, client, lspModel, model.Condition{
+		Field:    &lspModel.Name,
+		Function: ovsdb.ConditionEqual,
+		Value:    args.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ports) == 0 {
+		return &mcpsdk.CallToolResultFor[LogicalSwitchPortResult]{
+			IsError: true,
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: fmt.Sprintf("No logical switch port found with name %s", args.Name),
+				},
+			},
+		}, nil
+	}
+	lsp := ports[0]
+
+	addresses := args.Addresses
+	if addresses == nil {
+		addresses = []string{}
+	}
+	row := &ovnnb.LogicalSwitchPort{UUID: lsp.UUID, Addresses: addresses}
+	operations, err := client.Where(row).Update(row, &row.Addresses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create logical switch port update operation: %w", err)
+	}
+	reply, err := mcp.TransactWrite(ctx, client, operations...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+	if _, err := ovsdb.CheckOperationResults(reply, operations); err != nil {
+		return nil, fmt.Errorf("failed to set the addresses of logical switch port %s: %w", args.Name, err)
+	}
+
+	result := LogicalSwitchPortResult{
+		UUID:      lsp.UUID,
+		Name:      lsp.Name,
+		Type:      lsp.Type,
+		Addresses: addresses,
+		Context:   "The addresses column of the logical switch port was replaced. northd updates the port's MAC column in the southbound database and the switch's port security and ARP/ND responder flows; port_security is not changed, so update it too if it lists the old addresses.",
+	}
+	lsModel := &ovnnb.LogicalSwitch{}
+	switches, err := mcp.ExecuteSelectQuery(ctx, client, lsModel, model.Condition{
+		Field:    &lsModel.Ports,
+		Function: ovsdb.ConditionIncludes,
+		Value:    []string{lsp.UUID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(switches) > 0 {
+		result.Switch = switches[0].Name
+		result.SwitchUUID = switches[0].UUID
+	}
+
+	return mcp.NewResult(result)
+}
@@ -0,0 +1,314 @@
+package ovnnb
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type PortDSCPArgs struct {
+	Port string `json:"port" jsonschema:"the name of the logical switch port"`
+}
+
+// Scopes describing why a QoS rule applies to a port
+const (
+	qosScopePort    = "port"
+	qosScopeAddress = "address"
+	qosScopeSwitch  = "switch"
+)
+
+// AppliedQoS is a QoS rule that applies to a port
+type AppliedQoS struct {
+	UUID       string         `json:"uuid"`
+	Priority   int            `json:"priority"`
+	Match      string         `json:"match"`
+	Action     map[string]int `json:"action,omitempty"`
+	Bandwidth  map[string]int `json:"bandwidth,omitempty"`
+	Scope      string         `json:"scope"`
+	Overridden bool           `json:"overridden"`
+}
+
+// EffectiveQoS is the marking and rate limit applied to a port's traffic in
+// one direction
+type EffectiveQoS struct {
+	DSCP          any          `json:"dscp"`
+	DSCPRule      string       `json:"dscp_rule,omitempty"`
+	Mark          *int         `json:"mark,omitempty"`
+	RateKbps      *int         `json:"rate_kbps,omitempty"`
+	BurstKbits    *int         `json:"burst_kbits,omitempty"`
+	BandwidthRule string       `json:"bandwidth_rule,omitempty"`
+	Rules         []AppliedQoS `json:"rules"`
+}
+
+var (
+	// qosPortMatch finds inport and outport equality tests, whose value is a
+	// quoted port name, a port group or a set of either
+	qosPortMatch = regexp.MustCompile(`\b(inport|outport)\s*==\s*(\{[^}]*\}|"[^"]*"|@[\w.]+)`)
+	// qosIPMatch finds IP source and destination equality tests, whose value
+	// is an address, an address set or a set of either
+	qosIPMatch = regexp.MustCompile(`\bip[46]\.(src|dst)\s*==\s*(\{[^}]*\}|\$[\w.]+|[0-9a-fA-F:./]+)`)
+)
+
+// matchValues splits a match value that may be a set into its elements
+func matchValues(v string) []string {
+	v = strings.TrimSuffix(strings.TrimPrefix(v, "{"), "}")
+	var values []string
+	for _, e := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' }) {
+		values = append(values, strings.Trim(e, `"`))
+	}
+	return values
+}
+
+// qosTarget is what is needed to decide whether a QoS match refers to a port
+type qosTarget struct {
+	lsp         ovnnb.LogicalSwitchPort
+	portGroups  []string
+	addresses   []netip.Addr
+	addressSets map[string][]string
+}
+
+func (t *qosTarget) hasAddress(value string) bool {
+	if prefix, err := netip.ParsePrefix(value); err == nil {
+		for _, a := range t.addresses {
+			if prefix.Contains(a) {
+				return true
+			}
+		}
+		return false
+	}
+	if addr, err := netip.ParseAddr(value); err == nil {
+		return slices.Contains(t.addresses, addr)
+	}
+	return false
+}
+
+func (t *qosTarget) matchesAddress(value string) bool {
+	name, ok := strings.CutPrefix(value, "$")
+	if !ok {
+		return t.hasAddress(value)
+	}
+	// Port groups have generated address sets for their addresses
+	for _, suffix := range []string{"_ip4", "_ip6"} {
+		if pg, ok := strings.CutSuffix(name, suffix); ok && slices.Contains(t.portGroups, pg) {
+			return true
+		}
+	}
+	for _, a := range t.addressSets[name] {
+		if t.hasAddress(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// scope decides whether a QoS rule applies to the port's traffic. Rules that
+// name ports apply only to those ports. Otherwise, rules that test the IP on
+// the port's side of the direction apply to matching ports. Rules with
+// neither apply to every port on the switch.
+func (t *qosTarget) scope(qos ovnnb.QoS) (string, bool) {
+	ports := qosPortMatch.FindAllStringSubmatch(qos.Match, -1)
+	if len(ports) > 0 {
+		for _, m := range ports {
+			for _, v := range matchValues(m[2]) {
+				if v == t.lsp.Name {
+					return qosScopePort, true
+				}
+				if pg, ok := strings.CutPrefix(v, "@"); ok && slices.Contains(t.portGroups, pg) {
+					return qosScopePort, true
+				}
+			}
+		}
+		return "", false
+	}
+
+	side := "src"
+	if qos.Direction == ovnnb.QoSDirectionToLport {
+		side = "dst"
+	}
+	ips := qosIPMatch.FindAllStringSubmatch(qos.Match, -1)
+	identified := false
+	for _, m := range ips {
+		if m[1] != side {
+			continue
+		}
+		identified = true
+		for _, v := range matchValues(m[2]) {
+			if t.matchesAddress(v) {
+				return qosScopeAddress, true
+			}
+		}
+	}
+	if identified {
+		return "", false
+	}
+	return qosScopeSwitch, true
+}
+
+// portAddresses returns the IPs in a port's addresses and dynamic addresses
+func portAddresses(lsp ovnnb.LogicalSwitchPort) []netip.Addr {
+	entries := slices.Clone(lsp.Addresses)
+	if lsp.DynamicAddresses != nil {
+		entries = append(entries, *lsp.DynamicAddresses)
+	}
+	var addrs []netip.Addr
+	for _, entry := range entries {
+		for _, f := range strings.Fields(entry) {
+			if a, err := netip.ParseAddr(f); err == nil {
+				addrs = append(addrs, a)
+			}
+		}
+	}
+	return addrs
+}
+
+func effectiveQoS(rules []ovnnb.QoS, t *qosTarget) EffectiveQoS {
+	// OVN marks and meters in separate stages, each of which uses the
+	// highest priority matching rule
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].Priority > rules[j].Priority })
+
+	e := EffectiveQoS{
+		DSCP:  "no marking",
+		Rules: []AppliedQoS{},
+	}
+	marked, metered := false, false
+	for _, qos := range rules {
+		scope, ok := t.scope(qos)
+		if !ok {
+			continue
+		}
+		applied := AppliedQoS{
+			UUID:      qos.UUID,
+			Priority:  qos.Priority,
+			Match:     qos.Match,
+			Action:    qos.Action,
+			Bandwidth: qos.Bandwidth,
+			Scope:     scope,
+		}
+		used := false
+		if len(qos.Action) > 0 && !marked {
+			if dscp, ok := qos.Action[ovnnb.QoSActionDSCP]; ok {
+				e.DSCP = dscp
+			}
+			if mark, ok := qos.Action[ovnnb.QoSActionMark]; ok {
+				e.Mark = &mark
+			}
+			e.DSCPRule = qos.UUID
+			marked, used = true, true
+		}
+		if rate, ok := qos.Bandwidth["rate"]; ok && !metered {
+			e.RateKbps = &rate
+			if burst, ok := qos.Bandwidth["burst"]; ok {
+				e.BurstKbits = &burst
+			}
+			e.BandwidthRule = qos.UUID
+			metered, used = true, true
+		}
+		applied.Overridden = !used
+		e.Rules = append(e.Rules, applied)
+	}
+	return e
+}
+
+func (s *Server) PortDSCP(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[PortDSCPArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	if args.Port == "" {
+		return nil, fmt.Errorf("port is required")
+	}
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	lspModel := &ovnnb.LogicalSwitchPort{}
+	ports, err := mcp.ExecuteSelectQuery(ctx, client, lspModel, model.Condition{
+		Field:    &lspModel.Name,
+		Function: ovsdb.ConditionEqual,
+		Value:    args.Port,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ports) == 0 {
+		return &mcpsdk.CallToolResultFor[map[string]any]{
+			IsError: true,
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: fmt.Sprintf("No logical switch port found with name %s", args.Port),
+				},
+			},
+		}, nil
+	}
+	lsp := ports[0]
+
+	switches, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.LogicalSwitch{})
+	if err != nil {
+		return nil, err
+	}
+	var ls *ovnnb.LogicalSwitch
+	for i := range switches {
+		if slices.Contains(switches[i].Ports, lsp.UUID) {
+			ls = &switches[i]
+			break
+		}
+	}
+	if ls == nil {
+		return nil, fmt.Errorf("logical switch port %s is not on a logical switch", args.Port)
+	}
+
+	portGroups, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.PortGroup{})
+	if err != nil {
+		return nil, err
+	}
+	addressSets, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.AddressSet{})
+	if err != nil {
+		return nil, err
+	}
+	qosRules, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.QoS{})
+	if err != nil {
+		return nil, err
+	}
+
+	t := &qosTarget{
+		lsp:         lsp,
+		addresses:   portAddresses(lsp),
+		addressSets: make(map[string][]string, len(addressSets)),
+	}
+	for _, pg := range portGroups {
+		if slices.Contains(pg.Ports, lsp.UUID) {
+			t.portGroups = append(t.portGroups, pg.Name)
+		}
+	}
+	for _, as := range addressSets {
+		t.addressSets[as.Name] = as.Addresses
+	}
+
+	byDirection := map[string][]ovnnb.QoS{}
+	for _, qos := range qosRules {
+		if slices.Contains(ls.QOSRules, qos.UUID) {
+			byDirection[qos.Direction] = append(byDirection[qos.Direction], qos)
+		}
+	}
+
+	result := map[string]interface{}{
+		"port":                      lsp.Name,
+		"switch":                    ls.Name,
+		ovnnb.QoSDirectionFromLport: effectiveQoS(byDirection[ovnnb.QoSDirectionFromLport], t),
+		ovnnb.QoSDirectionToLport:   effectiveQoS(byDirection[ovnnb.QoSDirectionToLport], t),
+		"context":                   "QoS rules on the port's logical switch that apply to it, per direction: from-lport is traffic sent by the port and to-lport is traffic delivered to it. A rule applies when its match names the port or one of its port groups, when it tests the IP on the port's side against one of the port's addresses, or when it tests neither and so applies to every port on the switch. DSCP marking and rate limiting are each taken from the highest priority applicable rule; rules that contribute neither are overridden. Matches may have further conditions, such as a protocol, that limit which of the port's traffic is affected.",
+	}
+
+	return mcp.NewResult(result)
+}
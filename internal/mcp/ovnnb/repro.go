@@ -0,0 +1,388 @@
+package ovnnb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+const (
+	defaultReproDepth = 3
+	maxReproDepth     = 10
+)
+
+type MinimalReproArgs struct {
+	Table string `json:"table" jsonschema:"the NB table of the target object, e.g. Logical_Switch"`
+	Name  string `json:"name" jsonschema:"the name or UUID of the target object"`
+	Depth int    `json:"depth,omitempty" jsonschema:"how many references to follow from the target, defaults to 3 and is capped at 10"`
+}
+
+// matchNameRef finds address set ($name) and port group (@name) references
+// in a match expression
+var matchNameRef = regexp.MustCompile(`([$@])([a-zA-Z_.][a-zA-Z_.0-9]*)`)
+
+type reproRow struct {
+	table string
+	row   ovsdb.Row
+}
+
+// reproGraph is a snapshot of the NB database with the references between
+// rows indexed in both directions. owners only holds strong references,
+// which are the ones that keep non-root rows from being garbage collected.
+type reproGraph struct {
+	schema    ovsdb.DatabaseSchema
+	rows      map[string]reproRow
+	names     map[string]map[string][]string
+	referrers map[string][]string
+	owners    map[string][]string
+}
+
+func loadReproGraph(ctx context.Context, c client.Client, schema ovsdb.DatabaseSchema) (*reproGraph, error) {
+	tables := make([]string, 0, len(schema.Tables))
+	for table := range schema.Tables {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	ops := make([]ovsdb.Operation, 0, len(tables))
+	for _, table := range tables {
+		ops = append(ops, ovsdb.Operation{
+			Op:    ovsdb.OperationSelect,
+			Table: table,
+		})
+	}
+	reply, err := mcp.Transact(ctx, c, ops...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+	if _, err := ovsdb.CheckOperationResults(reply, ops); err != nil {
+		return nil, fmt.Errorf("failed to select rows: %w", err)
+	}
+
+	g := &reproGraph{
+		schema:    schema,
+		rows:      make(map[string]reproRow),
+		names:     make(map[string]map[string][]string),
+		referrers: make(map[string][]string),
+		owners:    make(map[string][]string),
+	}
+	for i, table := range tables {
+		g.names[table] = make(map[string][]string)
+		for _, row := range reply[i].Rows {
+			id := rowUUID(row)
+			g.rows[id] = reproRow{table: table, row: row}
+			if name, ok := row["name"].(string); ok && name != "" {
+				g.names[table][name] = append(g.names[table][name], id)
+			}
+		}
+	}
+	for id, r := range g.rows {
+		for _, ref := range g.references(id) {
+			g.referrers[ref] = append(g.referrers[ref], id)
+		}
+		for column, value := range r.row {
+			if !strongRef(schema.Tables[r.table].Column(column)) {
+				continue
+			}
+			walkUUIDs(value, func(u string) {
+				g.owners[u] = append(g.owners[u], id)
+			})
+		}
+	}
+	for _, refs := range g.referrers {
+		sort.Strings(refs)
+	}
+	for _, refs := range g.owners {
+		sort.Strings(refs)
+	}
+	return g, nil
+}
+
+// strongRef reports whether a column holds strong references
+func strongRef(column *ovsdb.ColumnSchema) bool {
+	if column == nil || column.TypeObj == nil {
+		return false
+	}
+	for _, t := range []*ovsdb.BaseType{column.TypeObj.Key, column.TypeObj.Value} {
+		if t == nil || t.Type != ovsdb.TypeUUID {
+			continue
+		}
+		if refType, err := t.RefType(); err == nil && refType == ovsdb.Strong {
+			return true
+		}
+	}
+	return false
+}
+
+func rowUUID(row ovsdb.Row) string {
+	if u, ok := row["_uuid"].(ovsdb.UUID); ok {
+		return u.GoUUID
+	}
+	return ""
+}
+
+// references returns the rows that id references, either through a UUID
+// column or by name from a match expression
+func (g *reproGraph) references(id string) []string {
+	r := g.rows[id]
+	var refs []string
+	for column, value := range r.row {
+		if column == "_uuid" || column == "_version" {
+			continue
+		}
+		walkUUIDs(value, func(u string) {
+			if _, ok := g.rows[u]; ok {
+				refs = append(refs, u)
+			}
+		})
+	}
+	if match, ok := r.row["match"].(string); ok {
+		for _, m := range matchNameRef.FindAllStringSubmatch(match, -1) {
+			name := m[2]
+			if m[1] == "@" {
+				refs = append(refs, g.names[ovnnb.PortGroupTable][name]...)
+				continue
+			}
+			refs = append(refs, g.names[ovnnb.AddressSetTable][name]...)
+			// Port groups have generated address sets for their addresses
+			for _, suffix := range []string{"_ip4", "_ip6"} {
+				if pg, ok := strings.CutSuffix(name, suffix); ok {
+					refs = append(refs, g.names[ovnnb.PortGroupTable][pg]...)
+				}
+			}
+		}
+	}
+	sort.Strings(refs)
+	return refs
+}
+
+func walkUUIDs(value any, fn func(string)) {
+	switch v := value.(type) {
+	case ovsdb.UUID:
+		fn(v.GoUUID)
+	case ovsdb.OvsSet:
+		for _, e := range v.GoSet {
+			walkUUIDs(e, fn)
+		}
+	case ovsdb.OvsMap:
+		for k, e := range v.GoMap {
+			walkUUIDs(k, fn)
+			walkUUIDs(e, fn)
+		}
+	}
+}
+
+// closure returns the rows within depth references of seeds, in either
+// direction. Rows of non-root tables are garbage collected unless another
+// row holds a strong reference to them, so an owner is added beyond the
+// depth limit to keep the subset replayable.
+func (g *reproGraph) closure(seeds []string, depth int) map[string]bool {
+	included := make(map[string]bool)
+	distance := make(map[string]int)
+	queue := append([]string{}, seeds...)
+	for _, id := range seeds {
+		distance[id] = 0
+		included[id] = true
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if distance[id] >= depth {
+			continue
+		}
+		neighbours := append(g.references(id), g.referrers[id]...)
+		for _, n := range neighbours {
+			if included[n] {
+				continue
+			}
+			included[n] = true
+			distance[n] = distance[id] + 1
+			queue = append(queue, n)
+		}
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for id := range included {
+			table := g.rows[id].table
+			if g.schema.Tables[table].IsRoot || len(g.owners[id]) == 0 {
+				continue
+			}
+			owned := false
+			for _, r := range g.owners[id] {
+				if included[r] {
+					owned = true
+					break
+				}
+			}
+			if !owned {
+				included[g.owners[id][0]] = true
+				changed = true
+			}
+		}
+	}
+	return included
+}
+
+// transaction builds insert operations for the included rows. References
+// between included rows use named UUIDs, references to rows outside the
+// subset are dropped.
+func (g *reproGraph) transaction(included map[string]bool) ([]ovsdb.Operation, []string) {
+	ids := make([]string, 0, len(included))
+	for id := range included {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		ti, tj := g.rows[ids[i]].table, g.rows[ids[j]].table
+		if ti != tj {
+			return ti < tj
+		}
+		return ids[i] < ids[j]
+	})
+
+	named := make(map[string]string, len(ids))
+	for i, id := range ids {
+		named[id] = fmt.Sprintf("%s_%d", strings.ToLower(g.rows[id].table), i)
+	}
+
+	warnings := []string{}
+	ops := make([]ovsdb.Operation, 0, len(ids))
+	for _, id := range ids {
+		r := g.rows[id]
+		row := ovsdb.Row{}
+		for column, value := range r.row {
+			if column == "_uuid" || column == "_version" {
+				continue
+			}
+			v, ok := remapUUIDs(value, named)
+			if !ok {
+				warnings = append(warnings, fmt.Sprintf("%s %s column %s references a row outside the subset and was omitted", r.table, id, column))
+				continue
+			}
+			row[column] = v
+		}
+		ops = append(ops, ovsdb.Operation{
+			Op:       ovsdb.OperationInsert,
+			Table:    r.table,
+			Row:      row,
+			UUIDName: named[id],
+		})
+	}
+	return ops, warnings
+}
+
+// remapUUIDs replaces UUIDs of included rows with their named UUIDs and
+// drops any others. ok is false if value is a single UUID that was dropped.
+func remapUUIDs(value any, named map[string]string) (any, bool) {
+	switch v := value.(type) {
+	case ovsdb.UUID:
+		name, ok := named[v.GoUUID]
+		if !ok {
+			return nil, false
+		}
+		return ovsdb.UUID{GoUUID: name}, true
+	case ovsdb.OvsSet:
+		set := ovsdb.OvsSet{GoSet: []any{}}
+		for _, e := range v.GoSet {
+			if e, ok := remapUUIDs(e, named); ok {
+				set.GoSet = append(set.GoSet, e)
+			}
+		}
+		return set, true
+	case ovsdb.OvsMap:
+		m := ovsdb.OvsMap{GoMap: map[any]any{}}
+		for k, e := range v.GoMap {
+			k, ok := remapUUIDs(k, named)
+			if !ok {
+				continue
+			}
+			if e, ok := remapUUIDs(e, named); ok {
+				m.GoMap[k] = e
+			}
+		}
+		return m, true
+	}
+	return value, true
+}
+
+func (s *Server) MinimalRepro(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[MinimalReproArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	schema := ovnnb.DatabaseSchema()
+	if _, ok := schema.Tables[args.Table]; !ok {
+		return nil, fmt.Errorf("unknown table %q", args.Table)
+	}
+	if args.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	depth := args.Depth
+	if depth <= 0 {
+		depth = defaultReproDepth
+	}
+	if depth > maxReproDepth {
+		depth = maxReproDepth
+	}
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	g, err := loadReproGraph(ctx, client, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var seeds []string
+	if err := ovsdb.ValidateUUID(args.Name); err == nil {
+		if r, ok := g.rows[args.Name]; ok && r.table == args.Table {
+			seeds = append(seeds, args.Name)
+		}
+	} else {
+		seeds = g.names[args.Table][args.Name]
+	}
+	if len(seeds) == 0 {
+		return &mcpsdk.CallToolResultFor[map[string]any]{
+			IsError: true,
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: fmt.Sprintf("No %s found with name or UUID %s", args.Table, args.Name),
+				},
+			},
+		}, nil
+	}
+
+	included := g.closure(seeds, depth)
+	ops, warnings := g.transaction(included)
+
+	rows := make(map[string]int)
+	for id := range included {
+		rows[g.rows[id].table]++
+	}
+	transaction := []any{schema.Name}
+	for _, op := range ops {
+		transaction = append(transaction, op)
+	}
+
+	result := map[string]interface{}{
+		"table":       args.Table,
+		"name":        args.Name,
+		"depth":       depth,
+		"rows":        rows,
+		"transaction": transaction,
+		"warnings":    warnings,
+		"count":       len(ops),
+		"context":     "The transaction inserts the target and every row within depth references of it, following UUID references in both directions and address set and port group names in match expressions. Rows of non-root tables also bring the row that owns them, so they are not garbage collected. Replay it against an empty NB database with: ovsdb-client transact <endpoint> '<transaction>'. Review it for sensitive data before sharing.",
+	}
+
+	return mcp.NewResult(result)
+}
@@ -0,0 +1,203 @@
+package ovnnb
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"sort"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type CheckRouterNetworksArgs struct {
+	Router     string `json:"router" jsonschema:"the name of the logical router to check"`
+	CrossCheck bool   `json:"cross_check,omitempty" jsonschema:"also check the router's networks against the ports of every other logical router"`
+}
+
+// Kinds of network conflict between router ports
+const (
+	networkDuplicate = "duplicate"
+	networkOverlap   = "overlap"
+)
+
+// NetworkConflict is a pair of router port networks whose subnets overlap
+type NetworkConflict struct {
+	Kind         string `json:"kind"`
+	Port         string `json:"port"`
+	Network      string `json:"network"`
+	OtherRouter  string `json:"other_router"`
+	OtherPort    string `json:"other_port"`
+	OtherNetwork string `json:"other_network"`
+}
+
+// portNetwork is one parsed network of a logical router port
+type portNetwork struct {
+	router  string
+	port    ovnnb.LogicalRouterPort
+	network string
+	prefix  netip.Prefix
+}
+
+// parsePortNetworks parses the networks of a router's ports. Networks are the
+// port's address and prefix length, e.g. 10.0.0.1/24.
+func parsePortNetworks(router string, ports []ovnnb.LogicalRouterPort) ([]portNetwork, []string) {
+	var networks []portNetwork
+	var invalid []string
+	for _, lrp := range ports {
+		for _, network := range lrp.Networks {
+			prefix, err := netip.ParsePrefix(network)
+			if err != nil {
+				invalid = append(invalid, fmt.Sprintf("port %s has an invalid network %q: %v", lrp.Name, network, err))
+				continue
+			}
+			networks = append(networks, portNetwork{
+				router:  router,
+				port:    lrp,
+				network: network,
+				prefix:  prefix.Masked(),
+			})
+		}
+	}
+	return networks, invalid
+}
+
+// conflict compares two port networks. Peer router ports share the subnet
+// that connects them, so they are not a conflict.
+func conflict(a, b portNetwork) (NetworkConflict, bool) {
+	if a.port.UUID == b.port.UUID && a.network == b.network {
+		return NetworkConflict{}, false
+	}
+	if (a.port.Peer != nil && *a.port.Peer == b.port.Name) || (b.port.Peer != nil && *b.port.Peer == a.port.Name) {
+		return NetworkConflict{}, false
+	}
+	if !a.prefix.Overlaps(b.prefix) {
+		return NetworkConflict{}, false
+	}
+	kind := networkOverlap
+	if a.prefix == b.prefix {
+		kind = networkDuplicate
+	}
+	return NetworkConflict{
+		Kind:         kind,
+		Port:         a.port.Name,
+		Network:      a.network,
+		OtherRouter:  b.router,
+		OtherPort:    b.port.Name,
+		OtherNetwork: b.network,
+	}, true
+}
+
+func (s *Server) CheckRouterNetworks(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[CheckRouterNetworksArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	if args.Router == "" {
+		return nil, fmt.Errorf("router is required")
+	}
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	routerModel := &ovnnb.LogicalRouter{}
+	var conditions []model.Condition
+	if !args.CrossCheck {
+		conditions = append(conditions, model.Condition{
+			Field:    &routerModel.Name,
+			Function: ovsdb.ConditionEqual,
+			Value:    args.Router,
+		})
+	}
+	routers, err := mcp.ExecuteSelectQuery(ctx, client, routerModel, conditions...)
+	if err != nil {
+		return nil, err
+	}
+	idx := slices.IndexFunc(routers, func(lr ovnnb.LogicalRouter) bool { return lr.Name == args.Router })
+	if idx < 0 {
+		return &mcpsdk.CallToolResultFor[map[string]any]{
+			IsError: true,
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: fmt.Sprintf("No logical router found with name %s", args.Router),
+				},
+			},
+		}, nil
+	}
+	router := routers[idx]
+
+	routerPorts, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.LogicalRouterPort{})
+	if err != nil {
+		return nil, err
+	}
+	lrpByUUID := make(map[string]ovnnb.LogicalRouterPort, len(routerPorts))
+	for _, lrp := range routerPorts {
+		lrpByUUID[lrp.UUID] = lrp
+	}
+	portsOf := func(lr ovnnb.LogicalRouter) []ovnnb.LogicalRouterPort {
+		var ports []ovnnb.LogicalRouterPort
+		for _, uuid := range lr.Ports {
+			if lrp, ok := lrpByUUID[uuid]; ok {
+				ports = append(ports, lrp)
+			}
+		}
+		sort.Slice(ports, func(i, j int) bool { return ports[i].Name < ports[j].Name })
+		return ports
+	}
+
+	ports := portsOf(router)
+	networks, invalid := parsePortNetworks(router.Name, ports)
+	if invalid == nil {
+		invalid = []string{}
+	}
+
+	conflicts := []NetworkConflict{}
+	for i := range networks {
+		for j := i + 1; j < len(networks); j++ {
+			if c, ok := conflict(networks[i], networks[j]); ok {
+				conflicts = append(conflicts, c)
+			}
+		}
+	}
+	if args.CrossCheck {
+		for _, other := range routers {
+			if other.UUID == router.UUID {
+				continue
+			}
+			// Invalid networks of other routers are not reported, only
+			// those of the router being checked
+			otherNetworks, _ := parsePortNetworks(other.Name, portsOf(other))
+			for _, a := range networks {
+				for _, b := range otherNetworks {
+					if c, ok := conflict(a, b); ok {
+						conflicts = append(conflicts, c)
+					}
+				}
+			}
+		}
+	}
+
+	portNetworks := make([]map[string]any, 0, len(ports))
+	for _, lrp := range ports {
+		portNetworks = append(portNetworks, map[string]any{
+			"port":     lrp.Name,
+			"networks": lrp.Networks,
+		})
+	}
+
+	result := map[string]interface{}{
+		"router":    router.Name,
+		"ports":     portNetworks,
+		"conflicts": conflicts,
+		"invalid":   invalid,
+		"count":     len(conflicts),
+		"context":   "Each router port's networks are compared as subnets. Two ports of the same router with overlapping subnets make routing ambiguous: the router has a connected route for the same destination through more than one port, and which one is used depends on prefix length and is rarely what was intended. A duplicate is the same subnet on both ports, an overlap is one subnet containing the other. With cross_check, subnets on other routers are also compared; these only matter if the routers are connected or share address space, as isolated tenant routers may legitimately reuse subnets. The subnet shared by peer router ports is expected and not reported.",
+	}
+
+	return mcp.NewResult(result)
+}
@@ -0,0 +1,262 @@
+package ovnnb
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type ValidateRouterPortSubnetsArgs struct {
+	Router string `json:"router" jsonschema:"the name of the logical router to validate"`
+}
+
+// Kinds of disagreement between a router port and its switch's subnets
+const (
+	subnetMismatch       = "subnet_mismatch"
+	subnetPrefixMismatch = "prefix_length_mismatch"
+	subnetMissingGateway = "missing_gateway_ip"
+	subnetInvalid        = "invalid"
+)
+
+// Status of a router port's validation
+const (
+	subnetsOK         = "ok"
+	subnetsMismatch   = "mismatch"
+	subnetsNotChecked = "not_checked"
+)
+
+// SubnetFinding is a disagreement between a router port network and a
+// subnet of the switch it is connected to
+type SubnetFinding struct {
+	Kind    string `json:"kind"`
+	Switch  string `json:"switch,omitempty"`
+	Subnet  string `json:"subnet,omitempty"`
+	Network string `json:"network,omitempty"`
+	Detail  string `json:"detail"`
+}
+
+// RouterPortSubnets is the validation of one router port
+type RouterPortSubnets struct {
+	Port     string          `json:"port"`
+	Networks []string        `json:"networks"`
+	Switches []string        `json:"switches"`
+	Subnets  []string        `json:"subnets"`
+	Status   string          `json:"status"`
+	Findings []SubnetFinding `json:"findings"`
+}
+
+// parseSwitchSubnet parses a subnet from a switch's other_config. The
+// ipv6_prefix key holds a /64 prefix, which may be given without a length.
+func parseSwitchSubnet(key, value string) (netip.Prefix, error) {
+	if key == "ipv6_prefix" && !strings.Contains(value, "/") {
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return netip.PrefixFrom(addr, 64).Masked(), nil
+	}
+	prefix, err := netip.ParsePrefix(value)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return prefix.Masked(), nil
+}
+
+// validateSubnet compares a switch subnet with the router port networks
+// of the same address family
+func validateSubnet(ls string, subnet netip.Prefix, networks []netip.Prefix) *SubnetFinding {
+	var family []netip.Prefix
+	for _, n := range networks {
+		if n.Addr().Is4() == subnet.Addr().Is4() {
+			family = append(family, n)
+		}
+	}
+	if len(family) == 0 {
+		return &SubnetFinding{
+			Kind:   subnetMissingGateway,
+			Switch: ls,
+			Subnet: subnet.String(),
+			Detail: fmt.Sprintf("The router port has no network in switch %s's subnet %s, so the router has no gateway IP for it and won't route for addresses assigned from it", ls, subnet),
+		}
+	}
+	// A network in the subnet with another prefix length is only reported
+	// when no other network matches the subnet exactly
+	var inside *netip.Prefix
+	for _, n := range family {
+		if !subnet.Contains(n.Addr()) {
+			continue
+		}
+		if n.Bits() == subnet.Bits() {
+			return nil
+		}
+		if inside == nil {
+			inside = &n
+		}
+	}
+	if inside != nil {
+		return &SubnetFinding{
+			Kind:    subnetPrefixMismatch,
+			Switch:  ls,
+			Subnet:  subnet.String(),
+			Network: inside.String(),
+			Detail:  fmt.Sprintf("The gateway IP %s is in switch %s's subnet %s but has a /%d prefix, so the router's connected route does not match the subnet addresses are assigned from", inside.Addr(), ls, subnet, inside.Bits()),
+		}
+	}
+	names := make([]string, 0, len(family))
+	for _, n := range family {
+		names = append(names, n.String())
+	}
+	return &SubnetFinding{
+		Kind:    subnetMismatch,
+		Switch:  ls,
+		Subnet:  subnet.String(),
+		Network: strings.Join(names, ", "),
+		Detail:  fmt.Sprintf("None of the router port networks %s are in switch %s's subnet %s, so addresses assigned from the subnet are not routed by the router", strings.Join(names, ", "), ls, subnet),
+	}
+}
+
+func (s *Server) ValidateRouterPortSubnets(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ValidateRouterPortSubnetsArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	if args.Router == "" {
+		return nil, fmt.Errorf("router is required")
+	}
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	routerModel := &ovnnb.LogicalRouter{}
+	routers, err := mcp.ExecuteSelectQuery(ctx, client, routerModel, model.Condition{
+		Field:    &routerModel.Name,
+		Function: ovsdb.ConditionEqual,
+		Value:    args.Router,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(routers) == 0 {
+		return &mcpsdk.CallToolResultFor[map[string]any]{
+			IsError: true,
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: fmt.Sprintf("No logical router found with name %s", args.Router),
+				},
+			},
+		}, nil
+	}
+	router := routers[0]
+
+	lrp := &ovnnb.LogicalRouterPort{}
+	ports, err := mcp.ExecuteSelectByUUIDs(ctx, client, lrp, &lrp.UUID, router.Ports)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i].Name < ports[j].Name })
+
+	switches, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.LogicalSwitch{})
+	if err != nil {
+		return nil, err
+	}
+	switchPorts, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.LogicalSwitchPort{})
+	if err != nil {
+		return nil, err
+	}
+	// The switches attached to each router port, keyed by port name
+	lspByUUID := make(map[string]ovnnb.LogicalSwitchPort, len(switchPorts))
+	for _, lsp := range switchPorts {
+		lspByUUID[lsp.UUID] = lsp
+	}
+	attached := make(map[string][]ovnnb.LogicalSwitch)
+	for _, ls := range switches {
+		for _, u := range ls.Ports {
+			if lsp, ok := lspByUUID[u]; ok && lsp.Type == "router" && lsp.Options["router-port"] != "" {
+				attached[lsp.Options["router-port"]] = append(attached[lsp.Options["router-port"]], ls)
+			}
+		}
+	}
+
+	results := make([]RouterPortSubnets, 0, len(ports))
+	mismatches := 0
+	for _, port := range ports {
+		result := RouterPortSubnets{
+			Port:     port.Name,
+			Networks: port.Networks,
+			Switches: []string{},
+			Subnets:  []string{},
+			Findings: []SubnetFinding{},
+		}
+		if result.Networks == nil {
+			result.Networks = []string{}
+		}
+
+		var networks []netip.Prefix
+		for _, network := range port.Networks {
+			prefix, err := netip.ParsePrefix(network)
+			if err != nil {
+				result.Findings = append(result.Findings, SubnetFinding{
+					Kind:    subnetInvalid,
+					Network: network,
+					Detail:  fmt.Sprintf("The router port network %q is not an IP address and prefix length: %v", network, err),
+				})
+				continue
+			}
+			networks = append(networks, prefix)
+		}
+
+		checked := false
+		for _, ls := range attached[port.Name] {
+			result.Switches = append(result.Switches, ls.Name)
+			for _, key := range []string{"subnet", "ipv6_prefix"} {
+				value := ls.OtherConfig[key]
+				if value == "" {
+					continue
+				}
+				result.Subnets = append(result.Subnets, value)
+				subnet, err := parseSwitchSubnet(key, value)
+				if err != nil {
+					result.Findings = append(result.Findings, SubnetFinding{
+						Kind:   subnetInvalid,
+						Switch: ls.Name,
+						Subnet: value,
+						Detail: fmt.Sprintf("Switch %s's other_config:%s %q is not a valid subnet: %v", ls.Name, key, value, err),
+					})
+					continue
+				}
+				checked = true
+				if finding := validateSubnet(ls.Name, subnet, networks); finding != nil {
+					result.Findings = append(result.Findings, *finding)
+				}
+			}
+		}
+
+		switch {
+		case len(result.Findings) > 0:
+			result.Status = subnetsMismatch
+			mismatches++
+		case checked:
+			result.Status = subnetsOK
+		default:
+			result.Status = subnetsNotChecked
+		}
+		results = append(results, result)
+	}
+
+	return mcp.NewResult(map[string]interface{}{
+		"router":     router.Name,
+		"ports":      results,
+		"count":      len(results),
+		"mismatches": mismatches,
+		"context":    "Each router port's networks are compared with the subnets configured for IPAM in the other_config (subnet and ipv6_prefix) of the switches it is attached to through a switch port of type router. Every switch subnet needs a router port network inside it with the same prefix length: that address is the gateway IP of the pods or VMs on the switch, and the router only routes for addresses within its port networks. A missing_gateway_ip finding means the port has no network of the subnet's address family, subnet_mismatch means its networks are outside the subnet and prefix_length_mismatch means the gateway IP is in the subnet but the prefix lengths differ. Ports that are not attached to a switch, e.g. those peered with another router, or whose switches have no subnet configured are not_checked.",
+	})
+}
@@ -0,0 +1,400 @@
+package ovnnb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type GatewayRouterServicesArgs struct {
+	Router string `json:"router" jsonschema:"the name of the logical router"`
+}
+
+// ServiceNAT is a NAT rule of a router
+type ServiceNAT struct {
+	Type              string `json:"type"`
+	ExternalIP        string `json:"external_ip"`
+	LogicalIP         string `json:"logical_ip"`
+	ExternalPortRange string `json:"external_port_range,omitempty"`
+	LogicalPort       string `json:"logical_port,omitempty"`
+	ExternalMAC       string `json:"external_mac,omitempty"`
+	GatewayPort       string `json:"gateway_port,omitempty"`
+	Distributed       bool   `json:"distributed"`
+}
+
+// ServiceLoadBalancer is a load balancer applied to a router, directly or
+// through a load balancer group
+type ServiceLoadBalancer struct {
+	Name     string            `json:"name"`
+	Protocol string            `json:"protocol,omitempty"`
+	VIPs     map[string]string `json:"vips"`
+	Group    string            `json:"group,omitempty"`
+}
+
+// ServiceRoute is a static route of a router
+type ServiceRoute struct {
+	IPPrefix   string `json:"ip_prefix"`
+	Nexthop    string `json:"nexthop"`
+	OutputPort string `json:"output_port,omitempty"`
+	Policy     string `json:"policy,omitempty"`
+	RouteTable string `json:"route_table,omitempty"`
+	BFD        bool   `json:"bfd"`
+}
+
+// ServiceGatewayPort is a router port scheduled on gateway chassis
+type ServiceGatewayPort struct {
+	Port           string                   `json:"port"`
+	Networks       []string                 `json:"networks"`
+	GatewayChassis []GatewayChassisPriority `json:"gateway_chassis,omitempty"`
+	HAChassisGroup string                   `json:"ha_chassis_group,omitempty"`
+	HAChassis      []GatewayChassisPriority `json:"ha_chassis,omitempty"`
+}
+
+// ServiceDNS is the DNS records served on a switch attached to a router
+type ServiceDNS struct {
+	Switch  string            `json:"switch"`
+	Records map[string]string `json:"records"`
+}
+
+// routerNATs returns the NAT rules of a router
+func routerNATs(ctx context.Context, c client.Client, router ovnnb.LogicalRouter) ([]ServiceNAT, error) {
+	natModel := &ovnnb.NAT{}
+	natRules, err := mcp.ExecuteSelectByUUIDs(ctx, c, natModel, &natModel.UUID, router.Nat)
+	if err != nil {
+		return nil, err
+	}
+	nats := make([]ServiceNAT, 0, len(natRules))
+	for _, nat := range natRules {
+		n := ServiceNAT{
+			Type:              string(nat.Type),
+			ExternalIP:        nat.ExternalIP,
+			LogicalIP:         nat.LogicalIP,
+			ExternalPortRange: nat.ExternalPortRange,
+			Distributed:       natDistributed(nat),
+		}
+		if nat.LogicalPort != nil {
+			n.LogicalPort = *nat.LogicalPort
+		}
+		if nat.ExternalMAC != nil {
+			n.ExternalMAC = *nat.ExternalMAC
+		}
+		if nat.GatewayPort != nil {
+			n.GatewayPort = *nat.GatewayPort
+		}
+		nats = append(nats, n)
+	}
+	sort.Slice(nats, func(i, j int) bool {
+		if nats[i].Type != nats[j].Type {
+			return nats[i].Type < nats[j].Type
+		}
+		if nats[i].ExternalIP != nats[j].ExternalIP {
+			return nats[i].ExternalIP < nats[j].ExternalIP
+		}
+		return nats[i].LogicalIP < nats[j].LogicalIP
+	})
+	return nats, nil
+}
+
+// routerLoadBalancers returns the load balancers applied to a router
+func routerLoadBalancers(ctx context.Context, c client.Client, router ovnnb.LogicalRouter) ([]ServiceLoadBalancer, error) {
+	// The load balancers of the router, with the group they were applied
+	// through, keyed by UUID
+	lbGroups := make(map[string]string)
+	for _, u := range router.LoadBalancer {
+		lbGroups[u] = ""
+	}
+	groupModel := &ovnnb.LoadBalancerGroup{}
+	groups, err := mcp.ExecuteSelectByUUIDs(ctx, c, groupModel, &groupModel.UUID, router.LoadBalancerGroup)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		for _, u := range g.LoadBalancer {
+			if _, ok := lbGroups[u]; !ok {
+				lbGroups[u] = g.Name
+			}
+		}
+	}
+	lbUUIDs := make([]string, 0, len(lbGroups))
+	for u := range lbGroups {
+		lbUUIDs = append(lbUUIDs, u)
+	}
+	lbModel := &ovnnb.LoadBalancer{}
+	lbs, err := mcp.ExecuteSelectByUUIDs(ctx, c, lbModel, &lbModel.UUID, lbUUIDs)
+	if err != nil {
+		return nil, err
+	}
+	loadBalancers := make([]ServiceLoadBalancer, 0, len(lbs))
+	for _, lb := range lbs {
+		l := ServiceLoadBalancer{
+			Name:  lb.Name,
+			VIPs:  lb.Vips,
+			Group: lbGroups[lb.UUID],
+		}
+		if l.VIPs == nil {
+			l.VIPs = map[string]string{}
+		}
+		if lb.Protocol != nil {
+			l.Protocol = string(*lb.Protocol)
+		}
+		loadBalancers = append(loadBalancers, l)
+	}
+	sort.Slice(loadBalancers, func(i, j int) bool { return loadBalancers[i].Name < loadBalancers[j].Name })
+	return loadBalancers, nil
+}
+
+// routerStaticRoutes returns the static routes of a router
+func routerStaticRoutes(ctx context.Context, c client.Client, router ovnnb.LogicalRouter) ([]ServiceRoute, error) {
+	routeModel := &ovnnb.LogicalRouterStaticRoute{}
+	staticRoutes, err := mcp.ExecuteSelectByUUIDs(ctx, c, routeModel, &routeModel.UUID, router.StaticRoutes)
+	if err != nil {
+		return nil, err
+	}
+	routes := make([]ServiceRoute, 0, len(staticRoutes))
+	for _, route := range staticRoutes {
+		r := ServiceRoute{
+			IPPrefix:   route.IPPrefix,
+			Nexthop:    route.Nexthop,
+			RouteTable: route.RouteTable,
+			BFD:        route.BFD != nil,
+		}
+		if route.OutputPort != nil {
+			r.OutputPort = *route.OutputPort
+		}
+		if route.Policy != nil {
+			r.Policy = string(*route.Policy)
+		}
+		routes = append(routes, r)
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].RouteTable != routes[j].RouteTable {
+			return routes[i].RouteTable < routes[j].RouteTable
+		}
+		return routes[i].IPPrefix < routes[j].IPPrefix
+	})
+	return routes, nil
+}
+
+// routerGatewayPorts returns the ports of a router scheduled on gateway chassis
+func routerGatewayPorts(ctx context.Context, c client.Client, router ovnnb.LogicalRouter) ([]ServiceGatewayPort, error) {
+	lrp := &ovnnb.LogicalRouterPort{}
+	ports, err := mcp.ExecuteSelectByUUIDs(ctx, c, lrp, &lrp.UUID, router.Ports)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i].Name < ports[j].Name })
+	gatewayChassis, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.GatewayChassis{})
+	if err != nil {
+		return nil, err
+	}
+	haGroups, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.HAChassisGroup{})
+	if err != nil {
+		return nil, err
+	}
+	haChassis, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.HAChassis{})
+	if err != nil {
+		return nil, err
+	}
+	gcByUUID := make(map[string]ovnnb.GatewayChassis, len(gatewayChassis))
+	for _, gc := range gatewayChassis {
+		gcByUUID[gc.UUID] = gc
+	}
+	groupByUUID := make(map[string]ovnnb.HAChassisGroup, len(haGroups))
+	for _, g := range haGroups {
+		groupByUUID[g.UUID] = g
+	}
+	hcByUUID := make(map[string]ovnnb.HAChassis, len(haChassis))
+	for _, hc := range haChassis {
+		hcByUUID[hc.UUID] = hc
+	}
+	gatewayPorts := []ServiceGatewayPort{}
+	for _, port := range ports {
+		if len(port.GatewayChassis) == 0 && port.HaChassisGroup == nil {
+			continue
+		}
+		gp := ServiceGatewayPort{
+			Port:     port.Name,
+			Networks: port.Networks,
+		}
+		if gp.Networks == nil {
+			gp.Networks = []string{}
+		}
+		for _, u := range port.GatewayChassis {
+			if gc, ok := gcByUUID[u]; ok {
+				gp.GatewayChassis = append(gp.GatewayChassis, GatewayChassisPriority{Chassis: gc.ChassisName, Priority: gc.Priority})
+			}
+		}
+		sortByPriority(gp.GatewayChassis)
+		if port.HaChassisGroup != nil {
+			if g, ok := groupByUUID[*port.HaChassisGroup]; ok {
+				gp.HAChassisGroup = g.Name
+				for _, u := range g.HaChassis {
+					if hc, ok := hcByUUID[u]; ok {
+						gp.HAChassis = append(gp.HAChassis, GatewayChassisPriority{Chassis: hc.ChassisName, Priority: hc.Priority})
+					}
+				}
+				sortByPriority(gp.HAChassis)
+			}
+		}
+		gatewayPorts = append(gatewayPorts, gp)
+	}
+	return gatewayPorts, nil
+}
+
+// routerDNS returns the DNS records served by the switches attached to a
+// router
+func routerDNS(ctx context.Context, c client.Client, router ovnnb.LogicalRouter) ([]ServiceDNS, error) {
+	lrp := &ovnnb.LogicalRouterPort{}
+	ports, err := mcp.ExecuteSelectByUUIDs(ctx, c, lrp, &lrp.UUID, router.Ports)
+	if err != nil {
+		return nil, err
+	}
+	portNames := make([]string, 0, len(ports))
+	for _, port := range ports {
+		portNames = append(portNames, port.Name)
+	}
+	switches, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.LogicalSwitch{})
+	if err != nil {
+		return nil, err
+	}
+	switchPorts, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.LogicalSwitchPort{})
+	if err != nil {
+		return nil, err
+	}
+	dnsRows, err := mcp.ExecuteSelectQuery(ctx, c, &ovnnb.DNS{})
+	if err != nil {
+		return nil, err
+	}
+	lspByUUID := make(map[string]ovnnb.LogicalSwitchPort, len(switchPorts))
+	for _, lsp := range switchPorts {
+		lspByUUID[lsp.UUID] = lsp
+	}
+	dnsByUUID := make(map[string]ovnnb.DNS, len(dnsRows))
+	for _, dns := range dnsRows {
+		dnsByUUID[dns.UUID] = dns
+	}
+	dnsRecords := []ServiceDNS{}
+	for _, ls := range switches {
+		attached := slices.ContainsFunc(ls.Ports, func(u string) bool {
+			lsp, ok := lspByUUID[u]
+			return ok && lsp.Type == "router" && slices.Contains(portNames, lsp.Options["router-port"])
+		})
+		if !attached {
+			continue
+		}
+		records := map[string]string{}
+		for _, u := range ls.DNSRecords {
+			for host, ips := range dnsByUUID[u].Records {
+				records[host] = ips
+			}
+		}
+		if len(records) > 0 {
+			dnsRecords = append(dnsRecords, ServiceDNS{Switch: ls.Name, Records: records})
+		}
+	}
+	sort.Slice(dnsRecords, func(i, j int) bool { return dnsRecords[i].Switch < dnsRecords[j].Switch })
+	return dnsRecords, nil
+}
+
+func (s *Server) GatewayRouterServices(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[GatewayRouterServicesArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	if args.Router == "" {
+		return nil, fmt.Errorf("router is required")
+	}
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	routerModel := &ovnnb.LogicalRouter{}
+	routers, err := mcp.ExecuteSelectQuery(ctx, client, routerModel, model.Condition{
+		Field:    &routerModel.Name,
+		Function: ovsdb.ConditionEqual,
+		Value:    args.Router,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(routers) == 0 {
+		return &mcpsdk.CallToolResultFor[map[string]any]{
+			IsError: true,
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: fmt.Sprintf("No logical router found with name %s", args.Router),
+				},
+			},
+		}, nil
+	}
+	router := routers[0]
+
+	// Each concern is gathered separately, so that one failing, e.g. as
+	// its table is missing from an older schema, still returns the others
+	var parts mcp.Parts
+	nats := []ServiceNAT{}
+	parts.Gather("nat", func() (err error) {
+		nats, err = routerNATs(ctx, client, router)
+		return err
+	})
+	loadBalancers := []ServiceLoadBalancer{}
+	parts.Gather("load_balancers", func() (err error) {
+		loadBalancers, err = routerLoadBalancers(ctx, client, router)
+		return err
+	})
+	routes := []ServiceRoute{}
+	parts.Gather("static_routes", func() (err error) {
+		routes, err = routerStaticRoutes(ctx, client, router)
+		return err
+	})
+	gatewayPorts := []ServiceGatewayPort{}
+	parts.Gather("gateway_ports", func() (err error) {
+		gatewayPorts, err = routerGatewayPorts(ctx, client, router)
+		return err
+	})
+	dnsRecords := []ServiceDNS{}
+	parts.Gather("dns", func() (err error) {
+		dnsRecords, err = routerDNS(ctx, client, router)
+		return err
+	})
+	if err := parts.Err(); err != nil {
+		return nil, err
+	}
+	vipCount := 0
+	for _, lb := range loadBalancers {
+		vipCount += len(lb.VIPs)
+	}
+
+	result := map[string]interface{}{
+		"router":         router.Name,
+		"nat":            nats,
+		"load_balancers": loadBalancers,
+		"static_routes":  routes,
+		"gateway_ports":  gatewayPorts,
+		"dns":            dnsRecords,
+		"counts": map[string]int{
+			"nat":            len(nats),
+			"load_balancers": len(loadBalancers),
+			"vips":           vipCount,
+			"static_routes":  len(routes),
+			"gateway_ports":  len(gatewayPorts),
+		},
+		"context": "Everything about how the router exposes and translates traffic, keyed by concern. chassis is only set for a gateway router, which is bound to that chassis by options:chassis. nat lists its SNAT, DNAT and dnat_and_snat rules; distributed rules are handled on the chassis of their logical_port rather than the gateway chassis. load_balancers are those applied to the router directly or through the load balancer group named in group, with their VIPs mapped to backends. static_routes are the router's static routes, with bfd true when the route is monitored with BFD. gateway_ports are the router ports scheduled on gateway chassis, highest priority first, which is where centralized NAT and load balancing happen. dns lists the DNS records served on the switches attached to the router. When partial is true some of these could not be read, failed_parts says which and why, and they are returned empty.",
+	}
+	if chassis := router.Options["chassis"]; chassis != "" {
+		// A gateway router is bound to a single chassis
+		result["chassis"] = chassis
+	}
+	parts.Apply(result)
+
+	return mcp.NewResult(result)
+}
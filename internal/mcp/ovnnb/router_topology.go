@@ -0,0 +1,197 @@
+package ovnnb
+
+import (
+	"context"
+	"net/netip"
+	"slices"
+	"sort"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type TopologyByRouterArgs struct {
+}
+
+// AttachedSwitch is a logical switch in the topology tree
+type AttachedSwitch struct {
+	Name string `json:"name"`
+	UUID string `json:"uuid"`
+	// RouterPort is the router port the switch is attached through, empty
+	// for standalone switches
+	RouterPort string `json:"router_port,omitempty"`
+	// Subnets are the subnets of the router port on the switch and those
+	// configured for IPAM in the switch's other_config
+	Subnets   []string `json:"subnets"`
+	PortCount int      `json:"port_count"`
+}
+
+// RouterTopology is a logical router and the switches attached to it
+type RouterTopology struct {
+	Name     string           `json:"name"`
+	UUID     string           `json:"uuid"`
+	Switches []AttachedSwitch `json:"switches"`
+	// Routers are the routers connected directly through peer router ports
+	Routers []string `json:"routers"`
+}
+
+// switchSubnets returns the IPAM subnets of a logical switch
+func switchSubnets(ls ovnnb.LogicalSwitch) []string {
+	var subnets []string
+	for _, key := range []string{"subnet", "ipv6_prefix"} {
+		if subnet := ls.OtherConfig[key]; subnet != "" {
+			subnets = append(subnets, subnet)
+		}
+	}
+	return subnets
+}
+
+func (s *Server) TopologyByRouter(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[TopologyByRouterArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	switches, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.LogicalSwitch{})
+	if err != nil {
+		return nil, err
+	}
+	switchPorts, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.LogicalSwitchPort{})
+	if err != nil {
+		return nil, err
+	}
+	routers, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.LogicalRouter{})
+	if err != nil {
+		return nil, err
+	}
+	routerPorts, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.LogicalRouterPort{})
+	if err != nil {
+		return nil, err
+	}
+
+	lspByUUID := make(map[string]ovnnb.LogicalSwitchPort, len(switchPorts))
+	for _, lsp := range switchPorts {
+		lspByUUID[lsp.UUID] = lsp
+	}
+	lrpByUUID := make(map[string]ovnnb.LogicalRouterPort, len(routerPorts))
+	lrpByName := make(map[string]ovnnb.LogicalRouterPort, len(routerPorts))
+	for _, lrp := range routerPorts {
+		lrpByUUID[lrp.UUID] = lrp
+		lrpByName[lrp.Name] = lrp
+	}
+	// The UUID of the router owning each router port, keyed by port name
+	lrpRouter := make(map[string]string)
+	for _, lr := range routers {
+		for _, u := range lr.Ports {
+			if lrp, ok := lrpByUUID[u]; ok {
+				lrpRouter[lrp.Name] = lr.UUID
+			}
+		}
+	}
+
+	routerNames := make(map[string]string, len(routers))
+	for _, lr := range routers {
+		routerNames[lr.UUID] = lr.Name
+	}
+	tree := make(map[string]*RouterTopology, len(routers))
+	for _, lr := range routers {
+		node := &RouterTopology{
+			Name:     lr.Name,
+			UUID:     lr.UUID,
+			Switches: []AttachedSwitch{},
+			Routers:  []string{},
+		}
+		for _, u := range lr.Ports {
+			lrp, ok := lrpByUUID[u]
+			if !ok || lrp.Peer == nil {
+				continue
+			}
+			if peer, ok := lrpRouter[*lrp.Peer]; ok && !slices.Contains(node.Routers, routerNames[peer]) {
+				node.Routers = append(node.Routers, routerNames[peer])
+			}
+		}
+		sort.Strings(node.Routers)
+		tree[lr.UUID] = node
+	}
+
+	standalone := []AttachedSwitch{}
+	for _, ls := range switches {
+		attached := false
+		portCount := 0
+		for _, u := range ls.Ports {
+			lsp, ok := lspByUUID[u]
+			if !ok {
+				continue
+			}
+			if lsp.Type != "router" {
+				portCount++
+			}
+		}
+		for _, u := range ls.Ports {
+			lsp, ok := lspByUUID[u]
+			if !ok || lsp.Type != "router" {
+				continue
+			}
+			name := lsp.Options["router-port"]
+			router, ok := lrpRouter[name]
+			if !ok {
+				continue
+			}
+			subnets := []string{}
+			for _, network := range lrpByName[name].Networks {
+				if prefix, err := netip.ParsePrefix(network); err == nil {
+					network = prefix.Masked().String()
+				}
+				if !slices.Contains(subnets, network) {
+					subnets = append(subnets, network)
+				}
+			}
+			for _, subnet := range switchSubnets(ls) {
+				if !slices.Contains(subnets, subnet) {
+					subnets = append(subnets, subnet)
+				}
+			}
+			tree[router].Switches = append(tree[router].Switches, AttachedSwitch{
+				Name:       ls.Name,
+				UUID:       ls.UUID,
+				RouterPort: name,
+				Subnets:    subnets,
+				PortCount:  portCount,
+			})
+			attached = true
+		}
+		if !attached {
+			subnets := switchSubnets(ls)
+			if subnets == nil {
+				subnets = []string{}
+			}
+			standalone = append(standalone, AttachedSwitch{
+				Name:      ls.Name,
+				UUID:      ls.UUID,
+				Subnets:   subnets,
+				PortCount: portCount,
+			})
+		}
+	}
+
+	byName := func(switches []AttachedSwitch) {
+		sort.Slice(switches, func(i, j int) bool { return switches[i].Name < switches[j].Name })
+	}
+	result := make([]RouterTopology, 0, len(tree))
+	for _, node := range tree {
+		byName(node.Switches)
+		result = append(result, *node)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	byName(standalone)
+
+	return mcp.NewResult(map[string]interface{}{
+		"routers":             result,
+		"standalone_switches": standalone,
+		"router_count":        len(result),
+		"switch_count":        len(switches),
+		"context":             "Logical switches grouped by the logical router they are attached to through a router port, which usually corresponds to a tenant or network. A switch attached to several routers is listed under each of them. Subnets are the networks of the router port on the switch plus any IPAM subnet from the switch's other_config; port_count excludes the ports connecting the switch to routers. routers lists the other routers connected directly through peer router ports. Standalone switches are not attached to any router and are only reachable at layer 2, e.g. through a localnet port.",
+	})
+}
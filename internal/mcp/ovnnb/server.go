@@ -2,14 +2,13 @@ package ovnnb
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/dave-tucker/ariadne/internal/mcp"
 	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
 	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
-	"github.com/ovn-kubernetes/libovsdb/client"
 	"github.com/ovn-kubernetes/libovsdb/model"
 	"github.com/ovn-kubernetes/libovsdb/ovsdb"
 )
@@ -17,816 +16,1542 @@ import (
 const defaultEndpoint = "unix:/var/run/ovn/ovnnb_db.sock"
 
 type Server struct {
-	*mcpsdk.Server
-	dbModel    model.ClientDBModel
-	httpServer *http.Server
+	*mcp.BaseServer
 }
 
 type ListLogicalSwitchesArgs struct {
-	NameFilter string `json:"name_filter" jsonschema:"the name of the logical switch to filter by"`
+	NameFilter  string `json:"name_filter" jsonschema:"the name of the logical switch to filter by"`
+	MatchMode   string `json:"match,omitempty" jsonschema:"how name_filter is matched: exact (default), prefix, substring or regex, prefix and substring matches are case-insensitive"`
+	ResolveRefs bool   `json:"resolve_refs,omitempty" jsonschema:"replace UUID references with the UUID and name of the referenced row, this costs an extra query"`
+	CountOnly   bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit       int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset      int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type GetLogicalSwitchByUUIDArgs struct {
+	UUID string `json:"uuid" jsonschema:"the UUID of the logical switch"`
+}
+
+type GetLogicalSwitchArgs struct {
+	Name string `json:"name" jsonschema:"the name of the logical switch"`
 }
 
 type ListLogicalSwitchPortsArgs struct {
 	SwitchFilter string `json:"switch_filter" jsonschema:"the name of the logical switch to filter by"`
+	PortType     string `json:"type,omitempty" jsonschema:"the type of port to filter by, e.g. router, localnet, localport, vtep or external, all types when not set. Ports attached to VMs and containers have an empty type and can't be selected with this filter"`
+	ResolveRefs  bool   `json:"resolve_refs,omitempty" jsonschema:"replace UUID references with the UUID and name of the referenced row, this costs an extra query"`
+	CountOnly    bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset       int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListLogicalRoutersArgs struct {
-	NameFilter string `json:"name_filter" jsonschema:"the name of the logical router to filter by"`
+	NameFilter  string `json:"name_filter" jsonschema:"the name of the logical router to filter by"`
+	MatchMode   string `json:"match,omitempty" jsonschema:"how name_filter is matched: exact (default), prefix, substring or regex, prefix and substring matches are case-insensitive"`
+	ResolveRefs bool   `json:"resolve_refs,omitempty" jsonschema:"replace UUID references with the UUID and name of the referenced row, this costs an extra query"`
+	CountOnly   bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit       int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset      int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type ListLogicalRouterPortsArgs struct {
+	RouterFilter string `json:"router_filter,omitempty" jsonschema:"the name of the logical router to filter by"`
+	ResolveRefs  bool   `json:"resolve_refs,omitempty" jsonschema:"replace UUID references with the UUID and name of the referenced row, this costs an extra query"`
+	CountOnly    bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset       int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListACLsArgs struct {
 	SwitchFilter string `json:"switch_filter" jsonschema:"the name of the logical switch to filter by"`
+	Direction    string `json:"direction,omitempty" jsonschema:"only return ACLs in this direction: from-lport or to-lport"`
+	Action       string `json:"action,omitempty" jsonschema:"only return ACLs with this action: allow, allow-related, allow-stateless, drop, reject or pass"`
+	LoggingOnly  bool   `json:"logging_only,omitempty" jsonschema:"only return ACLs that log or sample matched traffic"`
+	ResolveRefs  bool   `json:"resolve_refs,omitempty" jsonschema:"replace UUID references with the UUID and name of the referenced row, this costs an extra query"`
+	CountOnly    bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset       int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListLoadBalancersArgs struct {
 	SwitchFilter string `json:"switch_filter" jsonschema:"the name of the logical switch to filter by"`
+	ResolveRefs  bool   `json:"resolve_refs,omitempty" jsonschema:"replace UUID references with the UUID and name of the referenced row, this costs an extra query"`
+	CountOnly    bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset       int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListNATRulesArgs struct {
 	RouterFilter string `json:"router_filter" jsonschema:"the name of the logical router to filter by"`
+	ResolveRefs  bool   `json:"resolve_refs,omitempty" jsonschema:"replace UUID references with the UUID and name of the referenced row, this costs an extra query"`
+	CountOnly    bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset       int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type ListLogicalRouterStaticRoutesArgs struct {
+	RouterFilter string `json:"router_filter" jsonschema:"the name of the logical router to filter by"`
+	ResolveRefs  bool   `json:"resolve_refs,omitempty" jsonschema:"replace UUID references with the UUID and name of the referenced row, this costs an extra query"`
+	CountOnly    bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset       int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListPortGroupsArgs struct {
-	NameFilter string `json:"name_filter" jsonschema:"the name of the port group to filter by"`
+	NameFilter  string `json:"name_filter" jsonschema:"the name of the port group to filter by"`
+	MatchMode   string `json:"match,omitempty" jsonschema:"how name_filter is matched: exact (default), prefix, substring or regex, prefix and substring matches are case-insensitive"`
+	ResolveRefs bool   `json:"resolve_refs,omitempty" jsonschema:"replace UUID references with the UUID and name of the referenced row, this costs an extra query"`
+	CountOnly   bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit       int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset      int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListAddressSetsArgs struct {
 	NameFilter string `json:"name_filter" jsonschema:"the name of the address set to filter by"`
+	MatchMode  string `json:"match,omitempty" jsonschema:"how name_filter is matched: exact (default), prefix, substring or regex, prefix and substring matches are case-insensitive"`
+	CountOnly  bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset     int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListQoSRulesArgs struct {
 	SwitchFilter string `json:"switch_filter" jsonschema:"the name of the logical switch to filter by"`
+	CountOnly    bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset       int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListMetersArgs struct {
-	NameFilter string `json:"name_filter" jsonschema:"the name of the meter to filter by"`
+	NameFilter  string `json:"name_filter" jsonschema:"the name of the meter to filter by"`
+	MatchMode   string `json:"match,omitempty" jsonschema:"how name_filter is matched: exact (default), prefix, substring or regex, prefix and substring matches are case-insensitive"`
+	ResolveRefs bool   `json:"resolve_refs,omitempty" jsonschema:"replace UUID references with the UUID and name of the referenced row, this costs an extra query"`
+	CountOnly   bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit       int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset      int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type ListBFDArgs struct {
+	LogicalPortFilter string `json:"logical_port_filter" jsonschema:"the name of the logical port to filter by"`
+	CountOnly         bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit             int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset            int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type ListStaticMACBindingsArgs struct {
+	LogicalPortFilter string `json:"logical_port_filter,omitempty" jsonschema:"the name of the logical router port to filter by"`
+	CountOnly         bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit             int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset            int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type ListGatewayChassisArgs struct {
+	ChassisFilter string `json:"chassis_filter" jsonschema:"the name of the chassis to filter by"`
+	CountOnly     bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit         int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset        int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type ListDNSArgs struct {
+	Hostname     string `json:"hostname,omitempty" jsonschema:"only return DNS entries with a record for this hostname, matched case-insensitively"`
+	SwitchFilter string `json:"switch_filter,omitempty" jsonschema:"only return the DNS entries referenced by the logical switch with this name"`
+	CountOnly    bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset       int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type ListDHCPOptionsArgs struct {
+	CIDRFilter string `json:"cidr_filter" jsonschema:"the CIDR of the DHCP options to filter by"`
+	CountOnly  bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset     int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type ListNBGlobalArgs struct {
+	CountOnly bool `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit     int  `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset    int  `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type PriorityRange struct {
+	Min    int    `json:"min" jsonschema:"the lowest priority in the range"`
+	Max    int    `json:"max" jsonschema:"the highest priority in the range"`
+	Reason string `json:"reason,omitempty" jsonschema:"why the range is reserved"`
+}
+
+type CheckACLPrioritiesArgs struct {
+	ReservedRanges []PriorityRange `json:"reserved_ranges,omitempty" jsonschema:"the ACL priority ranges reserved for internal use, defaults to the ranges used by ovn-kubernetes"`
+}
+
+// maxACLPriority is the highest priority allowed by the OVN NB schema
+const maxACLPriority = 32767
+
+// defaultReservedACLPriorities are the ACL priority bands ovn-kubernetes
+// programs itself
+var defaultReservedACLPriorities = []PriorityRange{
+	{Min: 1000, Max: 1001, Reason: "ovn-kubernetes network policy default deny and allow"},
+	{Min: 2000, Max: 10000, Reason: "ovn-kubernetes egress firewall"},
+	{Min: 20000, Max: 30000, Reason: "ovn-kubernetes admin network policy"},
 }
 
-func (s *Server) ListLogicalSwitches(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListLogicalSwitchesArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListLogicalSwitches(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListLogicalSwitchesArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	nameFilter := args.NameFilter
+	matcher, err := mcp.NewNameMatcher(args.NameFilter, args.MatchMode)
+	if err != nil {
+		return nil, err
+	}
+
+	logicalSwitch := &ovnnb.LogicalSwitch{}
 	var conditions []model.Condition
-	if nameFilter != "" {
+	if matcher.Exact() {
 		conditions = append(conditions, model.Condition{
-			Field:    &(&ovnnb.LogicalSwitch{}).Name,
+			Field:    &logicalSwitch.Name,
 			Function: ovsdb.ConditionEqual,
-			Value:    nameFilter,
+			Value:    args.NameFilter,
 		})
 	}
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
+	results, err := mcp.ExecuteSelectQuery(ctx, client, logicalSwitch, conditions...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+		return nil, err
+	}
+	results = mcp.FilterByName(results, matcher, func(r ovnnb.LogicalSwitch) string { return r.Name })
+
+	if args.CountOnly {
+		return mcp.CountResult(len(results))
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnnb.LogicalSwitch{}, conditions...)
+	results, page := mcp.Paginate(results, args.Limit, args.Offset)
+
+	rows, err := mcp.MapRows(ovnnb.LogicalSwitchTable, ovnnb.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
+	if args.ResolveRefs {
+		if err := mcp.ResolveRefs(ctx, client, ovnnb.DatabaseSchema(), ovnnb.LogicalSwitchTable, rows); err != nil {
+			return nil, err
+		}
+	}
 
-	result := map[string]interface{}{
-		"logical_switches": results,
-		"count":            len(results),
-		"context":          "Logical switches are the primary networking entities in OVN that connect logical ports. They represent virtual Layer 2 networks.",
+	result := mcp.ListResult{
+		Data:       map[string]any{"logical_switches": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Logical switches are the primary networking entities in OVN that connect logical ports. They represent virtual Layer 2 networks.",
+	}
+
+	return mcp.NewResult(result)
+}
+
+func (s *Server) GetLogicalSwitchByUUID(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[GetLogicalSwitchByUUIDArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	if args.UUID == "" {
+		return nil, fmt.Errorf("uuid is required")
 	}
 
-	json, err := json.Marshal(result)
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	logicalSwitch := &ovnnb.LogicalSwitch{}
+	results, err := mcp.ExecuteSelectQuery(ctx, client, logicalSwitch, model.Condition{
+		Field:    &logicalSwitch.UUID,
+		Function: ovsdb.ConditionEqual,
+		Value:    args.UUID,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
+	if len(results) == 0 {
+		return &mcpsdk.CallToolResultFor[map[string]any]{
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: fmt.Sprintf("No logical switch found with UUID %s", args.UUID),
+				},
 			},
-		},
-	}, nil
+			IsError: true,
+		}, nil
+	}
+
+	rows, err := mcp.MapRows(ovnnb.LogicalSwitchTable, ovnnb.DatabaseSchema(), results)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"logical_switch": rows[0],
+		"context":        "Logical switches are the primary networking entities in OVN that connect logical ports. They represent virtual Layer 2 networks.",
+	}
+
+	return mcp.NewResult(result)
 }
 
-func (s *Server) ListLogicalSwitchPorts(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListLogicalSwitchPortsArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) GetLogicalSwitch(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[GetLogicalSwitchArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
 	args := params.Arguments
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
+	logicalSwitch := &ovnnb.LogicalSwitch{}
+	switches, err := mcp.ExecuteSelectQuery(ctx, client, logicalSwitch, model.Condition{
+		Field:    &logicalSwitch.Name,
+		Function: ovsdb.ConditionEqual,
+		Value:    args.Name,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+		return nil, err
 	}
 
-	switchFilter := args.SwitchFilter
-	var conditions []model.Condition
-	if switchFilter != "" {
-		// First, get the logical switch UUID
-		var switches []ovnnb.LogicalSwitch
-		switchCondition := model.Condition{
-			Field:    &(&ovnnb.LogicalSwitch{}).Name,
-			Function: ovsdb.ConditionEqual,
-			Value:    switchFilter,
-		}
-		switchSelectOps, switchQueryID, switchSelectErr := client.WhereAll(&ovnnb.LogicalSwitch{}, switchCondition).Select()
-		if switchSelectErr != nil {
-			return nil, fmt.Errorf("failed to create logical switch select operation: %w", switchSelectErr)
-		}
-
-		switchReply, err := client.Transact(ctx, switchSelectOps...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute logical switch transaction: %w", err)
-		}
-
-		err = client.GetSelectResults(switchSelectOps, switchReply, map[string]interface{}{switchQueryID: &switches})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get logical switch select results: %w", err)
-		}
-
-		if len(switches) == 0 {
-			result := map[string]interface{}{
-				"logical_switch_ports": []ovnnb.LogicalSwitchPort{},
-				"count":                0,
-				"context":              "No logical switch found with the specified filter.",
-			}
-			json, err := json.Marshal(result)
-			if err != nil {
-				return nil, err
-			}
-			return &mcpsdk.CallToolResult{
-				Content: []mcpsdk.Content{
-					&mcpsdk.TextContent{
-						Text: string(json),
-					},
+	if len(switches) == 0 {
+		return &mcpsdk.CallToolResultFor[map[string]any]{
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: fmt.Sprintf("No logical switch found with name %s", args.Name),
 				},
-			}, nil
-		}
+			},
+			IsError: true,
+		}, nil
 	}
+	ls := switches[0]
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnnb.LogicalSwitchPort{}, conditions...)
+	// Resolve the references with a select keyed on the referenced UUIDs
+	lsp := &ovnnb.LogicalSwitchPort{}
+	ports, err := mcp.ExecuteSelectByUUIDs(ctx, client, lsp, &lsp.UUID, ls.Ports)
+	if err != nil {
+		return nil, err
+	}
+	acl := &ovnnb.ACL{}
+	acls, err := mcp.ExecuteSelectByUUIDs(ctx, client, acl, &acl.UUID, ls.ACLs)
+	if err != nil {
+		return nil, err
+	}
+	qos := &ovnnb.QoS{}
+	qosRules, err := mcp.ExecuteSelectByUUIDs(ctx, client, qos, &qos.UUID, ls.QOSRules)
 	if err != nil {
 		return nil, err
 	}
 
+	rows, err := mcp.MapRows(ovnnb.LogicalSwitchTable, ovnnb.DatabaseSchema(), switches)
+	if err != nil {
+		return nil, err
+	}
+	row := rows[0]
+	if row["ports"], err = mcp.MapRows(ovnnb.LogicalSwitchPortTable, ovnnb.DatabaseSchema(), ports); err != nil {
+		return nil, err
+	}
+	if row["acls"], err = mcp.MapRows(ovnnb.ACLTable, ovnnb.DatabaseSchema(), acls); err != nil {
+		return nil, err
+	}
+	if row["qos_rules"], err = mcp.MapRows(ovnnb.QoSTable, ovnnb.DatabaseSchema(), qosRules); err != nil {
+		return nil, err
+	}
+
 	result := map[string]interface{}{
-		"logical_switch_ports": results,
-		"count":                len(results),
-		"context":              "Logical switch ports connect to logical switches and represent network endpoints. Each port belongs to a logical switch and can have various configuration options.",
+		"logical_switch": row,
+		"context":        "The logical switch with its ports, ACLs and QoS rules resolved from UUIDs to the rows they reference. Logical switches are virtual Layer 2 networks, their ports are the network endpoints attached to them.",
 	}
 
-	json, err := json.Marshal(result)
+	return mcp.NewResult(result)
+}
+
+func (s *Server) ListLogicalSwitchPorts(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListLogicalSwitchPortsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer client.Close()
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
+	port := &ovnnb.LogicalSwitchPort{}
+	query := mcp.ListQuery[ovnnb.LogicalSwitch, ovnnb.LogicalSwitchPort]{
+		Model:       port,
+		Table:       ovnnb.LogicalSwitchPortTable,
+		Schema:      ovnnb.DatabaseSchema(),
+		Key:         "logical_switch_ports",
+		Context:     "Logical switch ports connect to logical switches and represent network endpoints. Each port belongs to a logical switch and can have various configuration options.",
+		Limit:       args.Limit,
+		Offset:      args.Offset,
+		CountOnly:   args.CountOnly,
+		ResolveRefs: args.ResolveRefs,
+	}
+	if args.PortType != "" {
+		query.Conditions = append(query.Conditions, model.Condition{
+			Field:    &port.Type,
+			Function: ovsdb.ConditionEqual,
+			Value:    args.PortType,
+		})
+	}
+	if args.SwitchFilter != "" {
+		logicalSwitch := &ovnnb.LogicalSwitch{}
+		query.Parent = &mcp.ParentFilter[ovnnb.LogicalSwitch, ovnnb.LogicalSwitchPort]{
+			Model: logicalSwitch,
+			Field: &logicalSwitch.Name,
+			Value: args.SwitchFilter,
+			Kind:  "logical switch",
+			// Only keep the ports referenced by the switch's ports column
+			Keep: func(ls ovnnb.LogicalSwitch, port ovnnb.LogicalSwitchPort) bool {
+				return slices.Contains(ls.Ports, port.UUID)
 			},
-		},
-	}, nil
+		}
+	}
+
+	return mcp.ListWithParentFilter(ctx, client, query)
 }
 
-func (s *Server) ListLogicalRouters(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListLogicalRoutersArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListLogicalRouters(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListLogicalRoutersArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	nameFilter := args.NameFilter
+	matcher, err := mcp.NewNameMatcher(args.NameFilter, args.MatchMode)
+	if err != nil {
+		return nil, err
+	}
+
+	logicalRouter := &ovnnb.LogicalRouter{}
 	var conditions []model.Condition
-	if nameFilter != "" {
+	if matcher.Exact() {
 		conditions = append(conditions, model.Condition{
-			Field:    &(&ovnnb.LogicalRouter{}).Name,
+			Field:    &logicalRouter.Name,
 			Function: ovsdb.ConditionEqual,
-			Value:    nameFilter,
+			Value:    args.NameFilter,
 		})
 	}
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
+	results, err := mcp.ExecuteSelectQuery(ctx, client, logicalRouter, conditions...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+		return nil, err
+	}
+	results = mcp.FilterByName(results, matcher, func(r ovnnb.LogicalRouter) string { return r.Name })
+
+	if args.CountOnly {
+		return mcp.CountResult(len(results))
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnnb.LogicalRouter{}, conditions...)
+	results, page := mcp.Paginate(results, args.Limit, args.Offset)
+
+	rows, err := mcp.MapRows(ovnnb.LogicalRouterTable, ovnnb.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
+	if args.ResolveRefs {
+		if err := mcp.ResolveRefs(ctx, client, ovnnb.DatabaseSchema(), ovnnb.LogicalRouterTable, rows); err != nil {
+			return nil, err
+		}
+	}
 
-	result := map[string]interface{}{
-		"logical_routers": results,
-		"count":           len(results),
-		"context":         "Logical routers provide Layer 3 routing between logical switches. They handle routing decisions and can have multiple logical router ports.",
+	result := mcp.ListResult{
+		Data:       map[string]any{"logical_routers": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Logical routers provide Layer 3 routing between logical switches. They handle routing decisions and can have multiple logical router ports.",
 	}
 
-	json, err := json.Marshal(result)
+	return mcp.NewResult(result)
+}
+
+func (s *Server) ListLogicalRouterPorts(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListLogicalRouterPortsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer client.Close()
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
+	query := mcp.ListQuery[ovnnb.LogicalRouter, ovnnb.LogicalRouterPort]{
+		Model:       &ovnnb.LogicalRouterPort{},
+		Table:       ovnnb.LogicalRouterPortTable,
+		Schema:      ovnnb.DatabaseSchema(),
+		Key:         "logical_router_ports",
+		Context:     "Logical router ports attach logical routers to the network. Each port has a MAC address and one or more networks (IP address and prefix length) the router is directly connected to. A port with a peer is connected directly to a port on another logical router; ports connected to a logical switch are instead referenced by a switch port of type router whose router-port option names them.",
+		Limit:       args.Limit,
+		Offset:      args.Offset,
+		CountOnly:   args.CountOnly,
+		ResolveRefs: args.ResolveRefs,
+	}
+	if args.RouterFilter != "" {
+		logicalRouter := &ovnnb.LogicalRouter{}
+		query.Parent = &mcp.ParentFilter[ovnnb.LogicalRouter, ovnnb.LogicalRouterPort]{
+			Model: logicalRouter,
+			Field: &logicalRouter.Name,
+			Value: args.RouterFilter,
+			Kind:  "logical router",
+			// Only keep the ports referenced by the router's ports column
+			Keep: func(lr ovnnb.LogicalRouter, port ovnnb.LogicalRouterPort) bool {
+				return slices.Contains(lr.Ports, port.UUID)
 			},
-		},
-	}, nil
+		}
+	}
+
+	return mcp.ListWithParentFilter(ctx, client, query)
 }
 
-func (s *Server) ListACLs(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListACLsArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListACLs(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListACLsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	if args.Direction != "" && !slices.Contains(aclDirections, args.Direction) {
+		return nil, fmt.Errorf("invalid direction %q, must be one of %s", args.Direction, strings.Join(aclDirections, ", "))
+	}
+	if args.Action != "" && !slices.Contains(aclActions, args.Action) {
+		return nil, fmt.Errorf("invalid action %q, must be one of %s", args.Action, strings.Join(aclActions, ", "))
+	}
+
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
+	var parent *mcp.ParentFilter[ovnnb.LogicalSwitch, ovnnb.ACL]
+	if args.SwitchFilter != "" {
+		logicalSwitch := &ovnnb.LogicalSwitch{}
+		parent = &mcp.ParentFilter[ovnnb.LogicalSwitch, ovnnb.ACL]{
+			Model: logicalSwitch,
+			Field: &logicalSwitch.Name,
+			Value: args.SwitchFilter,
+			Kind:  "logical switch",
+			// Only keep the ACLs referenced by the switch's acls column
+			Keep: func(ls ovnnb.LogicalSwitch, acl ovnnb.ACL) bool {
+				return slices.Contains(ls.ACLs, acl.UUID)
+			},
+		}
+	}
+	results, found, err := mcp.SelectWithParentFilter(ctx, client, &ovnnb.ACL{}, parent)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+		return nil, err
+	}
+	if !found {
+		return mcp.NoParentResult("acls", parent.Kind)
 	}
 
-	switchFilter := args.SwitchFilter
-	var conditions []model.Condition
-	if switchFilter != "" {
-		// First, get the logical switch UUID
-		var switches []ovnnb.LogicalSwitch
-		switchCondition := model.Condition{
-			Field:    &(&ovnnb.LogicalSwitch{}).Name,
-			Function: ovsdb.ConditionEqual,
-			Value:    switchFilter,
+	decoder, err := newACLLoggingDecoder(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	acls := []ACLWithLogging{}
+	for _, acl := range results {
+		// direction and action are enum columns, which libovsdb can't build
+		// select conditions for, so they are matched here
+		if args.Direction != "" && acl.Direction != args.Direction {
+			continue
 		}
-		switchSelectOps, switchQueryID, switchSelectErr := client.WhereAll(&ovnnb.LogicalSwitch{}, switchCondition).Select()
-		if switchSelectErr != nil {
-			return nil, fmt.Errorf("failed to create logical switch select operation: %w", switchSelectErr)
+		if args.Action != "" && acl.Action != args.Action {
+			continue
 		}
-
-		switchReply, err := client.Transact(ctx, switchSelectOps...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute logical switch transaction: %w", err)
+		logging := decoder.decode(acl)
+		if args.LoggingOnly && !logging.Enabled && !logging.Sampling {
+			continue
 		}
+		acls = append(acls, ACLWithLogging{ACL: acl, Logging: logging})
+	}
+
+	if args.CountOnly {
+		return mcp.CountResult(len(acls))
+	}
 
-		err = client.GetSelectResults(switchSelectOps, switchReply, map[string]interface{}{switchQueryID: &switches})
+	acls, page := mcp.Paginate(acls, args.Limit, args.Offset)
+
+	rows := make([]map[string]any, 0, len(acls))
+	for _, acl := range acls {
+		mapped, err := mcp.MapRows(ovnnb.ACLTable, ovnnb.DatabaseSchema(), []ovnnb.ACL{acl.ACL})
 		if err != nil {
-			return nil, fmt.Errorf("failed to get logical switch select results: %w", err)
+			return nil, err
 		}
-
-		if len(switches) == 0 {
-			result := map[string]interface{}{
-				"acls":    []ovnnb.ACL{},
-				"count":   0,
-				"context": "No logical switch found with the specified filter.",
-			}
-			json, err := json.Marshal(result)
-			if err != nil {
-				return nil, err
-			}
-			return &mcpsdk.CallToolResult{
-				Content: []mcpsdk.Content{
-					&mcpsdk.TextContent{
-						Text: string(json),
-					},
-				},
-			}, nil
+		mapped[0]["logging"] = acl.Logging
+		rows = append(rows, mapped[0])
+	}
+	if args.ResolveRefs {
+		if err := mcp.ResolveRefs(ctx, client, ovnnb.DatabaseSchema(), ovnnb.ACLTable, rows); err != nil {
+			return nil, err
 		}
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnnb.ACL{}, conditions...)
+	result := mcp.ListResult{
+		Data:       map[string]any{"acls": rows},
+		Count:      len(acls),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "ACLs (Access Control Lists) define security policies for logical switches. They control which traffic is allowed or denied based on various criteria. The logging field decodes whether matched traffic is logged, at which severity (info by default) and through which meter, which rate limits the log messages, and whether it is sampled to IPFIX collectors.",
+	}
+
+	return mcp.NewResult(result)
+}
+
+func (s *Server) ListLoadBalancers(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListLoadBalancersArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer client.Close()
 
-	result := map[string]interface{}{
-		"acls":    results,
-		"count":   len(results),
-		"context": "ACLs (Access Control Lists) define security policies for logical switches. They control which traffic is allowed or denied based on various criteria.",
+	query := mcp.ListQuery[ovnnb.LogicalSwitch, ovnnb.LoadBalancer]{
+		Model:       &ovnnb.LoadBalancer{},
+		Table:       ovnnb.LoadBalancerTable,
+		Schema:      ovnnb.DatabaseSchema(),
+		Key:         "load_balancers",
+		Context:     "Load balancers distribute incoming traffic across multiple backend servers. They provide high availability and scalability for services.",
+		Limit:       args.Limit,
+		Offset:      args.Offset,
+		CountOnly:   args.CountOnly,
+		ResolveRefs: args.ResolveRefs,
+	}
+	if args.SwitchFilter != "" {
+		logicalSwitch := &ovnnb.LogicalSwitch{}
+		query.Parent = &mcp.ParentFilter[ovnnb.LogicalSwitch, ovnnb.LoadBalancer]{
+			Model: logicalSwitch,
+			Field: &logicalSwitch.Name,
+			Value: args.SwitchFilter,
+			Kind:  "logical switch",
+			// Only keep the load balancers referenced by the switch's load_balancer column
+			Keep: func(ls ovnnb.LogicalSwitch, lb ovnnb.LoadBalancer) bool {
+				return slices.Contains(ls.LoadBalancer, lb.UUID)
+			},
+		}
 	}
 
-	json, err := json.Marshal(result)
+	return mcp.ListWithParentFilter(ctx, client, query)
+}
+
+func (s *Server) ListNATRules(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListNATRulesArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer client.Close()
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
+	query := mcp.ListQuery[ovnnb.LogicalRouter, ovnnb.NAT]{
+		Model:       &ovnnb.NAT{},
+		Table:       ovnnb.NATTable,
+		Schema:      ovnnb.DatabaseSchema(),
+		Key:         "nat_rules",
+		Context:     "NAT (Network Address Translation) rules modify packet headers to change source or destination addresses. They are used for network address translation.",
+		Limit:       args.Limit,
+		Offset:      args.Offset,
+		CountOnly:   args.CountOnly,
+		ResolveRefs: args.ResolveRefs,
+	}
+	if args.RouterFilter != "" {
+		logicalRouter := &ovnnb.LogicalRouter{}
+		query.Parent = &mcp.ParentFilter[ovnnb.LogicalRouter, ovnnb.NAT]{
+			Model: logicalRouter,
+			Field: &logicalRouter.Name,
+			Value: args.RouterFilter,
+			Kind:  "logical router",
+			// Only keep the NAT rules referenced by the router's nat column
+			Keep: func(lr ovnnb.LogicalRouter, nat ovnnb.NAT) bool {
+				return slices.Contains(lr.Nat, nat.UUID)
 			},
-		},
-	}, nil
+		}
+	}
+
+	return mcp.ListWithParentFilter(ctx, client, query)
 }
 
-func (s *Server) ListLoadBalancers(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListLoadBalancersArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListLogicalRouterStaticRoutes(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListLogicalRouterStaticRoutesArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
+	query := mcp.ListQuery[ovnnb.LogicalRouter, ovnnb.LogicalRouterStaticRoute]{
+		Model:       &ovnnb.LogicalRouterStaticRoute{},
+		Table:       ovnnb.LogicalRouterStaticRouteTable,
+		Schema:      ovnnb.DatabaseSchema(),
+		Key:         "static_routes",
+		Context:     "Static routes are configured on logical routers through their static_routes column. Each route sends traffic whose destination (or source, with the src-ip policy) is within ip_prefix to the nexthop IP address, optionally out of output_port when the nexthop is not reachable through a router port network. Routes are looked up in their route_table.",
+		Limit:       args.Limit,
+		Offset:      args.Offset,
+		CountOnly:   args.CountOnly,
+		ResolveRefs: args.ResolveRefs,
+	}
+	if args.RouterFilter != "" {
+		logicalRouter := &ovnnb.LogicalRouter{}
+		query.Parent = &mcp.ParentFilter[ovnnb.LogicalRouter, ovnnb.LogicalRouterStaticRoute]{
+			Model: logicalRouter,
+			Field: &logicalRouter.Name,
+			Value: args.RouterFilter,
+			Kind:  "logical router",
+			// Only keep the routes referenced by the router's static_routes column
+			Keep: func(lr ovnnb.LogicalRouter, route ovnnb.LogicalRouterStaticRoute) bool {
+				return slices.Contains(lr.StaticRoutes, route.UUID)
+			},
+		}
+	}
+
+	return mcp.ListWithParentFilter(ctx, client, query)
+}
+
+func (s *Server) ListPortGroups(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListPortGroupsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	matcher, err := mcp.NewNameMatcher(args.NameFilter, args.MatchMode)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+		return nil, err
 	}
 
-	switchFilter := args.SwitchFilter
+	portGroup := &ovnnb.PortGroup{}
 	var conditions []model.Condition
-	if switchFilter != "" {
-		// First, get the logical switch UUID
-		var switches []ovnnb.LogicalSwitch
-		switchCondition := model.Condition{
-			Field:    &(&ovnnb.LogicalSwitch{}).Name,
+	if matcher.Exact() {
+		conditions = append(conditions, model.Condition{
+			Field:    &portGroup.Name,
 			Function: ovsdb.ConditionEqual,
-			Value:    switchFilter,
-		}
-		switchSelectOps, switchQueryID, switchSelectErr := client.WhereAll(&ovnnb.LogicalSwitch{}, switchCondition).Select()
-		if switchSelectErr != nil {
-			return nil, fmt.Errorf("failed to create logical switch select operation: %w", switchSelectErr)
-		}
-
-		switchReply, err := client.Transact(ctx, switchSelectOps...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute logical switch transaction: %w", err)
-		}
-
-		err = client.GetSelectResults(switchSelectOps, switchReply, map[string]interface{}{switchQueryID: &switches})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get logical switch select results: %w", err)
-		}
+			Value:    args.NameFilter,
+		})
+	}
 
-		if len(switches) == 0 {
-			result := map[string]interface{}{
-				"load_balancers": []ovnnb.LoadBalancer{},
-				"count":          0,
-				"context":        "No logical switch found with the specified filter.",
-			}
-			json, err := json.Marshal(result)
-			if err != nil {
-				return nil, err
-			}
-			return &mcpsdk.CallToolResult{
-				Content: []mcpsdk.Content{
-					&mcpsdk.TextContent{
-						Text: string(json),
-					},
-				},
-			}, nil
-		}
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer client.Close()
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnnb.LoadBalancer{}, conditions...)
+	results, err := mcp.ExecuteSelectQuery(ctx, client, portGroup, conditions...)
 	if err != nil {
 		return nil, err
 	}
+	results = mcp.FilterByName(results, matcher, func(r ovnnb.PortGroup) string { return r.Name })
 
-	result := map[string]interface{}{
-		"load_balancers": results,
-		"count":          len(results),
-		"context":        "Load balancers distribute incoming traffic across multiple backend servers. They provide high availability and scalability for services.",
+	if args.CountOnly {
+		return mcp.CountResult(len(results))
 	}
 
-	json, err := json.Marshal(result)
+	results, page := mcp.Paginate(results, args.Limit, args.Offset)
+
+	rows, err := mcp.MapRows(ovnnb.PortGroupTable, ovnnb.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
+	if args.ResolveRefs {
+		if err := mcp.ResolveRefs(ctx, client, ovnnb.DatabaseSchema(), ovnnb.PortGroupTable, rows); err != nil {
+			return nil, err
+		}
+	}
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	result := mcp.ListResult{
+		Data:       map[string]any{"port_groups": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Port groups are collections of logical switch ports that can be referenced together for ACLs and other policies.",
+	}
+
+	return mcp.NewResult(result)
 }
 
-func (s *Server) ListNATRules(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListNATRulesArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListAddressSets(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListAddressSetsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	matcher, err := mcp.NewNameMatcher(args.NameFilter, args.MatchMode)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
-	}
-	defer client.Close()
-
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+		return nil, err
 	}
 
-	routerFilter := args.RouterFilter
+	addressSet := &ovnnb.AddressSet{}
 	var conditions []model.Condition
-	if routerFilter != "" {
-		// First, get the logical router UUID
-		var routers []ovnnb.LogicalRouter
-		routerCondition := model.Condition{
-			Field:    &(&ovnnb.LogicalRouter{}).Name,
+	if matcher.Exact() {
+		conditions = append(conditions, model.Condition{
+			Field:    &addressSet.Name,
 			Function: ovsdb.ConditionEqual,
-			Value:    routerFilter,
-		}
-		routerSelectOps, routerQueryID, routerSelectErr := client.WhereAll(&ovnnb.LogicalRouter{}, routerCondition).Select()
-		if routerSelectErr != nil {
-			return nil, fmt.Errorf("failed to create logical router select operation: %w", routerSelectErr)
-		}
+			Value:    args.NameFilter,
+		})
+	}
 
-		routerReply, err := client.Transact(ctx, routerSelectOps...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute logical router transaction: %w", err)
-		}
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
 
-		err = client.GetSelectResults(routerSelectOps, routerReply, map[string]interface{}{routerQueryID: &routers})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get logical router select results: %w", err)
-		}
+	results, err := mcp.ExecuteSelectQuery(ctx, client, addressSet, conditions...)
+	if err != nil {
+		return nil, err
+	}
+	results = mcp.FilterByName(results, matcher, func(r ovnnb.AddressSet) string { return r.Name })
 
-		if len(routers) == 0 {
-			result := map[string]interface{}{
-				"nat_rules": []ovnnb.NAT{},
-				"count":     0,
-				"context":   "No logical router found with the specified filter.",
-			}
-			json, err := json.Marshal(result)
-			if err != nil {
-				return nil, err
-			}
-			return &mcpsdk.CallToolResult{
-				Content: []mcpsdk.Content{
-					&mcpsdk.TextContent{
-						Text: string(json),
-					},
-				},
-			}, nil
-		}
+	if args.CountOnly {
+		return mcp.CountResult(len(results))
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnnb.NAT{}, conditions...)
+	results, page := mcp.Paginate(results, args.Limit, args.Offset)
+
+	rows, err := mcp.MapRows(ovnnb.AddressSetTable, ovnnb.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
 
-	result := map[string]interface{}{
-		"nat_rules": results,
-		"count":     len(results),
-		"context":   "NAT (Network Address Translation) rules modify packet headers to change source or destination addresses. They are used for network address translation.",
+	result := mcp.ListResult{
+		Data:       map[string]any{"address_sets": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Address sets are collections of IP addresses that can be referenced together in ACLs and other policies.",
 	}
 
-	json, err := json.Marshal(result)
+	return mcp.NewResult(result)
+}
+
+func (s *Server) ListQoSRules(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListQoSRulesArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer client.Close()
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
+	query := mcp.ListQuery[ovnnb.LogicalSwitch, ovnnb.QoS]{
+		Model:     &ovnnb.QoS{},
+		Table:     ovnnb.QoSTable,
+		Schema:    ovnnb.DatabaseSchema(),
+		Key:       "qos_rules",
+		Context:   "QoS (Quality of Service) rules define bandwidth and traffic shaping policies for logical switch ports.",
+		Limit:     args.Limit,
+		Offset:    args.Offset,
+		CountOnly: args.CountOnly,
+	}
+	if args.SwitchFilter != "" {
+		logicalSwitch := &ovnnb.LogicalSwitch{}
+		query.Parent = &mcp.ParentFilter[ovnnb.LogicalSwitch, ovnnb.QoS]{
+			Model: logicalSwitch,
+			Field: &logicalSwitch.Name,
+			Value: args.SwitchFilter,
+			Kind:  "logical switch",
+			// Only keep the QoS rules referenced by the switch's qos_rules column
+			Keep: func(ls ovnnb.LogicalSwitch, qos ovnnb.QoS) bool {
+				return slices.Contains(ls.QOSRules, qos.UUID)
 			},
-		},
-	}, nil
+		}
+	}
+
+	return mcp.ListWithParentFilter(ctx, client, query)
 }
 
-func (s *Server) ListPortGroups(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListPortGroupsArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListMeters(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListMetersArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	nameFilter := args.NameFilter
+	matcher, err := mcp.NewNameMatcher(args.NameFilter, args.MatchMode)
+	if err != nil {
+		return nil, err
+	}
+
+	meter := &ovnnb.Meter{}
 	var conditions []model.Condition
-	if nameFilter != "" {
+	if matcher.Exact() {
 		conditions = append(conditions, model.Condition{
-			Field:    &(&ovnnb.PortGroup{}).Name,
+			Field:    &meter.Name,
 			Function: ovsdb.ConditionEqual,
-			Value:    nameFilter,
+			Value:    args.NameFilter,
 		})
 	}
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
-	}
-
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnnb.PortGroup{}, conditions...)
+	results, err := mcp.ExecuteSelectQuery(ctx, client, meter, conditions...)
 	if err != nil {
 		return nil, err
 	}
+	results = mcp.FilterByName(results, matcher, func(r ovnnb.Meter) string { return r.Name })
 
-	result := map[string]interface{}{
-		"port_groups": results,
-		"count":       len(results),
-		"context":     "Port groups are collections of logical switch ports that can be referenced together for ACLs and other policies.",
+	if args.CountOnly {
+		return mcp.CountResult(len(results))
 	}
 
-	json, err := json.Marshal(result)
+	results, page := mcp.Paginate(results, args.Limit, args.Offset)
+
+	rows, err := mcp.MapRows(ovnnb.MeterTable, ovnnb.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
+	if args.ResolveRefs {
+		if err := mcp.ResolveRefs(ctx, client, ovnnb.DatabaseSchema(), ovnnb.MeterTable, rows); err != nil {
+			return nil, err
+		}
+	}
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	result := mcp.ListResult{
+		Data:       map[string]any{"meters": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Meters provide rate limiting and policing capabilities for traffic flows. They can be used to enforce bandwidth limits.",
+	}
+
+	return mcp.NewResult(result)
 }
 
-func (s *Server) ListAddressSets(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListAddressSetsArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListBFD(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListBFDArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	nameFilter := args.NameFilter
+	bfd := &ovnnb.BFD{}
 	var conditions []model.Condition
-	if nameFilter != "" {
+	if args.LogicalPortFilter != "" {
 		conditions = append(conditions, model.Condition{
-			Field:    &(&ovnnb.AddressSet{}).Name,
+			Field:    &bfd.LogicalPort,
 			Function: ovsdb.ConditionEqual,
-			Value:    nameFilter,
+			Value:    args.LogicalPortFilter,
 		})
 	}
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+	if args.CountOnly {
+		count, err := mcp.CountRows(ctx, client, bfd, conditions...)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.CountResult(count)
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnnb.AddressSet{}, conditions...)
+	results, page, err := mcp.ExecuteSelectQueryPaged(ctx, client, bfd, args.Limit, args.Offset, conditions...)
 	if err != nil {
 		return nil, err
 	}
 
-	result := map[string]interface{}{
-		"address_sets": results,
-		"count":        len(results),
-		"context":      "Address sets are collections of IP addresses that can be referenced together in ACLs and other policies.",
-	}
-
-	json, err := json.Marshal(result)
+	rows, err := mcp.MapRows(ovnnb.BFDTable, ovnnb.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	result := mcp.ListResult{
+		Data:       map[string]any{"bfd": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "BFD sessions monitor the liveness of the next hop reached through a logical router port (logical_port) at dst_ip. A session whose status is down makes static routes with BFD enabled through that next hop inactive, so traffic fails over to other routes. Gateway chassis liveness used for gateway port failover is tracked by BFD between chassis tunnel endpoints in OVS, not by these rows.",
+	}
+
+	return mcp.NewResult(result)
 }
 
-func (s *Server) ListQoSRules(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListQoSRulesArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListStaticMACBindings(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListStaticMACBindingsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	binding := &ovnnb.StaticMACBinding{}
+	var conditions []model.Condition
+	if args.LogicalPortFilter != "" {
+		conditions = append(conditions, model.Condition{
+			Field:    &binding.LogicalPort,
+			Function: ovsdb.ConditionEqual,
+			Value:    args.LogicalPortFilter,
+		})
+	}
+
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
+	if args.CountOnly {
+		count, err := mcp.CountRows(ctx, client, binding, conditions...)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.CountResult(count)
+	}
+
+	results, page, err := mcp.ExecuteSelectQueryPaged(ctx, client, binding, args.Limit, args.Offset, conditions...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+		return nil, err
+	}
+
+	rows, err := mcp.MapRows(ovnnb.StaticMACBindingTable, ovnnb.DatabaseSchema(), results)
+	if err != nil {
+		return nil, err
+	}
+
+	result := mcp.ListResult{
+		Data:       map[string]any{"static_mac_bindings": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Static MAC bindings are configured IP to MAC address mappings for neighbours reached through a logical router port (logical_port), so the router doesn't have to resolve them with ARP or IPv6 neighbour discovery. ovn-northd copies them to the southbound Static_MAC_Binding table. When override_dynamic_mac is true the static MAC is used even if a different MAC has been learnt in a southbound MAC_Binding, otherwise the learnt one takes precedence.",
 	}
 
-	switchFilter := args.SwitchFilter
+	return mcp.NewResult(result)
+}
+
+func (s *Server) ListGatewayChassis(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListGatewayChassisArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	gatewayChassis := &ovnnb.GatewayChassis{}
 	var conditions []model.Condition
-	if switchFilter != "" {
-		// First, get the logical switch UUID
-		var switches []ovnnb.LogicalSwitch
-		switchCondition := model.Condition{
-			Field:    &(&ovnnb.LogicalSwitch{}).Name,
+	if args.ChassisFilter != "" {
+		conditions = append(conditions, model.Condition{
+			Field:    &gatewayChassis.ChassisName,
 			Function: ovsdb.ConditionEqual,
-			Value:    switchFilter,
-		}
-		switchSelectOps, switchQueryID, switchSelectErr := client.WhereAll(&ovnnb.LogicalSwitch{}, switchCondition).Select()
-		if switchSelectErr != nil {
-			return nil, fmt.Errorf("failed to create logical switch select operation: %w", switchSelectErr)
-		}
+			Value:    args.ChassisFilter,
+		})
+	}
 
-		switchReply, err := client.Transact(ctx, switchSelectOps...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute logical switch transaction: %w", err)
-		}
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
 
-		err = client.GetSelectResults(switchSelectOps, switchReply, map[string]interface{}{switchQueryID: &switches})
+	if args.CountOnly {
+		count, err := mcp.CountRows(ctx, client, gatewayChassis, conditions...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get logical switch select results: %w", err)
+			return nil, err
 		}
+		return mcp.CountResult(count)
+	}
 
-		if len(switches) == 0 {
-			result := map[string]interface{}{
-				"qos_rules": []ovnnb.QoS{},
-				"count":     0,
-				"context":   "No logical switch found with the specified filter.",
-			}
-			json, err := json.Marshal(result)
-			if err != nil {
-				return nil, err
-			}
-			return &mcpsdk.CallToolResult{
-				Content: []mcpsdk.Content{
-					&mcpsdk.TextContent{
-						Text: string(json),
-					},
-				},
-			}, nil
-		}
+	results, page, err := mcp.ExecuteSelectQueryPaged(ctx, client, gatewayChassis, args.Limit, args.Offset, conditions...)
+	if err != nil {
+		return nil, err
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnnb.QoS{}, conditions...)
+	rows, err := mcp.MapRows(ovnnb.GatewayChassisTable, ovnnb.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
 
-	result := map[string]interface{}{
-		"qos_rules": results,
-		"count":     len(results),
-		"context":   "QoS (Quality of Service) rules define bandwidth and traffic shaping policies for logical switch ports.",
+	result := mcp.ListResult{
+		Data:       map[string]any{"gateway_chassis": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Gateway chassis are the chassis a distributed gateway port can be scheduled on, referenced from the port's gateway_chassis column. The highest priority chassis that is up hosts the port; if it fails, as detected by BFD between chassis, the port fails over to the next highest priority chassis.",
+	}
+
+	return mcp.NewResult(result)
+}
+
+func (s *Server) CheckACLPriorities(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[CheckACLPrioritiesArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	reserved := args.ReservedRanges
+	if len(reserved) == 0 {
+		reserved = defaultReservedACLPriorities
+	}
+	for _, r := range reserved {
+		if r.Min > r.Max {
+			return nil, fmt.Errorf("invalid reserved range %d-%d: min is greater than max", r.Min, r.Max)
+		}
 	}
 
-	json, err := json.Marshal(result)
+	client, err := s.Connect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer client.Close()
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	acls, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.ACL{})
+	if err != nil {
+		return nil, err
+	}
+
+	var flagged []map[string]interface{}
+	for _, acl := range acls {
+		var violations []string
+		if acl.Priority < 0 || acl.Priority > maxACLPriority {
+			violations = append(violations, fmt.Sprintf("outside the allowed range 0-%d", maxACLPriority))
+		}
+		for _, r := range reserved {
+			if acl.Priority >= r.Min && acl.Priority <= r.Max {
+				violations = append(violations, fmt.Sprintf("reserved range %d-%d: %s", r.Min, r.Max, r.Reason))
+			}
+		}
+		if len(violations) == 0 {
+			continue
+		}
+		flagged = append(flagged, map[string]interface{}{
+			"uuid":         acl.UUID,
+			"priority":     acl.Priority,
+			"direction":    acl.Direction,
+			"match":        acl.Match,
+			"action":       acl.Action,
+			"external_ids": acl.ExternalIDs,
+			"violations":   violations,
+		})
+	}
+
+	result := map[string]interface{}{
+		"flagged_acls":    flagged,
+		"count":           len(flagged),
+		"checked":         len(acls),
+		"reserved_ranges": reserved,
+		"context":         "ACL priorities must be between 0 and 32767. Some priority bands are reserved for ACLs programmed by OVN or the CMS (such as ovn-kubernetes), user ACLs in those bands may be shadowed by, or shadow, the internal ones.",
+	}
+
+	return mcp.NewResult(result)
 }
 
-func (s *Server) ListMeters(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListMetersArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListDHCPOptions(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListDHCPOptionsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	nameFilter := args.NameFilter
+	dhcpOptions := &ovnnb.DHCPOptions{}
 	var conditions []model.Condition
-	if nameFilter != "" {
+	if args.CIDRFilter != "" {
 		conditions = append(conditions, model.Condition{
-			Field:    &(&ovnnb.Meter{}).Name,
+			Field:    &dhcpOptions.Cidr,
 			Function: ovsdb.ConditionEqual,
-			Value:    nameFilter,
+			Value:    args.CIDRFilter,
 		})
 	}
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
+	if args.CountOnly {
+		count, err := mcp.CountRows(ctx, client, dhcpOptions, conditions...)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.CountResult(count)
+	}
+
+	results, page, err := mcp.ExecuteSelectQueryPaged(ctx, client, dhcpOptions, args.Limit, args.Offset, conditions...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+		return nil, err
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnnb.Meter{}, conditions...)
+	rows, err := mcp.MapRows(ovnnb.DHCPOptionsTable, ovnnb.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
 
-	result := map[string]interface{}{
-		"meters":  results,
-		"count":   len(results),
-		"context": "Meters provide rate limiting and policing capabilities for traffic flows. They can be used to enforce bandwidth limits.",
+	result := mcp.ListResult{
+		Data:       map[string]any{"dhcp_options": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "DHCP options configure OVN's native DHCP server for the subnet in cidr. A logical switch port uses them when its dhcpv4_options or dhcpv6_options column references the row and its addresses include an IP in the subnet. For DHCPv4, server_id, server_mac, lease_time and router must be set in options or OVN does not reply to DHCP requests; for DHCPv6, server_id is required.",
+	}
+
+	return mcp.NewResult(result)
+}
+
+func (s *Server) ListNBGlobal(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListNBGlobalArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	if args.CountOnly {
+		count, err := mcp.CountRows(ctx, client, &ovnnb.NBGlobal{})
+		if err != nil {
+			return nil, err
+		}
+		return mcp.CountResult(count)
+	}
+
+	results, page, err := mcp.ExecuteSelectQueryPaged(ctx, client, &ovnnb.NBGlobal{}, args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := mcp.MapRows(ovnnb.NBGlobalTable, ovnnb.DatabaseSchema(), results)
+	if err != nil {
+		return nil, err
+	}
+
+	result := mcp.ListResult{
+		Data:       map[string]any{"nb_globals": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "NB Global is the single row of global OVN configuration: the options map, ipsec, and the connections and ssl used by ovsdb-server. nb_cfg is incremented by clients such as ovn-nbctl --wait to request a sequence number; northd copies it to sb_cfg once the southbound database reflects it, and hv_cfg once every chassis has caught up. sb_cfg or hv_cfg lagging behind nb_cfg shows how far northd or the chassis are behind.",
+	}
+	if len(results) > 0 {
+		global := results[0]
+		result.Data["sequence"] = map[string]interface{}{
+			"nb_cfg":           global.NbCfg,
+			"sb_cfg":           global.SbCfg,
+			"hv_cfg":           global.HvCfg,
+			"sb_behind":        global.NbCfg - global.SbCfg,
+			"hv_behind":        global.NbCfg - global.HvCfg,
+			"nb_cfg_timestamp": global.NbCfgTimestamp,
+			"sb_cfg_timestamp": global.SbCfgTimestamp,
+			"hv_cfg_timestamp": global.HvCfgTimestamp,
+		}
 	}
 
-	json, err := json.Marshal(result)
+	return mcp.NewResult(result)
+}
+
+func (s *Server) ListDNS(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListDNSArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer client.Close()
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
+	var parent *mcp.ParentFilter[ovnnb.LogicalSwitch, ovnnb.DNS]
+	if args.SwitchFilter != "" {
+		logicalSwitch := &ovnnb.LogicalSwitch{}
+		parent = &mcp.ParentFilter[ovnnb.LogicalSwitch, ovnnb.DNS]{
+			Model: logicalSwitch,
+			Field: &logicalSwitch.Name,
+			Value: args.SwitchFilter,
+			Kind:  "logical switch",
+			Keep: func(ls ovnnb.LogicalSwitch, dns ovnnb.DNS) bool {
+				return slices.Contains(ls.DNSRecords, dns.UUID)
 			},
-		},
-	}, nil
+		}
+	}
+	dnsRows, found, err := mcp.SelectWithParentFilter(ctx, client, &ovnnb.DNS{}, parent)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return mcp.NoParentResult("dns", parent.Kind)
+	}
+
+	switches, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.LogicalSwitch{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Index the switches referencing each DNS row
+	switchesByDNS := make(map[string][]map[string]string)
+	for _, ls := range switches {
+		for _, dnsUUID := range ls.DNSRecords {
+			switchesByDNS[dnsUUID] = append(switchesByDNS[dnsUUID], map[string]string{
+				"uuid": ls.UUID,
+				"name": ls.Name,
+			})
+		}
+	}
+
+	hostname := strings.ToLower(args.Hostname)
+	var entries []map[string]interface{}
+	for _, dns := range dnsRows {
+		// OVN answers queries case-insensitively, the records may hold
+		// several space separated addresses per hostname
+		records := make(map[string][]string, len(dns.Records))
+		for name, addresses := range dns.Records {
+			records[strings.ToLower(name)] = strings.Fields(addresses)
+		}
+
+		entry := map[string]interface{}{
+			"uuid":         dns.UUID,
+			"records":      records,
+			"options":      dns.Options,
+			"external_ids": dns.ExternalIDs,
+			"switches":     switchesByDNS[dns.UUID],
+		}
+		if hostname != "" {
+			addresses, ok := records[hostname]
+			if !ok {
+				continue
+			}
+			entry["addresses"] = addresses
+		}
+		entries = append(entries, entry)
+	}
+
+	if args.CountOnly {
+		return mcp.CountResult(len(entries))
+	}
+
+	entries, page := mcp.Paginate(entries, args.Limit, args.Offset)
+
+	result := mcp.ListResult{
+		Data:       map[string]any{"dns": entries},
+		Count:      len(entries),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "DNS rows hold the hostname to IP records OVN answers DNS queries with, for logical switch ports on the switches that reference them in their dns_records column. ovn-controller intercepts DNS requests from those ports and replies with the addresses of a matching hostname itself, without forwarding the request; queries for other names go to the port's usual DNS server. Records are shown as a list of addresses per lowercased hostname, as a hostname can resolve to several IPv4 and IPv6 addresses. Unreferenced DNS rows are never used.",
+	}
+
+	return mcp.NewResult(result)
 }
 
 // NewServer creates a new OVN NB MCP server
-func NewServer(host string, port int) (*Server, error) {
+func NewServer(host string, port int, opts ...mcp.Option) (*Server, error) {
 
-	// Create OVSDB client model using generated code
-	dbModel, err := ovnnb.FullDatabaseModel()
+	// Use the OVSDB client model shared by every server in the process
+	dbModel, err := ovnnb.DatabaseModel()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create database model: %w", err)
 	}
 
-	server := mcpsdk.NewServer(&mcpsdk.Implementation{
+	base, err := mcp.NewBaseServer(&mcpsdk.Implementation{
 		Name:    "ovn-nb-mcp",
 		Title:   "OVN NB MCP Server",
 		Version: "0.1.0",
-	}, nil)
+	}, dbModel, defaultEndpoint, opts...)
+	if err != nil {
+		return nil, err
+	}
 
 	s := Server{
-		Server:  server,
-		dbModel: dbModel,
+		BaseServer: base,
 	}
 
 	// Register tools inline
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_logical_switches",
-		Description: "List all logical switches in OVN NB database. Logical switches are the primary networking entities that connect logical ports.",
+		Description: "List all logical switches in OVN NB database. Logical switches are the primary networking entities that connect logical ports. Set count_only to only get the number of matching rows.",
 	}, s.ListLogicalSwitches)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "get_logical_switch_by_uuid",
+		Description: "Get a single logical switch from OVN NB database by its UUID.",
+	}, s.GetLogicalSwitchByUUID)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "get_logical_switch",
+		Description: "Get a single logical switch from OVN NB database by name, with its ports, ACLs and QoS rules inlined rather than as UUIDs.",
+	}, s.GetLogicalSwitch)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_logical_switch_ports",
-		Description: "List all logical switch ports in OVN NB database. Logical switch ports connect to logical switches and represent network endpoints.",
+		Description: "List all logical switch ports in OVN NB database. Logical switch ports connect to logical switches and represent network endpoints. Filter by switch_filter and type, e.g. type router for the ports connecting switches to routers. Set count_only to only get the number of matching rows.",
 	}, s.ListLogicalSwitchPorts)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_logical_routers",
-		Description: "List all logical routers in OVN NB database. Logical routers provide Layer 3 routing between logical switches.",
+		Description: "List all logical routers in OVN NB database. Logical routers provide Layer 3 routing between logical switches. Set count_only to only get the number of matching rows.",
 	}, s.ListLogicalRouters)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_logical_router_ports",
+		Description: "List logical router ports in OVN NB database, optionally only those of one router. Shows each port's MAC address, networks and peer. Set count_only to only get the number of matching rows.",
+	}, s.ListLogicalRouterPorts)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "get_topology",
+		Description: "Get the whole logical network shape of the OVN NB database in one call, as a compact graph: logical switches and routers as nodes with only their names and types, and the links between them as edges with the pair of ports that connects them and their relationship. Switches are linked to routers through switch ports of type router, and routers to each other through peered router ports.",
+	}, s.GetTopology)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "topology_by_router",
+		Description: "Show the logical switches in OVN NB database grouped by the logical router they are attached to, as a tree with each switch's subnets and port count, plus the standalone switches not attached to any router. Gives a per-tenant view of the network layout.",
+	}, s.TopologyByRouter)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "find_distributed_gateway_ports",
+		Description: "Find the distributed gateway ports of logical routers in OVN NB database, with their gateway chassis or HA chassis group, external networks and whether NAT is centralized or distributed. Flags routers with NAT or load balancers but no gateway.",
+	}, s.FindDistributedGatewayPorts)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "gateway_router_services",
+		Description: "Show everything about how a logical router in the OVN NB database exposes and translates traffic in one view: its NAT rules, the load balancers applied to it with their VIPs, its static routes, the router ports scheduled on gateway chassis with their chassis priorities, and the DNS records served on its attached switches. Useful for debugging external connectivity through a gateway router.",
+	}, s.GatewayRouterServices)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "external_reachable_ports",
+		Description: "Find which logical switch ports in OVN NB database have a path through the switch and router topology to an external (localnet) network, and which are isolated. Returns the path and gateway used by each reachable port and the list of isolated ports, for answering why workloads cannot reach outside OVN.",
+	}, s.ExternalReachablePorts)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "check_router_networks",
+		Description: "Check the networks of a logical router's ports for duplicate or overlapping subnets, which make routing ambiguous. Optionally cross-checks against the ports of every other logical router.",
+	}, s.CheckRouterNetworks)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "validate_router_port_subnets",
+		Description: "Validate the networks of a logical router's ports against the subnets (other_config subnet and ipv6_prefix) of the switches they are attached to. Flags ports whose gateway IP is missing, outside the switch subnet or has a different prefix length, a common provisioning error that leaves addresses assigned from the subnet unrouted.",
+	}, s.ValidateRouterPortSubnets)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_acls",
-		Description: "List all ACLs in OVN NB database. ACLs define security policies for logical switches.",
+		Description: "List all ACLs in OVN NB database with their decoded logging and sampling configuration. ACLs define security policies for logical switches. Can be restricted to one direction (from-lport or to-lport), one action such as drop, or the ACLs that log or sample traffic. Set count_only to only get the number of matching rows.",
 	}, s.ListACLs)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_load_balancers",
-		Description: "List all load balancers in OVN NB database. Load balancers distribute incoming traffic across multiple backend servers.",
+		Description: "List all load balancers in OVN NB database. Load balancers distribute incoming traffic across multiple backend servers. Set count_only to only get the number of matching rows.",
 	}, s.ListLoadBalancers)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_nat_rules",
-		Description: "List all NAT rules in OVN NB database. NAT rules modify packet headers to change source or destination addresses.",
+		Description: "List all NAT rules in OVN NB database. NAT rules modify packet headers to change source or destination addresses. Set count_only to only get the number of matching rows.",
 	}, s.ListNATRules)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_logical_router_static_routes",
+		Description: "List all logical router static routes in OVN NB database. Static routes forward traffic for an IP prefix to a nexthop, optionally through a specific output port. Set count_only to only get the number of matching rows.",
+	}, s.ListLogicalRouterStaticRoutes)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_port_groups",
-		Description: "List all port groups in OVN NB database. Port groups are collections of logical switch ports.",
+		Description: "List all port groups in OVN NB database. Port groups are collections of logical switch ports. Set count_only to only get the number of matching rows.",
 	}, s.ListPortGroups)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_address_sets",
-		Description: "List all address sets in OVN NB database. Address sets are collections of IP addresses.",
+		Description: "List all address sets in OVN NB database. Address sets are collections of IP addresses. Set count_only to only get the number of matching rows.",
 	}, s.ListAddressSets)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_qos_rules",
-		Description: "List all QoS rules in OVN NB database. QoS rules define bandwidth and traffic shaping policies.",
+		Description: "List all QoS rules in OVN NB database. QoS rules define bandwidth and traffic shaping policies. Set count_only to only get the number of matching rows.",
 	}, s.ListQoSRules)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "port_dscp",
+		Description: "Show the effective QoS applied to a logical switch port in each direction: the DSCP marking and rate limit, and the QoS rules on its switch whose match applies to the port. Reports no marking when no rule sets DSCP.",
+	}, s.PortDSCP)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_meters",
-		Description: "List all meters in OVN NB database. Meters provide rate limiting and policing capabilities.",
+		Description: "List all meters in OVN NB database. Meters provide rate limiting and policing capabilities. Set count_only to only get the number of matching rows.",
 	}, s.ListMeters)
 
-	return &s, nil
-}
-
-// Start starts the MCP server on the specified address
-func (s *Server) Start(ctx context.Context, addr string) error {
-	// Create HTTP server using Streamable HTTP handler
-	streamableHandler := mcpsdk.NewStreamableHTTPHandler(func(request *http.Request) *mcpsdk.Server {
-		return s.Server
-	}, nil)
-
-	s.httpServer = &http.Server{
-		Addr:    addr,
-		Handler: streamableHandler,
-	}
-
-	// Start server in a goroutine
-	go func() {
-		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			// Log error if we had a logger
-		}
-	}()
-
-	return nil
-}
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_bfd",
+		Description: "List all BFD sessions in OVN NB database. BFD sessions monitor next hops of logical router ports so that routes can fail over when a next hop goes down. Set count_only to only get the number of matching rows.",
+	}, s.ListBFD)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_static_mac_bindings",
+		Description: "List static MAC bindings in OVN NB database, the configured IP to MAC address mappings of neighbours of logical router ports. Filter by logical router port. Set count_only to only get the number of matching rows.",
+	}, s.ListStaticMACBindings)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_gateway_chassis",
+		Description: "List all gateway chassis in OVN NB database. Gateway chassis define which chassis, in priority order, host a distributed gateway port for failover. Set count_only to only get the number of matching rows.",
+	}, s.ListGatewayChassis)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_ha_chassis_groups",
+		Description: "List all HA chassis groups in OVN NB database. HA chassis groups define which chassis, in priority order, a gateway or external port fails over between. Set resolve to expand each group's HA chassis with their priorities. Set count_only to only get the number of matching rows.",
+	}, s.ListHAChassisGroups)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "check_acl_priorities",
+		Description: "Audit ACL priorities in OVN NB database. Flags ACLs whose priority exceeds the maximum of 32767 or falls into a reserved priority range, the reserved ranges can be overridden.",
+	}, s.CheckACLPriorities)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "find_shadowed_acls",
+		Description: "Find ACLs on logical switches and port groups that never fire because a higher priority ACL in the same direction and tier matches every packet they match. Returns each shadowed ACL with the ACL shadowing it. Only simple matches of == comparisons and predicates joined by && are compared.",
+	}, s.FindShadowedACLs)
+
+	mcp.AddWriteTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "create_acl",
+		Description: "Create an ACL and add it to a logical switch or port group in one transaction. The direction and action are validated before anything is written. Returns the UUID of the new ACL. Set dry_run to get the OVSDB operations without running them.",
+	}, s.CreateACL)
+
+	mcp.AddWriteTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "delete_acl",
+		Description: "Delete an ACL by UUID, removing it from every logical switch and port group that references it in the same transaction. Set dry_run to get the OVSDB operations without running them.",
+	}, s.DeleteACL)
+
+	mcp.AddWriteTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "create_logical_switch_port",
+		Description: "Create a logical switch port and add it to a logical switch in one transaction. The type and addresses are validated and the switch must exist before anything is written. Returns the UUID of the new port. Set dry_run to get the OVSDB operations without running them.",
+	}, s.CreateLogicalSwitchPort)
+
+	mcp.AddWriteTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "set_logical_switch_port_addresses",
+		Description: "Replace the addresses of a logical switch port, each a MAC address followed by its IP addresses, or router, unknown or dynamic. The addresses are validated before anything is written. Returns the UUID of the port. Set dry_run to get the OVSDB operations without running them.",
+	}, s.SetLogicalSwitchPortAddresses)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_dns",
+		Description: "List all DNS entries in OVN NB database with their decoded records and the logical switches that reference them. Can be filtered to the entries resolving a hostname, or to those used by a logical switch. Set count_only to only get the number of matching rows.",
+	}, s.ListDNS)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_dhcp_options",
+		Description: "List all DHCP options in OVN NB database. DHCP options configure OVN's native DHCP server for a subnet and are referenced by logical switch ports. Set count_only to only get the number of matching rows.",
+	}, s.ListDHCPOptions)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_nb_global",
+		Description: "List the NB Global row in OVN NB database with its options and the nb_cfg, sb_cfg and hv_cfg sequence numbers, which show whether southbound and the chassis have caught up with northbound. Set count_only to only get the number of matching rows.",
+	}, s.ListNBGlobal)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "minimal_repro",
+		Description: "Extract the smallest self-contained subset of the OVN NB database around an object, for reproducing an issue offline. Follows references to and from the target (e.g. switch, ports, port groups, ACLs, address sets) up to a bounded depth and returns the rows as a replayable ovsdb-client insert transaction.",
+	}, s.MinimalRepro)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "ovsdb_select",
+		Description: "Run a select on any table of the OVN NB database with [column, function, value] conditions, which rows must all match unless match_all is false, optionally returning only some columns. An escape hatch for querying columns the other tools don't filter on; use get_schema to find the tables and columns. Unknown tables and columns are rejected with the valid names.",
+	}, s.OVSDBSelect)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "count_by",
+		Description: "Count the rows of any table of the OVN NB database grouped by the value of a column, e.g. Logical_Switch_Port by type or up, without returning the rows. Use for summary questions such as how many ports are down.",
+	}, s.CountBy)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "get_schema",
+		Description: "Get the schema of the OVN NB database: its tables, and each table's columns with their types, whether they are sets, maps or references to other tables, and the values allowed for enums. Optionally describe a single table. Useful for finding which columns exist before filtering on them. Needs no database connection.",
+	}, mcp.GetSchema(ovnnb.DatabaseSchema()))
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "describe_schema",
+		Description: "List the tables of the OVN NB database with the type of each of their columns, as compact JSON keyed by table. A quick overview of what can be queried before choosing a tool; use get_schema for the details of a table. Needs no database connection.",
+	}, mcp.DescribeSchema(ovnnb.DatabaseSchema()))
+
+	mcp.AddLongRunningTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "watch_table",
+		Description: "Watch one table of the OVN NB database (e.g. Logical_Switch) for a while and report its row inserts, updates and deletes as they happen. Each change is streamed to the client as a progress notification when the call has a progress token, and all of them are returned when the watch ends after duration_seconds (30 by default, at most 300) or max_events changes. Optionally only watch rows whose name matches a filter. The monitor is removed when the watch ends or the session closes.",
+	}, s.WatchTable)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "find_by_external_id_key",
+		Description: "Find the rows of every table in the OVN NB database that have an external_ids key, whatever its value, grouped by table. Optionally only match rows whose value for the key contains a substring. Useful for finding every object owned or labelled by a controller, e.g. by the k8s.ovn.org/owner key.",
+	}, s.FindByExternalIDKey)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "row_history",
+		Description: "Get the current value of any row in the OVN NB database by UUID, with its recent changes as timestamped before and after snapshots when the server is recording row history. OVSDB does not keep history itself, so only changes made since the server started recording are available.",
+	}, s.RowHistory)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "ping_database",
+		Description: "Check that the OVN NB database can be reached, with the connection latency and the schema version it serves. Use it before running other tools when the database may be down. Results are cached for a few seconds.",
+	}, s.PingDatabase)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "server_info",
+		Description: "Get the schema version of the OVN NB database and, from the ovsdb-server _Server database, whether it is standalone, clustered or a relay, whether the server is the cluster leader and connected to the cluster, and its cluster and server IDs. Use it to find the leader of a clustered database and the version to quote in bug reports.",
+	}, s.ServerInfo)
 
-// Stop stops the MCP server
-func (s *Server) Stop(ctx context.Context) error {
-	if s.httpServer != nil {
-		return s.httpServer.Shutdown(ctx)
-	}
-	return nil
+	return &s, nil
 }
@@ -0,0 +1,367 @@
+package ovnnb
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type FindShadowedACLsArgs struct {
+	SwitchFilter    string `json:"switch_filter,omitempty" jsonschema:"only check the ACLs of the logical switch with this name"`
+	PortGroupFilter string `json:"port_group_filter,omitempty" jsonschema:"only check the ACLs of the port group with this name"`
+}
+
+// ACLSummary is the part of an ACL needed to see why it is shadowed
+type ACLSummary struct {
+	UUID      string `json:"uuid"`
+	Name      string `json:"name,omitempty"`
+	Priority  int    `json:"priority"`
+	Direction string `json:"direction"`
+	Tier      int    `json:"tier"`
+	Match     string `json:"match"`
+	Action    string `json:"action"`
+}
+
+// ShadowedACL is an ACL that never matches because every packet it matches
+// is matched first by a higher priority ACL that decides the verdict
+type ShadowedACL struct {
+	Parent     string     `json:"parent"`
+	ParentType string     `json:"parent_type"`
+	Shadowed   ACLSummary `json:"shadowed"`
+	Shadowing  ACLSummary `json:"shadowing"`
+	// Redundant is set when both ACLs have the same action, so removing the
+	// shadowed one changes nothing. Otherwise the shadowed ACL's verdict is
+	// never applied, which is usually a policy bug.
+	Redundant bool `json:"redundant"`
+}
+
+// aclClause is one term of a match made of terms joined by &&, either a
+// field compared with == to a value or set of values, or a predicate such as
+// ip4 or tcp when values is nil
+type aclClause struct {
+	field  string
+	values []string
+}
+
+// aclField matches the fields and predicates of a match, e.g. ip4.src,
+// ct.est or reg0[7]
+var aclField = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*(\[[0-9.]+\])?$`)
+
+// parseACLMatch parses a match that is a conjunction of == comparisons and
+// predicates. Matches using anything else, such as ||, !, != or ranges, are
+// not comparable and false is returned.
+func parseACLMatch(match string) ([]aclClause, bool) {
+	match = strings.TrimSpace(match)
+	if match == "" || match == "1" {
+		// Matches every packet
+		return nil, true
+	}
+	if strings.ContainsAny(match, "()!<>|") {
+		return nil, false
+	}
+	var clauses []aclClause
+	for _, term := range strings.Split(match, "&&") {
+		term = strings.TrimSpace(term)
+		field, value, ok := strings.Cut(term, "==")
+		if !ok {
+			if !aclField.MatchString(term) {
+				return nil, false
+			}
+			clauses = append(clauses, aclClause{field: term})
+			continue
+		}
+		field = strings.TrimSpace(field)
+		value = strings.TrimSpace(value)
+		if !aclField.MatchString(field) || value == "" || strings.Contains(value, "..") {
+			return nil, false
+		}
+		var values []string
+		if strings.HasPrefix(value, "{") {
+			if !strings.HasSuffix(value, "}") {
+				return nil, false
+			}
+			for _, v := range strings.FieldsFunc(value[1:len(value)-1], func(r rune) bool { return r == ',' || r == ' ' }) {
+				values = append(values, normalizeACLValue(v))
+			}
+		} else {
+			values = []string{normalizeACLValue(value)}
+		}
+		if len(values) == 0 {
+			return nil, false
+		}
+		clauses = append(clauses, aclClause{field: field, values: values})
+	}
+	return clauses, true
+}
+
+// normalizeACLValue strips quotes and normalizes case so equal values
+// compare equal
+func normalizeACLValue(v string) string {
+	v = strings.Trim(strings.TrimSpace(v), `"`)
+	if strings.HasPrefix(v, "$") || strings.HasPrefix(v, "@") {
+		// Address set and port group names are case sensitive
+		return v
+	}
+	return strings.ToLower(v)
+}
+
+// aclValueContains reports whether every packet with value b has value a,
+// either because they are equal or a is a prefix containing b
+func aclValueContains(a, b string) bool {
+	if a == b {
+		return true
+	}
+	pa, err := netip.ParsePrefix(a)
+	if err != nil {
+		return false
+	}
+	pb, err := netip.ParsePrefix(b)
+	if err != nil {
+		addr, err := netip.ParseAddr(b)
+		if err != nil {
+			return false
+		}
+		pb = netip.PrefixFrom(addr, addr.BitLen())
+	}
+	return pa.Addr().Is4() == pb.Addr().Is4() && pa.Bits() <= pb.Bits() && pa.Contains(pb.Addr())
+}
+
+// impliesPredicate reports whether clauses only match packets for which the
+// predicate holds, e.g. ip4.src == ... implies ip4, as OVN adds a field's
+// prerequisites to the match
+func impliesPredicate(clauses []aclClause, predicate string) bool {
+	for _, c := range clauses {
+		if c.field == predicate || strings.HasPrefix(c.field, predicate+".") {
+			return true
+		}
+		if predicate == "ip" && (c.field == "ip4" || c.field == "ip6" || strings.HasPrefix(c.field, "ip4.") || strings.HasPrefix(c.field, "ip6.")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchCovers reports whether every packet matching inner also matches
+// outer
+func matchCovers(outer, inner []aclClause) bool {
+	for _, o := range outer {
+		if o.values == nil {
+			if !impliesPredicate(inner, o.field) {
+				return false
+			}
+			continue
+		}
+		covered := false
+		for _, i := range inner {
+			if i.field != o.field || i.values == nil {
+				continue
+			}
+			subset := true
+			for _, v := range i.values {
+				if !slices.ContainsFunc(o.values, func(ov string) bool { return aclValueContains(ov, v) }) {
+					subset = false
+					break
+				}
+			}
+			if subset {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+func summarizeACL(acl ovnnb.ACL) ACLSummary {
+	s := ACLSummary{
+		UUID:      acl.UUID,
+		Priority:  acl.Priority,
+		Direction: acl.Direction,
+		Tier:      acl.Tier,
+		Match:     acl.Match,
+		Action:    acl.Action,
+	}
+	if acl.Name != nil {
+		s.Name = *acl.Name
+	}
+	return s
+}
+
+// aclStage is the pipeline stage an ACL is evaluated in. ACLs only shadow
+// ACLs in the same stage.
+type aclStage struct {
+	direction    string
+	tier         int
+	applyAfterLB bool
+}
+
+// findShadowedACLs returns the ACLs of one switch or port group that are
+// shadowed, and the number whose match could not be compared
+func findShadowedACLs(parent, parentType string, acls []ovnnb.ACL) ([]ShadowedACL, int) {
+	type parsedACL struct {
+		acl     ovnnb.ACL
+		clauses []aclClause
+	}
+	stages := make(map[aclStage][]parsedACL)
+	notComparable := 0
+	for _, acl := range acls {
+		clauses, ok := parseACLMatch(acl.Match)
+		if !ok {
+			notComparable++
+			continue
+		}
+		stage := aclStage{
+			direction:    acl.Direction,
+			tier:         acl.Tier,
+			applyAfterLB: acl.Options["apply-after-lb"] == "true",
+		}
+		stages[stage] = append(stages[stage], parsedACL{acl: acl, clauses: clauses})
+	}
+
+	var shadowed []ShadowedACL
+	for _, stage := range stages {
+		sort.SliceStable(stage, func(i, j int) bool { return stage[i].acl.Priority > stage[j].acl.Priority })
+		for i, inner := range stage {
+			for _, outer := range stage[:i] {
+				// pass moves on to the next tier rather than deciding the
+				// verdict, and ACLs of equal priority are not ordered
+				if outer.acl.Action == ovnnb.ACLActionPass || outer.acl.Priority == inner.acl.Priority {
+					continue
+				}
+				if matchCovers(outer.clauses, inner.clauses) {
+					shadowed = append(shadowed, ShadowedACL{
+						Parent:     parent,
+						ParentType: parentType,
+						Shadowed:   summarizeACL(inner.acl),
+						Shadowing:  summarizeACL(outer.acl),
+						Redundant:  outer.acl.Action == inner.acl.Action,
+					})
+					break
+				}
+			}
+		}
+	}
+	return shadowed, notComparable
+}
+
+func (s *Server) FindShadowedACLs(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[FindShadowedACLsArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	if args.SwitchFilter != "" && args.PortGroupFilter != "" {
+		return nil, fmt.Errorf("only one of switch_filter and port_group_filter can be set")
+	}
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	acls, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.ACL{})
+	if err != nil {
+		return nil, err
+	}
+	aclByUUID := make(map[string]ovnnb.ACL, len(acls))
+	for _, acl := range acls {
+		aclByUUID[acl.UUID] = acl
+	}
+	resolve := func(uuids []string) []ovnnb.ACL {
+		resolved := make([]ovnnb.ACL, 0, len(uuids))
+		for _, uuid := range uuids {
+			if acl, ok := aclByUUID[uuid]; ok {
+				resolved = append(resolved, acl)
+			}
+		}
+		return resolved
+	}
+
+	shadowed := []ShadowedACL{}
+	checked := 0
+	notComparable := 0
+	check := func(parent, parentType string, uuids []string) {
+		found, skipped := findShadowedACLs(parent, parentType, resolve(uuids))
+		shadowed = append(shadowed, found...)
+		checked += len(uuids)
+		notComparable += skipped
+	}
+
+	if args.PortGroupFilter == "" {
+		switches, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.LogicalSwitch{})
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, ls := range switches {
+			if args.SwitchFilter != "" && ls.Name != args.SwitchFilter {
+				continue
+			}
+			found = true
+			check(ls.Name, "logical_switch", ls.ACLs)
+		}
+		if args.SwitchFilter != "" && !found {
+			return &mcpsdk.CallToolResultFor[map[string]any]{
+				IsError: true,
+				Content: []mcpsdk.Content{
+					&mcpsdk.TextContent{
+						Text: fmt.Sprintf("No logical switch found with name %s", args.SwitchFilter),
+					},
+				},
+			}, nil
+		}
+	}
+	if args.SwitchFilter == "" {
+		portGroups, err := mcp.ExecuteSelectQuery(ctx, client, &ovnnb.PortGroup{})
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, pg := range portGroups {
+			if args.PortGroupFilter != "" && pg.Name != args.PortGroupFilter {
+				continue
+			}
+			found = true
+			check(pg.Name, "port_group", pg.ACLs)
+		}
+		if args.PortGroupFilter != "" && !found {
+			return &mcpsdk.CallToolResultFor[map[string]any]{
+				IsError: true,
+				Content: []mcpsdk.Content{
+					&mcpsdk.TextContent{
+						Text: fmt.Sprintf("No port group found with name %s", args.PortGroupFilter),
+					},
+				},
+			}, nil
+		}
+	}
+
+	sort.Slice(shadowed, func(i, j int) bool {
+		if shadowed[i].Parent != shadowed[j].Parent {
+			return shadowed[i].Parent < shadowed[j].Parent
+		}
+		if shadowed[i].Shadowed.Priority != shadowed[j].Shadowed.Priority {
+			return shadowed[i].Shadowed.Priority > shadowed[j].Shadowed.Priority
+		}
+		return shadowed[i].Shadowed.UUID < shadowed[j].Shadowed.UUID
+	})
+
+	result := map[string]interface{}{
+		"shadowed_acls":  shadowed,
+		"count":          len(shadowed),
+		"checked":        checked,
+		"not_comparable": notComparable,
+		"context":        "An ACL is shadowed when a higher priority ACL with the same direction and tier, on the same switch or port group, matches every packet it matches and decides the verdict (any action but pass), so the shadowed ACL never fires. Redundant ACLs have the same action as the one shadowing them and can be removed; the others are policy bugs, as their action is never applied. Only matches made of == comparisons and predicates joined by && are compared, with address sets and port groups compared by name and IP prefixes by containment, so not_comparable ACLs (using ||, !, != or ranges) may be shadowed without being reported. ACLs of a port group also interact with those of the switches its ports are on, which is not checked.",
+	}
+
+	return mcp.NewResult(result)
+}
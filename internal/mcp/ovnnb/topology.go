@@ -0,0 +1,166 @@
+package ovnnb
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type GetTopologyArgs struct {
+}
+
+// Types of the nodes in the topology graph
+const (
+	topologySwitch = "switch"
+	topologyRouter = "router"
+)
+
+// Relationships of the edges in the topology graph
+const (
+	// A switch port of type router and the router port it names
+	topologySwitchRouter = "switch_router"
+	// Two router ports whose peer columns name each other
+	topologyRouterPeer = "router_peer"
+)
+
+// TopologyNode is a logical switch or router in the topology graph
+type TopologyNode struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TopologyEdge is a link between two nodes of the topology graph through a
+// pair of ports: a switch port of type router and its router port, or two
+// peered router ports
+type TopologyEdge struct {
+	From         string `json:"from"`
+	FromPort     string `json:"from_port"`
+	To           string `json:"to"`
+	ToPort       string `json:"to_port"`
+	Relationship string `json:"relationship"`
+}
+
+func (s *Server) GetTopology(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[GetTopologyArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	// A row that can't be decoded leaves a gap in the graph rather than
+	// hiding all of it
+	var warnings []string
+	switches, skipped, err := mcp.ExecuteSelectQueryLenient(ctx, client, &ovnnb.LogicalSwitch{})
+	if err != nil {
+		return nil, err
+	}
+	warnings = append(warnings, skipped...)
+	switchPorts, skipped, err := mcp.ExecuteSelectQueryLenient(ctx, client, &ovnnb.LogicalSwitchPort{})
+	if err != nil {
+		return nil, err
+	}
+	warnings = append(warnings, skipped...)
+	routers, skipped, err := mcp.ExecuteSelectQueryLenient(ctx, client, &ovnnb.LogicalRouter{})
+	if err != nil {
+		return nil, err
+	}
+	warnings = append(warnings, skipped...)
+	routerPorts, skipped, err := mcp.ExecuteSelectQueryLenient(ctx, client, &ovnnb.LogicalRouterPort{})
+	if err != nil {
+		return nil, err
+	}
+	warnings = append(warnings, skipped...)
+
+	lspByUUID := make(map[string]ovnnb.LogicalSwitchPort, len(switchPorts))
+	for _, lsp := range switchPorts {
+		lspByUUID[lsp.UUID] = lsp
+	}
+	lrpByUUID := make(map[string]ovnnb.LogicalRouterPort, len(routerPorts))
+	for _, lrp := range routerPorts {
+		lrpByUUID[lrp.UUID] = lrp
+	}
+	// The name of the router owning each router port, keyed by port name
+	lrpRouter := make(map[string]string)
+	for _, lr := range routers {
+		for _, u := range lr.Ports {
+			if lrp, ok := lrpByUUID[u]; ok {
+				lrpRouter[lrp.Name] = lr.Name
+			}
+		}
+	}
+
+	nodes := make([]TopologyNode, 0, len(switches)+len(routers))
+	edges := []TopologyEdge{}
+	for _, ls := range switches {
+		nodes = append(nodes, TopologyNode{Name: ls.Name, Type: topologySwitch})
+		for _, u := range ls.Ports {
+			lsp, ok := lspByUUID[u]
+			if !ok || lsp.Type != "router" {
+				continue
+			}
+			name := lsp.Options["router-port"]
+			router, ok := lrpRouter[name]
+			if !ok {
+				continue
+			}
+			edges = append(edges, TopologyEdge{
+				From:         ls.Name,
+				FromPort:     lsp.Name,
+				To:           router,
+				ToPort:       name,
+				Relationship: topologySwitchRouter,
+			})
+		}
+	}
+	for _, lr := range routers {
+		nodes = append(nodes, TopologyNode{Name: lr.Name, Type: topologyRouter})
+		for _, u := range lr.Ports {
+			lrp, ok := lrpByUUID[u]
+			if !ok || lrp.Peer == nil {
+				continue
+			}
+			peer, ok := lrpRouter[*lrp.Peer]
+			// Each pair of peered ports is one edge, from the port with
+			// the lower name
+			if !ok || lrp.Name > *lrp.Peer {
+				continue
+			}
+			edges = append(edges, TopologyEdge{
+				From:         lr.Name,
+				FromPort:     lrp.Name,
+				To:           peer,
+				ToPort:       *lrp.Peer,
+				Relationship: topologyRouterPeer,
+			})
+		}
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Type != nodes[j].Type {
+			return nodes[i].Type < nodes[j].Type
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].FromPort < edges[j].FromPort
+	})
+
+	result := map[string]interface{}{
+		"nodes":        nodes,
+		"edges":        edges,
+		"switch_count": len(switches),
+		"router_count": len(routers),
+		"context":      "The logical network as a graph. Nodes are logical switches and routers by name. Edges with the switch_router relationship link a switch to a router through the switch port of type router (from_port) and the router port it names in options:router-port (to_port). Edges with the router_peer relationship link two routers through a pair of router ports whose peer columns name each other. Switches without edges are only reachable at layer 2, e.g. through a localnet port. Use the list and get tools for the details of a node or port.",
+	}
+	if len(warnings) > 0 {
+		result["warnings"] = warnings
+	}
+
+	return mcp.NewResult(result)
+}
@@ -0,0 +1,147 @@
+package ovnsb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+// Defaults for binding_churn
+const (
+	defaultChurnWindow = 15 * time.Minute
+	defaultChurnTop    = 10
+)
+
+type BindingChurnArgs struct {
+	WindowMinutes int `json:"window_minutes,omitempty" jsonschema:"the number of minutes to look back, 15 when not set"`
+	Top           int `json:"top,omitempty" jsonschema:"the number of chassis to return, highest churn first, 10 when not set"`
+}
+
+// ChassisChurn is the number of port bindings claimed by and released from
+// a chassis
+type ChassisChurn struct {
+	Chassis     string `json:"chassis"`
+	ChassisUUID string `json:"chassis_uuid"`
+	Added       int    `json:"added"`
+	Removed     int    `json:"removed"`
+	Total       int    `json:"total"`
+}
+
+// bindingChassis returns the chassis column of a port binding row in OVSDB
+// notation, or "" if it is not bound
+func bindingChassis(row map[string]any) string {
+	switch v := row["chassis"].(type) {
+	case ovsdb.UUID:
+		return v.GoUUID
+	case ovsdb.OvsSet:
+		if len(v.GoSet) == 1 {
+			if uuid, ok := v.GoSet[0].(ovsdb.UUID); ok {
+				return uuid.GoUUID
+			}
+		}
+	}
+	return ""
+}
+
+// BindingChurn reports the chassis that port bindings moved to and from
+// most during a recent window, from the changes recorded for row history
+func (s *Server) BindingChurn(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[BindingChurnArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	window := defaultChurnWindow
+	if args.WindowMinutes < 0 {
+		return nil, fmt.Errorf("window_minutes must not be negative")
+	} else if args.WindowMinutes > 0 {
+		window = time.Duration(args.WindowMinutes) * time.Minute
+	}
+	top := defaultChurnTop
+	if args.Top > 0 {
+		top = args.Top
+	}
+
+	history := s.ChangeLog()
+	if history == nil {
+		return &mcpsdk.CallToolResultFor[map[string]any]{
+			IsError: true,
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: "Port binding churn is computed from the row changes recorded by the server, and this server is not recording them. Start the server with row history enabled to record changes from then on.",
+				},
+			},
+		}, nil
+	}
+
+	since := time.Now().Add(-window)
+	changes, complete := history.Changes(ovnsb.PortBindingTable, since)
+
+	churn := make(map[string]*ChassisChurn)
+	count := func(uuid string) *ChassisChurn {
+		if churn[uuid] == nil {
+			churn[uuid] = &ChassisChurn{ChassisUUID: uuid}
+		}
+		return churn[uuid]
+	}
+	for _, change := range changes {
+		before := bindingChassis(change.Before)
+		after := bindingChassis(change.After)
+		if before == after {
+			continue
+		}
+		if before != "" {
+			count(before).Removed++
+		}
+		if after != "" {
+			count(after).Added++
+		}
+	}
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	chassis, err := mcp.ExecuteSelectQuery(ctx, client, &ovnsb.Chassis{})
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(chassis))
+	for _, ch := range chassis {
+		names[ch.UUID] = ch.Name
+	}
+
+	results := make([]ChassisChurn, 0, len(churn))
+	for uuid, c := range churn {
+		c.Chassis = names[uuid]
+		c.Total = c.Added + c.Removed
+		results = append(results, *c)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Total != results[j].Total {
+			return results[i].Total > results[j].Total
+		}
+		return results[i].ChassisUUID < results[j].ChassisUUID
+	})
+	chassisCount := len(results)
+	if len(results) > top {
+		results = results[:top]
+	}
+
+	result := map[string]interface{}{
+		"chassis":        results,
+		"chassis_count":  chassisCount,
+		"window_start":   since.UTC().Format(time.RFC3339),
+		"window_minutes": int(window / time.Minute),
+		"changes":        len(changes),
+		"complete":       complete,
+		"context":        "Port bindings claimed by (added) and released from (removed) each chassis during the window, highest churn first. A binding moving between chassis counts as removed from one and added to the other. A chassis with high churn may be unstable, or being drained, or pods may be rescheduling en masse. The chassis name is empty for chassis that have since been deleted. Only changes recorded by the server since it started are counted, and when complete is false older changes to port bindings have been dropped from the server's row history, so the window is not fully covered.",
+	}
+
+	return mcp.NewResult(result)
+}
@@ -0,0 +1,141 @@
+package ovnsb
+
+import (
+	"context"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type ListChassisPrivateArgs struct {
+	NameFilter string `json:"name_filter" jsonschema:"the name of the chassis to filter by"`
+	MatchMode  string `json:"match,omitempty" jsonschema:"how name_filter is matched: exact (default), prefix, substring or regex, prefix and substring matches are case-insensitive"`
+	CountOnly  bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset     int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+// sbNbCfg returns the nb_cfg of the SB_Global row, the sequence number each
+// chassis acknowledges in its Chassis_Private row once it has processed it,
+// and false when there is no SB_Global row
+func sbNbCfg(ctx context.Context, c client.Client) (int, bool, error) {
+	globals, err := mcp.ExecuteSelectQuery(ctx, c, &ovnsb.SBGlobal{})
+	if err != nil {
+		return 0, false, err
+	}
+	if len(globals) == 0 {
+		return 0, false, nil
+	}
+	return globals[0].NbCfg, true, nil
+}
+
+// addUpToDate sets up_to_date on the row of each Chassis_Private, whether
+// the chassis has processed the latest nb_cfg, when it is known
+func addUpToDate(private []ovnsb.ChassisPrivate, rows []map[string]any, nbCfg int, known bool) {
+	if !known {
+		return
+	}
+	for i, cp := range private {
+		rows[i]["up_to_date"] = cp.NbCfg >= nbCfg
+	}
+}
+
+// addChassisPrivate sets private on the row of each chassis to its
+// Chassis_Private row, matched by name, or nil when it has none, as for a
+// chassis registered by an OVN release older than the table
+func addChassisPrivate(ctx context.Context, c client.Client, chassis []ovnsb.Chassis, rows []map[string]any) error {
+	if len(chassis) == 0 {
+		return nil
+	}
+	private, err := mcp.ExecuteSelectQuery(ctx, c, &ovnsb.ChassisPrivate{})
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]ovnsb.ChassisPrivate, len(private))
+	for _, cp := range private {
+		byName[cp.Name] = cp
+	}
+	nbCfg, known, err := sbNbCfg(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	for i, ch := range chassis {
+		cp, ok := byName[ch.Name]
+		if !ok {
+			rows[i]["private"] = nil
+			continue
+		}
+		privateRows, err := mcp.MapRows(ovnsb.ChassisPrivateTable, ovnsb.DatabaseSchema(), []ovnsb.ChassisPrivate{cp})
+		if err != nil {
+			return err
+		}
+		addUpToDate([]ovnsb.ChassisPrivate{cp}, privateRows, nbCfg, known)
+		rows[i]["private"] = privateRows[0]
+	}
+	return nil
+}
+
+func (s *Server) ListChassisPrivate(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListChassisPrivateArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	matcher, err := mcp.NewNameMatcher(args.NameFilter, args.MatchMode)
+	if err != nil {
+		return nil, err
+	}
+
+	private := &ovnsb.ChassisPrivate{}
+	var conditions []model.Condition
+	if matcher.Exact() {
+		conditions = append(conditions, model.Condition{
+			Field:    &private.Name,
+			Function: ovsdb.ConditionEqual,
+			Value:    args.NameFilter,
+		})
+	}
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	results, err := mcp.ExecuteSelectQuery(ctx, client, private, conditions...)
+	if err != nil {
+		return nil, err
+	}
+	results = mcp.FilterByName(results, matcher, func(r ovnsb.ChassisPrivate) string { return r.Name })
+
+	if args.CountOnly {
+		return mcp.CountResult(len(results))
+	}
+
+	results, page := mcp.Paginate(results, args.Limit, args.Offset)
+
+	rows, err := mcp.MapRows(ovnsb.ChassisPrivateTable, ovnsb.DatabaseSchema(), results)
+	if err != nil {
+		return nil, err
+	}
+	nbCfg, known, err := sbNbCfg(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	addUpToDate(results, rows, nbCfg, known)
+
+	result := mcp.ListResult{
+		Data:       map[string]any{"chassis_private": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Chassis_Private holds the state each ovn-controller writes about its own chassis, split from Chassis so that frequent updates aren't sent to every other chassis. Rows are matched to their Chassis by name, and chassis references it. nb_cfg is the SB_Global nb_cfg sequence number the chassis last finished processing, and nb_cfg_timestamp when it did, in milliseconds since the epoch; up_to_date is true when it has caught up with the current SB_Global nb_cfg. A chassis that stays behind is not applying configuration changes, so check that its ovn-controller is running and connected. A row with an empty chassis belongs to a chassis that was deleted or has not registered.",
+	}
+	if known {
+		result.Data["sb_nb_cfg"] = nbCfg
+	}
+
+	return mcp.NewResult(result)
+}
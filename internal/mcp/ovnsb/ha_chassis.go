@@ -0,0 +1,223 @@
+package ovnsb
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type ListHAChassisArgs struct {
+	ChassisFilter string `json:"chassis_filter,omitempty" jsonschema:"the name of the chassis to filter by"`
+	CountOnly     bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit         int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset        int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type ListHAChassisGroupsArgs struct {
+	NameFilter string `json:"name_filter" jsonschema:"the name of the HA chassis group to filter by"`
+	MatchMode  string `json:"match,omitempty" jsonschema:"how name_filter is matched: exact (default), prefix, substring or regex, prefix and substring matches are case-insensitive"`
+	Resolve    bool   `json:"resolve,omitempty" jsonschema:"replace the UUIDs in ha_chassis with the HA_Chassis rows and their chassis names, highest priority first, this costs extra queries"`
+	CountOnly  bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset     int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+// sortByPriority orders HA chassis highest priority first, as that is the
+// order failover happens in
+func sortByPriority(chassis []ovnsb.HAChassis) {
+	sort.SliceStable(chassis, func(i, j int) bool { return chassis[i].Priority > chassis[j].Priority })
+}
+
+// chassisNames returns the names of the chassis the HA chassis reference,
+// keyed by UUID, as the chassis column only holds the UUID
+func chassisNames(ctx context.Context, c client.Client, haChassis []ovnsb.HAChassis) (map[string]string, error) {
+	var uuids []string
+	for _, hc := range haChassis {
+		if hc.Chassis != nil {
+			uuids = append(uuids, *hc.Chassis)
+		}
+	}
+	chassis := &ovnsb.Chassis{}
+	results, err := mcp.ExecuteSelectByUUIDs(ctx, c, chassis, &chassis.UUID, uuids)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(results))
+	for _, ch := range results {
+		names[ch.UUID] = ch.Name
+	}
+	return names, nil
+}
+
+// addChassisNames sets chassis_name on the row of each HA chassis whose
+// chassis is in names
+func addChassisNames(haChassis []ovnsb.HAChassis, rows []map[string]any, names map[string]string) {
+	for i, hc := range haChassis {
+		if hc.Chassis == nil {
+			continue
+		}
+		if name, ok := names[*hc.Chassis]; ok {
+			rows[i]["chassis_name"] = name
+		}
+	}
+}
+
+func (s *Server) ListHAChassis(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListHAChassisArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	var parent *mcp.ParentFilter[ovnsb.Chassis, ovnsb.HAChassis]
+	if args.ChassisFilter != "" {
+		chassis := &ovnsb.Chassis{}
+		parent = &mcp.ParentFilter[ovnsb.Chassis, ovnsb.HAChassis]{
+			Model: chassis,
+			Field: &chassis.Name,
+			Value: args.ChassisFilter,
+			Kind:  "chassis",
+			Keep: func(ch ovnsb.Chassis, hc ovnsb.HAChassis) bool {
+				return hc.Chassis != nil && *hc.Chassis == ch.UUID
+			},
+		}
+	}
+	results, found, err := mcp.SelectWithParentFilter(ctx, client, &ovnsb.HAChassis{}, parent)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return mcp.NoParentResult("ha_chassis", parent.Kind)
+	}
+	sortByPriority(results)
+	if args.CountOnly {
+		return mcp.CountResult(len(results))
+	}
+
+	results, page := mcp.Paginate(results, args.Limit, args.Offset)
+
+	rows, err := mcp.MapRows(ovnsb.HAChassisTable, ovnsb.DatabaseSchema(), results)
+	if err != nil {
+		return nil, err
+	}
+	names, err := chassisNames(ctx, client, results)
+	if err != nil {
+		return nil, err
+	}
+	addChassisNames(results, rows, names)
+
+	result := mcp.ListResult{
+		Data:       map[string]any{"ha_chassis": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "HA chassis are the members of HA chassis groups, each a chassis and its priority within the group, copied by ovn-northd from the northbound HA_Chassis rows. Rows are ordered by priority, highest first, and chassis_name is the name of the chassis each one references. An empty chassis column means the chassis has not registered or was deleted, so it can never become active. Use list_ha_chassis_groups to see which group each one belongs to.",
+	}
+
+	return mcp.NewResult(result)
+}
+
+func (s *Server) ListHAChassisGroups(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListHAChassisGroupsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	matcher, err := mcp.NewNameMatcher(args.NameFilter, args.MatchMode)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &ovnsb.HAChassisGroup{}
+	var conditions []model.Condition
+	if matcher.Exact() {
+		conditions = append(conditions, model.Condition{
+			Field:    &group.Name,
+			Function: ovsdb.ConditionEqual,
+			Value:    args.NameFilter,
+		})
+	}
+
+	c, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	results, err := mcp.ExecuteSelectQuery(ctx, c, group, conditions...)
+	if err != nil {
+		return nil, err
+	}
+	results = mcp.FilterByName(results, matcher, func(r ovnsb.HAChassisGroup) string { return r.Name })
+
+	if args.CountOnly {
+		return mcp.CountResult(len(results))
+	}
+
+	results, page := mcp.Paginate(results, args.Limit, args.Offset)
+
+	rows, err := mcp.MapRows(ovnsb.HAChassisGroupTable, ovnsb.DatabaseSchema(), results)
+	if err != nil {
+		return nil, err
+	}
+	if args.Resolve {
+		if err := s.resolveHAChassis(ctx, c, results, rows); err != nil {
+			return nil, err
+		}
+	}
+
+	result := mcp.ListResult{
+		Data:       map[string]any{"ha_chassis_groups": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "HA chassis groups are the chassis, each with a priority, that a gateway router port or an external port fails over between; ovn-northd copies them from the northbound HA_Chassis_Group rows and port bindings reference them from their ha_chassis_group column. The members are in ha_chassis. The highest priority member whose chassis is alive, as detected by BFD between the chassis' tunnel endpoints, claims the port; if it fails, the next highest takes over, so members with equal priorities make the active chassis unpredictable. ref_chassis lists the chassis that need BFD sessions to the members because they host ports behind the gateway. Set resolve to get each member's chassis name and priority, highest first.",
+	}
+
+	return mcp.NewResult(result)
+}
+
+// resolveHAChassis replaces the ha_chassis column of each group's row with
+// its HA_Chassis rows, highest priority first, each with the name of its
+// chassis. The rows of every group, and their chassis, are selected at once.
+func (s *Server) resolveHAChassis(ctx context.Context, c client.Client, groups []ovnsb.HAChassisGroup, rows []map[string]any) error {
+	var uuids []string
+	for _, group := range groups {
+		uuids = append(uuids, group.HaChassis...)
+	}
+	haChassis := &ovnsb.HAChassis{}
+	chassis, err := mcp.ExecuteSelectByUUIDs(ctx, c, haChassis, &haChassis.UUID, uuids)
+	if err != nil {
+		return err
+	}
+	byUUID := make(map[string]ovnsb.HAChassis, len(chassis))
+	for _, hc := range chassis {
+		byUUID[hc.UUID] = hc
+	}
+	names, err := chassisNames(ctx, c, chassis)
+	if err != nil {
+		return err
+	}
+
+	for i, group := range groups {
+		members := make([]ovnsb.HAChassis, 0, len(group.HaChassis))
+		for _, uuid := range group.HaChassis {
+			if hc, ok := byUUID[uuid]; ok {
+				members = append(members, hc)
+			}
+		}
+		sortByPriority(members)
+		memberRows, err := mcp.MapRows(ovnsb.HAChassisTable, ovnsb.DatabaseSchema(), members)
+		if err != nil {
+			return err
+		}
+		addChassisNames(members, memberRows, names)
+		rows[i]["ha_chassis"] = memberRows
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package ovnsb
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type ListMulticastGroupsArgs struct {
+	DatapathFilter string `json:"datapath_filter,omitempty" jsonschema:"the name of the datapath to filter by"`
+	Name           string `json:"name,omitempty" jsonschema:"only return groups with this name, e.g. _MC_flood, _MC_unknown or _MC_mrouter_flood"`
+	CountOnly      bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit          int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset         int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type ListIGMPGroupsArgs struct {
+	DatapathFilter string `json:"datapath_filter,omitempty" jsonschema:"the name of the datapath to filter by"`
+	Address        string `json:"address,omitempty" jsonschema:"only return groups with this multicast address, e.g. 239.1.1.1 or ff0e::1"`
+	CountOnly      bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit          int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset         int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+// datapathParent returns the parent filter selecting the rows of the
+// datapath named name, or nil when name is empty
+func datapathParent[C any](name string, keep func(ovnsb.DatapathBinding, C) bool) *mcp.ParentFilter[ovnsb.DatapathBinding, C] {
+	if name == "" {
+		return nil
+	}
+	datapathBinding := &ovnsb.DatapathBinding{}
+	return &mcp.ParentFilter[ovnsb.DatapathBinding, C]{
+		Model: datapathBinding,
+		Field: &datapathBinding.ExternalIDs,
+		Value: map[string]string{"name": name},
+		Kind:  "datapath",
+		Keep:  keep,
+	}
+}
+
+// addLogicalPorts sets logical_ports on each row to the names of the
+// logical ports whose port bindings are in the row's ports column, as the
+// column only holds their UUIDs. The port bindings of every row are
+// selected at once.
+func addLogicalPorts(ctx context.Context, c client.Client, ports [][]string, rows []map[string]any) error {
+	var uuids []string
+	for _, p := range ports {
+		uuids = append(uuids, p...)
+	}
+	portBinding := &ovnsb.PortBinding{}
+	bindings, err := mcp.ExecuteSelectByUUIDs(ctx, c, portBinding, &portBinding.UUID, uuids)
+	if err != nil {
+		return err
+	}
+	names := make(map[string]string, len(bindings))
+	for _, pb := range bindings {
+		names[pb.UUID] = pb.LogicalPort
+	}
+	for i, p := range ports {
+		logicalPorts := make([]string, 0, len(p))
+		for _, uuid := range p {
+			if name, ok := names[uuid]; ok {
+				logicalPorts = append(logicalPorts, name)
+			}
+		}
+		sort.Strings(logicalPorts)
+		rows[i]["logical_ports"] = logicalPorts
+	}
+	return nil
+}
+
+func (s *Server) ListMulticastGroups(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListMulticastGroupsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	group := &ovnsb.MulticastGroup{}
+	var conditions []model.Condition
+	if args.Name != "" {
+		conditions = append(conditions, model.Condition{
+			Field:    &group.Name,
+			Function: ovsdb.ConditionEqual,
+			Value:    args.Name,
+		})
+	}
+	parent := datapathParent(args.DatapathFilter, func(dp ovnsb.DatapathBinding, mg ovnsb.MulticastGroup) bool {
+		return mg.Datapath == dp.UUID
+	})
+	results, found, err := mcp.SelectWithParentFilter(ctx, client, group, parent, conditions...)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return mcp.NoParentResult("multicast_groups", parent.Kind)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Datapath != results[j].Datapath {
+			return results[i].Datapath < results[j].Datapath
+		}
+		return results[i].TunnelKey < results[j].TunnelKey
+	})
+	if args.CountOnly {
+		return mcp.CountResult(len(results))
+	}
+
+	results, page := mcp.Paginate(results, args.Limit, args.Offset)
+
+	rows, err := mcp.MapRows(ovnsb.MulticastGroupTable, ovnsb.DatabaseSchema(), results)
+	if err != nil {
+		return nil, err
+	}
+	ports := make([][]string, len(results))
+	for i, mg := range results {
+		ports[i] = mg.Ports
+	}
+	if err := addLogicalPorts(ctx, client, ports, rows); err != nil {
+		return nil, err
+	}
+
+	result := mcp.ListResult{
+		Data:       map[string]any{"multicast_groups": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Multicast groups are the sets of logical ports that ovn-northd has a datapath's packets flooded or multicast to, each identified by a tunnel key of 32768 or higher on its datapath; logical_ports are the names of the ports in the group. _MC_flood holds every port of a switch and is used for broadcast, _MC_unknown the ports with unknown addresses that get packets for unknown MAC addresses, _MC_mrouter_flood and _MC_static the ports flooding IP multicast to routers or configured statically, and groups named after an IP multicast address are the ports learnt by IGMP or MLD snooping. A port missing from _MC_flood does not receive ARP requests or broadcasts. Use list_igmp_groups for the groups each chassis has learnt.",
+	}
+
+	return mcp.NewResult(result)
+}
+
+func (s *Server) ListIGMPGroups(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListIGMPGroupsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	group := &ovnsb.IGMPGroup{}
+	var conditions []model.Condition
+	if args.Address != "" {
+		conditions = append(conditions, model.Condition{
+			Field:    &group.Address,
+			Function: ovsdb.ConditionEqual,
+			Value:    args.Address,
+		})
+	}
+	parent := datapathParent(args.DatapathFilter, func(dp ovnsb.DatapathBinding, ig ovnsb.IGMPGroup) bool {
+		return ig.Datapath != nil && *ig.Datapath == dp.UUID
+	})
+	results, found, err := mcp.SelectWithParentFilter(ctx, client, group, parent, conditions...)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return mcp.NoParentResult("igmp_groups", parent.Kind)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Address != results[j].Address {
+			return results[i].Address < results[j].Address
+		}
+		return results[i].ChassisName < results[j].ChassisName
+	})
+	if args.CountOnly {
+		return mcp.CountResult(len(results))
+	}
+
+	results, page := mcp.Paginate(results, args.Limit, args.Offset)
+
+	rows, err := mcp.MapRows(ovnsb.IGMPGroupTable, ovnsb.DatabaseSchema(), results)
+	if err != nil {
+		return nil, err
+	}
+	ports := make([][]string, len(results))
+	for i, ig := range results {
+		ports[i] = ig.Ports
+	}
+	if err := addLogicalPorts(ctx, client, ports, rows); err != nil {
+		return nil, err
+	}
+
+	result := mcp.ListResult{
+		Data:       map[string]any{"igmp_groups": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "IGMP groups are the IP multicast groups that ovn-controller has learnt by snooping IGMP and MLD reports on a logical switch with mcast_snoop enabled, one row per group, datapath and chassis; logical_ports are the names of the ports on that chassis that joined. ovn-northd merges the rows of every chassis into a multicast group named after the address, which list_multicast_groups returns. A group that is missing was never joined or has aged out because no querier is refreshing it, so check the switch's mcast_querier and mcast_idle_timeout options.",
+	}
+
+	return mcp.NewResult(result)
+}
@@ -0,0 +1,184 @@
+package ovnsb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type ResolvePortBindingArgs struct {
+	LogicalPort string `json:"logical_port" jsonschema:"the name of the logical switch or router port, as in the northbound database"`
+}
+
+// PortBindingChassis is a chassis a port is bound to, or requested to be
+type PortBindingChassis struct {
+	UUID     string   `json:"uuid"`
+	Name     string   `json:"name"`
+	Hostname string   `json:"hostname,omitempty"`
+	EncapIPs []string `json:"encap_ips,omitempty"`
+}
+
+// PortBindingResult is the binding of a logical port to a chassis
+type PortBindingResult struct {
+	UUID              string               `json:"uuid"`
+	LogicalPort       string               `json:"logical_port"`
+	Type              string               `json:"type"`
+	TunnelKey         int                  `json:"tunnel_key"`
+	Datapath          TunnelKeyDatapath    `json:"datapath"`
+	MAC               []string             `json:"mac"`
+	Up                *bool                `json:"up,omitempty"`
+	Bound             bool                 `json:"bound"`
+	Chassis           *PortBindingChassis  `json:"chassis,omitempty"`
+	AdditionalChassis []PortBindingChassis `json:"additional_chassis,omitempty"`
+	RequestedChassis  *PortBindingChassis  `json:"requested_chassis,omitempty"`
+	Context           string               `json:"context"`
+}
+
+// chassisResolver looks up chassis by UUID, with the IPs of their encaps
+type chassisResolver struct {
+	client client.Client
+	encaps map[string]string
+}
+
+func (r *chassisResolver) resolve(ctx context.Context, uuid string) (*PortBindingChassis, error) {
+	ch := &ovnsb.Chassis{}
+	chassis, err := mcp.ExecuteSelectQuery(ctx, r.client, ch, model.Condition{
+		Field:    &ch.UUID,
+		Function: ovsdb.ConditionEqual,
+		Value:    uuid,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(chassis) == 0 {
+		// The chassis was deleted, the reference is cleared by OVSDB
+		return &PortBindingChassis{UUID: uuid}, nil
+	}
+
+	if r.encaps == nil {
+		encaps, err := mcp.ExecuteSelectQuery(ctx, r.client, &ovnsb.Encap{})
+		if err != nil {
+			return nil, err
+		}
+		r.encaps = make(map[string]string, len(encaps))
+		for _, encap := range encaps {
+			r.encaps[encap.UUID] = encap.IP
+		}
+	}
+
+	resolved := &PortBindingChassis{
+		UUID:     chassis[0].UUID,
+		Name:     chassis[0].Name,
+		Hostname: chassis[0].Hostname,
+	}
+	for _, encap := range chassis[0].Encaps {
+		if ip, ok := r.encaps[encap]; ok {
+			resolved.EncapIPs = append(resolved.EncapIPs, ip)
+		}
+	}
+	return resolved, nil
+}
+
+// portBindingContext explains why a port is or isn't bound
+func portBindingContext(pb ovnsb.PortBinding) string {
+	switch {
+	case pb.Chassis != nil:
+		return "The port is bound to chassis: the ovn-controller of that chassis claimed it, so traffic to the port is tunnelled to the chassis' encap IPs. For a VM or pod, the chassis is the node it runs on."
+	case pb.Type == "patch" || pb.Type == "router" || pb.Type == "localnet" || pb.Type == "localport":
+		return fmt.Sprintf("The port is not bound to a chassis, which is expected for %s ports: they exist on every chassis the datapath is present on rather than on one of them.", pb.Type)
+	case pb.Type == "":
+		return "The port is not bound to any chassis. No ovn-controller has claimed it: either no OVS interface has external_ids:iface-id set to the logical port's name, the ovn-controller of that node is down, or requested_chassis names another chassis. Until it is bound, traffic to the port is dropped."
+	default:
+		return fmt.Sprintf("The port is not bound to any chassis. %s ports are bound to the gateway chassis with the highest priority that is up, so check the port's gateway chassis or HA chassis group and that their ovn-controllers are running.", pb.Type)
+	}
+}
+
+func (s *Server) ResolvePortBinding(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ResolvePortBindingArgs]) (*mcpsdk.CallToolResultFor[PortBindingResult], error) {
+	args := params.Arguments
+
+	if args.LogicalPort == "" {
+		return nil, fmt.Errorf("logical_port must not be empty")
+	}
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	portBinding := &ovnsb.PortBinding{}
+	bindings, err := mcp.ExecuteSelectQuery(ctx, client, portBinding, model.Condition{
+		Field:    &portBinding.LogicalPort,
+		Function: ovsdb.ConditionEqual,
+		Value:    args.LogicalPort,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(bindings) == 0 {
+		return &mcpsdk.CallToolResultFor[PortBindingResult]{
+			IsError: true,
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: fmt.Sprintf("No port binding found for logical port %s, check that the port exists in the northbound database and that northd is running", args.LogicalPort),
+				},
+			},
+		}, nil
+	}
+	pb := bindings[0]
+
+	datapathBinding := &ovnsb.DatapathBinding{}
+	datapaths, err := mcp.ExecuteSelectQuery(ctx, client, datapathBinding, model.Condition{
+		Field:    &datapathBinding.UUID,
+		Function: ovsdb.ConditionEqual,
+		Value:    pb.Datapath,
+	})
+	if err != nil {
+		return nil, err
+	}
+	datapath := TunnelKeyDatapath{UUID: pb.Datapath}
+	if len(datapaths) > 0 {
+		datapath.Name = datapaths[0].ExternalIDs["name"]
+		datapath.Type = datapathType(datapaths[0])
+		datapath.TunnelKey = datapaths[0].TunnelKey
+	}
+
+	result := PortBindingResult{
+		UUID:        pb.UUID,
+		LogicalPort: pb.LogicalPort,
+		Type:        pb.Type,
+		TunnelKey:   pb.TunnelKey,
+		Datapath:    datapath,
+		MAC:         pb.MAC,
+		Up:          pb.Up,
+		Bound:       pb.Chassis != nil,
+		Context:     portBindingContext(pb),
+	}
+
+	resolver := &chassisResolver{client: client}
+	if pb.Chassis != nil {
+		if result.Chassis, err = resolver.resolve(ctx, *pb.Chassis); err != nil {
+			return nil, err
+		}
+	}
+	for _, uuid := range pb.AdditionalChassis {
+		ch, err := resolver.resolve(ctx, uuid)
+		if err != nil {
+			return nil, err
+		}
+		result.AdditionalChassis = append(result.AdditionalChassis, *ch)
+	}
+	if pb.RequestedChassis != nil {
+		if result.RequestedChassis, err = resolver.resolve(ctx, *pb.RequestedChassis); err != nil {
+			return nil, err
+		}
+	}
+
+	return mcp.NewResult(result)
+}
@@ -2,14 +2,14 @@ package ovnsb
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/dave-tucker/ariadne/internal/mcp"
 	"github.com/dave-tucker/ariadne/internal/schema/ovnsb"
 	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
-	"github.com/ovn-kubernetes/libovsdb/client"
 	"github.com/ovn-kubernetes/libovsdb/model"
 	"github.com/ovn-kubernetes/libovsdb/ovsdb"
 )
@@ -17,700 +17,808 @@ import (
 const defaultEndpoint = "unix:/var/run/ovn/ovnsb_db.sock"
 
 type Server struct {
-	*mcpsdk.Server
-	dbModel    model.ClientDBModel
-	httpServer *http.Server
+	*mcp.BaseServer
+	// trace is how trace_packet runs ovn-trace
+	trace traceConfig
 }
 
 type ListDatapathBindingsArgs struct {
 	NameFilter string `json:"name_filter" jsonschema:"the name of the datapath to filter by"`
+	MatchMode  string `json:"match,omitempty" jsonschema:"how name_filter is matched: exact (default), prefix, substring or regex, prefix and substring matches are case-insensitive"`
+	CountOnly  bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset     int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListPortBindingsArgs struct {
 	DatapathFilter string `json:"datapath_filter" jsonschema:"the name of the datapath to filter by"`
+	CountOnly      bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit          int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset         int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListChassisArgs struct {
-	NameFilter string `json:"name_filter" jsonschema:"the name of the chassis to filter by"`
+	NameFilter     string `json:"name_filter" jsonschema:"the name of the chassis to filter by"`
+	MatchMode      string `json:"match,omitempty" jsonschema:"how name_filter is matched: exact (default), prefix, substring or regex, prefix and substring matches are case-insensitive"`
+	Hostname       string `json:"hostname,omitempty" jsonschema:"the hostname of the chassis' node to filter by"`
+	EncapIP        string `json:"encap_ip,omitempty" jsonschema:"the tunnel IP address of the chassis to filter by"`
+	IncludePrivate bool   `json:"include_private,omitempty" jsonschema:"add each chassis' Chassis_Private row as private, with the nb_cfg it has processed and whether it is up to date, this costs extra queries"`
+	CountOnly      bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit          int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset         int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListLogicalFlowsArgs struct {
 	DatapathFilter string `json:"datapath_filter" jsonschema:"the name of the datapath to filter by"`
+	Pipeline       string `json:"pipeline,omitempty" jsonschema:"only return flows of this pipeline, ingress or egress"`
+	TableID        *int   `json:"table_id,omitempty" jsonschema:"only return flows of this table (stage) of the pipeline"`
+	CountOnly      bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit          int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset         int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type SearchLogicalFlowsArgs struct {
+	MatchContains  string `json:"match_contains" jsonschema:"the text the flows' match must contain, e.g. an IP address, a port name or arp"`
+	DatapathFilter string `json:"datapath_filter,omitempty" jsonschema:"the name of the datapath to filter by"`
+	Pipeline       string `json:"pipeline,omitempty" jsonschema:"only return flows of this pipeline, ingress or egress"`
+	TableID        *int   `json:"table_id,omitempty" jsonschema:"only return flows of this table (stage) of the pipeline"`
+	CountOnly      bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit          int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset         int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListMACBindingsArgs struct {
 	DatapathFilter string `json:"datapath_filter" jsonschema:"the name of the datapath to filter by"`
+	CountOnly      bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit          int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset         int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListEncapsArgs struct {
 	ChassisFilter string `json:"chassis_filter" jsonschema:"the name of the chassis to filter by"`
+	CountOnly     bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit         int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset        int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type ListGatewayChassisArgs struct {
+	ChassisFilter string `json:"chassis_filter,omitempty" jsonschema:"the name of the chassis to filter by"`
+	CountOnly     bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit         int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset        int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListMetersArgs struct {
 	NameFilter string `json:"name_filter" jsonschema:"the name of the meter to filter by"`
+	MatchMode  string `json:"match,omitempty" jsonschema:"how name_filter is matched: exact (default), prefix, substring or regex, prefix and substring matches are case-insensitive"`
+	CountOnly  bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset     int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListFDBEntriesArgs struct {
 	DatapathFilter string `json:"datapath_filter" jsonschema:"the name of the datapath to filter by"`
+	CountOnly      bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit          int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset         int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
-func (s *Server) ListDatapathBindings(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListDatapathBindingsArgs]) (*mcpsdk.CallToolResult, error) {
+type ListSBGlobalArgs struct {
+	CountOnly bool `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit     int  `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset    int  `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+func (s *Server) ListDatapathBindings(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListDatapathBindingsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	nameFilter := args.NameFilter
+	matcher, err := mcp.NewNameMatcher(args.NameFilter, args.MatchMode)
+	if err != nil {
+		return nil, err
+	}
+
+	datapathBinding := &ovnsb.DatapathBinding{}
 	var conditions []model.Condition
-	if nameFilter != "" {
+	if matcher.Exact() {
 		conditions = append(conditions, model.Condition{
-			Field:    &(&ovnsb.DatapathBinding{}).ExternalIDs,
+			Field:    &datapathBinding.ExternalIDs,
 			Function: ovsdb.ConditionEqual,
-			Value:    map[string]string{"name": nameFilter},
+			Value:    map[string]string{"name": args.NameFilter},
 		})
 	}
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
-	}
-
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnsb.DatapathBinding{}, conditions...)
+	results, err := mcp.ExecuteSelectQuery(ctx, client, datapathBinding, conditions...)
 	if err != nil {
 		return nil, err
 	}
+	results = mcp.FilterByName(results, matcher, func(r ovnsb.DatapathBinding) string { return r.ExternalIDs["name"] })
 
-	result := map[string]interface{}{
-		"datapath_bindings": results,
-		"count":             len(results),
-		"context":           "Datapath bindings represent the physical or virtual switches that implement logical switches and routers.",
+	if args.CountOnly {
+		return mcp.CountResult(len(results))
 	}
 
-	json, err := json.Marshal(result)
+	results, page := mcp.Paginate(results, args.Limit, args.Offset)
+
+	rows, err := mcp.MapRows(ovnsb.DatapathBindingTable, ovnsb.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	result := mcp.ListResult{
+		Data:       map[string]any{"datapath_bindings": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Datapath bindings represent the physical or virtual switches that implement logical switches and routers.",
+	}
+
+	return mcp.NewResult(result)
 }
 
-func (s *Server) ListPortBindings(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListPortBindingsArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListPortBindings(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListPortBindingsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
-	}
-
-	datapathFilter := args.DatapathFilter
-	var conditions []model.Condition
-	if datapathFilter != "" {
-		// First, get the datapath UUID
-		var datapaths []ovnsb.DatapathBinding
-		datapathCondition := model.Condition{
-			Field:    &(&ovnsb.DatapathBinding{}).ExternalIDs,
-			Function: ovsdb.ConditionEqual,
-			Value:    map[string]string{"name": datapathFilter},
-		}
-		datapathSelectOps, datapathQueryID, datapathSelectErr := client.WhereAll(&ovnsb.DatapathBinding{}, datapathCondition).Select()
-		if datapathSelectErr != nil {
-			return nil, fmt.Errorf("failed to create datapath select operation: %w", datapathSelectErr)
-		}
-
-		datapathReply, err := client.Transact(ctx, datapathSelectOps...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute datapath transaction: %w", err)
-		}
-
-		err = client.GetSelectResults(datapathSelectOps, datapathReply, map[string]interface{}{datapathQueryID: &datapaths})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get datapath select results: %w", err)
-		}
-
-		if len(datapaths) == 0 {
-			result := map[string]interface{}{
-				"port_bindings": []ovnsb.PortBinding{},
-				"count":         0,
-				"context":       "No datapath found with the specified filter.",
-			}
-			json, err := json.Marshal(result)
-			if err != nil {
-				return nil, err
-			}
-			return &mcpsdk.CallToolResult{
-				Content: []mcpsdk.Content{
-					&mcpsdk.TextContent{
-						Text: string(json),
-					},
-				},
-			}, nil
+	query := mcp.ListQuery[ovnsb.DatapathBinding, ovnsb.PortBinding]{
+		Model:     &ovnsb.PortBinding{},
+		Table:     ovnsb.PortBindingTable,
+		Schema:    ovnsb.DatabaseSchema(),
+		Key:       "port_bindings",
+		Context:   "Port bindings map logical ports to physical ports on datapaths. They represent the actual network connections.",
+		Limit:     args.Limit,
+		Offset:    args.Offset,
+		CountOnly: args.CountOnly,
+	}
+	if args.DatapathFilter != "" {
+		datapathBinding := &ovnsb.DatapathBinding{}
+		query.Parent = &mcp.ParentFilter[ovnsb.DatapathBinding, ovnsb.PortBinding]{
+			Model: datapathBinding,
+			Field: &datapathBinding.ExternalIDs,
+			Value: map[string]string{"name": args.DatapathFilter},
+			Kind:  "datapath",
+			Keep: func(dp ovnsb.DatapathBinding, pb ovnsb.PortBinding) bool {
+				return pb.Datapath == dp.UUID
+			},
 		}
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnsb.PortBinding{}, conditions...)
-	if err != nil {
-		return nil, err
-	}
+	return mcp.ListWithParentFilter(ctx, client, query)
+}
 
-	result := map[string]interface{}{
-		"port_bindings": results,
-		"count":         len(results),
-		"context":       "Port bindings map logical ports to physical ports on datapaths. They represent the actual network connections.",
-	}
+func (s *Server) ListChassis(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListChassisArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
 
-	json, err := json.Marshal(result)
+	matcher, err := mcp.NewNameMatcher(args.NameFilter, args.MatchMode)
 	if err != nil {
 		return nil, err
 	}
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
-}
-
-func (s *Server) ListChassis(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListChassisArgs]) (*mcpsdk.CallToolResult, error) {
-	args := params.Arguments
-
-	nameFilter := args.NameFilter
+	chassis := &ovnsb.Chassis{}
 	var conditions []model.Condition
-	if nameFilter != "" {
+	if matcher.Exact() {
 		conditions = append(conditions, model.Condition{
-			Field:    &(&ovnsb.Chassis{}).Name,
+			Field:    &chassis.Name,
 			Function: ovsdb.ConditionEqual,
-			Value:    nameFilter,
+			Value:    args.NameFilter,
 		})
 	}
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+	// Chassis refer to their tunnel endpoints through the encaps column, so
+	// the encaps with the IP are found first
+	var encaps []string
+	if args.EncapIP != "" {
+		encap := &ovnsb.Encap{}
+		matched, err := mcp.ExecuteSelectQuery(ctx, client, encap, model.Condition{
+			Field:    &encap.IP,
+			Function: ovsdb.ConditionEqual,
+			Value:    args.EncapIP,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(matched) == 0 {
+			return mcp.NoParentResult("chassis", "encap")
+		}
+		for _, e := range matched {
+			encaps = append(encaps, e.UUID)
+		}
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnsb.Chassis{}, conditions...)
+	results, err := mcp.ExecuteSelectQuery(ctx, client, chassis, conditions...)
 	if err != nil {
 		return nil, err
 	}
-
-	result := map[string]interface{}{
-		"chassis": results,
-		"count":   len(results),
-		"context": "Chassis represent physical or virtual machines that host OVN components and can run datapaths.",
+	results = mcp.FilterByName(results, matcher, func(r ovnsb.Chassis) string { return r.Name })
+	if args.Hostname != "" || args.EncapIP != "" {
+		kept := []ovnsb.Chassis{}
+		for _, ch := range results {
+			// ovn-controller sets the hostname column, older versions and
+			// other integrations only set external_ids:hostname
+			if args.Hostname != "" && ch.Hostname != args.Hostname && ch.ExternalIDs["hostname"] != args.Hostname {
+				continue
+			}
+			if args.EncapIP != "" && !slices.ContainsFunc(ch.Encaps, func(uuid string) bool { return slices.Contains(encaps, uuid) }) {
+				continue
+			}
+			kept = append(kept, ch)
+		}
+		results = kept
 	}
 
-	json, err := json.Marshal(result)
-	if err != nil {
-		return nil, err
+	if args.CountOnly {
+		return mcp.CountResult(len(results))
 	}
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
-}
+	results, page := mcp.Paginate(results, args.Limit, args.Offset)
 
-func (s *Server) ListLogicalFlows(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListLogicalFlowsArgs]) (*mcpsdk.CallToolResult, error) {
-	args := params.Arguments
-
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	rows, err := mcp.MapRows(ovnsb.ChassisTable, ovnsb.DatabaseSchema(), results)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
+	}
+	if args.IncludePrivate {
+		if err := addChassisPrivate(ctx, client, results, rows); err != nil {
+			return nil, err
+		}
 	}
-	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+	result := mcp.ListResult{
+		Data:       map[string]any{"chassis": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Chassis represent physical or virtual machines that host OVN components and can run datapaths.",
 	}
 
-	datapathFilter := args.DatapathFilter
-	var conditions []model.Condition
-	if datapathFilter != "" {
-		// First, get the datapath UUID
-		var datapaths []ovnsb.DatapathBinding
-		datapathCondition := model.Condition{
-			Field:    &(&ovnsb.DatapathBinding{}).ExternalIDs,
-			Function: ovsdb.ConditionEqual,
-			Value:    map[string]string{"name": datapathFilter},
-		}
-		datapathSelectOps, datapathQueryID, datapathSelectErr := client.WhereAll(&ovnsb.DatapathBinding{}, datapathCondition).Select()
-		if datapathSelectErr != nil {
-			return nil, fmt.Errorf("failed to create datapath select operation: %w", datapathSelectErr)
-		}
+	return mcp.NewResult(result)
+}
 
-		datapathReply, err := client.Transact(ctx, datapathSelectOps...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute datapath transaction: %w", err)
-		}
+func (s *Server) ListLogicalFlows(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListLogicalFlowsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	return s.listLogicalFlows(ctx, params.Arguments, "")
+}
 
-		err = client.GetSelectResults(datapathSelectOps, datapathReply, map[string]interface{}{datapathQueryID: &datapaths})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get datapath select results: %w", err)
-		}
+func (s *Server) SearchLogicalFlows(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[SearchLogicalFlowsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
 
-		if len(datapaths) == 0 {
-			result := map[string]interface{}{
-				"logical_flows": []ovnsb.LogicalFlow{},
-				"count":         0,
-				"context":       "No datapath found with the specified filter.",
-			}
-			json, err := json.Marshal(result)
-			if err != nil {
-				return nil, err
-			}
-			return &mcpsdk.CallToolResult{
-				Content: []mcpsdk.Content{
-					&mcpsdk.TextContent{
-						Text: string(json),
-					},
-				},
-			}, nil
-		}
+	if args.MatchContains == "" {
+		return nil, fmt.Errorf("match_contains must not be empty")
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnsb.LogicalFlow{}, conditions...)
-	if err != nil {
-		return nil, err
-	}
+	return s.listLogicalFlows(ctx, ListLogicalFlowsArgs{
+		DatapathFilter: args.DatapathFilter,
+		Pipeline:       args.Pipeline,
+		TableID:        args.TableID,
+		CountOnly:      args.CountOnly,
+		Limit:          args.Limit,
+		Offset:         args.Offset,
+	}, args.MatchContains)
+}
 
-	result := map[string]interface{}{
-		"logical_flows": results,
-		"count":         len(results),
-		"context":       "Logical flows represent the forwarding rules that are translated into OpenFlow flows on datapaths.",
+// listLogicalFlows lists the logical flows matching args, and whose match
+// contains matchContains when it is set
+func (s *Server) listLogicalFlows(ctx context.Context, args ListLogicalFlowsArgs, matchContains string) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	if args.Pipeline != "" && args.Pipeline != ovnsb.LogicalFlowPipelineIngress && args.Pipeline != ovnsb.LogicalFlowPipelineEgress {
+		return nil, fmt.Errorf("invalid pipeline %q, must be ingress or egress", args.Pipeline)
 	}
 
-	json, err := json.Marshal(result)
+	client, err := s.Connect(ctx)
 	if err != nil {
 		return nil, err
 	}
-
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
-}
-
-func (s *Server) ListMACBindings(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListMACBindingsArgs]) (*mcpsdk.CallToolResult, error) {
-	args := params.Arguments
-
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
-	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+	logicalFlow := &ovnsb.LogicalFlow{}
+	query := mcp.ListQuery[ovnsb.DatapathBinding, ovnsb.LogicalFlow]{
+		Model:     logicalFlow,
+		Table:     ovnsb.LogicalFlowTable,
+		Schema:    ovnsb.DatabaseSchema(),
+		Key:       "logical_flows",
+		Context:   "Logical flows represent the forwarding rules that are translated into OpenFlow flows on datapaths.",
+		Limit:     args.Limit,
+		Offset:    args.Offset,
+		CountOnly: args.CountOnly,
+	}
+	if args.Pipeline != "" || matchContains != "" {
+		// libovsdb doesn't support conditions on enum columns, and OVSDB has
+		// no substring condition
+		query.Filter = func(flow ovnsb.LogicalFlow) bool {
+			if args.Pipeline != "" && flow.Pipeline != args.Pipeline {
+				return false
+			}
+			return strings.Contains(flow.Match, matchContains)
+		}
 	}
-
-	datapathFilter := args.DatapathFilter
-	var conditions []model.Condition
-	if datapathFilter != "" {
-		// First, get the datapath UUID
-		var datapaths []ovnsb.DatapathBinding
-		datapathCondition := model.Condition{
-			Field:    &(&ovnsb.DatapathBinding{}).ExternalIDs,
+	if args.TableID != nil {
+		query.Conditions = append(query.Conditions, model.Condition{
+			Field:    &logicalFlow.TableID,
 			Function: ovsdb.ConditionEqual,
-			Value:    map[string]string{"name": datapathFilter},
-		}
-		datapathSelectOps, datapathQueryID, datapathSelectErr := client.WhereAll(&ovnsb.DatapathBinding{}, datapathCondition).Select()
-		if datapathSelectErr != nil {
-			return nil, fmt.Errorf("failed to create datapath select operation: %w", datapathSelectErr)
-		}
-
-		datapathReply, err := client.Transact(ctx, datapathSelectOps...)
+			Value:    *args.TableID,
+		})
+	}
+	if args.DatapathFilter != "" {
+		// Flows shared by several datapaths refer to them through a
+		// datapath group rather than the logical_datapath column
+		groups, err := mcp.ExecuteSelectQuery(ctx, client, &ovnsb.LogicalDPGroup{})
 		if err != nil {
-			return nil, fmt.Errorf("failed to execute datapath transaction: %w", err)
+			return nil, err
 		}
-
-		err = client.GetSelectResults(datapathSelectOps, datapathReply, map[string]interface{}{datapathQueryID: &datapaths})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get datapath select results: %w", err)
+		groupDatapaths := make(map[string][]string, len(groups))
+		for _, group := range groups {
+			groupDatapaths[group.UUID] = group.Datapaths
 		}
 
-		if len(datapaths) == 0 {
-			result := map[string]interface{}{
-				"mac_bindings": []ovnsb.MACBinding{},
-				"count":        0,
-				"context":      "No datapath found with the specified filter.",
-			}
-			json, err := json.Marshal(result)
-			if err != nil {
-				return nil, err
-			}
-			return &mcpsdk.CallToolResult{
-				Content: []mcpsdk.Content{
-					&mcpsdk.TextContent{
-						Text: string(json),
-					},
-				},
-			}, nil
+		datapathBinding := &ovnsb.DatapathBinding{}
+		query.Parent = &mcp.ParentFilter[ovnsb.DatapathBinding, ovnsb.LogicalFlow]{
+			Model: datapathBinding,
+			Field: &datapathBinding.ExternalIDs,
+			Value: map[string]string{"name": args.DatapathFilter},
+			Kind:  "datapath",
+			Keep: func(dp ovnsb.DatapathBinding, flow ovnsb.LogicalFlow) bool {
+				if flow.LogicalDatapath != nil {
+					return *flow.LogicalDatapath == dp.UUID
+				}
+				return flow.LogicalDpGroup != nil && slices.Contains(groupDatapaths[*flow.LogicalDpGroup], dp.UUID)
+			},
 		}
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnsb.MACBinding{}, conditions...)
-	if err != nil {
-		return nil, err
-	}
+	return mcp.ListWithParentFilter(ctx, client, query)
+}
 
-	result := map[string]interface{}{
-		"mac_bindings": results,
-		"count":        len(results),
-		"context":      "MAC bindings map MAC addresses to logical ports and IP addresses. They are used for ARP resolution.",
-	}
+func (s *Server) ListMACBindings(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListMACBindingsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
 
-	json, err := json.Marshal(result)
+	client, err := s.Connect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer client.Close()
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
+	query := mcp.ListQuery[ovnsb.DatapathBinding, ovnsb.MACBinding]{
+		Model:     &ovnsb.MACBinding{},
+		Table:     ovnsb.MACBindingTable,
+		Schema:    ovnsb.DatabaseSchema(),
+		Key:       "mac_bindings",
+		Context:   "MAC bindings map MAC addresses to logical ports and IP addresses. They are used for ARP resolution.",
+		Limit:     args.Limit,
+		Offset:    args.Offset,
+		CountOnly: args.CountOnly,
+	}
+	if args.DatapathFilter != "" {
+		datapathBinding := &ovnsb.DatapathBinding{}
+		query.Parent = &mcp.ParentFilter[ovnsb.DatapathBinding, ovnsb.MACBinding]{
+			Model: datapathBinding,
+			Field: &datapathBinding.ExternalIDs,
+			Value: map[string]string{"name": args.DatapathFilter},
+			Kind:  "datapath",
+			Keep: func(dp ovnsb.DatapathBinding, mb ovnsb.MACBinding) bool {
+				return mb.Datapath == dp.UUID
 			},
-		},
-	}, nil
+		}
+	}
+
+	return mcp.ListWithParentFilter(ctx, client, query)
 }
 
-func (s *Server) ListEncaps(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListEncapsArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListEncaps(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListEncapsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+	query := mcp.ListQuery[ovnsb.Chassis, ovnsb.Encap]{
+		Model:     &ovnsb.Encap{},
+		Table:     ovnsb.EncapTable,
+		Schema:    ovnsb.DatabaseSchema(),
+		Key:       "encaps",
+		Context:   "Encapsulations define the tunneling protocols used to connect chassis in an OVN deployment.",
+		Limit:     args.Limit,
+		Offset:    args.Offset,
+		CountOnly: args.CountOnly,
+	}
+	if args.ChassisFilter != "" {
+		chassis := &ovnsb.Chassis{}
+		query.Parent = &mcp.ParentFilter[ovnsb.Chassis, ovnsb.Encap]{
+			Model: chassis,
+			Field: &chassis.Name,
+			Value: args.ChassisFilter,
+			Kind:  "chassis",
+			// Only keep the encaps referenced by the chassis' encaps column
+			Keep: func(ch ovnsb.Chassis, encap ovnsb.Encap) bool {
+				return slices.Contains(ch.Encaps, encap.UUID)
+			},
+		}
 	}
 
-	chassisFilter := args.ChassisFilter
-	var conditions []model.Condition
-	if chassisFilter != "" {
-		// First, get the chassis UUID
-		var chassis []ovnsb.Chassis
-		chassisCondition := model.Condition{
-			Field:    &(&ovnsb.Chassis{}).Name,
-			Function: ovsdb.ConditionEqual,
-			Value:    chassisFilter,
-		}
-		chassisSelectOps, chassisQueryID, chassisSelectErr := client.WhereAll(&ovnsb.Chassis{}, chassisCondition).Select()
-		if chassisSelectErr != nil {
-			return nil, fmt.Errorf("failed to create chassis select operation: %w", chassisSelectErr)
-		}
+	return mcp.ListWithParentFilter(ctx, client, query)
+}
 
-		chassisReply, err := client.Transact(ctx, chassisSelectOps...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute chassis transaction: %w", err)
-		}
+func (s *Server) ListGatewayChassis(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListGatewayChassisArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
 
-		err = client.GetSelectResults(chassisSelectOps, chassisReply, map[string]interface{}{chassisQueryID: &chassis})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get chassis select results: %w", err)
-		}
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
 
-		if len(chassis) == 0 {
-			result := map[string]interface{}{
-				"encaps":  []ovnsb.Encap{},
-				"count":   0,
-				"context": "No chassis found with the specified filter.",
-			}
-			json, err := json.Marshal(result)
-			if err != nil {
-				return nil, err
-			}
-			return &mcpsdk.CallToolResult{
-				Content: []mcpsdk.Content{
-					&mcpsdk.TextContent{
-						Text: string(json),
-					},
-				},
-			}, nil
+	var parent *mcp.ParentFilter[ovnsb.Chassis, ovnsb.GatewayChassis]
+	if args.ChassisFilter != "" {
+		chassis := &ovnsb.Chassis{}
+		parent = &mcp.ParentFilter[ovnsb.Chassis, ovnsb.GatewayChassis]{
+			Model: chassis,
+			Field: &chassis.Name,
+			Value: args.ChassisFilter,
+			Kind:  "chassis",
+			Keep: func(ch ovnsb.Chassis, gc ovnsb.GatewayChassis) bool {
+				return gc.Chassis != nil && *gc.Chassis == ch.UUID
+			},
 		}
 	}
-
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnsb.Encap{}, conditions...)
+	results, found, err := mcp.SelectWithParentFilter(ctx, client, &ovnsb.GatewayChassis{}, parent)
 	if err != nil {
 		return nil, err
 	}
-
-	result := map[string]interface{}{
-		"encaps":  results,
-		"count":   len(results),
-		"context": "Encapsulations define the tunneling protocols used to connect chassis in an OVN deployment.",
+	if !found {
+		return mcp.NoParentResult("gateway_chassis", parent.Kind)
+	}
+	// Highest priority first, as that is the order failover happens in
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Priority != results[j].Priority {
+			return results[i].Priority > results[j].Priority
+		}
+		return results[i].Name < results[j].Name
+	})
+	if args.CountOnly {
+		return mcp.CountResult(len(results))
 	}
 
-	json, err := json.Marshal(result)
+	results, page := mcp.Paginate(results, args.Limit, args.Offset)
+
+	rows, err := mcp.MapRows(ovnsb.GatewayChassisTable, ovnsb.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	result := mcp.ListResult{
+		Data:       map[string]any{"gateway_chassis": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Gateway chassis are the chassis a distributed gateway port can be bound to, copied by ovn-northd from the northbound Gateway_Chassis rows; the port's name is in the row's name and external_ids. Rows are ordered by priority, highest first. The highest priority chassis that is alive hosts the port and is the only one to answer ARP and handle its NAT; when BFD between the chassis' tunnel endpoints reports it down, the next highest priority chassis claims the port. An empty chassis column means the chassis has not registered or was deleted, so it can never take over.",
+	}
+
+	return mcp.NewResult(result)
 }
 
-func (s *Server) ListMeters(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListMetersArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListMeters(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListMetersArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	nameFilter := args.NameFilter
+	matcher, err := mcp.NewNameMatcher(args.NameFilter, args.MatchMode)
+	if err != nil {
+		return nil, err
+	}
+
+	meter := &ovnsb.Meter{}
 	var conditions []model.Condition
-	if nameFilter != "" {
+	if matcher.Exact() {
 		conditions = append(conditions, model.Condition{
-			Field:    &(&ovnsb.Meter{}).Name,
+			Field:    &meter.Name,
 			Function: ovsdb.ConditionEqual,
-			Value:    nameFilter,
+			Value:    args.NameFilter,
 		})
 	}
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
-	}
-
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnsb.Meter{}, conditions...)
+	results, err := mcp.ExecuteSelectQuery(ctx, client, meter, conditions...)
 	if err != nil {
 		return nil, err
 	}
+	results = mcp.FilterByName(results, matcher, func(r ovnsb.Meter) string { return r.Name })
 
-	result := map[string]interface{}{
-		"meters":  results,
-		"count":   len(results),
-		"context": "Meters provide rate limiting and policing capabilities for traffic flows on datapaths.",
+	if args.CountOnly {
+		return mcp.CountResult(len(results))
 	}
 
-	json, err := json.Marshal(result)
+	results, page := mcp.Paginate(results, args.Limit, args.Offset)
+
+	rows, err := mcp.MapRows(ovnsb.MeterTable, ovnsb.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	result := mcp.ListResult{
+		Data:       map[string]any{"meters": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Meters provide rate limiting and policing capabilities for traffic flows on datapaths.",
+	}
+
+	return mcp.NewResult(result)
 }
 
-func (s *Server) ListFDBEntries(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListFDBEntriesArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListFDBEntries(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListFDBEntriesArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+	query := mcp.ListQuery[ovnsb.DatapathBinding, ovnsb.FDB]{
+		Model:     &ovnsb.FDB{},
+		Table:     ovnsb.FDBTable,
+		Schema:    ovnsb.DatabaseSchema(),
+		Key:       "fdb_entries",
+		Context:   "FDB (Forwarding Database) entries map MAC addresses to ports on datapaths for Layer 2 forwarding.",
+		Limit:     args.Limit,
+		Offset:    args.Offset,
+		CountOnly: args.CountOnly,
+	}
+	if args.DatapathFilter != "" {
+		datapathBinding := &ovnsb.DatapathBinding{}
+		query.Parent = &mcp.ParentFilter[ovnsb.DatapathBinding, ovnsb.FDB]{
+			Model: datapathBinding,
+			Field: &datapathBinding.ExternalIDs,
+			Value: map[string]string{"name": args.DatapathFilter},
+			Kind:  "datapath",
+			// FDB entries refer to their datapath by its tunnel key
+			Keep: func(dp ovnsb.DatapathBinding, fdb ovnsb.FDB) bool {
+				return fdb.DpKey == dp.TunnelKey
+			},
+		}
 	}
 
-	datapathFilter := args.DatapathFilter
-	var conditions []model.Condition
-	if datapathFilter != "" {
-		// First, get the datapath UUID
-		var datapaths []ovnsb.DatapathBinding
-		datapathCondition := model.Condition{
-			Field:    &(&ovnsb.DatapathBinding{}).ExternalIDs,
-			Function: ovsdb.ConditionEqual,
-			Value:    map[string]string{"name": datapathFilter},
-		}
-		datapathSelectOps, datapathQueryID, datapathSelectErr := client.WhereAll(&ovnsb.DatapathBinding{}, datapathCondition).Select()
-		if datapathSelectErr != nil {
-			return nil, fmt.Errorf("failed to create datapath select operation: %w", datapathSelectErr)
-		}
+	return mcp.ListWithParentFilter(ctx, client, query)
+}
 
-		datapathReply, err := client.Transact(ctx, datapathSelectOps...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute datapath transaction: %w", err)
-		}
+func (s *Server) ListSBGlobal(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListSBGlobalArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
 
-		err = client.GetSelectResults(datapathSelectOps, datapathReply, map[string]interface{}{datapathQueryID: &datapaths})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get datapath select results: %w", err)
-		}
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
 
-		if len(datapaths) == 0 {
-			result := map[string]interface{}{
-				"fdb_entries": []ovnsb.FDB{},
-				"count":       0,
-				"context":     "No datapath found with the specified filter.",
-			}
-			json, err := json.Marshal(result)
-			if err != nil {
-				return nil, err
-			}
-			return &mcpsdk.CallToolResult{
-				Content: []mcpsdk.Content{
-					&mcpsdk.TextContent{
-						Text: string(json),
-					},
-				},
-			}, nil
+	if args.CountOnly {
+		count, err := mcp.CountRows(ctx, client, &ovnsb.SBGlobal{})
+		if err != nil {
+			return nil, err
 		}
+		return mcp.CountResult(count)
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, ovnsb.FDB{}, conditions...)
+	results, page, err := mcp.ExecuteSelectQueryPaged(ctx, client, &ovnsb.SBGlobal{}, args.Limit, args.Offset)
 	if err != nil {
 		return nil, err
 	}
 
-	result := map[string]interface{}{
-		"fdb_entries": results,
-		"count":       len(results),
-		"context":     "FDB (Forwarding Database) entries map MAC addresses to ports on datapaths for Layer 2 forwarding.",
-	}
-
-	json, err := json.Marshal(result)
+	rows, err := mcp.MapRows(ovnsb.SBGlobalTable, ovnsb.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	result := mcp.ListResult{
+		Data:       map[string]any{"sb_globals": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "SB Global is the single row of global southbound configuration: the options northd copies from NB Global, ipsec, and the connections and ssl used by ovsdb-server. nb_cfg is the NB sequence number that northd has propagated to the southbound database; each chassis reports the value it has processed in Chassis_Private, so a chassis with a lower nb_cfg has not yet caught up.",
+	}
+	if len(results) > 0 {
+		result.Data["sequence"] = map[string]interface{}{
+			"nb_cfg": results[0].NbCfg,
+		}
+	}
+
+	return mcp.NewResult(result)
 }
 
 // NewServer creates a new OVN SB MCP server
-func NewServer(host string, port int) (*Server, error) {
+func NewServer(host string, port int, opts ...mcp.Option) (*Server, error) {
 
-	// Create OVSDB client model using generated code
-	dbModel, err := ovnsb.FullDatabaseModel()
+	// Use the OVSDB client model shared by every server in the process
+	dbModel, err := ovnsb.DatabaseModel()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create database model: %w", err)
 	}
 
-	server := mcpsdk.NewServer(&mcpsdk.Implementation{
+	base, err := mcp.NewBaseServer(&mcpsdk.Implementation{
 		Name:    "ovn-sb-mcp",
 		Title:   "OVN SB MCP Server",
 		Version: "0.1.0",
-	}, nil)
+	}, dbModel, defaultEndpoint, opts...)
+	if err != nil {
+		return nil, err
+	}
 
 	s := Server{
-		Server:  server,
-		dbModel: dbModel,
+		BaseServer: base,
+		trace:      newTraceConfig(mcp.NewOptions(opts...)),
 	}
 
 	// Register tools inline
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_datapath_bindings",
-		Description: "List all datapath bindings in OVN SB database. Datapath bindings represent physical or virtual switches.",
+		Description: "List all datapath bindings in OVN SB database. Datapath bindings represent physical or virtual switches. Set count_only to only get the number of matching rows.",
 	}, s.ListDatapathBindings)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_port_bindings",
-		Description: "List all port bindings in OVN SB database. Port bindings map logical ports to physical ports.",
+		Description: "List all port bindings in OVN SB database. Port bindings map logical ports to physical ports. Set count_only to only get the number of matching rows.",
 	}, s.ListPortBindings)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_chassis",
-		Description: "List all chassis in OVN SB database. Chassis represent physical or virtual machines that host OVN components.",
+		Description: "List all chassis in OVN SB database, optionally only those with a name, the hostname of their node or a tunnel (encap) IP address. Chassis represent physical or virtual machines that host OVN components. Set include_private to see whether each chassis has processed the latest configuration. Set count_only to only get the number of matching rows.",
 	}, s.ListChassis)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_chassis_private",
+		Description: "List the Chassis_Private rows in OVN SB database, the state each ovn-controller reports for its chassis, with the nb_cfg sequence number it has processed and whether that is the latest. Use it to find chassis that haven't applied a configuration change. Set count_only to only get the number of matching rows.",
+	}, s.ListChassisPrivate)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_logical_flows",
-		Description: "List all logical flows in OVN SB database. Logical flows represent forwarding rules translated to OpenFlow flows.",
+		Description: "List all logical flows in OVN SB database, optionally only those of a datapath, pipeline (ingress or egress) and table. Logical flows represent forwarding rules translated to OpenFlow flows. Set count_only to only get the number of matching rows.",
 	}, s.ListLogicalFlows)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "search_logical_flows",
+		Description: "Search the logical flows in OVN SB database for those whose match contains some text, such as an IP address, a port name or a protocol, optionally only those of a datapath, pipeline (ingress or egress) and table. The table is selected by the database and the match is searched here, so set table_id to narrow large flow tables. Set count_only to only get the number of matching rows.",
+	}, s.SearchLogicalFlows)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "trace_packet",
+		Description: "Simulate a packet entering a logical switch or router through a logical port, like ovn-trace, returning the logical flows it matches in each table, the actions applied and whether it is output or dropped. The flow gives the packet's fields, either as an OVN match such as ip4.src == 10.0.0.1 && ip4.dst == 10.0.0.2 && tcp.dst == 80 or in ovs-ofctl style such as tcp,ip_src=10.0.0.1,tcp_dst=80. ovn-trace is run when it is installed on the server's host, otherwise a built-in tracer approximates it; mode in the result says which was used.",
+	}, s.TracePacket)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_mac_bindings",
-		Description: "List all MAC bindings in OVN SB database. MAC bindings map MAC addresses to logical ports and IP addresses.",
+		Description: "List all MAC bindings in OVN SB database. MAC bindings map MAC addresses to logical ports and IP addresses. Set count_only to only get the number of matching rows.",
 	}, s.ListMACBindings)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "find_stale_mac_bindings",
+		Description: "Find MAC bindings in OVN SB database that are likely stale: their MAC is not assigned to any port on the network they were learned from, has moved to another network, or their IP now belongs to a port with a different MAC. Stale bindings black-hole traffic until they age out.",
+	}, s.FindStaleMACBindings)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_encaps",
-		Description: "List all encapsulations in OVN SB database. Encapsulations define tunneling protocols for chassis connections.",
+		Description: "List all encapsulations in OVN SB database. Encapsulations define tunneling protocols for chassis connections. Set count_only to only get the number of matching rows.",
 	}, s.ListEncaps)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_gateway_chassis",
+		Description: "List gateway chassis in OVN SB database, the chassis each distributed gateway port can be bound to and their priorities, highest first. Filter by chassis name to see which gateway ports a chassis can host. Set count_only to only get the number of matching rows.",
+	}, s.ListGatewayChassis)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_ha_chassis",
+		Description: "List HA chassis in OVN SB database, the members of HA chassis groups with their chassis names and priorities, highest first. Filter by chassis name to see which groups a chassis is a member of. Set count_only to only get the number of matching rows.",
+	}, s.ListHAChassis)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_ha_chassis_groups",
+		Description: "List HA chassis groups in OVN SB database. HA chassis groups define which chassis, in priority order, a gateway router port or external port fails over between. Set resolve to expand each group's members with their chassis names and priorities. Set count_only to only get the number of matching rows.",
+	}, s.ListHAChassisGroups)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_meters",
-		Description: "List all meters in OVN SB database. Meters provide rate limiting and policing capabilities.",
+		Description: "List all meters in OVN SB database. Meters provide rate limiting and policing capabilities. Set count_only to only get the number of matching rows.",
 	}, s.ListMeters)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_fdb_entries",
-		Description: "List all FDB entries in OVN SB database. FDB entries map MAC addresses to ports for Layer 2 forwarding.",
+		Description: "List all FDB entries in OVN SB database. FDB entries map MAC addresses to ports for Layer 2 forwarding. Set count_only to only get the number of matching rows.",
 	}, s.ListFDBEntries)
 
-	return &s, nil
-}
-
-// Start starts the MCP server on the specified address
-func (s *Server) Start(ctx context.Context, addr string) error {
-	// Create HTTP server using Streamable HTTP handler
-	streamableHandler := mcpsdk.NewStreamableHTTPHandler(func(request *http.Request) *mcpsdk.Server {
-		return s.Server
-	}, nil)
-
-	s.httpServer = &http.Server{
-		Addr:    addr,
-		Handler: streamableHandler,
-	}
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_multicast_groups",
+		Description: "List multicast groups in OVN SB database, the sets of logical ports a datapath floods or multicasts packets to, with their tunnel keys and the names of their ports. Filter by datapath or group name, e.g. _MC_flood. Set count_only to only get the number of matching rows.",
+	}, s.ListMulticastGroups)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_igmp_groups",
+		Description: "List IGMP groups in OVN SB database, the IP multicast groups each chassis has learnt by IGMP or MLD snooping, with the names of the ports that joined. Filter by datapath or multicast address. Set count_only to only get the number of matching rows.",
+	}, s.ListIGMPGroups)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_service_monitors",
+		Description: "List service monitors in OVN SB database, the health checks of load balancer backends with their status (online, offline or error), protocol, IP and port. Use it to find out why a backend gets no traffic. Filter by logical port, IP, status or the chassis running the checks. Set count_only to only get the number of matching rows.",
+	}, s.ListServiceMonitors)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_sb_global",
+		Description: "List the SB Global row in OVN SB database with its options and the nb_cfg sequence number northd has propagated, to compare against nb_cfg and sb_cfg in NB Global. Set count_only to only get the number of matching rows.",
+	}, s.ListSBGlobal)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "binding_churn",
+		Description: "Report which chassis port bindings were added to and removed from most over a recent window, highest churn first. High churn is a sign of node instability, drains or mass pod rescheduling. Requires the server to be recording row history, as OVSDB only holds the current bindings.",
+	}, s.BindingChurn)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "resolve_tunnel_key",
+		Description: "Resolve a tunnel key to the datapath binding, port bindings or multicast groups it identifies, e.g. what metadata=0x5 or reg15=0x2 in a logical or OpenFlow flow refers to. Keys are decimal or hex.",
+	}, s.ResolveTunnelKey)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "resolve_port_binding",
+		Description: "Resolve a logical port, named as in the northbound database, to its port binding: the chassis it is bound to with its hostname and tunnel IPs, its tunnel key and its datapath. Answers which node a VM or pod runs on, and reports ports that no chassis has claimed as unbound.",
+	}, s.ResolvePortBinding)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "ovsdb_select",
+		Description: "Run a select on any table of the OVN SB database with [column, function, value] conditions, which rows must all match unless match_all is false, optionally returning only some columns. An escape hatch for querying columns the other tools don't filter on; use get_schema to find the tables and columns. Unknown tables and columns are rejected with the valid names.",
+	}, s.OVSDBSelect)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "count_by",
+		Description: "Count the rows of any table of the OVN SB database grouped by the value of a column, e.g. Port_Binding by type or chassis, without returning the rows. Use for summary questions such as how many ports are down.",
+	}, s.CountBy)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "get_schema",
+		Description: "Get the schema of the OVN SB database: its tables, and each table's columns with their types, whether they are sets, maps or references to other tables, and the values allowed for enums. Optionally describe a single table. Useful for finding which columns exist before filtering on them. Needs no database connection.",
+	}, mcp.GetSchema(ovnsb.DatabaseSchema()))
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "describe_schema",
+		Description: "List the tables of the OVN SB database with the type of each of their columns, as compact JSON keyed by table. A quick overview of what can be queried before choosing a tool; use get_schema for the details of a table. Needs no database connection.",
+	}, mcp.DescribeSchema(ovnsb.DatabaseSchema()))
+
+	mcp.AddLongRunningTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "watch_table",
+		Description: "Watch one table of the OVN SB database (e.g. Port_Binding) for a while and report its row inserts, updates and deletes as they happen. Each change is streamed to the client as a progress notification when the call has a progress token, and all of them are returned when the watch ends after duration_seconds (30 by default, at most 300) or max_events changes. Optionally only watch rows whose name matches a filter. The monitor is removed when the watch ends or the session closes.",
+	}, s.WatchTable)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "find_by_external_id_key",
+		Description: "Find the rows of every table in the OVN SB database that have an external_ids key, whatever its value, grouped by table. Optionally only match rows whose value for the key contains a substring. Useful for finding every object owned or labelled by a controller, e.g. by the k8s.ovn.org/owner key.",
+	}, s.FindByExternalIDKey)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "row_history",
+		Description: "Get the current value of any row in the OVN SB database by UUID, with its recent changes as timestamped before and after snapshots when the server is recording row history. OVSDB does not keep history itself, so only changes made since the server started recording are available.",
+	}, s.RowHistory)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "ping_database",
+		Description: "Check that the OVN SB database can be reached, with the connection latency and the schema version it serves. Use it before running other tools when the database may be down. Results are cached for a few seconds.",
+	}, s.PingDatabase)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "server_info",
+		Description: "Get the schema version of the OVN SB database and, from the ovsdb-server _Server database, whether it is standalone, clustered or a relay, whether the server is the cluster leader and connected to the cluster, and its cluster and server IDs. Use it to find the leader of a clustered database and the version to quote in bug reports.",
+	}, s.ServerInfo)
 
-	// Start server in a goroutine
-	go func() {
-		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			// Log error if we had a logger
-		}
-	}()
-
-	return nil
-}
-
-// Stop stops the MCP server
-func (s *Server) Stop(ctx context.Context) error {
-	if s.httpServer != nil {
-		return s.httpServer.Shutdown(ctx)
-	}
-	return nil
+	return &s, nil
 }
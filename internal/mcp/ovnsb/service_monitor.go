@@ -0,0 +1,89 @@
+package ovnsb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type ListServiceMonitorsArgs struct {
+	LogicalPort   string `json:"logical_port,omitempty" jsonschema:"only return the monitors of the backend with this logical port"`
+	IP            string `json:"ip,omitempty" jsonschema:"only return the monitors of the backend with this IP address"`
+	Status        string `json:"status,omitempty" jsonschema:"only return monitors with this status: online, offline or error"`
+	ChassisFilter string `json:"chassis_filter,omitempty" jsonschema:"the name of the chassis running the health checks to filter by"`
+	CountOnly     bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit         int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset        int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+var serviceMonitorStatuses = []string{
+	ovnsb.ServiceMonitorStatusOnline,
+	ovnsb.ServiceMonitorStatusOffline,
+	ovnsb.ServiceMonitorStatusError,
+}
+
+func (s *Server) ListServiceMonitors(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListServiceMonitorsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	if args.Status != "" && !slices.Contains(serviceMonitorStatuses, args.Status) {
+		return nil, fmt.Errorf("invalid status %q, must be one of online, offline or error", args.Status)
+	}
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	monitor := &ovnsb.ServiceMonitor{}
+	query := mcp.ListQuery[ovnsb.Chassis, ovnsb.ServiceMonitor]{
+		Model:     monitor,
+		Table:     ovnsb.ServiceMonitorTable,
+		Schema:    ovnsb.DatabaseSchema(),
+		Key:       "service_monitors",
+		Context:   "Service monitors are the health checks of load balancer backends. ovn-northd creates one for each backend of a load balancer with a health check configured, using the backend's logical port and source IP from the load balancer's ip_port_mappings. The ovn-controller of the chassis hosting the backend's port (chassis_name) probes ip and port over protocol from src_ip and src_mac, and sets status: online when the backend answers, offline when it stops answering, and error when the probe can't be sent. Backends whose monitor is not online are removed from the load balancer's VIP, so an offline backend receives no new connections; an empty status means the backend has not been probed yet, usually because its port is not bound to a chassis.",
+		Limit:     args.Limit,
+		Offset:    args.Offset,
+		CountOnly: args.CountOnly,
+	}
+	if args.LogicalPort != "" {
+		query.Conditions = append(query.Conditions, model.Condition{
+			Field:    &monitor.LogicalPort,
+			Function: ovsdb.ConditionEqual,
+			Value:    args.LogicalPort,
+		})
+	}
+	if args.IP != "" {
+		query.Conditions = append(query.Conditions, model.Condition{
+			Field:    &monitor.IP,
+			Function: ovsdb.ConditionEqual,
+			Value:    args.IP,
+		})
+	}
+	if args.Status != "" {
+		// status is an enum, which libovsdb can't build conditions on
+		query.Filter = func(sm ovnsb.ServiceMonitor) bool {
+			return sm.Status != nil && *sm.Status == args.Status
+		}
+	}
+	if args.ChassisFilter != "" {
+		chassis := &ovnsb.Chassis{}
+		query.Parent = &mcp.ParentFilter[ovnsb.Chassis, ovnsb.ServiceMonitor]{
+			Model: chassis,
+			Field: &chassis.Name,
+			Value: args.ChassisFilter,
+			Kind:  "chassis",
+			Keep: func(ch ovnsb.Chassis, sm ovnsb.ServiceMonitor) bool {
+				return sm.ChassisName == ch.Name
+			},
+		}
+	}
+
+	return mcp.ListWithParentFilter(ctx, client, query)
+}
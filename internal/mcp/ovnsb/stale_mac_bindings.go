@@ -0,0 +1,275 @@
+package ovnsb
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type FindStaleMACBindingsArgs struct {
+	DatapathFilter string `json:"datapath_filter,omitempty" jsonschema:"only check MAC bindings learned on the router datapath with this name"`
+}
+
+// Reasons a MAC binding is suspected to be stale
+const (
+	staleMACUnknown   = "mac_not_assigned"
+	staleMACMoved     = "mac_on_other_network"
+	staleIPReassigned = "ip_reassigned"
+)
+
+// StaleMACBinding is a MAC binding whose MAC does not match the ports on the
+// network it was learned from
+type StaleMACBinding struct {
+	UUID        string `json:"uuid"`
+	IP          string `json:"ip"`
+	MAC         string `json:"mac"`
+	LogicalPort string `json:"logical_port"`
+	Datapath    string `json:"datapath"`
+	Network     string `json:"network,omitempty"`
+	Timestamp   int    `json:"timestamp"`
+	Reason      string `json:"reason"`
+	Detail      string `json:"detail"`
+	// ExternalPossible is set when the network has localnet ports or ports
+	// with unknown addresses, so the MAC may belong to a host outside OVN
+	ExternalPossible bool `json:"external_possible"`
+}
+
+// portAddress is a MAC and its IPs from a port binding's mac or nat_addresses
+// column
+type portAddress struct {
+	port string
+	mac  string
+	ips  []netip.Addr
+}
+
+// parsePortAddresses parses "MAC IP..." entries, others such as "router" or
+// "unknown" are skipped
+func parsePortAddresses(port string, entries []string) []portAddress {
+	var addresses []portAddress
+	for _, entry := range entries {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			continue
+		}
+		hw, err := net.ParseMAC(fields[0])
+		if err != nil {
+			continue
+		}
+		address := portAddress{port: port, mac: hw.String()}
+		for _, f := range fields[1:] {
+			if prefix, err := netip.ParsePrefix(f); err == nil {
+				address.ips = append(address.ips, prefix.Addr())
+			} else if addr, err := netip.ParseAddr(f); err == nil {
+				address.ips = append(address.ips, addr)
+			}
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+// network is the MACs and IPs known on one logical switch datapath
+type network struct {
+	addresses []portAddress
+	// external is set if hosts outside OVN can appear on the network
+	external bool
+}
+
+func (n *network) findMAC(mac string) *portAddress {
+	for i := range n.addresses {
+		if n.addresses[i].mac == mac {
+			return &n.addresses[i]
+		}
+	}
+	return nil
+}
+
+func (n *network) findIP(ip netip.Addr) *portAddress {
+	for i := range n.addresses {
+		for _, a := range n.addresses[i].ips {
+			if a == ip {
+				return &n.addresses[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Server) FindStaleMACBindings(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[FindStaleMACBindingsArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	datapaths, err := mcp.ExecuteSelectQuery(ctx, client, &ovnsb.DatapathBinding{})
+	if err != nil {
+		return nil, err
+	}
+	bindings, err := mcp.ExecuteSelectQuery(ctx, client, &ovnsb.PortBinding{})
+	if err != nil {
+		return nil, err
+	}
+	macBindings, err := mcp.ExecuteSelectQuery(ctx, client, &ovnsb.MACBinding{})
+	if err != nil {
+		return nil, err
+	}
+
+	datapathNames := make(map[string]string, len(datapaths))
+	for _, dp := range datapaths {
+		datapathNames[dp.UUID] = dp.ExternalIDs["name"]
+	}
+	if args.DatapathFilter != "" {
+		found := false
+		for _, name := range datapathNames {
+			if name == args.DatapathFilter {
+				found = true
+			}
+		}
+		if !found {
+			return &mcpsdk.CallToolResultFor[map[string]any]{
+				IsError: true,
+				Content: []mcpsdk.Content{
+					&mcpsdk.TextContent{
+						Text: fmt.Sprintf("No datapath found with name %s", args.DatapathFilter),
+					},
+				},
+			}, nil
+		}
+	}
+
+	portByName := make(map[string]ovnsb.PortBinding, len(bindings))
+	for _, pb := range bindings {
+		portByName[pb.LogicalPort] = pb
+	}
+
+	// Index the addresses on each datapath. Switch ports that connect to a
+	// router have the MAC "router" and take the router port's addresses.
+	networks := make(map[string]*network)
+	for _, pb := range bindings {
+		n, ok := networks[pb.Datapath]
+		if !ok {
+			n = &network{}
+			networks[pb.Datapath] = n
+		}
+		n.addresses = append(n.addresses, parsePortAddresses(pb.LogicalPort, pb.MAC)...)
+		n.addresses = append(n.addresses, parsePortAddresses(pb.LogicalPort, pb.NatAddresses)...)
+		for _, mac := range pb.MAC {
+			fields := strings.Fields(mac)
+			if len(fields) == 0 {
+				continue
+			}
+			switch fields[0] {
+			case "router":
+				if peer, ok := portByName[pb.Options["peer"]]; ok {
+					n.addresses = append(n.addresses, parsePortAddresses(peer.LogicalPort, peer.MAC)...)
+				}
+			case "unknown":
+				n.external = true
+			}
+		}
+		if pb.Type == "localnet" || pb.Type == "l2gateway" || pb.Type == "vtep" {
+			n.external = true
+		}
+	}
+
+	var all network
+	for _, n := range networks {
+		all.addresses = append(all.addresses, n.addresses...)
+	}
+
+	stale := []StaleMACBinding{}
+	checked := 0
+	for _, mb := range macBindings {
+		datapath := datapathNames[mb.Datapath]
+		if args.DatapathFilter != "" && datapath != args.DatapathFilter {
+			continue
+		}
+		checked++
+
+		mac := strings.ToLower(mb.MAC)
+		if hw, err := net.ParseMAC(mb.MAC); err == nil {
+			mac = hw.String()
+		}
+		ip, ipErr := netip.ParseAddr(mb.IP)
+
+		// The binding was learned on a router port, the neighbour is on the
+		// switch at the other end of it
+		var n *network
+		var networkName string
+		if lrp, ok := portByName[mb.LogicalPort]; ok {
+			if peer, ok := portByName[lrp.Options["peer"]]; ok {
+				n = networks[peer.Datapath]
+				networkName = datapathNames[peer.Datapath]
+			}
+		}
+
+		binding := StaleMACBinding{
+			UUID:        mb.UUID,
+			IP:          mb.IP,
+			MAC:         mb.MAC,
+			LogicalPort: mb.LogicalPort,
+			Datapath:    datapath,
+			Network:     networkName,
+			Timestamp:   mb.Timestamp,
+		}
+		if n == nil {
+			// Gateway ports on a localnet network have no peer, so only
+			// look for the MAC anywhere in OVN
+			if all.findMAC(mac) != nil {
+				continue
+			}
+			binding.Reason = staleMACUnknown
+			binding.Detail = "no port in OVN has this MAC"
+			binding.ExternalPossible = true
+			stale = append(stale, binding)
+			continue
+		}
+
+		owner := n.findMAC(mac)
+		if owner == nil {
+			binding.ExternalPossible = n.external
+			if other := all.findMAC(mac); other != nil {
+				binding.Reason = staleMACMoved
+				binding.Detail = fmt.Sprintf("the MAC belongs to port %s, which is not on %s", other.port, networkName)
+			} else {
+				binding.Reason = staleMACUnknown
+				binding.Detail = fmt.Sprintf("no port on %s has this MAC", networkName)
+			}
+			stale = append(stale, binding)
+			continue
+		}
+		if ipErr == nil {
+			if ipOwner := n.findIP(ip); ipOwner != nil && ipOwner.mac != mac {
+				binding.Reason = staleIPReassigned
+				binding.Detail = fmt.Sprintf("the IP is assigned to port %s with MAC %s", ipOwner.port, ipOwner.mac)
+				stale = append(stale, binding)
+			}
+		}
+	}
+
+	sort.Slice(stale, func(i, j int) bool {
+		if stale[i].Datapath != stale[j].Datapath {
+			return stale[i].Datapath < stale[j].Datapath
+		}
+		return stale[i].IP < stale[j].IP
+	})
+
+	result := map[string]interface{}{
+		"stale_mac_bindings": stale,
+		"checked":            checked,
+		"count":              len(stale),
+		"context":            "MAC bindings are the IP to MAC mappings logical routers learn with ARP and ND. Each binding is checked against the MACs of the ports on the switch connected to the router port it was learned on. mac_not_assigned bindings point at a MAC no port has, mac_on_other_network bindings at a MAC that is now on a different network, and ip_reassigned bindings map an IP that a port now has with a different MAC. Traffic to these IPs is black-holed until the binding ages out or is removed (ovn-sbctl destroy MAC_Binding <uuid>). When external_possible is set the network has localnet or unknown-address ports, so the MAC may be a legitimate host outside OVN.",
+	}
+
+	return mcp.NewResult(result)
+}
@@ -0,0 +1,961 @@
+package ovnsb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+// Modes of trace_packet
+const (
+	traceModeAuto     = "auto"
+	traceModeOVNTrace = "ovn-trace"
+	traceModeBuiltin  = "builtin"
+)
+
+// Verdicts of a built-in trace
+const (
+	verdictOutput  = "output"
+	verdictDrop    = "drop"
+	verdictStopped = "stopped"
+	verdictUnknown = "unknown"
+)
+
+// maxTraceSteps bounds the flows a built-in trace matches, in case the
+// pipeline loops
+const maxTraceSteps = 256
+
+// defaultTTL is the ip.ttl of traced IP packets that don't set it, as a TTL
+// of zero would be dropped by the first router
+const defaultTTL = "64"
+
+type TracePacketArgs struct {
+	Datapath string `json:"datapath" jsonschema:"the name of the logical switch or router the packet enters"`
+	Inport   string `json:"inport" jsonschema:"the name of the logical port the packet enters through"`
+	Flow     string `json:"flow" jsonschema:"the packet's fields, as an OVN match such as ip4.src == 10.0.0.1 && tcp.dst == 80 or in ovs-ofctl style such as tcp,ip_src=10.0.0.1,tcp_dst=80"`
+	Mode     string `json:"mode,omitempty" jsonschema:"auto (default) runs ovn-trace when it is installed and uses the built-in tracer otherwise, ovn-trace or builtin force a mode"`
+}
+
+// TraceStep is a logical flow matched by the traced packet
+type TraceStep struct {
+	Datapath string `json:"datapath"`
+	Pipeline string `json:"pipeline"`
+	Table    int    `json:"table"`
+	Stage    string `json:"stage,omitempty"`
+	// StageHint is the start of the UUID of the northbound row the flow
+	// was generated for, such as an ACL or load balancer
+	StageHint string `json:"stage_hint,omitempty"`
+	Priority  int    `json:"priority"`
+	Match     string `json:"match"`
+	Actions   string `json:"actions"`
+	UUID      string `json:"uuid"`
+	// Uncertain are the flows of the table with a higher priority whose
+	// match could not be evaluated, so one of them may match instead
+	Uncertain []string `json:"uncertain,omitempty"`
+	// Notes are the actions that were not simulated or assumed a result
+	Notes []string `json:"notes,omitempty"`
+}
+
+// TraceResult is the path of a packet through the logical pipeline
+type TraceResult struct {
+	Mode      string      `json:"mode"`
+	Datapath  string      `json:"datapath"`
+	Microflow string      `json:"microflow"`
+	Command   []string    `json:"command,omitempty"`
+	Output    string      `json:"output,omitempty"`
+	Steps     []TraceStep `json:"steps,omitempty"`
+	Verdict   string      `json:"verdict,omitempty"`
+	Reason    string      `json:"reason,omitempty"`
+	Outport   string      `json:"outport,omitempty"`
+	Packet    packet      `json:"packet,omitempty"`
+	Context   string      `json:"context"`
+}
+
+// traceConfig is how ovn-trace connects to the southbound database
+type traceConfig struct {
+	certFile string
+	keyFile  string
+	caFile   string
+	// tlsConfigOnly is set when TLS was configured without certificate
+	// files, which ovn-trace can't use
+	tlsConfigOnly bool
+}
+
+func newTraceConfig(o *mcp.Options) traceConfig {
+	return traceConfig{
+		certFile:      o.CertFile,
+		keyFile:       o.KeyFile,
+		caFile:        o.CAFile,
+		tlsConfigOnly: o.TLSConfig != nil,
+	}
+}
+
+// args returns the ovn-trace arguments connecting to the database at
+// endpoint, or an error when it can't connect the way the server does
+func (c traceConfig) args(endpoint string) ([]string, error) {
+	args := []string{"--db=" + endpoint}
+	if !strings.HasPrefix(endpoint, "ssl:") {
+		return args, nil
+	}
+	if c.tlsConfigOnly || c.certFile == "" || c.keyFile == "" || c.caFile == "" {
+		return nil, errors.New("ovn-trace needs certificate, key and CA files to connect to an ssl: endpoint")
+	}
+	return append(args, "--certificate="+c.certFile, "--private-key="+c.keyFile, "--ca-cert="+c.caFile), nil
+}
+
+// microflowField is a field set by the flow argument of trace_packet, or
+// a protocol when value is empty
+type microflowField struct {
+	name  string
+	value string
+}
+
+// ofctlFields maps the field names of ovs-ofctl flows to OVN fields. tp_src
+// and tp_dst depend on the protocol.
+var ofctlFields = map[string]string{
+	"in_port":   "inport",
+	"dl_src":    "eth.src",
+	"dl_dst":    "eth.dst",
+	"dl_type":   "eth.type",
+	"dl_vlan":   "vlan.vid",
+	"nw_src":    "ip4.src",
+	"nw_dst":    "ip4.dst",
+	"ip_src":    "ip4.src",
+	"ip_dst":    "ip4.dst",
+	"ipv6_src":  "ip6.src",
+	"ipv6_dst":  "ip6.dst",
+	"nw_proto":  "ip.proto",
+	"nw_ttl":    "ip.ttl",
+	"nw_tos":    "ip.dscp",
+	"tcp_src":   "tcp.src",
+	"tcp_dst":   "tcp.dst",
+	"udp_src":   "udp.src",
+	"udp_dst":   "udp.dst",
+	"sctp_src":  "sctp.src",
+	"sctp_dst":  "sctp.dst",
+	"icmp_type": "icmp4.type",
+	"icmp_code": "icmp4.code",
+	"arp_spa":   "arp.spa",
+	"arp_tpa":   "arp.tpa",
+	"arp_sha":   "arp.sha",
+	"arp_tha":   "arp.tha",
+	"arp_op":    "arp.op",
+	"nd_target": "nd.target",
+}
+
+// ofctlProtocols maps the protocol keywords of ovs-ofctl flows to OVN
+var ofctlProtocols = map[string][]string{
+	"ip":     {"ip4"},
+	"ipv6":   {"ip6"},
+	"arp":    {"arp"},
+	"icmp":   {"icmp4"},
+	"icmp6":  {"icmp6"},
+	"tcp":    {"ip4", "tcp"},
+	"tcp6":   {"ip6", "tcp"},
+	"udp":    {"ip4", "udp"},
+	"udp6":   {"ip6", "udp"},
+	"sctp":   {"ip4", "sctp"},
+	"sctp6":  {"ip6", "sctp"},
+	"igmp":   {"igmp"},
+	"ip4":    {"ip4"},
+	"ip6":    {"ip6"},
+	"icmp4":  {"icmp4"},
+	"rarp":   {"rarp"},
+	"dl_arp": {"arp"},
+}
+
+// parseMicroflow parses the flow argument of trace_packet, either an OVN
+// match of fields compared with == and protocols joined by &&, or an
+// ovs-ofctl flow of protocols and field=value pairs joined by commas
+func parseMicroflow(flow string) ([]microflowField, error) {
+	var fields []microflowField
+	if strings.Contains(flow, "==") || strings.Contains(flow, "&&") {
+		if strings.ContainsAny(flow, "|!<>()") {
+			return nil, fmt.Errorf("invalid flow %q, the flow may only compare fields with == and join them with &&", flow)
+		}
+		for _, term := range strings.Split(flow, "&&") {
+			term = strings.TrimSpace(term)
+			if term == "" {
+				continue
+			}
+			name, value, ok := strings.Cut(term, "==")
+			if !ok {
+				if strings.Contains(term, " ") {
+					return nil, fmt.Errorf("invalid flow term %q, the flow may only compare fields with == and join them with &&", term)
+				}
+				fields = append(fields, microflowField{name: term})
+				continue
+			}
+			fields = append(fields, microflowField{
+				name:  strings.TrimSpace(name),
+				value: strings.Trim(strings.TrimSpace(value), `"`),
+			})
+		}
+		return fields, nil
+	}
+
+	var protocol string
+	for _, term := range strings.Split(flow, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(term, "=")
+		if !ok {
+			protocols, known := ofctlProtocols[term]
+			if !known {
+				return nil, fmt.Errorf("unknown protocol %q in flow", term)
+			}
+			for _, p := range protocols {
+				fields = append(fields, microflowField{name: p})
+			}
+			protocol = protocols[len(protocols)-1]
+			continue
+		}
+		name = strings.TrimSpace(name)
+		field, known := ofctlFields[name]
+		switch {
+		case known:
+		case name == "tp_src" || name == "tp_dst":
+			if protocol != "tcp" && protocol != "udp" && protocol != "sctp" {
+				return nil, fmt.Errorf("%s needs tcp, udp or sctp before it in the flow", name)
+			}
+			field = protocol + "." + strings.TrimPrefix(name, "tp_")
+		case strings.Contains(name, "."):
+			// Already an OVN field
+			field = name
+		default:
+			return nil, fmt.Errorf("unknown field %q in flow", name)
+		}
+		fields = append(fields, microflowField{name: field, value: strings.Trim(strings.TrimSpace(value), `"`)})
+	}
+	return fields, nil
+}
+
+// microflowMatch formats the packet as the OVN match that ovn-trace expects,
+// starting with its inport
+func microflowMatch(inport string, fields []microflowField) string {
+	terms := []string{fmt.Sprintf("inport == %q", inport)}
+	for _, f := range fields {
+		switch {
+		case f.name == "inport":
+		case f.value == "":
+			terms = append(terms, f.name)
+		case stringFields[f.name]:
+			terms = append(terms, fmt.Sprintf("%s == %q", f.name, f.value))
+		default:
+			terms = append(terms, f.name+" == "+f.value)
+		}
+	}
+	return strings.Join(terms, " && ")
+}
+
+// protocolFields are the fields set by a protocol of the flow, and the
+// protocols it implies
+var protocolFields = map[string]struct {
+	implies []string
+	fields  map[string]string
+}{
+	"ip4":   {fields: map[string]string{"eth.type": "0x800"}},
+	"ip6":   {fields: map[string]string{"eth.type": "0x86dd"}},
+	"arp":   {fields: map[string]string{"eth.type": "0x806"}},
+	"rarp":  {fields: map[string]string{"eth.type": "0x8035"}},
+	"ip":    {implies: []string{"ip4"}},
+	"icmp":  {implies: []string{"icmp4"}},
+	"icmp4": {implies: []string{"ip4"}, fields: map[string]string{"ip.proto": "1"}},
+	"icmp6": {implies: []string{"ip6"}, fields: map[string]string{"ip.proto": "58"}},
+	"igmp":  {implies: []string{"ip4"}, fields: map[string]string{"ip.proto": "2"}},
+	"tcp":   {implies: []string{"ip"}, fields: map[string]string{"ip.proto": "6"}},
+	"udp":   {implies: []string{"ip"}, fields: map[string]string{"ip.proto": "17"}},
+	"sctp":  {implies: []string{"ip"}, fields: map[string]string{"ip.proto": "132"}},
+}
+
+// newPacket builds the packet described by fields, setting the fields
+// implied by its protocols, such as eth.type for ip4. The protocol of a
+// field's name is implied too, e.g. tcp for tcp.dst.
+func newPacket(inport string, fields []microflowField) (packet, error) {
+	p := packet{}
+	protocols := map[string]bool{}
+	var addProtocol func(name string)
+	addProtocol = func(name string) {
+		if protocols[name] {
+			return
+		}
+		protocols[name] = true
+		proto := protocolFields[name]
+		for _, implied := range proto.implies {
+			// An IPv6 packet is not made IPv4 by tcp or icmp
+			if implied == "ip" && protocols["ip6"] || implied == "ip4" && protocols["ip6"] {
+				continue
+			}
+			addProtocol(implied)
+		}
+		for k, v := range proto.fields {
+			if _, set := p[k]; !set {
+				p[k] = v
+			}
+		}
+	}
+
+	// IPv6 first, so that protocols don't imply IPv4 for IPv6 packets
+	for _, f := range fields {
+		if f.name == "ip6" || strings.HasPrefix(f.name, "ip6.") || f.name == "icmp6" || strings.HasPrefix(f.name, "icmp6.") {
+			addProtocol("ip6")
+		}
+	}
+	for _, f := range fields {
+		if f.value != "" {
+			continue
+		}
+		if _, known := protocolFields[f.name]; !known {
+			return nil, fmt.Errorf("unknown protocol %q in flow", f.name)
+		}
+		addProtocol(f.name)
+	}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		if prefix, _, ok := strings.Cut(f.name, "."); ok {
+			if _, known := protocolFields[prefix]; known {
+				addProtocol(prefix)
+			}
+		}
+		if !stringFields[f.name] {
+			if _, _, err := parseConstant(f.value); err != nil {
+				return nil, fmt.Errorf("invalid value for %s: %w", f.name, err)
+			}
+		}
+		p[f.name] = f.value
+	}
+	if protocols["ip4"] || protocols["ip6"] {
+		if _, set := p["ip.ttl"]; !set {
+			p["ip.ttl"] = defaultTTL
+		}
+	}
+	p["inport"] = inport
+	return p, nil
+}
+
+func (s *Server) TracePacket(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[TracePacketArgs]) (*mcpsdk.CallToolResultFor[TraceResult], error) {
+	args := params.Arguments
+
+	if args.Datapath == "" {
+		return nil, fmt.Errorf("datapath must not be empty")
+	}
+	if strings.HasPrefix(args.Datapath, "-") {
+		// It would be taken as an option by ovn-trace
+		return nil, fmt.Errorf("invalid datapath %q, must not start with -", args.Datapath)
+	}
+	if args.Inport == "" {
+		return nil, fmt.Errorf("inport must not be empty")
+	}
+	fields, err := parseMicroflow(args.Flow)
+	if err != nil {
+		return nil, err
+	}
+	microflow := microflowMatch(args.Inport, fields)
+
+	mode := args.Mode
+	if mode == "" {
+		mode = traceModeAuto
+	}
+	var ovnTrace string
+	switch mode {
+	case traceModeAuto:
+		if path, err := exec.LookPath("ovn-trace"); err == nil {
+			if _, err := s.trace.args(s.Endpoint(ctx)); err == nil {
+				ovnTrace = path
+			}
+		}
+	case traceModeOVNTrace:
+		path, err := exec.LookPath("ovn-trace")
+		if err != nil {
+			return nil, fmt.Errorf("ovn-trace is not installed on the server's host, use the builtin mode")
+		}
+		ovnTrace = path
+	case traceModeBuiltin:
+	default:
+		return nil, fmt.Errorf("invalid mode %q, must be one of %s, %s or %s", mode, traceModeAuto, traceModeOVNTrace, traceModeBuiltin)
+	}
+
+	if ovnTrace != "" {
+		return s.runOVNTrace(ctx, ovnTrace, args.Datapath, microflow)
+	}
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	result, err := traceBuiltin(ctx, client, args.Datapath, args.Inport, fields)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return &mcpsdk.CallToolResultFor[TraceResult]{
+			IsError: true,
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: fmt.Sprintf("No datapath found with name %s", args.Datapath),
+				},
+			},
+		}, nil
+	}
+	return mcp.NewResult(*result)
+}
+
+// Trace walks the packet described by flow, entering the logical switch or
+// router named datapath through inport, through the logical flows of the
+// southbound database with the built-in tracer. It returns nil when there
+// is no such datapath. It is used by tools correlating the trace with the
+// northbound database.
+func Trace(ctx context.Context, c client.Client, datapath, inport, flow string) (*TraceResult, error) {
+	fields, err := parseMicroflow(flow)
+	if err != nil {
+		return nil, err
+	}
+	return traceBuiltin(ctx, c, datapath, inport, fields)
+}
+
+func traceBuiltin(ctx context.Context, c client.Client, datapath, inport string, fields []microflowField) (*TraceResult, error) {
+	p, err := newPacket(inport, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	tracer, err := newTracer(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	dp, err := tracer.datapathByName(ctx, datapath)
+	if err != nil || dp == nil {
+		return nil, err
+	}
+
+	result, err := tracer.trace(ctx, dp, p)
+	if err != nil {
+		return nil, err
+	}
+	result.Mode = traceModeBuiltin
+	result.Datapath = datapath
+	result.Microflow = microflowMatch(inport, fields)
+	result.Context = "The packet was traced by the built-in tracer, which walks the logical flows of the southbound database: in each table, the highest priority flow whose match is true is followed. It approximates ovn-trace: fields that are not set are zero (ip.ttl is 64), connection tracking treats every packet as a new connection, functions such as is_chassis_resident() and check_in_port_sec() are not evaluated, and actions that generate new packets, such as arp { ... }, end the trace. Patch ports are followed into their peer's datapath. uncertain lists higher priority flows whose match could not be evaluated, and notes list assumptions made for the actions of a step. Install ovn-trace on the server's host for an exact trace."
+	return result, nil
+}
+
+// runOVNTrace traces microflow with ovn-trace, connected to the server's
+// database
+func (s *Server) runOVNTrace(ctx context.Context, path, datapath, microflow string) (*mcpsdk.CallToolResultFor[TraceResult], error) {
+	dbArgs, err := s.trace.args(s.Endpoint(ctx))
+	if err != nil {
+		return nil, err
+	}
+	command := append([]string{path}, dbArgs...)
+	// Nothing after -- is parsed as an option
+	command = append(command, "--", datapath, microflow)
+
+	output, err := exec.CommandContext(ctx, command[0], command[1:]...).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("ovn-trace failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return mcp.NewResult(TraceResult{
+		Mode:      traceModeOVNTrace,
+		Datapath:  datapath,
+		Microflow: microflow,
+		Command:   command,
+		Output:    string(output),
+		Context:   "The packet was traced by ovn-trace, which simulates it through the logical flows of the southbound database. The output lists each logical flow it matched by pipeline, table and stage, with the actions applied, and ends with the port the packet is output to or the reason it is dropped.",
+	})
+}
+
+// tracer walks packets through the logical flows of a southbound database
+type tracer struct {
+	client client.Client
+	sets   traceSets
+	groups map[string][]string
+	// flows are the flows of each datapath traced so far, by pipeline and
+	// table, highest priority first
+	flows     map[string]map[string]map[int][]ovnsb.LogicalFlow
+	datapaths map[string]*ovnsb.DatapathBinding
+	ports     map[string]*ovnsb.PortBinding
+}
+
+func newTracer(ctx context.Context, c client.Client) (*tracer, error) {
+	t := &tracer{
+		client: c,
+		sets: traceSets{
+			addressSets: map[string][]string{},
+			portGroups:  map[string][]string{},
+		},
+		groups:    map[string][]string{},
+		flows:     map[string]map[string]map[int][]ovnsb.LogicalFlow{},
+		datapaths: map[string]*ovnsb.DatapathBinding{},
+		ports:     map[string]*ovnsb.PortBinding{},
+	}
+
+	addressSets, err := mcp.ExecuteSelectQuery(ctx, c, &ovnsb.AddressSet{})
+	if err != nil {
+		return nil, err
+	}
+	for _, as := range addressSets {
+		t.sets.addressSets[as.Name] = as.Addresses
+	}
+	portGroups, err := mcp.ExecuteSelectQuery(ctx, c, &ovnsb.PortGroup{})
+	if err != nil {
+		return nil, err
+	}
+	for _, pg := range portGroups {
+		t.sets.portGroups[pg.Name] = pg.Ports
+	}
+	groups, err := mcp.ExecuteSelectQuery(ctx, c, &ovnsb.LogicalDPGroup{})
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		for _, dp := range group.Datapaths {
+			t.groups[dp] = append(t.groups[dp], group.UUID)
+		}
+	}
+	return t, nil
+}
+
+// datapathByName returns the datapath of the logical switch or router, or
+// nil when there is none
+func (t *tracer) datapathByName(ctx context.Context, name string) (*ovnsb.DatapathBinding, error) {
+	dp := &ovnsb.DatapathBinding{}
+	datapaths, err := mcp.ExecuteSelectQuery(ctx, t.client, dp, model.Condition{
+		Field:    &dp.ExternalIDs,
+		Function: ovsdb.ConditionIncludes,
+		Value:    map[string]string{"name": name},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(datapaths) == 0 {
+		return nil, nil
+	}
+	t.datapaths[datapaths[0].UUID] = &datapaths[0]
+	return &datapaths[0], nil
+}
+
+// datapathByUUID returns the datapath with uuid, or nil when there is none
+func (t *tracer) datapathByUUID(ctx context.Context, uuid string) (*ovnsb.DatapathBinding, error) {
+	if dp, ok := t.datapaths[uuid]; ok {
+		return dp, nil
+	}
+	dp := &ovnsb.DatapathBinding{}
+	datapaths, err := mcp.ExecuteSelectQuery(ctx, t.client, dp, model.Condition{
+		Field:    &dp.UUID,
+		Function: ovsdb.ConditionEqual,
+		Value:    uuid,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(datapaths) == 0 {
+		return nil, nil
+	}
+	t.datapaths[uuid] = &datapaths[0]
+	return &datapaths[0], nil
+}
+
+// port returns the port binding of the logical port, or nil when there is
+// none
+func (t *tracer) port(ctx context.Context, name string) (*ovnsb.PortBinding, error) {
+	if pb, ok := t.ports[name]; ok {
+		return pb, nil
+	}
+	pb := &ovnsb.PortBinding{}
+	ports, err := mcp.ExecuteSelectQuery(ctx, t.client, pb, model.Condition{
+		Field:    &pb.LogicalPort,
+		Function: ovsdb.ConditionEqual,
+		Value:    name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ports) == 0 {
+		t.ports[name] = nil
+		return nil, nil
+	}
+	t.ports[name] = &ports[0]
+	return &ports[0], nil
+}
+
+// datapathFlows returns the logical flows of the datapath by pipeline and
+// table, highest priority first
+func (t *tracer) datapathFlows(ctx context.Context, dp string) (map[string]map[int][]ovnsb.LogicalFlow, error) {
+	if flows, ok := t.flows[dp]; ok {
+		return flows, nil
+	}
+
+	// Flows shared by several datapaths refer to them through a datapath
+	// group rather than the logical_datapath column
+	lf := &ovnsb.LogicalFlow{}
+	conditions := []model.Condition{{
+		Field:    &lf.LogicalDatapath,
+		Function: ovsdb.ConditionEqual,
+		Value:    &dp,
+	}}
+	for _, group := range t.groups[dp] {
+		conditions = append(conditions, model.Condition{
+			Field:    &lf.LogicalDpGroup,
+			Function: ovsdb.ConditionEqual,
+			Value:    &group,
+		})
+	}
+	results, err := mcp.ExecuteSelectQueryAny(ctx, t.client, lf, conditions...)
+	if err != nil {
+		return nil, err
+	}
+
+	flows := map[string]map[int][]ovnsb.LogicalFlow{}
+	for _, flow := range results {
+		if flows[flow.Pipeline] == nil {
+			flows[flow.Pipeline] = map[int][]ovnsb.LogicalFlow{}
+		}
+		flows[flow.Pipeline][flow.TableID] = append(flows[flow.Pipeline][flow.TableID], flow)
+	}
+	for _, tables := range flows {
+		for _, table := range tables {
+			sort.SliceStable(table, func(i, j int) bool {
+				if table[i].Priority != table[j].Priority {
+					return table[i].Priority > table[j].Priority
+				}
+				return table[i].UUID < table[j].UUID
+			})
+		}
+	}
+	t.flows[dp] = flows
+	return flows, nil
+}
+
+// datapathName is the name of the logical switch or router of dp
+func datapathName(dp *ovnsb.DatapathBinding) string {
+	if name := dp.ExternalIDs["name"]; name != "" {
+		return name
+	}
+	return dp.UUID
+}
+
+// trace walks p through the pipelines of dp, following patch ports into
+// the datapaths of their peers
+func (t *tracer) trace(ctx context.Context, dp *ovnsb.DatapathBinding, p packet) (*TraceResult, error) {
+	result := &TraceResult{Steps: []TraceStep{}}
+	pipeline, table := ovnsb.LogicalFlowPipelineIngress, 0
+
+	for len(result.Steps) < maxTraceSteps {
+		flows, err := t.datapathFlows(ctx, dp.UUID)
+		if err != nil {
+			return nil, err
+		}
+
+		var uncertain []string
+		var matched *ovnsb.LogicalFlow
+		for i, flow := range flows[pipeline][table] {
+			match, reasons := evaluateMatch(flow.Match, p, &t.sets)
+			if match == matchTrue {
+				matched = &flows[pipeline][table][i]
+				break
+			}
+			if match == matchUnknown {
+				uncertain = append(uncertain, fmt.Sprintf("%s (priority %d, %s): %s", flow.UUID, flow.Priority, strings.Join(reasons, "; "), flow.Match))
+			}
+		}
+		if matched == nil {
+			result.Verdict = verdictDrop
+			result.Reason = fmt.Sprintf("no logical flow of table %d of the %s pipeline of %s matched, so the packet is dropped", table, pipeline, datapathName(dp))
+			if len(uncertain) > 0 {
+				result.Verdict = verdictUnknown
+				result.Reason = fmt.Sprintf("no logical flow of table %d of the %s pipeline of %s is known to match, but these could not be evaluated: %s", table, pipeline, datapathName(dp), strings.Join(uncertain, ", "))
+			}
+			break
+		}
+
+		step := TraceStep{
+			Datapath:  datapathName(dp),
+			Pipeline:  pipeline,
+			Table:     table,
+			Stage:     matched.ExternalIDs["stage-name"],
+			StageHint: matched.ExternalIDs["stage-hint"],
+			Priority:  matched.Priority,
+			Match:     matched.Match,
+			Actions:   matched.Actions,
+			UUID:      matched.UUID,
+			Uncertain: uncertain,
+		}
+		next := executeActions(matched.Actions, p, pipeline, table, &step)
+		result.Steps = append(result.Steps, step)
+
+		switch next.verdict {
+		case "":
+			pipeline, table = next.pipeline, next.table
+			continue
+		case verdictOutput:
+			if pipeline == ovnsb.LogicalFlowPipelineIngress {
+				pipeline, table = ovnsb.LogicalFlowPipelineEgress, 0
+				continue
+			}
+			// A patch port delivers the packet to its peer, on another
+			// datapath
+			peer, peerDP, err := t.patchPeer(ctx, p["outport"])
+			if err != nil {
+				return nil, err
+			}
+			if peerDP != nil {
+				p = p.crossPatch(peer)
+				dp = peerDP
+				pipeline, table = ovnsb.LogicalFlowPipelineIngress, 0
+				continue
+			}
+			result.Outport = p["outport"]
+			result.Verdict = verdictOutput
+			result.Reason = fmt.Sprintf("the packet is output to %s", p["outport"])
+		default:
+			result.Verdict = next.verdict
+			result.Reason = next.reason
+		}
+		break
+	}
+	if result.Verdict == "" {
+		result.Verdict = verdictUnknown
+		result.Reason = fmt.Sprintf("the trace was stopped after %d steps, the pipeline may loop", maxTraceSteps)
+	}
+	result.Packet = p
+	return result, nil
+}
+
+// patchPeer returns the peer of the logical port and its datapath when it
+// is a patch port, or nil when it isn't
+func (t *tracer) patchPeer(ctx context.Context, name string) (string, *ovnsb.DatapathBinding, error) {
+	if name == "" {
+		return "", nil, nil
+	}
+	pb, err := t.port(ctx, name)
+	if err != nil || pb == nil || pb.Type != "patch" || pb.Options["peer"] == "" {
+		return "", nil, err
+	}
+	peer, err := t.port(ctx, pb.Options["peer"])
+	if err != nil || peer == nil {
+		return "", nil, err
+	}
+	dp, err := t.datapathByUUID(ctx, peer.Datapath)
+	if err != nil || dp == nil {
+		return "", nil, err
+	}
+	return peer.LogicalPort, dp, nil
+}
+
+// crossPatch returns the packet received by the patch port peer when p is
+// output to its patch port. Its headers are kept, but not its registers,
+// flags, connection tracking state or outport.
+func (p packet) crossPatch(peer string) packet {
+	crossed := packet{"inport": peer}
+	for k, v := range p {
+		if k == "inport" || k == "outport" || strings.HasPrefix(k, "reg") || strings.HasPrefix(k, "xreg") || strings.HasPrefix(k, "xxreg") || strings.HasPrefix(k, "flags") || strings.HasPrefix(k, "ct") {
+			continue
+		}
+		crossed[k] = v
+	}
+	return crossed
+}
+
+// set sets the field to value, or unsets it so that it is zero
+func (p packet) set(field, value string, ok bool) {
+	if ok {
+		p[field] = value
+	} else {
+		delete(p, field)
+	}
+}
+
+func hasField(p packet, field string) bool {
+	_, ok := p[field]
+	return ok
+}
+
+// actionResult is where the actions of a flow send the packet: to the
+// pipeline and table when verdict is empty
+type actionResult struct {
+	pipeline string
+	table    int
+	verdict  string
+	reason   string
+}
+
+var nextArgs = regexp.MustCompile(`^next\s*\((.*)\)$`)
+
+// ctActions send the packet through connection tracking and on to the next
+// table
+var ctActions = []string{"ct_next", "ct_lb", "ct_lb_mark", "ct_dnat", "ct_snat", "ct_dnat_in_czone", "ct_snat_in_czone"}
+
+// ignoredActions don't change where the packet goes or the fields matched
+// by later tables
+var ignoredActions = []string{"log", "sample", "ct_commit", "ct_commit_nat", "ct_mark", "ct_label", "mirror"}
+
+// executeActions applies the actions of a flow in pipeline and table to p,
+// noting those that are not simulated in step
+func executeActions(actions string, p packet, pipeline string, table int, step *TraceStep) actionResult {
+	for _, action := range splitActions(actions) {
+		name := actionName(action)
+		switch {
+		case action == "next":
+			return actionResult{pipeline: pipeline, table: table + 1}
+		case nextArgs.MatchString(action):
+			next, err := parseNext(nextArgs.FindStringSubmatch(action)[1], pipeline)
+			if err != nil {
+				step.Notes = append(step.Notes, err.Error())
+				return actionResult{verdict: verdictUnknown, reason: err.Error()}
+			}
+			return next
+		case action == "output":
+			return actionResult{verdict: verdictOutput}
+		case action == "drop":
+			return actionResult{verdict: verdictDrop, reason: fmt.Sprintf("the actions of flow %s drop the packet", step.UUID)}
+		case slices.Contains(ctActions, name):
+			if p["ct.est"] == "" {
+				p["ct.new"] = "1"
+			}
+			p["ct.trk"] = "1"
+			step.Notes = append(step.Notes, fmt.Sprintf("%s: connection tracking is not simulated, the packet is a new connection and its addresses are not translated", name))
+			return actionResult{pipeline: pipeline, table: table + 1}
+		case action == "ct_clear":
+			for k := range p {
+				if strings.HasPrefix(k, "ct") {
+					delete(p, k)
+				}
+			}
+		case slices.Contains(ignoredActions, name):
+		case strings.HasSuffix(action, "--") && !strings.ContainsAny(action, " ({"):
+			// Only ip.ttl-- decrements a field
+			field := strings.TrimSuffix(action, "--")
+			if value, err := strconv.Atoi(p[field]); err == nil && value > 0 {
+				p[field] = strconv.Itoa(value - 1)
+			}
+		case strings.Contains(action, "<->"):
+			lhs, rhs, _ := strings.Cut(action, "<->")
+			lhs, rhs = strings.TrimSpace(lhs), strings.TrimSpace(rhs)
+			lhsValue, lhsSet := p[lhs]
+			p.set(lhs, p[rhs], hasField(p, rhs))
+			p.set(rhs, lhsValue, lhsSet)
+		case isAssignment(action):
+			lhs, rhs, _ := strings.Cut(action, "=")
+			assign(p, strings.TrimSpace(lhs), strings.TrimSpace(rhs), step)
+		default:
+			step.Notes = append(step.Notes, fmt.Sprintf("%s is not simulated", name))
+			if strings.Contains(action, "{") {
+				// Actions such as arp { ... } and icmp4 { ... } replace the
+				// packet with one they generate
+				return actionResult{verdict: verdictStopped, reason: fmt.Sprintf("the %s action of flow %s generates a new packet in place of the traced one, which is not traced further", name, step.UUID)}
+			}
+		}
+	}
+	return actionResult{verdict: verdictStopped, reason: fmt.Sprintf("the actions of flow %s end without sending the packet to another table, so it is not forwarded", step.UUID)}
+}
+
+// splitActions splits actions at the semicolons that are not in braces,
+// parentheses or strings
+func splitActions(actions string) []string {
+	var statements []string
+	depth, start, quoted := 0, 0, false
+	for i := 0; i < len(actions); i++ {
+		switch c := actions[i]; {
+		case c == '"' && (i == 0 || actions[i-1] != '\\'):
+			quoted = !quoted
+		case quoted:
+		case c == '{' || c == '(':
+			depth++
+		case c == '}' || c == ')':
+			depth--
+		case c == ';' && depth == 0:
+			statements = append(statements, strings.TrimSpace(actions[start:i]))
+			start = i + 1
+		}
+	}
+	statements = append(statements, strings.TrimSpace(actions[start:]))
+
+	var nonEmpty []string
+	for _, s := range statements {
+		if s != "" {
+			nonEmpty = append(nonEmpty, s)
+		}
+	}
+	return nonEmpty
+}
+
+// actionName returns the name of the action, e.g. ct_lb_mark for
+// ct_lb_mark(backends=...)
+func actionName(action string) string {
+	end := strings.IndexAny(action, " ({=;")
+	if end < 0 {
+		return action
+	}
+	return action[:end]
+}
+
+// isAssignment reports whether action sets a field, e.g. outport = "lsp1"
+func isAssignment(action string) bool {
+	i := strings.Index(action, "=")
+	return i > 0 && !strings.ContainsAny(action[:i], "({\"") && (i+1 == len(action) || action[i+1] != '=')
+}
+
+// assign sets the field lhs of p to rhs, a constant, a string, or another
+// field. Functions, such as check_in_port_sec(), are assumed to return 0.
+func assign(p packet, lhs, rhs string, step *TraceStep) {
+	switch {
+	case strings.HasPrefix(rhs, `"`):
+		p[lhs] = strings.Trim(rhs, `"`)
+	case strings.Contains(rhs, "("):
+		delete(p, lhs)
+		step.Notes = append(step.Notes, fmt.Sprintf("%s is not simulated, %s is assumed to be 0", actionName(rhs), lhs))
+	default:
+		if _, _, err := parseConstant(rhs); err == nil {
+			p[lhs] = rhs
+			return
+		}
+		// Copy another field, which is zero when it isn't set
+		value, ok := p[rhs]
+		p.set(lhs, value, ok)
+	}
+}
+
+// parseNext parses the arguments of next(), a table or pipeline=, table=
+func parseNext(args, pipeline string) (actionResult, error) {
+	next := actionResult{pipeline: pipeline}
+	for _, arg := range strings.Split(args, ",") {
+		arg = strings.TrimSpace(arg)
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			key, value = "table", arg
+		}
+		switch strings.TrimSpace(key) {
+		case "table":
+			table, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return actionResult{}, fmt.Errorf("invalid table in next(%s)", args)
+			}
+			next.table = table
+		case "pipeline":
+			next.pipeline = strings.TrimSpace(value)
+		default:
+			return actionResult{}, fmt.Errorf("invalid argument %q in next(%s)", arg, args)
+		}
+	}
+	return next, nil
+}
@@ -0,0 +1,529 @@
+package ovnsb
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// The built-in tracer evaluates the matches of logical flows against a
+// microflow, a packet described by the values of some of its fields. As in
+// ovn-trace, fields that are not set are zero. Only part of the OVN match
+// language is understood: matches calling functions such as
+// is_chassis_resident() evaluate to unknown rather than true or false.
+
+// tristate is the result of evaluating a match
+type tristate int
+
+const (
+	matchFalse tristate = iota
+	matchTrue
+	matchUnknown
+)
+
+func boolMatch(b bool) tristate {
+	if b {
+		return matchTrue
+	}
+	return matchFalse
+}
+
+func (t tristate) and(o tristate) tristate {
+	switch {
+	case t == matchFalse || o == matchFalse:
+		return matchFalse
+	case t == matchUnknown || o == matchUnknown:
+		return matchUnknown
+	default:
+		return matchTrue
+	}
+}
+
+func (t tristate) or(o tristate) tristate {
+	switch {
+	case t == matchTrue || o == matchTrue:
+		return matchTrue
+	case t == matchUnknown || o == matchUnknown:
+		return matchUnknown
+	default:
+		return matchFalse
+	}
+}
+
+func (t tristate) not() tristate {
+	switch t {
+	case matchTrue:
+		return matchFalse
+	case matchFalse:
+		return matchTrue
+	default:
+		return matchUnknown
+	}
+}
+
+// packet is the microflow being traced, the values of its fields keyed by
+// their OVN names, e.g. ip4.src. The logical port fields inport and outport
+// hold port names.
+type packet map[string]string
+
+// stringFields hold names rather than numbers
+var stringFields = map[string]bool{"inport": true, "outport": true}
+
+// predicates are the fields that OVN defines in terms of other fields. Any
+// other field used on its own in a match is true when it isn't zero.
+var predicates = map[string]string{
+	"eth.bcast":    "eth.dst == ff:ff:ff:ff:ff:ff",
+	"eth.mcast":    "eth.dst == 01:00:00:00:00:00/01:00:00:00:00:00",
+	"eth.mcastv6":  "eth.dst == 33:33:00:00:00:00/ff:ff:00:00:00:00",
+	"vlan.present": "vlan.tci == 0x1000/0x1000",
+	"ip4":          "eth.type == 0x800",
+	"ip6":          "eth.type == 0x86dd",
+	"ip":           "ip4 || ip6",
+	"arp":          "eth.type == 0x806",
+	"rarp":         "eth.type == 0x8035",
+	"ip4.mcast":    "ip4.dst == 224.0.0.0/4",
+	"ip6.mcast":    "ip6.dst == ff00::/8",
+	"icmp4":        "ip4 && ip.proto == 1",
+	"icmp6":        "ip6 && ip.proto == 58",
+	"icmp":         "icmp4 || icmp6",
+	"tcp":          "ip && ip.proto == 6",
+	"udp":          "ip && ip.proto == 17",
+	"sctp":         "ip && ip.proto == 132",
+	"igmp":         "ip4 && ip.proto == 2",
+	"nd":           "icmp6 && icmp6.type == {135, 136} && icmp6.code == 0 && ip.ttl == 255",
+	"nd_rs":        "icmp6 && icmp6.type == 133 && icmp6.code == 0 && ip.ttl == 255",
+	"nd_ra":        "icmp6 && icmp6.type == 134 && icmp6.code == 0 && ip.ttl == 255",
+	"nd_ns":        "icmp6 && icmp6.type == 135 && icmp6.code == 0 && ip.ttl == 255",
+	"nd_na":        "icmp6 && icmp6.type == 136 && icmp6.code == 0 && ip.ttl == 255",
+}
+
+// traceSets are the address sets and port groups that matches refer to
+// with $name and @name
+type traceSets struct {
+	addressSets map[string][]string
+	portGroups  map[string][]string
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// tokenize splits an OVN match or action into words, quoted strings and
+// operators
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return nil, fmt.Errorf("unterminated string in %q", s)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: s[i+1 : end]})
+			i = end + 1
+		case i+1 < len(s) && slices.Contains([]string{"==", "!=", "<=", ">=", "&&", "||", "<-"}, s[i:i+2]):
+			if s[i:i+2] == "<-" && i+2 < len(s) && s[i+2] == '>' {
+				tokens = append(tokens, token{kind: tokenOp, text: "<->"})
+				i += 3
+				continue
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: s[i : i+2]})
+			i += 2
+		case strings.IndexByte("!<>=(){},;", c) >= 0:
+			tokens = append(tokens, token{kind: tokenOp, text: string(c)})
+			i++
+		default:
+			end := i
+			for end < len(s) && strings.IndexByte(" \t\n\"!<>=(){},;&|", s[end]) < 0 {
+				end++
+			}
+			if end == i {
+				return nil, fmt.Errorf("unexpected %q in %q", c, s)
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: s[i:end]})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// isRelation reports whether op compares a field with a value
+func isRelation(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
+
+// matchEvaluator evaluates a match against a packet
+type matchEvaluator struct {
+	tokens []token
+	pos    int
+	packet packet
+	sets   *traceSets
+	depth  int
+	// reasons are why the match could not be evaluated
+	reasons []string
+	err     error
+}
+
+// evaluateMatch evaluates match against p. When the result is unknown, the
+// reasons say which parts of the match could not be evaluated.
+func evaluateMatch(match string, p packet, sets *traceSets) (tristate, []string) {
+	return evaluateMatchDepth(match, p, sets, 0)
+}
+
+func evaluateMatchDepth(match string, p packet, sets *traceSets, depth int) (tristate, []string) {
+	tokens, err := tokenize(match)
+	if err != nil {
+		return matchUnknown, []string{err.Error()}
+	}
+	e := &matchEvaluator{tokens: tokens, packet: p, sets: sets, depth: depth}
+	result := e.parseOr()
+	if e.err == nil && e.pos < len(e.tokens) {
+		e.err = fmt.Errorf("unexpected %q", e.tokens[e.pos].text)
+	}
+	if e.err != nil {
+		return matchUnknown, append(e.reasons, fmt.Sprintf("failed to parse match: %v", e.err))
+	}
+	return result, e.reasons
+}
+
+func (e *matchEvaluator) peek() *token {
+	if e.pos < len(e.tokens) {
+		return &e.tokens[e.pos]
+	}
+	return nil
+}
+
+func (e *matchEvaluator) peekOp(op string) bool {
+	t := e.peek()
+	return t != nil && t.kind == tokenOp && t.text == op
+}
+
+func (e *matchEvaluator) expect(op string) {
+	if !e.peekOp(op) {
+		if e.err == nil {
+			e.err = fmt.Errorf("expected %q", op)
+		}
+		return
+	}
+	e.pos++
+}
+
+func (e *matchEvaluator) parseOr() tristate {
+	result := e.parseAnd()
+	for e.err == nil && e.peekOp("||") {
+		e.pos++
+		result = result.or(e.parseAnd())
+	}
+	return result
+}
+
+func (e *matchEvaluator) parseAnd() tristate {
+	result := e.parseNot()
+	for e.err == nil && e.peekOp("&&") {
+		e.pos++
+		result = result.and(e.parseNot())
+	}
+	return result
+}
+
+func (e *matchEvaluator) parseNot() tristate {
+	if e.peekOp("!") {
+		e.pos++
+		return e.parseNot().not()
+	}
+	return e.parsePrimary()
+}
+
+func (e *matchEvaluator) parsePrimary() tristate {
+	t := e.peek()
+	if t == nil {
+		e.err = fmt.Errorf("unexpected end of match")
+		return matchUnknown
+	}
+	if t.kind == tokenOp && t.text == "(" {
+		e.pos++
+		result := e.parseOr()
+		e.expect(")")
+		return result
+	}
+	if t.kind != tokenWord {
+		e.err = fmt.Errorf("unexpected %q", t.text)
+		return matchUnknown
+	}
+	name := t.text
+	e.pos++
+
+	if e.peekOp("(") {
+		e.skipBalanced("(", ")")
+		e.reasons = append(e.reasons, fmt.Sprintf("%s() is not simulated", name))
+		return matchUnknown
+	}
+	if next := e.peek(); next != nil && next.kind == tokenOp && isRelation(next.text) {
+		op := next.text
+		e.pos++
+		values, known := e.parseValues()
+		if e.err != nil || !known {
+			return matchUnknown
+		}
+		return e.relation(name, op, values)
+	}
+
+	switch name {
+	case "1":
+		return matchTrue
+	case "0":
+		return matchFalse
+	}
+	if definition, ok := predicates[name]; ok && e.depth < 8 {
+		result, reasons := evaluateMatchDepth(definition, e.packet, e.sets, e.depth+1)
+		e.reasons = append(e.reasons, reasons...)
+		return result
+	}
+	value, err := e.fieldValue(name)
+	if err != nil {
+		e.reasons = append(e.reasons, err.Error())
+		return matchUnknown
+	}
+	return boolMatch(value.Sign() != 0)
+}
+
+// skipBalanced skips from the open token to its matching close token
+func (e *matchEvaluator) skipBalanced(open, close string) {
+	depth := 0
+	for ; e.pos < len(e.tokens); e.pos++ {
+		t := e.tokens[e.pos]
+		if t.kind != tokenOp {
+			continue
+		}
+		switch t.text {
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				e.pos++
+				return
+			}
+		}
+	}
+	e.err = fmt.Errorf("expected %q", close)
+}
+
+// matchValue is a constant a field is compared with
+type matchValue struct {
+	text   string
+	quoted bool
+}
+
+// parseValues parses the value or set of values on the right of a
+// relation, expanding address sets and port groups. known is false when a
+// set does not exist.
+func (e *matchEvaluator) parseValues() (values []matchValue, known bool) {
+	var tokens []token
+	if e.peekOp("{") {
+		e.pos++
+		for e.err == nil && !e.peekOp("}") {
+			t := e.peek()
+			if t == nil {
+				e.err = fmt.Errorf("expected %q", "}")
+				return nil, false
+			}
+			if t.kind != tokenOp {
+				tokens = append(tokens, *t)
+			} else if t.text != "," {
+				e.err = fmt.Errorf("unexpected %q in set", t.text)
+				return nil, false
+			}
+			e.pos++
+		}
+		e.expect("}")
+	} else {
+		t := e.peek()
+		if t == nil || t.kind == tokenOp {
+			e.err = fmt.Errorf("expected a value")
+			return nil, false
+		}
+		tokens = append(tokens, *t)
+		e.pos++
+	}
+
+	for _, t := range tokens {
+		switch {
+		case t.kind == tokenString:
+			values = append(values, matchValue{text: t.text, quoted: true})
+		case strings.HasPrefix(t.text, "$"):
+			addresses, ok := e.sets.addressSets[t.text[1:]]
+			if !ok {
+				e.reasons = append(e.reasons, fmt.Sprintf("address set %s does not exist", t.text))
+				return nil, false
+			}
+			for _, address := range addresses {
+				values = append(values, matchValue{text: address})
+			}
+		case strings.HasPrefix(t.text, "@"):
+			ports, ok := e.sets.portGroups[t.text[1:]]
+			if !ok {
+				e.reasons = append(e.reasons, fmt.Sprintf("port group %s does not exist", t.text))
+				return nil, false
+			}
+			for _, port := range ports {
+				values = append(values, matchValue{text: port, quoted: true})
+			}
+		default:
+			values = append(values, matchValue{text: t.text})
+		}
+	}
+	return values, true
+}
+
+// relation compares field with values, == is true when it equals any of
+// them and != when it equals none
+func (e *matchEvaluator) relation(field, op string, values []matchValue) tristate {
+	result := boolMatch(op == "!=")
+	if op != "==" && op != "!=" && len(values) != 1 {
+		e.reasons = append(e.reasons, fmt.Sprintf("%s %s needs a single value", field, op))
+		return matchUnknown
+	}
+	for _, v := range values {
+		r := e.compare(field, op, v)
+		if op == "!=" {
+			result = result.and(r)
+		} else {
+			result = result.or(r)
+		}
+	}
+	return result
+}
+
+func (e *matchEvaluator) compare(field, op string, v matchValue) tristate {
+	if v.quoted || stringFields[field] {
+		if op != "==" && op != "!=" {
+			e.reasons = append(e.reasons, fmt.Sprintf("%s can only be compared with == or !=", field))
+			return matchUnknown
+		}
+		return boolMatch((e.packet[field] == v.text) == (op == "=="))
+	}
+
+	want, mask, err := parseConstant(v.text)
+	if err != nil {
+		e.reasons = append(e.reasons, err.Error())
+		return matchUnknown
+	}
+	got, err := e.fieldValue(field)
+	if err != nil {
+		e.reasons = append(e.reasons, err.Error())
+		return matchUnknown
+	}
+	if mask != nil {
+		got = new(big.Int).And(got, mask)
+		want = new(big.Int).And(want, mask)
+	}
+
+	cmp := got.Cmp(want)
+	switch op {
+	case "==":
+		return boolMatch(cmp == 0)
+	case "!=":
+		return boolMatch(cmp != 0)
+	case "<":
+		return boolMatch(cmp < 0)
+	case "<=":
+		return boolMatch(cmp <= 0)
+	case ">":
+		return boolMatch(cmp > 0)
+	default:
+		return boolMatch(cmp >= 0)
+	}
+}
+
+// fieldValue returns the value of a numeric field, zero when it isn't set
+func (e *matchEvaluator) fieldValue(field string) (*big.Int, error) {
+	if stringFields[field] {
+		return boolInt(e.packet[field] != ""), nil
+	}
+	s, ok := e.packet[field]
+	if !ok {
+		return new(big.Int), nil
+	}
+	value, _, err := parseConstant(s)
+	if err != nil {
+		return nil, fmt.Errorf("field %s: %w", field, err)
+	}
+	return value, nil
+}
+
+func boolInt(b bool) *big.Int {
+	if b {
+		return big.NewInt(1)
+	}
+	return new(big.Int)
+}
+
+// parseConstant parses an integer, IP or MAC address, with an optional mask
+// after a slash, either a prefix length or a value in the same format
+func parseConstant(s string) (value, mask *big.Int, err error) {
+	valueText, maskText, hasMask := strings.Cut(s, "/")
+	value, width, err := parseValue(valueText)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !hasMask {
+		return value, nil, nil
+	}
+	if prefix, err := strconv.Atoi(maskText); err == nil && width > 0 {
+		if prefix < 0 || prefix > width {
+			return nil, nil, fmt.Errorf("invalid prefix length in %q", s)
+		}
+		ones := new(big.Int).Lsh(big.NewInt(1), uint(prefix))
+		ones.Sub(ones, big.NewInt(1))
+		return value, ones.Lsh(ones, uint(width-prefix)), nil
+	}
+	mask, _, err = parseValue(maskText)
+	if err != nil {
+		return nil, nil, err
+	}
+	return value, mask, nil
+}
+
+// parseValue parses an integer, IP or MAC address, returning the width of
+// addresses in bits, or zero for integers
+func parseValue(s string) (*big.Int, int, error) {
+	if ip := net.ParseIP(s); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			return new(big.Int).SetBytes(ip4), 32, nil
+		}
+		return new(big.Int).SetBytes(ip), 128, nil
+	}
+	if mac, err := net.ParseMAC(s); err == nil && len(mac) == 6 {
+		return new(big.Int).SetBytes(mac), 48, nil
+	}
+	if value, ok := new(big.Int).SetString(s, 0); ok {
+		return value, 0, nil
+	}
+	return nil, 0, fmt.Errorf("%q is not a number or address", s)
+}
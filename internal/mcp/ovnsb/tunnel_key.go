@@ -0,0 +1,222 @@
+package ovnsb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type ResolveTunnelKeyArgs struct {
+	Key         string `json:"key" jsonschema:"the tunnel key to resolve, in decimal or hex, e.g. 5 or 0x5 as in metadata=0x5"`
+	Scope       string `json:"scope,omitempty" jsonschema:"what the key identifies: datapath (the metadata field of a flow) or port (the inport and outport registers, reg14 and reg15), both when not set"`
+	DatapathKey string `json:"datapath_key,omitempty" jsonschema:"only resolve port keys on the datapath with this tunnel key, as port keys are only unique within a datapath"`
+}
+
+// Scopes of a tunnel key
+const (
+	tunnelKeyDatapath = "datapath"
+	tunnelKeyPort     = "port"
+)
+
+// TunnelKeyDatapath is a datapath binding with the tunnel key being resolved
+type TunnelKeyDatapath struct {
+	UUID      string `json:"uuid"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	TunnelKey int    `json:"tunnel_key"`
+}
+
+// TunnelKeyPort is a port binding with the tunnel key being resolved
+type TunnelKeyPort struct {
+	UUID              string `json:"uuid"`
+	LogicalPort       string `json:"logical_port"`
+	Type              string `json:"type"`
+	TunnelKey         int    `json:"tunnel_key"`
+	Datapath          string `json:"datapath"`
+	DatapathTunnelKey int    `json:"datapath_tunnel_key"`
+}
+
+// TunnelKeyMulticastGroup is a multicast group with the tunnel key being
+// resolved, which outport holds when a packet is flooded or multicast
+type TunnelKeyMulticastGroup struct {
+	UUID              string `json:"uuid"`
+	Name              string `json:"name"`
+	TunnelKey         int    `json:"tunnel_key"`
+	Datapath          string `json:"datapath"`
+	DatapathTunnelKey int    `json:"datapath_tunnel_key"`
+}
+
+// parseTunnelKey parses a decimal or 0x prefixed hex tunnel key
+func parseTunnelKey(name, key string) (int, error) {
+	n, err := strconv.ParseInt(key, 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q, must be a decimal or hex number", name, key)
+	}
+	return int(n), nil
+}
+
+// datapathType returns whether a datapath implements a logical switch or
+// router, from the external ID northd sets to the northbound row's UUID
+func datapathType(dp ovnsb.DatapathBinding) string {
+	switch {
+	case dp.ExternalIDs["logical-switch"] != "":
+		return "switch"
+	case dp.ExternalIDs["logical-router"] != "":
+		return "router"
+	}
+	return ""
+}
+
+func (s *Server) ResolveTunnelKey(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ResolveTunnelKeyArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	if args.Key == "" {
+		return nil, fmt.Errorf("key must not be empty")
+	}
+	key, err := parseTunnelKey("key", args.Key)
+	if err != nil {
+		return nil, err
+	}
+	switch args.Scope {
+	case "", tunnelKeyDatapath, tunnelKeyPort:
+	default:
+		return nil, fmt.Errorf("invalid scope %q, must be %s or %s", args.Scope, tunnelKeyDatapath, tunnelKeyPort)
+	}
+	datapathKey := -1
+	if args.DatapathKey != "" {
+		if args.Scope == tunnelKeyDatapath {
+			return nil, fmt.Errorf("datapath_key can only be used with the %s scope", tunnelKeyPort)
+		}
+		if datapathKey, err = parseTunnelKey("datapath_key", args.DatapathKey); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	// Every datapath is needed to name the datapaths of the ports
+	datapaths, err := mcp.ExecuteSelectQuery(ctx, client, &ovnsb.DatapathBinding{})
+	if err != nil {
+		return nil, err
+	}
+	datapathByUUID := make(map[string]ovnsb.DatapathBinding, len(datapaths))
+	for _, dp := range datapaths {
+		datapathByUUID[dp.UUID] = dp
+	}
+
+	result := map[string]interface{}{
+		"key": key,
+		"hex": fmt.Sprintf("0x%x", key),
+	}
+	found := 0
+
+	if args.Scope != tunnelKeyPort {
+		matches := []TunnelKeyDatapath{}
+		for _, dp := range datapaths {
+			if dp.TunnelKey == key {
+				matches = append(matches, TunnelKeyDatapath{
+					UUID:      dp.UUID,
+					Name:      dp.ExternalIDs["name"],
+					Type:      datapathType(dp),
+					TunnelKey: dp.TunnelKey,
+				})
+			}
+		}
+		result["datapaths"] = matches
+		found += len(matches)
+	}
+
+	if args.Scope != tunnelKeyDatapath {
+		portBinding := &ovnsb.PortBinding{}
+		bindings, err := mcp.ExecuteSelectQuery(ctx, client, portBinding, model.Condition{
+			Field:    &portBinding.TunnelKey,
+			Function: ovsdb.ConditionEqual,
+			Value:    key,
+		})
+		if err != nil {
+			return nil, err
+		}
+		matches := []TunnelKeyPort{}
+		for _, pb := range bindings {
+			dp := datapathByUUID[pb.Datapath]
+			if datapathKey >= 0 && dp.TunnelKey != datapathKey {
+				continue
+			}
+			matches = append(matches, TunnelKeyPort{
+				UUID:              pb.UUID,
+				LogicalPort:       pb.LogicalPort,
+				Type:              pb.Type,
+				TunnelKey:         pb.TunnelKey,
+				Datapath:          dp.ExternalIDs["name"],
+				DatapathTunnelKey: dp.TunnelKey,
+			})
+		}
+		sort.Slice(matches, func(i, j int) bool {
+			if matches[i].DatapathTunnelKey != matches[j].DatapathTunnelKey {
+				return matches[i].DatapathTunnelKey < matches[j].DatapathTunnelKey
+			}
+			return matches[i].LogicalPort < matches[j].LogicalPort
+		})
+		result["ports"] = matches
+		found += len(matches)
+
+		multicastGroup := &ovnsb.MulticastGroup{}
+		groups, err := mcp.ExecuteSelectQuery(ctx, client, multicastGroup, model.Condition{
+			Field:    &multicastGroup.TunnelKey,
+			Function: ovsdb.ConditionEqual,
+			Value:    key,
+		})
+		if err != nil {
+			return nil, err
+		}
+		groupMatches := []TunnelKeyMulticastGroup{}
+		for _, mg := range groups {
+			dp := datapathByUUID[mg.Datapath]
+			if datapathKey >= 0 && dp.TunnelKey != datapathKey {
+				continue
+			}
+			groupMatches = append(groupMatches, TunnelKeyMulticastGroup{
+				UUID:              mg.UUID,
+				Name:              mg.Name,
+				TunnelKey:         mg.TunnelKey,
+				Datapath:          dp.ExternalIDs["name"],
+				DatapathTunnelKey: dp.TunnelKey,
+			})
+		}
+		sort.Slice(groupMatches, func(i, j int) bool {
+			if groupMatches[i].DatapathTunnelKey != groupMatches[j].DatapathTunnelKey {
+				return groupMatches[i].DatapathTunnelKey < groupMatches[j].DatapathTunnelKey
+			}
+			return groupMatches[i].Name < groupMatches[j].Name
+		})
+		result["multicast_groups"] = groupMatches
+		found += len(groupMatches)
+	}
+
+	if found == 0 {
+		return &mcpsdk.CallToolResultFor[map[string]any]{
+			IsError: true,
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: fmt.Sprintf("No datapath, port or multicast group found with tunnel key %d (0x%x)", key, key),
+				},
+			},
+		}, nil
+	}
+
+	result["count"] = found
+	result["context"] = "Tunnel keys identify datapaths and ports in logical flows and on the wire. A flow's metadata field is the tunnel key of its datapath, and the inport and outport registers (reg14 and reg15) hold port tunnel keys. Datapath keys are unique, but port keys are only unique within their datapath, so the same port key can resolve to a port on each datapath; pass datapath_key with the flow's metadata to narrow it down. Port keys of 32768 and above belong to multicast groups, such as _MC_flood, rather than ports."
+
+	return mcp.NewResult(result)
+}
@@ -0,0 +1,37 @@
+package mcp
+
+// DefaultLimit is the number of results a list tool returns when no limit
+// is given
+const DefaultLimit = 100
+
+// Page describes where a page of results sits in the full result set
+type Page struct {
+	// Total is the number of results matched before pagination
+	Total int `json:"total"`
+	// NextOffset is the offset of the next page, or nil on the last page
+	NextOffset *int `json:"next_offset"`
+}
+
+// Paginate returns at most limit results starting at offset. A limit of
+// zero or less uses DefaultLimit and a negative offset starts at the first
+// result. OVSDB has no server-side paging, so this is applied after the
+// select and any filtering done in Go.
+func Paginate[T any](results []T, limit, offset int) ([]T, Page) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	page := Page{Total: len(results)}
+	if offset >= len(results) {
+		return []T{}, page
+	}
+	end := offset + limit
+	if end < len(results) {
+		page.NextOffset = &end
+	} else {
+		end = len(results)
+	}
+	return results[offset:end], page
+}
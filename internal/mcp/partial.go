@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"fmt"
+)
+
+// PartFailure is a part of a composite tool's result that could not be
+// gathered
+type PartFailure struct {
+	Part  string `json:"part"`
+	Error string `json:"error"`
+}
+
+// Parts gathers the independent parts of a composite tool's result, e.g.
+// the NAT rules and load balancers of a router. A part that fails, for
+// example because its table is not in the database's schema version, is
+// recorded rather than failing the whole tool, so the caller still gets
+// the rest of the picture.
+type Parts struct {
+	gathered int
+	failed   []PartFailure
+	err      error
+}
+
+// Gather runs fn to gather part, recording its error if it fails. It
+// returns whether the part was gathered.
+func (p *Parts) Gather(part string, fn func() error) bool {
+	p.gathered++
+	err := fn()
+	if err == nil {
+		return true
+	}
+	if p.err == nil {
+		p.err = fmt.Errorf("failed to gather %s: %w", part, err)
+	}
+	p.failed = append(p.failed, PartFailure{Part: part, Error: err.Error()})
+	return false
+}
+
+// Err returns the first failure when every part failed, as there is then
+// nothing to return
+func (p *Parts) Err() error {
+	if p.gathered > 0 && len(p.failed) == p.gathered {
+		return p.err
+	}
+	return nil
+}
+
+// Apply sets partial in result, and failed_parts to the parts that failed
+// and why when it is true
+func (p *Parts) Apply(result map[string]any) {
+	result["partial"] = len(p.failed) > 0
+	if len(p.failed) > 0 {
+		result["failed_parts"] = p.failed
+	}
+}
@@ -0,0 +1,178 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+// Ref is a resolved reference to a row of another table. Name is empty when
+// the referenced table has no name column or the row no longer exists.
+type Ref struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name,omitempty"`
+}
+
+// refColumn is a column that references another table
+type refColumn struct {
+	table string
+	// many is set for columns that can hold more than one reference
+	many bool
+}
+
+// refColumns returns the columns of tableName that reference other tables,
+// keyed by column name
+func refColumns(schema ovsdb.DatabaseSchema, tableName string) map[string]refColumn {
+	columns := make(map[string]refColumn)
+	table := schema.Table(tableName)
+	if table == nil {
+		return columns
+	}
+	for name, column := range table.Columns {
+		if column.TypeObj == nil {
+			continue
+		}
+		for _, t := range []*ovsdb.BaseType{column.TypeObj.Key, column.TypeObj.Value} {
+			if t == nil || t.Type != ovsdb.TypeUUID {
+				continue
+			}
+			if refTable, err := t.RefTable(); err == nil && refTable != "" {
+				columns[name] = refColumn{
+					table: refTable,
+					many:  column.TypeObj.Max() != 1,
+				}
+			}
+		}
+	}
+	return columns
+}
+
+// walkRefs calls fn for every UUID in an OVSDB notation value
+func walkRefs(value any, fn func(string)) {
+	switch v := value.(type) {
+	case ovsdb.UUID:
+		fn(v.GoUUID)
+	case ovsdb.OvsSet:
+		for _, e := range v.GoSet {
+			walkRefs(e, fn)
+		}
+	case ovsdb.OvsMap:
+		for k, e := range v.GoMap {
+			walkRefs(k, fn)
+			walkRefs(e, fn)
+		}
+	}
+}
+
+// replaceRefs replaces the UUIDs in an OVSDB notation value with Refs. Sets
+// become lists and maps become objects, as Refs are not valid OVSDB atoms.
+func replaceRefs(value any, names map[string]string) any {
+	switch v := value.(type) {
+	case ovsdb.UUID:
+		return Ref{UUID: v.GoUUID, Name: names[v.GoUUID]}
+	case ovsdb.OvsSet:
+		refs := make([]any, 0, len(v.GoSet))
+		for _, e := range v.GoSet {
+			refs = append(refs, replaceRefs(e, names))
+		}
+		return refs
+	case ovsdb.OvsMap:
+		m := make(map[string]any, len(v.GoMap))
+		for k, e := range v.GoMap {
+			key := fmt.Sprint(k)
+			if u, ok := k.(ovsdb.UUID); ok {
+				key = u.GoUUID
+			}
+			m[key] = replaceRefs(e, names)
+		}
+		return m
+	}
+	return value
+}
+
+// ResolveRefs replaces the UUID references in rows of tableName, as returned
+// by MapRows, with a Ref holding the UUID and name of the referenced row. The
+// referenced rows are looked up in a single transaction.
+func ResolveRefs(ctx context.Context, c client.Client, schema ovsdb.DatabaseSchema, tableName string, rows []map[string]any) error {
+	columns := refColumns(schema, tableName)
+	if len(columns) == 0 || len(rows) == 0 {
+		return nil
+	}
+
+	uuids := make(map[string]map[string]bool)
+	for _, row := range rows {
+		for name, column := range columns {
+			walkRefs(row[name], func(u string) {
+				if uuids[column.table] == nil {
+					uuids[column.table] = make(map[string]bool)
+				}
+				uuids[column.table][u] = true
+			})
+		}
+	}
+
+	refTables := make([]string, 0, len(uuids))
+	for refTable := range uuids {
+		refTables = append(refTables, refTable)
+	}
+	sort.Strings(refTables)
+
+	var ops []ovsdb.Operation
+	for _, refTable := range refTables {
+		selectColumns := []string{"_uuid"}
+		if t := schema.Table(refTable); t != nil && t.Column("name") != nil {
+			selectColumns = append(selectColumns, "name")
+		}
+		for u := range uuids[refTable] {
+			ops = append(ops, ovsdb.Operation{
+				Op:      ovsdb.OperationSelect,
+				Table:   refTable,
+				Where:   []ovsdb.Condition{ovsdb.NewCondition("_uuid", ovsdb.ConditionEqual, ovsdb.UUID{GoUUID: u})},
+				Columns: selectColumns,
+			})
+		}
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+
+	reply, err := Transact(ctx, c, ops...)
+	if err != nil {
+		return fmt.Errorf("failed to execute transaction: %w", err)
+	}
+	if _, err := ovsdb.CheckOperationResults(reply, ops); err != nil {
+		return fmt.Errorf("failed to resolve references: %w", err)
+	}
+
+	names := make(map[string]string)
+	for _, result := range reply {
+		for _, row := range result.Rows {
+			u, ok := row["_uuid"].(ovsdb.UUID)
+			if !ok {
+				continue
+			}
+			if name, ok := row["name"].(string); ok {
+				names[u.GoUUID] = name
+			}
+		}
+	}
+
+	for _, row := range rows {
+		for name, column := range columns {
+			value, ok := row[name]
+			if !ok {
+				continue
+			}
+			// Sets of one element are encoded as a bare UUID, but are
+			// returned as a list so the shape doesn't depend on the size
+			if u, ok := value.(ovsdb.UUID); ok && column.many {
+				value = ovsdb.OvsSet{GoSet: []any{u}}
+			}
+			row[name] = replaceRefs(value, names)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/mapper"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+// ListResult is the result of the list tools. Data holds the rows keyed by
+// what they are, e.g. "logical_switches", and is nil when only the rows were
+// counted.
+type ListResult struct {
+	Data       map[string]any `json:"data,omitempty"`
+	Count      int            `json:"count"`
+	Total      int            `json:"total,omitempty"`
+	NextOffset *int           `json:"next_offset,omitempty"`
+	// Warnings name the rows that were skipped because they could not be
+	// decoded
+	Warnings []string `json:"warnings,omitempty"`
+	Context  string   `json:"context"`
+}
+
+// resultRows returns the number of rows in a tool's result, from the count
+// of list results and of the results with a count key
+func resultRows(result any) (int, bool) {
+	switch r := result.(type) {
+	case ListResult:
+		return r.Count, true
+	case map[string]any:
+		count, ok := r["count"].(int)
+		return count, ok
+	}
+	return 0, false
+}
+
+// Summary describes the result in a line of text
+func (r ListResult) Summary() string {
+	if r.Data == nil {
+		return fmt.Sprintf("Counted %d rows.", r.Count)
+	}
+	keys := make([]string, 0, len(r.Data))
+	for k := range r.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	summary := fmt.Sprintf("Returned %d %s", r.Count, strings.Join(keys, ", "))
+	if r.Total > r.Count {
+		summary += fmt.Sprintf(" of %d", r.Total)
+	}
+	if r.NextOffset != nil {
+		summary += fmt.Sprintf(", pass offset %d for the next page", *r.NextOffset)
+	}
+	summary += "."
+	if len(r.Warnings) > 0 {
+		summary += fmt.Sprintf(" Skipped %d rows that could not be decoded, see warnings.", len(r.Warnings))
+	}
+	return summary
+}
+
+// NewResult returns a tool result with v as its structured content. The JSON
+// encoding of v is also returned as text for clients that don't read
+// structured content, followed by a summary if v has one.
+func NewResult[T any](v T) (*mcpsdk.CallToolResultFor[T], error) {
+	text, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	content := []mcpsdk.Content{
+		&mcpsdk.TextContent{
+			Text: string(text),
+		},
+	}
+	if s, ok := any(v).(interface{ Summary() string }); ok {
+		content = append(content, &mcpsdk.TextContent{Text: s.Summary()})
+	}
+	return &mcpsdk.CallToolResultFor[T]{
+		Content:           content,
+		StructuredContent: v,
+	}, nil
+}
+
+// MapRows converts the models in results to rows of tableName in OVSDB
+// notation, so that sets, maps and UUIDs are encoded the same way by every
+// tool
+func MapRows[T any](tableName string, schema ovsdb.DatabaseSchema, results []T) ([]map[string]any, error) {
+	m := mapper.NewMapper(schema)
+	tableSchema := schema.Table(tableName)
+
+	rows := make([]map[string]any, 0, len(results))
+	for i := range results {
+		info, err := mapper.NewInfo(tableName, tableSchema, &results[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to create info: %w", err)
+		}
+		row, err := m.NewRow(info)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create row: %w", err)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
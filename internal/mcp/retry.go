@@ -0,0 +1,166 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+// ErrorClass is whether an error is worth retrying
+type ErrorClass string
+
+const (
+	// ErrorTransient errors are caused by the connection or the state of
+	// the server and may succeed when retried
+	ErrorTransient ErrorClass = "transient"
+	// ErrorPermanent errors are caused by the request itself, e.g. a
+	// constraint violation or a syntax error, and fail again when retried
+	ErrorPermanent ErrorClass = "permanent"
+)
+
+// Retry attempts and the delay before the first retry, which doubles with
+// each attempt
+const (
+	retryAttempts = 3
+	retryBackoff  = 100 * time.Millisecond
+)
+
+// transientMessages are error messages of transient errors that are not
+// returned as a typed error, e.g. from clustered ovsdb-servers during a
+// leader election
+var transientMessages = []string{
+	"not leader",
+	"leader change",
+	"connection is shut down",
+}
+
+// ClassifyError returns whether err is transient or permanent. Errors that
+// are not known to be transient are permanent, so they are never retried.
+func ClassifyError(err error) ErrorClass {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return ErrorPermanent
+	}
+	if errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, client.ErrNotConnected) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, syscall.ENOENT) {
+		return ErrorTransient
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorTransient
+	}
+	// OVSDB operation errors are permanent, apart from a wait operation
+	// timing out
+	var timedOut *ovsdb.TimedOut
+	if errors.As(err, &timedOut) {
+		return ErrorTransient
+	}
+	msg := err.Error()
+	for _, m := range transientMessages {
+		if strings.Contains(msg, m) {
+			return ErrorTransient
+		}
+	}
+	return ErrorPermanent
+}
+
+type loggerKey struct{}
+
+// contextWithLogger returns a context carrying logger, for logging from
+// helpers that are not called with a server
+func contextWithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+func loggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// Retry calls fn until it succeeds, returns a permanent error or the
+// attempts run out. op names the operation in the log messages.
+func Retry(ctx context.Context, op string, fn func() error) error {
+	logger := loggerFromContext(ctx)
+	backoff := retryBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		class := ClassifyError(err)
+		if class == ErrorPermanent {
+			logger.Debug("Not retrying permanent error", "op", op, "attempt", attempt, "class", class, "error", err)
+			return err
+		}
+		if attempt == retryAttempts {
+			logger.Warn("Giving up after transient errors", "op", op, "attempts", attempt, "class", class, "error", err)
+			return err
+		}
+		logger.Info("Retrying after transient error", "op", op, "attempt", attempt, "class", class, "backoff", backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// reconnect connects c again if its connection was lost
+func reconnect(ctx context.Context, c client.Client) error {
+	if c.Connected() {
+		return nil
+	}
+	return c.Connect(ctx)
+}
+
+// Transact executes a transaction that only reads from the database,
+// reconnecting the client and retrying on transient errors
+func Transact(ctx context.Context, c client.Client, ops ...ovsdb.Operation) ([]ovsdb.OperationResult, error) {
+	var reply []ovsdb.OperationResult
+	err := Retry(ctx, "transact", func() error {
+		if err := reconnect(ctx, c); err != nil {
+			return err
+		}
+		var err error
+		reply, err = c.Transact(ctx, ops...)
+		return err
+	})
+	return reply, err
+}
+
+// TransactWrite executes a transaction that modifies the database. The
+// client is reconnected first if its connection was lost, but the
+// transaction is not retried once sent, as one that failed in flight may
+// still have been committed.
+// During a dry run the operations are recorded as the tool's result and
+// errDryRun is returned instead.
+func TransactWrite(ctx context.Context, c client.Client, ops ...ovsdb.Operation) ([]ovsdb.OperationResult, error) {
+	if planTransaction(ctx, c.Schema().Name, ops) {
+		return nil, errDryRun
+	}
+	if err := Retry(ctx, "reconnect", func() error { return reconnect(ctx, c) }); err != nil {
+		return nil, err
+	}
+	reply, err := c.Transact(ctx, ops...)
+	if err != nil {
+		loggerFromContext(ctx).Debug("Write transaction failed", "class", ClassifyError(err), "error", err)
+	}
+	return reply, err
+}
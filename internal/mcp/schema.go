@@ -0,0 +1,192 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type GetSchemaArgs struct {
+	Table string `json:"table,omitempty" jsonschema:"only describe this table, e.g. Logical_Switch"`
+}
+
+// Kinds of column
+const (
+	columnAtomic   = "atomic"
+	columnOptional = "optional"
+	columnSet      = "set"
+	columnMap      = "map"
+)
+
+// ColumnInfo describes a column of a table. Type is the type of the column's
+// values, or of its keys for a map.
+type ColumnInfo struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Type      string `json:"type"`
+	ValueType string `json:"value_type,omitempty"`
+	// Max is -1 for sets that are unlimited
+	Min      int    `json:"min"`
+	Max      int    `json:"max"`
+	RefTable string `json:"ref_table,omitempty"`
+	RefType  string `json:"ref_type,omitempty"`
+	Enum     []any  `json:"enum,omitempty"`
+	Mutable  bool   `json:"mutable"`
+}
+
+// TableInfo describes a table of a database
+type TableInfo struct {
+	Name    string       `json:"name"`
+	Root    bool         `json:"root"`
+	Indexes [][]string   `json:"indexes,omitempty"`
+	Columns []ColumnInfo `json:"columns"`
+}
+
+// columnInfo describes a column from its schema
+func columnInfo(name string, column *ovsdb.ColumnSchema) ColumnInfo {
+	info := ColumnInfo{
+		Name:    name,
+		Kind:    columnAtomic,
+		Type:    column.Type,
+		Min:     1,
+		Max:     1,
+		Mutable: column.Mutable(),
+	}
+	if column.TypeObj == nil {
+		return info
+	}
+	typ := column.TypeObj
+	info.Type = typ.Key.Type
+	info.Min = typ.Min()
+	info.Max = typ.Max()
+	info.Enum = typ.Key.Enum
+	switch {
+	case typ.Value != nil:
+		info.Kind = columnMap
+		info.ValueType = typ.Value.Type
+	case info.Min == 0 && info.Max == 1:
+		info.Kind = columnOptional
+	case info.Max != 1:
+		info.Kind = columnSet
+	}
+	// A map's references are usually in its values, e.g. a map of
+	// priorities to rows
+	for _, t := range []*ovsdb.BaseType{typ.Key, typ.Value} {
+		if t == nil || t.Type != ovsdb.TypeUUID {
+			continue
+		}
+		if refTable, err := t.RefTable(); err == nil && refTable != "" {
+			info.RefTable = refTable
+			info.RefType, _ = t.RefType()
+		}
+	}
+	return info
+}
+
+// GetSchema returns a tool handler describing the tables and columns of
+// schema. The schema is compiled in, so no connection is made.
+func GetSchema(schema ovsdb.DatabaseSchema) mcpsdk.ToolHandlerFor[GetSchemaArgs, map[string]any] {
+	return func(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[GetSchemaArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+		args := params.Arguments
+
+		names := make([]string, 0, len(schema.Tables))
+		for name := range schema.Tables {
+			if args.Table == "" || name == args.Table {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			return &mcpsdk.CallToolResultFor[map[string]any]{
+				IsError: true,
+				Content: []mcpsdk.Content{
+					&mcpsdk.TextContent{
+						Text: fmt.Sprintf("No table found with name %s in the %s schema", args.Table, schema.Name),
+					},
+				},
+			}, nil
+		}
+		sort.Strings(names)
+
+		tables := make([]TableInfo, 0, len(names))
+		for _, name := range names {
+			table := schema.Tables[name]
+			info := TableInfo{
+				Name:    name,
+				Root:    table.IsRoot,
+				Indexes: table.Indexes,
+				Columns: make([]ColumnInfo, 0, len(table.Columns)),
+			}
+			for column, columnSchema := range table.Columns {
+				info.Columns = append(info.Columns, columnInfo(column, columnSchema))
+			}
+			sort.Slice(info.Columns, func(i, j int) bool { return info.Columns[i].Name < info.Columns[j].Name })
+			tables = append(tables, info)
+		}
+
+		return NewResult(map[string]interface{}{
+			"database":    schema.Name,
+			"version":     schema.Version,
+			"tables":      tables,
+			"table_count": len(tables),
+			"context":     "The tables of the database schema this server was built with, which may differ slightly from the version the database is running. Each column's kind is atomic (exactly one value), optional (zero or one value), set (min to max values, max -1 meaning unlimited) or map (type keys to value_type values). Columns of type uuid with a ref_table reference rows of that table; weak references are removed when the row is deleted, and rows of non-root tables are deleted when no strong reference to them remains. enum lists the values allowed. Every row also has a _uuid column. Indexes are the sets of columns whose values must be unique.",
+		})
+	}
+}
+
+type DescribeSchemaArgs struct{}
+
+// compactType describes a column's type in a few words, e.g. "set of uuid
+// -> Logical_Switch_Port" or "map of string to string"
+func compactType(info ColumnInfo) string {
+	var typ string
+	switch info.Kind {
+	case columnOptional:
+		typ = "optional " + info.Type
+	case columnSet:
+		typ = "set of " + info.Type
+	case columnMap:
+		typ = "map of " + info.Type + " to " + info.ValueType
+	default:
+		typ = info.Type
+	}
+	if info.RefTable != "" {
+		typ += " -> " + info.RefTable
+	}
+	if len(info.Enum) > 0 {
+		values := make([]string, 0, len(info.Enum))
+		for _, v := range info.Enum {
+			values = append(values, fmt.Sprint(v))
+		}
+		sort.Strings(values)
+		typ += " (one of " + strings.Join(values, ", ") + ")"
+	}
+	return typ
+}
+
+// DescribeSchema returns a tool handler listing the tables of schema with
+// the type of each of their columns, as a compact overview of what can be
+// queried. The schema is compiled in, so no connection is made.
+func DescribeSchema(schema ovsdb.DatabaseSchema) mcpsdk.ToolHandlerFor[DescribeSchemaArgs, map[string]any] {
+	return func(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[DescribeSchemaArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+		tables := make(map[string]map[string]string, len(schema.Tables))
+		for name, table := range schema.Tables {
+			columns := make(map[string]string, len(table.Columns))
+			for column, columnSchema := range table.Columns {
+				columns[column] = compactType(columnInfo(column, columnSchema))
+			}
+			tables[name] = columns
+		}
+
+		return NewResult(map[string]interface{}{
+			"database":    schema.Name,
+			"version":     schema.Version,
+			"tables":      tables,
+			"table_count": len(tables),
+			"context":     "The tables of the database schema this server was built with, keyed by table, with the type of each column. Columns are a single value unless they are optional, a set or a map; -> names the table a uuid column references, and enums list the values allowed. Every row also has a _uuid column. Use get_schema for the details of a table, such as set sizes, weak references and indexes.",
+		})
+	}
+}
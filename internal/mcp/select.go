@@ -0,0 +1,256 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type OVSDBSelectArgs struct {
+	Table      string   `json:"table" jsonschema:"the table to select from, e.g. Logical_Switch"`
+	Conditions [][]any  `json:"conditions,omitempty" jsonschema:"conditions rows must match, each a [column, function, value] triple, e.g. [\"name\", \"==\", \"sw0\"]. Functions are ==, !=, includes and excludes, and <, <=, > and >= for integer and real columns. Values are JSON, or OVSDB notation: a set column takes an array and a map column an object, and uuid columns take the UUID as a string."`
+	MatchAll   *bool    `json:"match_all,omitempty" jsonschema:"whether rows must match all of the conditions, the default, or any of them"`
+	Columns    []string `json:"columns,omitempty" jsonschema:"the columns to return, all when not set"`
+	Limit      int      `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset     int      `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+// selectColumns are the columns every row has in addition to those in the
+// schema
+var selectColumns = []string{"_uuid", "_version"}
+
+// conditionFunctions are the functions a select condition can use
+var conditionFunctions = []ovsdb.ConditionFunction{
+	ovsdb.ConditionEqual,
+	ovsdb.ConditionNotEqual,
+	ovsdb.ConditionIncludes,
+	ovsdb.ConditionExcludes,
+	ovsdb.ConditionLessThan,
+	ovsdb.ConditionLessThanOrEqual,
+	ovsdb.ConditionGreaterThan,
+	ovsdb.ConditionGreaterThanOrEqual,
+}
+
+// columnNames returns the columns of a table, including _uuid and _version,
+// sorted by name
+func columnNames(table *ovsdb.TableSchema) []string {
+	names := slices.Clone(selectColumns)
+	for name := range table.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupTable returns the schema of the table name, or an error listing the
+// valid tables if the database has no such table
+func lookupTable(schema ovsdb.DatabaseSchema, name string) (*ovsdb.TableSchema, error) {
+	table := schema.Table(name)
+	if table == nil {
+		tables := make([]string, 0, len(schema.Tables))
+		for name := range schema.Tables {
+			tables = append(tables, name)
+		}
+		sort.Strings(tables)
+		return nil, fmt.Errorf("unknown table %q, valid tables are: %s", name, strings.Join(tables, ", "))
+	}
+	return table, nil
+}
+
+// conditionValue converts a JSON value to OVSDB notation for column. Values
+// already in OVSDB notation are passed through, as are those that don't
+// match the column's type, for the server to reject.
+func conditionValue(column *ovsdb.ColumnSchema, value any) any {
+	if column.TypeObj == nil {
+		return value
+	}
+	atom := func(t *ovsdb.BaseType, v any) any {
+		if s, ok := v.(string); ok && t.Type == ovsdb.TypeUUID {
+			return ovsdb.UUID{GoUUID: s}
+		}
+		return v
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		if column.Type != ovsdb.TypeMap {
+			return value
+		}
+		m := ovsdb.OvsMap{GoMap: make(map[any]any, len(v))}
+		for key, val := range v {
+			m.GoMap[atom(column.TypeObj.Key, key)] = atom(column.TypeObj.Value, val)
+		}
+		return m
+	case []any:
+		// OVSDB notation, e.g. ["set", [...]] or ["uuid", "..."]
+		if len(v) == 2 {
+			if tag, ok := v[0].(string); ok && slices.Contains([]string{"set", "map", "uuid", "named-uuid"}, tag) {
+				return value
+			}
+		}
+		if column.Type != ovsdb.TypeSet {
+			return value
+		}
+		set := ovsdb.OvsSet{GoSet: make([]any, 0, len(v))}
+		for _, e := range v {
+			set.GoSet = append(set.GoSet, atom(column.TypeObj.Key, e))
+		}
+		return set
+	default:
+		if column.Type == ovsdb.TypeSet {
+			// A single value is a set of one
+			return ovsdb.OvsSet{GoSet: []any{atom(column.TypeObj.Key, value)}}
+		}
+		return atom(column.TypeObj.Key, value)
+	}
+}
+
+// selectCondition builds a condition on table from a [column, function,
+// value] triple
+func selectCondition(table *ovsdb.TableSchema, triple []any) (ovsdb.Condition, error) {
+	if len(triple) != 3 {
+		return ovsdb.Condition{}, fmt.Errorf("condition %v must be a [column, function, value] triple", triple)
+	}
+	name, ok := triple[0].(string)
+	if !ok {
+		return ovsdb.Condition{}, fmt.Errorf("condition %v: column must be a string", triple)
+	}
+	function, ok := triple[1].(string)
+	if !ok || !slices.Contains(conditionFunctions, ovsdb.ConditionFunction(function)) {
+		valid := make([]string, 0, len(conditionFunctions))
+		for _, f := range conditionFunctions {
+			valid = append(valid, string(f))
+		}
+		return ovsdb.Condition{}, fmt.Errorf("condition %v: unknown function %v, valid functions are: %s", triple, triple[1], strings.Join(valid, ", "))
+	}
+
+	if name == "_uuid" {
+		s, ok := triple[2].(string)
+		if !ok {
+			return ovsdb.Condition{}, fmt.Errorf("condition %v: _uuid must be compared with a UUID string", triple)
+		}
+		return ovsdb.NewCondition(name, ovsdb.ConditionFunction(function), ovsdb.UUID{GoUUID: s}), nil
+	}
+	column, ok := table.Columns[name]
+	if !ok {
+		return ovsdb.Condition{}, fmt.Errorf("condition %v: unknown column %q, valid columns are: %s", triple, name, strings.Join(columnNames(table), ", "))
+	}
+	switch column.Type {
+	case ovsdb.TypeInteger, ovsdb.TypeReal:
+	default:
+		switch ovsdb.ConditionFunction(function) {
+		case ovsdb.ConditionEqual, ovsdb.ConditionNotEqual, ovsdb.ConditionIncludes, ovsdb.ConditionExcludes:
+		default:
+			return ovsdb.Condition{}, fmt.Errorf("condition %v: function %s can only be used with integer and real columns, %s is a %s column", triple, function, name, column.Type)
+		}
+	}
+	return ovsdb.NewCondition(name, ovsdb.ConditionFunction(function), conditionValue(column, triple[2])), nil
+}
+
+// OVSDBSelect runs a select on any table of the database, with conditions
+// built from the arguments, for querying columns the typed tools don't
+// filter on
+func (s *BaseServer) OVSDBSelect(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[OVSDBSelectArgs]) (*mcpsdk.CallToolResultFor[ListResult], error) {
+	args := params.Arguments
+
+	if args.Table == "" {
+		return nil, fmt.Errorf("table is required")
+	}
+
+	c, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	table, err := lookupTable(c.Schema(), args.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := make([]ovsdb.Condition, 0, len(args.Conditions))
+	for _, triple := range args.Conditions {
+		condition, err := selectCondition(table, triple)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	for _, column := range args.Columns {
+		if _, ok := table.Columns[column]; !ok && !slices.Contains(selectColumns, column) {
+			return nil, fmt.Errorf("unknown column %q, valid columns are: %s", column, strings.Join(columnNames(table), ", "))
+		}
+	}
+
+	matchAll := args.MatchAll == nil || *args.MatchAll
+	columns := args.Columns
+	// The conditions of a select are all matched, so matching any of them
+	// takes a select per condition
+	var wheres [][]ovsdb.Condition
+	if matchAll || len(conditions) < 2 {
+		wheres = [][]ovsdb.Condition{conditions}
+	} else {
+		for _, condition := range conditions {
+			wheres = append(wheres, []ovsdb.Condition{condition})
+		}
+		// Rows matching several conditions are merged by UUID
+		if len(columns) > 0 && !slices.Contains(columns, "_uuid") {
+			columns = append(slices.Clone(columns), "_uuid")
+		}
+	}
+
+	ops := make([]ovsdb.Operation, 0, len(wheres))
+	for _, where := range wheres {
+		ops = append(ops, ovsdb.Operation{
+			Op:      ovsdb.OperationSelect,
+			Table:   args.Table,
+			Where:   where,
+			Columns: columns,
+		})
+	}
+	reply, err := Transact(ctx, c, ops...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+	if _, err := ovsdb.CheckOperationResults(reply, ops); err != nil {
+		// The server's error says which condition it rejected
+		for _, r := range reply {
+			if r.Error != "" {
+				return nil, fmt.Errorf("failed to select rows: %s: %s", r.Error, r.Details)
+			}
+		}
+		return nil, fmt.Errorf("failed to select rows: %w", err)
+	}
+
+	rows := make([]map[string]any, 0, len(reply[0].Rows))
+	seen := make(map[any]bool)
+	for _, r := range reply {
+		for _, row := range r.Rows {
+			if len(reply) > 1 {
+				if seen[row["_uuid"]] {
+					continue
+				}
+				seen[row["_uuid"]] = true
+			}
+			rows = append(rows, row)
+		}
+	}
+	rows, page := Paginate(rows, args.Limit, args.Offset)
+
+	matched := "all"
+	if !matchAll {
+		matched = "any"
+	}
+
+	return NewResult(ListResult{
+		Data:       map[string]any{"rows": rows},
+		Count:      len(rows),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    fmt.Sprintf("Rows of the %s table matching %s of the conditions, in OVSDB notation: sets are [\"set\", [...]] unless they have one element, maps are [\"map\", [[key, value], ...]] and references are [\"uuid\", \"...\"]. Use get_schema to find the table's columns and their types.", args.Table, matched),
+	})
+}
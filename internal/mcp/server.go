@@ -2,23 +2,395 @@ package mcp
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
 
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/ovn-kubernetes/libovsdb/client"
 	"github.com/ovn-kubernetes/libovsdb/model"
 	"github.com/ovn-kubernetes/libovsdb/ovsdb"
 )
 
-// ExecuteSelectQuery is a helper function for executing select operations
-func ExecuteSelectQuery[T any](ctx context.Context, client client.Client, model T, conditions ...model.Condition) ([]T, error) {
+// BaseServer implements the parts of an MCP server that are common to all
+// of the OVSDB databases
+type BaseServer struct {
+	*mcpsdk.Server
+	Logger   *slog.Logger
+	dbModel  model.ClientDBModel
+	endpoint string
+	// targets are the named endpoints tool calls can select, the first is
+	// endpoint
+	targets    []Target
+	leaderOnly bool
+	tlsConfig  *tls.Config
+	httpServer *http.Server
+
+	callTimeout time.Duration
+	// registrations add each of the server's tools to another server, for
+	// serving them from a MuxServer
+	registrations []func(target *mcpsdk.Server, prefix string)
+
+	historySize   int
+	history       *ChangeLog
+	historyCancel context.CancelFunc
+
+	statusDatabases []statusDatabase
+	health          healthCache
+	cache           *tableCache
+	// metrics is nil unless enabled with WithMetrics
+	metrics *serverMetrics
+}
+
+// NewBaseServer creates a new MCP server for the database described by dbModel.
+// The server connects to endpoint unless overridden with WithEndpoint,
+// WithEndpoints or WithTarget.
+// An error is returned if the TLS certificates cannot be loaded or a table
+// to cache is not in the database.
+func NewBaseServer(impl *mcpsdk.Implementation, dbModel model.ClientDBModel, endpoint string, opts ...Option) (*BaseServer, error) {
+	o := NewOptions(opts...)
+	if o.Endpoint != "" {
+		endpoint = o.Endpoint
+	}
+	if len(o.Targets) > 0 {
+		if o.Endpoint != "" {
+			return nil, fmt.Errorf("an endpoint and targets can't both be set, the first target is the default endpoint")
+		}
+		if err := validateTargets(o.Targets); err != nil {
+			return nil, err
+		}
+		endpoint = o.Targets[0].Endpoint
+	}
+	tlsConfig, err := o.LoadTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	cache, err := newTableCache(dbModel, o.CacheTables)
+	if err != nil {
+		return nil, err
+	}
+	s := &BaseServer{
+		Server:      mcpsdk.NewServer(impl, nil),
+		Logger:      o.Logger.With("server", impl.Name),
+		dbModel:     dbModel,
+		endpoint:    endpoint,
+		targets:     o.Targets,
+		leaderOnly:  o.LeaderOnly,
+		tlsConfig:   tlsConfig,
+		historySize: o.RowHistory,
+		callTimeout: o.CallTimeout,
+		cache:       cache,
+	}
+	if o.Metrics {
+		s.metrics = newServerMetrics(impl.Name)
+	}
+
+	if len(s.targets) > 0 {
+		for _, t := range s.targets {
+			s.statusDatabases = append(s.statusDatabases, statusDatabase{dbModel: dbModel, endpoint: t.Endpoint, target: t.Name})
+		}
+	} else {
+		s.AddStatusDatabase(dbModel, endpoint)
+	}
+	s.AddResource(&mcpsdk.Resource{
+		URI:         StatusURI,
+		Name:        "status",
+		Title:       "Database status",
+		Description: "Whether the server can connect to each of its OVSDB databases, with the endpoint, the time taken to connect in milliseconds, the schema version and the error if it failed. Reading it makes a fresh connection attempt, so it can be used to check the databases before running tools.",
+		MIMEType:    "application/json",
+	}, s.ReadStatus)
+
+	return s, nil
+}
+
+// ConnectError is returned when an OVSDB endpoint cannot be reached. Tool
+// calls that fail with a ConnectError return an error result to the client
+// rather than failing the call.
+type ConnectError struct {
+	Database string
+	Endpoint string
+	Err      error
+}
+
+func (e *ConnectError) Error() string {
+	return fmt.Sprintf("failed to connect to OVSDB: %v", e.Err)
+}
+
+func (e *ConnectError) Unwrap() error {
+	return e.Err
+}
+
+// Connect returns a client connected to the server's OVSDB endpoint, or to
+// the endpoint of the target the tool call selected. When tables are cached
+// it is the cache's shared connection, whose cached tables are read from
+// the cache by the select helpers; only the default endpoint is cached.
+// The caller is responsible for closing the client.
+func (s *BaseServer) Connect(ctx context.Context) (client.Client, error) {
+	endpoint := s.Endpoint(ctx)
+	if endpoint == s.endpoint {
+		if c := s.cache.connected(); c != nil {
+			return c, nil
+		}
+	}
+	return s.ConnectTo(ctx, s.dbModel, endpoint)
+}
+
+// clientOptions returns the options of a client connecting to endpoint,
+// which is split into the endpoints of a clustered database on commas
+func (s *BaseServer) clientOptions(endpoint string) []client.Option {
+	var opts []client.Option
+	for _, ep := range strings.Split(endpoint, ",") {
+		if ep = strings.TrimSpace(ep); ep != "" {
+			opts = append(opts, client.WithEndpoint(ep))
+		}
+	}
+	opts = append(opts, client.WithLeaderOnly(s.leaderOnly))
+	if s.tlsConfig != nil {
+		opts = append(opts, client.WithTLSConfig(s.tlsConfig))
+	}
+	return opts
+}
+
+// ConnectTo returns a client for dbModel connected to endpoint. It is used by
+// servers whose tools span more than one database.
+// The caller is responsible for closing the client.
+func (s *BaseServer) ConnectTo(ctx context.Context, dbModel model.ClientDBModel, endpoint string) (client.Client, error) {
+	c, err := client.NewOVSDBClient(dbModel, s.clientOptions(endpoint)...)
+	if err != nil {
+		s.Logger.Error("Failed to create OVSDB client", "endpoint", endpoint, "error", err)
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Connection failures are retried, as the endpoint may be restarting
+	err = Retry(ctx, "connect", func() error { return c.Connect(ctx) })
+	if err != nil {
+		c.Close()
+		s.Logger.Error("Failed to connect to OVSDB", "endpoint", endpoint, "error", err)
+		s.metrics.connectError(dbModel.Name())
+		return nil, &ConnectError{Database: dbModel.Name(), Endpoint: endpoint, Err: err}
+	}
+
+	return c, nil
+}
+
+// AddTool registers a tool with the server, logging each call with its
+// arguments, duration and the number of rows returned, and recording it in
+// the server's metrics when they are enabled.
+// Each call is bounded by the server's call timeout. Connection failures and
+// timeouts are returned as an error result explaining which database was
+// unreachable, so the agent can report it or retry.
+func AddTool[In, Out any](s *BaseServer, t *mcpsdk.Tool, h mcpsdk.ToolHandlerFor[In, Out]) {
+	addTool(s, t, h, s.callTimeout, false)
+}
+
+// AddLongRunningTool registers a tool like AddTool, but without the call
+// timeout, for tools that bound their own duration such as watch_table
+func AddLongRunningTool[In, Out any](s *BaseServer, t *mcpsdk.Tool, h mcpsdk.ToolHandlerFor[In, Out]) {
+	addTool(s, t, h, 0, false)
+}
+
+func addTool[In, Out any](s *BaseServer, t *mcpsdk.Tool, h mcpsdk.ToolHandlerFor[In, Out], timeout time.Duration, write bool) {
+	name := t.Name
+	handler := func(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[In]) (*mcpsdk.CallToolResultFor[Out], error) {
+		callCtx := ctx
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			callCtx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		s.Logger.Debug("Tool call started", "tool", name, "arguments", params.Arguments)
+		start := time.Now()
+		res, err := h(contextWithLogger(callCtx, s.Logger.With("tool", name)), ss, params)
+		duration := time.Since(start)
+		if errors.Is(err, errDryRun) {
+			// The operations were recorded, and are returned by the
+			// tool's dry run handler
+			s.Logger.Info("Tool call dry run completed", "tool", name, "duration", duration)
+			return nil, nil
+		}
+		if err != nil {
+			s.Logger.Warn("Tool call failed", "tool", name, "arguments", params.Arguments, "duration", duration, "class", ClassifyError(err), "error", err)
+			// Only the call's own deadline is reported as a timeout, not
+			// the client cancelling the call
+			timedOut := ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded)
+			var connectErr *ConnectError
+			if timedOut {
+				target := s.databases()
+				if errors.As(err, &connectErr) {
+					target = fmt.Sprintf("the %s database at %s", connectErr.Database, connectErr.Endpoint)
+				}
+				return &mcpsdk.CallToolResultFor[Out]{
+					IsError: true,
+					Content: []mcpsdk.Content{
+						&mcpsdk.TextContent{
+							Text: fmt.Sprintf("The OVSDB operation timed out after %s waiting for %s: %v. The database server may be overloaded or unresponsive; retry the call, or check the server and its endpoint.", timeout, target, err),
+						},
+					},
+				}, nil
+			}
+			if errors.As(err, &connectErr) {
+				return &mcpsdk.CallToolResultFor[Out]{
+					IsError: true,
+					Content: []mcpsdk.Content{
+						&mcpsdk.TextContent{
+							Text: fmt.Sprintf("Unable to connect to the %s database at %s: %v. Check that the database server is running and that the endpoint is correct, for unix sockets that the socket file exists.", connectErr.Database, connectErr.Endpoint, connectErr.Err),
+						},
+					},
+				}, nil
+			}
+		} else if res != nil && res.IsError {
+			s.Logger.Info("Tool call returned an error", "tool", name, "arguments", params.Arguments, "duration", duration)
+		} else {
+			attrs := []any{"tool", name, "duration", duration}
+			if res != nil {
+				if rows, ok := resultRows(res.StructuredContent); ok {
+					attrs = append(attrs, "rows", rows)
+				}
+			}
+			s.Logger.Info("Tool call completed", attrs...)
+		}
+		return res, err
+	}
+	handler = instrumentTool(s.metrics, name, handler)
+	// AddTool infers and resolves the tool's schemas in place, and resolved
+	// schemas can't be added again, so copy the tool before adding it
+	unresolved := *t
+	if len(s.targets) > 0 || write {
+		s.Server.AddTool(extendedTool(s, &unresolved, handler, write))
+		s.registrations = append(s.registrations, func(target *mcpsdk.Server, prefix string) {
+			prefixed := unresolved
+			prefixed.Name = prefix + unresolved.Name
+			target.AddTool(extendedTool(s, &prefixed, handler, write))
+		})
+		return
+	}
+	mcpsdk.AddTool(s.Server, t, handler)
+	s.registrations = append(s.registrations, func(target *mcpsdk.Server, prefix string) {
+		prefixed := unresolved
+		prefixed.Name = prefix + unresolved.Name
+		mcpsdk.AddTool(target, &prefixed, handler)
+	})
+}
+
+// Start starts the MCP server on the specified address, and the cache of
+// the tables it is enabled for. The listener is bound before Start returns
+// so that bind errors are reported to the caller.
+func (s *BaseServer) Start(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	s.startRecording()
+	s.startCaching()
+	s.httpServer = serve(s.Logger, s.Server, s.CheckHealth, metricsHandler(s.metrics.gatherer()), listener, addr)
+
+	return nil
+}
+
+// startRecording starts recording row history if it is enabled. Row history
+// is best effort, the tools work without it.
+func (s *BaseServer) startRecording() {
+	if s.historySize == 0 {
+		return
+	}
+	historyCtx, cancel := context.WithCancel(context.Background())
+	if err := s.startHistory(historyCtx); err != nil {
+		cancel()
+		s.Logger.Warn("Failed to start recording row history", "endpoint", s.endpoint, "error", err)
+		return
+	}
+	s.historyCancel = cancel
+}
+
+// stopRecording stops recording row history
+func (s *BaseServer) stopRecording() {
+	if s.historyCancel != nil {
+		s.historyCancel()
+	}
+}
+
+// serve serves server over Streamable HTTP on listener in a goroutine, with
+// the liveness and readiness probes, the latter checking the databases with
+// check, and metrics when it is not nil
+func serve(logger *slog.Logger, server *mcpsdk.Server, check func(ctx context.Context) HealthResult, metrics http.Handler, listener net.Listener, addr string) *http.Server {
+	streamableHandler := mcpsdk.NewStreamableHTTPHandler(func(request *http.Request) *mcpsdk.Server {
+		return server
+	}, nil)
+
+	mux := http.NewServeMux()
+	mux.Handle(HealthPath, healthHandler())
+	mux.Handle(ReadyPath, readyHandler(check))
+	if metrics != nil {
+		mux.Handle(MetricsPath, metrics)
+	}
+	mux.Handle(MCPPath, streamableHandler)
+	mux.Handle("/", streamableHandler)
+
+	httpServer := &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+
+	go func() {
+		if err := httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Error("MCP server failed", "addr", addr, "error", err)
+		}
+	}()
+
+	return httpServer
+}
+
+// Stop stops the MCP server
+func (s *BaseServer) Stop(ctx context.Context) error {
+	s.stopRecording()
+	s.stopCaching()
+	if s.httpServer != nil {
+		return s.httpServer.Shutdown(ctx)
+	}
+	return nil
+}
+
+// ExecuteSelectQuery is a helper function for executing select operations,
+// returning the rows matching all of conditions.
+// The Field of each condition must point into model, as libovsdb resolves the
+// column from the field's offset within the model. Cached tables are read
+// from the cache.
+func ExecuteSelectQuery[T any](ctx context.Context, client client.Client, model *T, conditions ...model.Condition) ([]T, error) {
+	return executeSelect(ctx, client, model, true, conditions...)
+}
+
+// ExecuteSelectQueryAny is ExecuteSelectQuery returning the rows matching
+// any of conditions, e.g. ports named A or B. Every row is returned when
+// there are no conditions.
+func ExecuteSelectQueryAny[T any](ctx context.Context, client client.Client, model *T, conditions ...model.Condition) ([]T, error) {
+	return executeSelect(ctx, client, model, false, conditions...)
+}
+
+// executeSelect selects the rows of model's table matching all of
+// conditions when matchAll is set, and any of them otherwise
+func executeSelect[T any](ctx context.Context, client client.Client, model *T, matchAll bool, conditions ...model.Condition) ([]T, error) {
+	if results, ok, err := listCached(ctx, client, model, matchAll, conditions...); ok {
+		return results, err
+	}
+
 	var selectOps []ovsdb.Operation
 	var queryID string
 	var selectErr error
 
-	if len(conditions) > 0 {
-		selectOps, queryID, selectErr = client.WhereAll(&model, conditions...).Select()
-	} else {
-		selectOps, queryID, selectErr = client.Where(&model).Select()
+	switch {
+	case len(conditions) == 0:
+		selectOps, queryID, selectErr = client.Where(model).Select()
+	case matchAll:
+		selectOps, queryID, selectErr = client.WhereAll(model, conditions...).Select()
+	default:
+		// One select per condition, whose results are merged
+		selectOps, queryID, selectErr = client.WhereAny(model, conditions...).Select()
 	}
 
 	if selectErr != nil {
@@ -26,7 +398,7 @@ func ExecuteSelectQuery[T any](ctx context.Context, client client.Client, model
 	}
 
 	// Execute the transaction
-	reply, err := client.Transact(ctx, selectOps...)
+	reply, err := Transact(ctx, client, selectOps...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute transaction: %w", err)
 	}
@@ -40,3 +412,100 @@ func ExecuteSelectQuery[T any](ctx context.Context, client client.Client, model
 
 	return results, nil
 }
+
+// ExecuteSelectQueryLenient selects rows like ExecuteSelectQuery, but rows
+// that can't be decoded into model, e.g. because the database's schema
+// differs from the one the model was generated from, are skipped rather than
+// failing the query. A warning naming each skipped row and why is returned
+// with the rows that could be decoded.
+func ExecuteSelectQueryLenient[T any](ctx context.Context, client client.Client, model *T, conditions ...model.Condition) ([]T, []string, error) {
+	// Cached rows were decoded when they were received
+	if results, ok, err := listCached(ctx, client, model, true, conditions...); ok {
+		return results, nil, err
+	}
+
+	var selectOps []ovsdb.Operation
+	var queryID string
+	var selectErr error
+
+	if len(conditions) > 0 {
+		selectOps, queryID, selectErr = client.WhereAll(model, conditions...).Select()
+	} else {
+		selectOps, queryID, selectErr = client.Where(model).Select()
+	}
+
+	if selectErr != nil {
+		return nil, nil, fmt.Errorf("failed to create select operation: %w", selectErr)
+	}
+
+	reply, err := Transact(ctx, client, selectOps...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+
+	return decodeSelectResults[T](client, selectOps, reply, queryID)
+}
+
+// decodeSelectResults decodes the rows of a select. When they can't all be
+// decoded at once, each row is decoded on its own so that only the rows
+// that fail are skipped.
+func decodeSelectResults[T any](c client.Client, ops []ovsdb.Operation, reply []ovsdb.OperationResult, queryID string) ([]T, []string, error) {
+	var results []T
+	err := c.GetSelectResults(ops, reply, map[string]interface{}{queryID: &results})
+	if err == nil {
+		return results, nil, nil
+	}
+	for _, r := range reply {
+		if r.Error != "" {
+			// The select itself failed, there are no rows to decode
+			return nil, nil, fmt.Errorf("failed to get select results: %w", err)
+		}
+	}
+
+	results = nil
+	var warnings []string
+	for i := range reply {
+		for _, row := range reply[i].Rows {
+			// Only the row being decoded is left in the reply
+			single := make([]ovsdb.OperationResult, len(reply))
+			single[i] = ovsdb.OperationResult{Rows: []ovsdb.Row{row}}
+			var decoded []T
+			if err := c.GetSelectResults(ops, single, map[string]interface{}{queryID: &decoded}); err != nil {
+				uuid, _ := row["_uuid"].(ovsdb.UUID)
+				warnings = append(warnings, fmt.Sprintf("skipped %s row %s: %v", ops[i].Table, uuid.GoUUID, err))
+				continue
+			}
+			results = append(results, decoded...)
+		}
+	}
+	return results, warnings, nil
+}
+
+// ExecuteSelectByUUIDs selects the rows of m's table whose UUID is one of
+// uuids, in a single transaction. uuidField must point to the UUID field of m.
+func ExecuteSelectByUUIDs[T any](ctx context.Context, client client.Client, m *T, uuidField *string, uuids []string) ([]T, error) {
+	if len(uuids) == 0 {
+		return []T{}, nil
+	}
+
+	conditions := make([]model.Condition, 0, len(uuids))
+	for _, uuid := range uuids {
+		conditions = append(conditions, model.Condition{
+			Field:    uuidField,
+			Function: ovsdb.ConditionEqual,
+			Value:    uuid,
+		})
+	}
+	return ExecuteSelectQueryAny(ctx, client, m, conditions...)
+}
+
+// ExecuteSelectQueryPaged is ExecuteSelectQuery followed by Paginate, for
+// list tools that do no filtering of their own after the select
+func ExecuteSelectQueryPaged[T any](ctx context.Context, client client.Client, model *T, limit, offset int, conditions ...model.Condition) ([]T, Page, error) {
+	results, err := ExecuteSelectQuery(ctx, client, model, conditions...)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	results, page := Paginate(results, limit, offset)
+	return results, page, nil
+}
@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"context"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb/serverdb"
+)
+
+// ServerInfo describes a database and the OVSDB server serving it
+type ServerInfo struct {
+	Database      string `json:"database"`
+	Target        string `json:"target,omitempty"`
+	Endpoint      string `json:"endpoint"`
+	SchemaVersion string `json:"schema_version,omitempty"`
+	// Model, Leader and the cluster fields are read from the server's
+	// _Server database, they are unset when it isn't served
+	Model     string `json:"model,omitempty"`
+	Leader    *bool  `json:"leader,omitempty"`
+	Connected *bool  `json:"connected,omitempty"`
+	ClusterID string `json:"cluster_id,omitempty"`
+	ServerID  string `json:"server_id,omitempty"`
+	Index     *int   `json:"index,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type ServerInfoArgs struct{}
+
+// serverInfo connects to a database and to the _Server database of the
+// same server once each, without retrying. Leader-only is disabled, so the
+// followers of a cluster are described rather than refused.
+func (s *BaseServer) serverInfo(ctx context.Context, db statusDatabase) ServerInfo {
+	info := ServerInfo{
+		Database: db.dbModel.Name(),
+		Target:   db.target,
+		Endpoint: db.endpoint,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, statusTimeout)
+	defer cancel()
+
+	connect := func(dbModel model.ClientDBModel) (client.Client, error) {
+		c, err := client.NewOVSDBClient(dbModel, append(s.clientOptions(db.endpoint), client.WithLeaderOnly(false))...)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.Connect(ctx); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+
+	c, err := connect(db.dbModel)
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	info.SchemaVersion = c.Schema().Version
+	c.Close()
+
+	serverModel, err := serverdb.FullDatabaseModel()
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	sc, err := connect(serverModel)
+	if err != nil {
+		// Only ovsdb-server serves _Server, the server's own details are
+		// left unset rather than failing
+		return info
+	}
+	defer sc.Close()
+
+	row := &serverdb.Database{}
+	databases, err := ExecuteSelectQuery(ctx, sc, row, model.Condition{
+		Field:    &row.Name,
+		Function: ovsdb.ConditionEqual,
+		Value:    info.Database,
+	})
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	if len(databases) == 0 {
+		return info
+	}
+	database := databases[0]
+	info.Model = database.Model
+	info.Leader = &database.Leader
+	info.Connected = &database.Connected
+	if database.Cid != nil {
+		info.ClusterID = *database.Cid
+	}
+	if database.Sid != nil {
+		info.ServerID = *database.Sid
+	}
+	info.Index = database.Index
+	return info
+}
+
+// ServerInfo reports the schema version of the server's databases and, from
+// the _Server database, whether each one is standalone or clustered and
+// whether the server is its leader
+func (s *BaseServer) ServerInfo(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ServerInfoArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	databases := make([]ServerInfo, 0, len(s.statusDatabases))
+	for _, db := range s.statusDatabases {
+		databases = append(databases, s.serverInfo(ctx, db))
+	}
+
+	result := map[string]interface{}{
+		"databases": databases,
+		"context":   "The schema version is reported by the database and changes with the OVN or OVS release that defines it, so it is the version to quote in bug reports. model, leader and connected are read from the _Server database of ovsdb-server: a standalone database is always its own leader, a clustered one is only written through its leader and a relay is never the leader, and connected is false when the server has lost contact with the rest of its cluster. They are unset when the server does not serve _Server. ovsdb-server does not report its own version over OVSDB; run ovsdb-server --version on its host to get it.",
+	}
+
+	return NewResult(result)
+}
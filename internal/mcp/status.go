@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/model"
+)
+
+// StatusURI is the URI of the resource reporting whether the server's
+// databases are reachable
+const StatusURI = "ariadne://status"
+
+// statusTimeout bounds each connection attempt when reading the status
+// resource, so an unresponsive endpoint is reported rather than waited on
+const statusTimeout = 5 * time.Second
+
+// DatabaseStatus is the result of a connection attempt to a database
+type DatabaseStatus struct {
+	Database      string  `json:"database"`
+	Target        string  `json:"target,omitempty"`
+	Endpoint      string  `json:"endpoint"`
+	Connected     bool    `json:"connected"`
+	LatencyMS     float64 `json:"latency_ms"`
+	SchemaVersion string  `json:"schema_version,omitempty"`
+	Error         string  `json:"error,omitempty"`
+}
+
+// statusDatabase is a database reported by the status resource
+type statusDatabase struct {
+	dbModel  model.ClientDBModel
+	endpoint string
+	// target is the name of the endpoint, when the server has targets
+	target string
+}
+
+// AddStatusDatabase adds a database to the status resource, for servers
+// whose tools span more than one database
+func (s *BaseServer) AddStatusDatabase(dbModel model.ClientDBModel, endpoint string) {
+	s.statusDatabases = append(s.statusDatabases, statusDatabase{dbModel: dbModel, endpoint: endpoint})
+}
+
+// databases describes the databases of the server and their endpoints, for
+// errors that can't be attributed to one of them
+func (s *BaseServer) databases() string {
+	described := make([]string, 0, len(s.statusDatabases))
+	for _, db := range s.statusDatabases {
+		described = append(described, fmt.Sprintf("the %s database at %s", db.dbModel.Name(), db.endpoint))
+	}
+	return strings.Join(described, " or ")
+}
+
+// probe connects to a database once, without retrying, and reports how
+// long it took or why it failed
+func (s *BaseServer) probe(ctx context.Context, db statusDatabase) DatabaseStatus {
+	status := DatabaseStatus{
+		Database: db.dbModel.Name(),
+		Target:   db.target,
+		Endpoint: db.endpoint,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, statusTimeout)
+	defer cancel()
+
+	c, err := client.NewOVSDBClient(db.dbModel, s.clientOptions(db.endpoint)...)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	defer c.Close()
+
+	start := time.Now()
+	err = c.Connect(ctx)
+	status.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Connected = true
+	status.SchemaVersion = c.Schema().Version
+	return status
+}
+
+// ReadStatus reads the status resource. Each database is reported as a
+// separate JSON object.
+func (s *BaseServer) ReadStatus(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.ReadResourceParams) (*mcpsdk.ReadResourceResult, error) {
+	contents := make([]*mcpsdk.ResourceContents, 0, len(s.statusDatabases))
+	for _, db := range s.statusDatabases {
+		status := s.probe(ctx, db)
+		if !status.Connected {
+			s.Logger.Warn("Database is unreachable", "database", status.Database, "endpoint", status.Endpoint, "error", status.Error)
+		}
+		text, err := json.Marshal(status)
+		if err != nil {
+			return nil, err
+		}
+		contents = append(contents, &mcpsdk.ResourceContents{
+			URI:      StatusURI,
+			MIMEType: "application/json",
+			Text:     string(text),
+		})
+	}
+	return &mcpsdk.ReadResourceResult{Contents: contents}, nil
+}
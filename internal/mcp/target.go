@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+)
+
+// targetArgument is the tool argument selecting the target to query
+const targetArgument = "target"
+
+// Target is a named OVSDB endpoint, the database of one deployment
+type Target struct {
+	Name     string
+	Endpoint string
+}
+
+// validateTargets checks that every target has a name and an endpoint, and
+// that the names are unique
+func validateTargets(targets []Target) error {
+	names := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		if t.Name == "" || t.Endpoint == "" {
+			return fmt.Errorf("target %q must have a name and an endpoint", t.Name+"="+t.Endpoint)
+		}
+		if names[t.Name] {
+			return fmt.Errorf("duplicate target %q", t.Name)
+		}
+		names[t.Name] = true
+	}
+	return nil
+}
+
+type targetKey struct{}
+
+// contextWithTarget returns a context carrying the target a tool call
+// selected, which Connect connects to
+func contextWithTarget(ctx context.Context, target Target) context.Context {
+	return context.WithValue(ctx, targetKey{}, target)
+}
+
+// Endpoint returns the endpoint tools connect to during a call: the
+// endpoint of the target the call selected, or the server's endpoint
+func (s *BaseServer) Endpoint(ctx context.Context) string {
+	if target, ok := ctx.Value(targetKey{}).(Target); ok {
+		return target.Endpoint
+	}
+	return s.endpoint
+}
+
+// targetNames returns the names of the server's targets
+func (s *BaseServer) targetNames() []string {
+	names := make([]string, 0, len(s.targets))
+	for _, t := range s.targets {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// target returns the server's target named name
+func (s *BaseServer) target(name string) (Target, bool) {
+	for _, t := range s.targets {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Target{}, false
+}
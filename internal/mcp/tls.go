@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// LoadTLSConfig returns the TLS configuration set with WithTLSConfig, or
+// loads one from the files set with WithCertPaths. It returns nil when
+// neither was set.
+func (o *Options) LoadTLSConfig() (*tls.Config, error) {
+	if o.TLSConfig != nil {
+		return o.TLSConfig, nil
+	}
+	if o.CertFile == "" && o.KeyFile == "" && o.CAFile == "" {
+		return nil, nil
+	}
+	if o.CertFile == "" || o.KeyFile == "" {
+		return nil, fmt.Errorf("both a certificate and a private key are required for TLS")
+	}
+
+	cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate %s and private key %s: %w", o.CertFile, o.KeyFile, err)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if o.CAFile != "" {
+		ca, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate %s: %w", o.CAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no PEM encoded certificates found in CA certificate %s", o.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// extendedTool adapts a tool to take the arguments every tool of a kind
+// shares: target when the server has targets, and dry_run when the tool
+// writes to the database. They are not part of In, and the SDK rejects
+// arguments In doesn't have, so the tool is served with the arguments as a
+// map: the shared arguments are removed and put in the call's context, and
+// the rest are decoded into In and validated against its schema as the SDK
+// would. The input schema is always inferred from In.
+func extendedTool[In, Out any](s *BaseServer, t *mcpsdk.Tool, h mcpsdk.ToolHandlerFor[In, Out], write bool) (*mcpsdk.Tool, mcpsdk.ToolHandler) {
+	tool := *t
+	// Resolving a schema marks it as resolved, and the SDK resolves the
+	// tool's schemas when it is added, so the schema validating the
+	// arguments and the tool's are inferred separately
+	inferInput := func() *jsonschema.Schema {
+		schema, err := jsonschema.For[In]()
+		if err != nil {
+			panic(fmt.Sprintf("inferring the input schema of tool %q: %v", t.Name, err))
+		}
+		return schema
+	}
+	resolved, err := inferInput().Resolve(&jsonschema.ResolveOptions{ValidateDefaults: true})
+	if err != nil {
+		panic(fmt.Sprintf("resolving the input schema of tool %q: %v", t.Name, err))
+	}
+	// A dry run's result is not an Out, so write tools have no output schema
+	if tool.OutputSchema == nil && !write && reflect.TypeFor[Out]() != reflect.TypeFor[any]() {
+		if tool.OutputSchema, err = jsonschema.For[Out](); err != nil {
+			panic(fmt.Sprintf("inferring the output schema of tool %q: %v", t.Name, err))
+		}
+	}
+
+	extended := inferInput()
+	if extended.Properties == nil {
+		extended.Properties = map[string]*jsonschema.Schema{}
+	}
+	// The SDK validates the map of arguments against the tool's schema. A
+	// struct has every field so required is never enforced for In, and
+	// mustn't be for the map either: the handlers check their arguments.
+	extended.Required = nil
+	if len(s.targets) > 0 {
+		// The names are not an enum: the SDK would reject an unknown name
+		// with a protocol error, rather than the error result listing the
+		// targets that the handler returns
+		names := s.targetNames()
+		extended.Properties[targetArgument] = &jsonschema.Schema{
+			Type:        "string",
+			Description: fmt.Sprintf("the deployment to query, one of %s, defaults to %s", strings.Join(names, ", "), names[0]),
+		}
+	}
+	if write {
+		extended.Properties[dryRunArgument] = &jsonschema.Schema{
+			Type:        "boolean",
+			Description: "return the OVSDB operations the call would run, as JSON, instead of running them, so they can be reviewed first",
+		}
+	}
+	tool.InputSchema = extended
+
+	handler := func(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[map[string]any]) (*mcpsdk.CallToolResultFor[any], error) {
+		args := maps.Clone(params.Arguments)
+		if len(s.targets) > 0 {
+			target := s.targets[0]
+			if name, ok := args[targetArgument].(string); ok {
+				if target, ok = s.target(name); !ok {
+					return &mcpsdk.CallToolResultFor[any]{
+						IsError: true,
+						Content: []mcpsdk.Content{
+							&mcpsdk.TextContent{
+								Text: fmt.Sprintf("Unknown target %q, must be one of %s", name, strings.Join(s.targetNames(), ", ")),
+							},
+						},
+					}, nil
+				}
+				delete(args, targetArgument)
+			}
+			ctx = contextWithTarget(ctx, target)
+		}
+		var plan *dryRun
+		if write {
+			if dry, ok := args[dryRunArgument].(bool); ok {
+				if dry {
+					plan = &dryRun{}
+					ctx = contextWithDryRun(ctx, plan)
+				}
+				delete(args, dryRunArgument)
+			}
+		}
+
+		var in In
+		data, err := json.Marshal(args)
+		if err != nil {
+			return nil, err
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&in); err != nil {
+			return nil, fmt.Errorf("unmarshaling: %w", err)
+		}
+		if err := resolved.ApplyDefaults(&in); err != nil {
+			return nil, fmt.Errorf("applying defaults: %w", err)
+		}
+		if err := resolved.Validate(&in); err != nil {
+			return nil, fmt.Errorf("validating arguments: %w", err)
+		}
+
+		res, err := h(ctx, ss, &mcpsdk.CallToolParamsFor[In]{
+			Meta:      params.Meta,
+			Name:      params.Name,
+			Arguments: in,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if plan != nil && plan.result != nil {
+			planned, err := NewResult(*plan.result)
+			if err != nil {
+				return nil, err
+			}
+			return &mcpsdk.CallToolResultFor[any]{
+				Content:           planned.Content,
+				StructuredContent: planned.StructuredContent,
+			}, nil
+		}
+		if res == nil {
+			return nil, nil
+		}
+		return &mcpsdk.CallToolResultFor[any]{
+			Meta:              res.Meta,
+			Content:           res.Content,
+			IsError:           res.IsError,
+			StructuredContent: res.StructuredContent,
+		}, nil
+	}
+	return &tool, handler
+}
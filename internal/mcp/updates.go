@@ -0,0 +1,192 @@
+package mcp
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ovn-kubernetes/libovsdb/model"
+)
+
+// Update is a change to a row of a monitored table. Old is nil for an insert
+// and New is nil for a delete.
+type Update struct {
+	Table  string
+	UUID   string
+	Old    model.Model
+	New    model.Model
+	Queued time.Time
+}
+
+type updateKey struct {
+	table string
+	uuid  string
+}
+
+// UpdateProcessor processes monitor cache updates on a bounded pool of
+// workers. It implements the libovsdb cache.EventHandler interface without
+// ever blocking the libovsdb callback, which drops events once its buffer
+// is full. Every update is processed, in the order it was queued for each
+// row, and the backlog it builds up while the workers are behind is
+// reported by Pending and Lag.
+type UpdateProcessor struct {
+	process func(Update)
+	workers int
+
+	mu       sync.Mutex
+	queue    []Update
+	inflight map[updateKey]bool
+	wake     chan struct{}
+}
+
+// NewUpdateProcessor creates an UpdateProcessor that calls process for each
+// update on workers goroutines. Updates to the same row are never
+// processed concurrently.
+func NewUpdateProcessor(workers int, process func(Update)) *UpdateProcessor {
+	if workers < 1 {
+		workers = 1
+	}
+	return &UpdateProcessor{
+		process:  process,
+		workers:  workers,
+		inflight: make(map[updateKey]bool),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Start starts the workers, they stop when ctx is done
+func (p *UpdateProcessor) Start(ctx context.Context) {
+	for i := 0; i < p.workers; i++ {
+		go p.run(ctx)
+	}
+}
+
+// OnAdd queues an insert
+func (p *UpdateProcessor) OnAdd(table string, m model.Model) {
+	p.enqueue(table, nil, m)
+}
+
+// OnUpdate queues a modification
+func (p *UpdateProcessor) OnUpdate(table string, old model.Model, newModel model.Model) {
+	p.enqueue(table, old, newModel)
+}
+
+// OnDelete queues a delete
+func (p *UpdateProcessor) OnDelete(table string, m model.Model) {
+	p.enqueue(table, m, nil)
+}
+
+// Pending returns the number of updates waiting to be processed
+func (p *UpdateProcessor) Pending() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.queue)
+}
+
+// Lag returns how long the oldest waiting update has been queued, or zero
+// when the processor has caught up
+func (p *UpdateProcessor) Lag() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.queue) == 0 {
+		return 0
+	}
+	return time.Since(p.queue[0].Queued)
+}
+
+func (p *UpdateProcessor) enqueue(table string, old, newModel model.Model) {
+	m := newModel
+	if m == nil {
+		m = old
+	}
+
+	p.mu.Lock()
+	p.queue = append(p.queue, Update{
+		Table:  table,
+		UUID:   rowUUID(m),
+		Old:    old,
+		New:    newModel,
+		Queued: time.Now(),
+	})
+	p.mu.Unlock()
+	p.signal()
+}
+
+func (p *UpdateProcessor) signal() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (p *UpdateProcessor) next(ctx context.Context) (Update, bool) {
+	for {
+		p.mu.Lock()
+		for i, u := range p.queue {
+			// Rows being processed wait until their worker is done, and
+			// so do their later updates, which come after this one
+			key := updateKey{table: u.Table, uuid: u.UUID}
+			if p.inflight[key] {
+				continue
+			}
+			if i == 0 {
+				p.queue = p.queue[1:]
+			} else {
+				p.queue = slices.Delete(p.queue, i, i+1)
+			}
+			p.inflight[key] = true
+			more := len(p.queue) > 0
+			p.mu.Unlock()
+			if more {
+				// Pass the wakeup on to another idle worker
+				p.signal()
+			}
+			return u, true
+		}
+		p.mu.Unlock()
+
+		select {
+		case <-p.wake:
+		case <-ctx.Done():
+			return Update{}, false
+		}
+	}
+}
+
+func (p *UpdateProcessor) done(u Update) {
+	p.mu.Lock()
+	delete(p.inflight, updateKey{table: u.Table, uuid: u.UUID})
+	more := len(p.queue) > 0
+	p.mu.Unlock()
+	if more {
+		p.signal()
+	}
+}
+
+func (p *UpdateProcessor) run(ctx context.Context) {
+	for {
+		u, ok := p.next(ctx)
+		if !ok {
+			return
+		}
+		p.process(u)
+		p.done(u)
+	}
+}
+
+// rowUUID returns the UUID field of a generated model
+func rowUUID(m model.Model) string {
+	v := reflect.ValueOf(m)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	if f := v.FieldByName("UUID"); f.IsValid() && f.Kind() == reflect.String {
+		return f.String()
+	}
+	return ""
+}
@@ -0,0 +1,195 @@
+package vswitch
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/vswitch"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+type BridgeL2FeaturesArgs struct {
+	Bridge string `json:"bridge,omitempty" jsonschema:"only report the bridge with this name"`
+}
+
+// L2Setting is a bridge other_config key that tunes an L2 feature. Unset
+// keys are reported with their default value.
+type L2Setting struct {
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	Default   string `json:"default"`
+	IsDefault bool   `json:"is_default"`
+}
+
+// L2Feature is the state of an L2 protocol on a bridge
+type L2Feature struct {
+	Enabled  bool              `json:"enabled"`
+	Settings []L2Setting       `json:"settings"`
+	Status   map[string]string `json:"status,omitempty"`
+}
+
+type BridgeL2Features struct {
+	Bridge        string      `json:"bridge"`
+	STP           L2Feature   `json:"stp"`
+	RSTP          L2Feature   `json:"rstp"`
+	McastSnooping L2Feature   `json:"mcast_snooping"`
+	MACLearning   []L2Setting `json:"mac_learning"`
+	ForwardBPDU   bool        `json:"forward_bpdu"`
+	FloodVLANs    []int       `json:"flood_vlans"`
+	Issues        []string    `json:"issues"`
+}
+
+type BridgeL2FeaturesResult struct {
+	Bridges []BridgeL2Features `json:"bridges"`
+	Count   int                `json:"count"`
+	Context string             `json:"context"`
+}
+
+type l2Key struct {
+	key        string
+	defaultVal string
+}
+
+// Bridge other_config keys for each L2 feature and their defaults, see
+// ovs-vswitchd.conf.db(5). An empty default is derived by ovs-vswitchd, e.g.
+// from the bridge MAC address.
+var (
+	stpKeys = []l2Key{
+		{"stp-system-id", ""},
+		{"stp-priority", "32768"},
+		{"stp-hello-time", "2"},
+		{"stp-max-age", "20"},
+		{"stp-forward-delay", "15"},
+	}
+	rstpKeys = []l2Key{
+		{"rstp-address", ""},
+		{"rstp-priority", "32768"},
+		{"rstp-ageing-time", "300"},
+		{"rstp-force-protocol-version", "2"},
+		{"rstp-max-age", "20"},
+		{"rstp-forward-delay", "15"},
+		{"rstp-transmit-hold-count", "6"},
+	}
+	mcastSnoopingKeys = []l2Key{
+		{"mcast-snooping-table-size", "2048"},
+		{"mcast-snooping-aging-time", "300"},
+		{"mcast-snooping-disable-flood-unregistered", "false"},
+	}
+	macLearningKeys = []l2Key{
+		{"mac-aging-time", "300"},
+		{"mac-table-size", "2048"},
+	}
+)
+
+func l2Settings(otherConfig map[string]string, keys []l2Key) []L2Setting {
+	settings := make([]L2Setting, 0, len(keys))
+	for _, k := range keys {
+		value, ok := otherConfig[k.key]
+		if !ok {
+			value = k.defaultVal
+		}
+		settings = append(settings, L2Setting{
+			Key:       k.key,
+			Value:     value,
+			Default:   k.defaultVal,
+			IsDefault: !ok || value == k.defaultVal,
+		})
+	}
+	return settings
+}
+
+// stpStatus returns the STP entries of a bridge's status column
+func stpStatus(status map[string]string) map[string]string {
+	stp := make(map[string]string)
+	for k, v := range status {
+		if strings.HasPrefix(k, "stp_") {
+			stp[k] = v
+		}
+	}
+	return stp
+}
+
+func (s *Server) BridgeL2Features(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[BridgeL2FeaturesArgs]) (*mcpsdk.CallToolResultFor[BridgeL2FeaturesResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	bridgeModel := &vswitch.Bridge{}
+	var conditions []model.Condition
+	if args.Bridge != "" {
+		conditions = append(conditions, model.Condition{
+			Field:    &bridgeModel.Name,
+			Function: ovsdb.ConditionEqual,
+			Value:    args.Bridge,
+		})
+	}
+	bridges, err := mcp.ExecuteSelectQuery(ctx, client, bridgeModel, conditions...)
+	if err != nil {
+		return nil, err
+	}
+	if args.Bridge != "" && len(bridges) == 0 {
+		return &mcpsdk.CallToolResultFor[BridgeL2FeaturesResult]{
+			IsError: true,
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: fmt.Sprintf("bridge %s does not exist", args.Bridge),
+				},
+			},
+		}, nil
+	}
+	sort.Slice(bridges, func(i, j int) bool { return bridges[i].Name < bridges[j].Name })
+
+	results := make([]BridgeL2Features, 0, len(bridges))
+	for _, bridge := range bridges {
+		result := BridgeL2Features{
+			Bridge: bridge.Name,
+			STP: L2Feature{
+				Enabled:  bridge.STPEnable,
+				Settings: l2Settings(bridge.OtherConfig, stpKeys),
+				Status:   stpStatus(bridge.Status),
+			},
+			RSTP: L2Feature{
+				Enabled:  bridge.RSTPEnable,
+				Settings: l2Settings(bridge.OtherConfig, rstpKeys),
+				Status:   bridge.RSTPStatus,
+			},
+			McastSnooping: L2Feature{
+				Enabled:  bridge.McastSnoopingEnable,
+				Settings: l2Settings(bridge.OtherConfig, mcastSnoopingKeys),
+			},
+			MACLearning: l2Settings(bridge.OtherConfig, macLearningKeys),
+			ForwardBPDU: bridge.OtherConfig["forward-bpdu"] == "true",
+			FloodVLANs:  slices.Clone(bridge.FloodVLANs),
+			Issues:      []string{},
+		}
+		if result.FloodVLANs == nil {
+			result.FloodVLANs = []int{}
+		}
+		if bridge.STPEnable && bridge.RSTPEnable {
+			result.Issues = append(result.Issues, "both STP and RSTP are enabled, they are mutually exclusive and RSTP is used")
+		}
+		if result.ForwardBPDU && (bridge.STPEnable || bridge.RSTPEnable) {
+			result.Issues = append(result.Issues, "forward-bpdu is set but BPDUs are consumed by spanning tree while it is enabled")
+		}
+		if !bridge.McastSnoopingEnable && bridge.OtherConfig["mcast-snooping-disable-flood-unregistered"] == "true" {
+			result.Issues = append(result.Issues, "mcast-snooping-disable-flood-unregistered has no effect while multicast snooping is disabled")
+		}
+		results = append(results, result)
+	}
+
+	return mcp.NewResult(BridgeL2FeaturesResult{
+		Bridges: results,
+		Count:   len(results),
+		Context: "Layer 2 behaviour of each bridge. STP, RSTP and multicast snooping are disabled by default and enabled by the bridge's stp_enable, rstp_enable and mcast_snooping_enable columns, their tuning comes from other_config keys which are shown with the default used when unset (an empty default is derived by ovs-vswitchd, e.g. from the bridge MAC address). Spanning tree status is only present while the protocol is running. MAC learning settings apply to bridges using the NORMAL action; flood_vlans lists VLANs on which MAC learning is disabled and all packets are flooded. forward-bpdu makes the bridge forward BPDUs instead of dropping them when spanning tree is disabled.",
+	})
+}
@@ -0,0 +1,123 @@
+package vswitch
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/vswitch"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type FindPortByMACArgs struct {
+	MAC string `json:"mac" jsonschema:"the MAC address to find, e.g. aa:bb:cc:dd:ee:ff, in any case and with colons, dashes or dots"`
+}
+
+// sameMAC reports whether an optional MAC column holds mac, comparing the
+// parsed addresses so the formatting doesn't matter
+func sameMAC(column *string, mac net.HardwareAddr) bool {
+	if column == nil {
+		return false
+	}
+	addr, err := net.ParseMAC(*column)
+	return err == nil && addr.String() == mac.String()
+}
+
+func (s *Server) FindPortByMAC(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[FindPortByMACArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	if args.MAC == "" {
+		return nil, fmt.Errorf("mac must not be empty")
+	}
+	mac, err := net.ParseMAC(args.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mac %q: %w", args.MAC, err)
+	}
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	// The MAC columns are free-form strings, so they are compared here
+	// rather than by the select
+	ifaces, err := mcp.ExecuteSelectQuery(ctx, client, &vswitch.Interface{})
+	if err != nil {
+		return nil, err
+	}
+	var results []vswitch.Interface
+	var matched [][]string
+	for _, iface := range ifaces {
+		var columns []string
+		if sameMAC(iface.MACInUse, mac) {
+			columns = append(columns, "mac_in_use")
+		}
+		if sameMAC(iface.MAC, mac) {
+			columns = append(columns, "mac")
+		}
+		if len(columns) > 0 {
+			results = append(results, iface)
+			matched = append(matched, columns)
+		}
+	}
+
+	bridges, err := mcp.ExecuteSelectQuery(ctx, client, &vswitch.Bridge{})
+	if err != nil {
+		return nil, err
+	}
+	ports, err := mcp.ExecuteSelectQuery(ctx, client, &vswitch.Port{})
+	if err != nil {
+		return nil, err
+	}
+	bridgeRows, err := mcp.MapRows(vswitch.BridgeTable, vswitch.DatabaseSchema(), bridges)
+	if err != nil {
+		return nil, err
+	}
+	portRows, err := mcp.MapRows(vswitch.PortTable, vswitch.DatabaseSchema(), ports)
+	if err != nil {
+		return nil, err
+	}
+	// The rows of the port and bridge owning each interface, keyed by the
+	// interface UUID
+	portBridge := make(map[string]map[string]any)
+	for i, bridge := range bridges {
+		for _, port := range bridge.Ports {
+			portBridge[port] = bridgeRows[i]
+		}
+	}
+	ifacePort := make(map[string]map[string]any)
+	ifaceBridge := make(map[string]map[string]any)
+	for i, port := range ports {
+		for _, iface := range port.Interfaces {
+			ifacePort[iface] = portRows[i]
+			ifaceBridge[iface] = portBridge[port.UUID]
+		}
+	}
+
+	rows, err := mcp.MapRows(vswitch.InterfaceTable, vswitch.DatabaseSchema(), results)
+	if err != nil {
+		return nil, err
+	}
+	data := []map[string]any{}
+	for i, row := range rows {
+		data = append(data, map[string]any{
+			"interface":       row,
+			"port":            ifacePort[results[i].UUID],
+			"bridge":          ifaceBridge[results[i].UUID],
+			"matched_columns": matched[i],
+		})
+	}
+
+	summary := fmt.Sprintf("Found %d interfaces with MAC %s.", len(results), mac)
+	if len(results) == 0 {
+		summary = fmt.Sprintf("No interface on this host has MAC %s in its mac or mac_in_use column.", mac)
+	}
+
+	return mcp.NewResult(mcp.ListResult{
+		Data:    map[string]any{"interfaces": data},
+		Count:   len(results),
+		Context: summary + " mac_in_use is the MAC address OVS reads from the interface, mac is the one configured to be set on it. Each interface is returned with the full rows of the port and bridge it is attached to, which are null if it is not attached to one.",
+	})
+}
@@ -0,0 +1,155 @@
+package vswitch
+
+import (
+	"context"
+	"slices"
+
+	"github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/schema/vswitch"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ListMirrorsArgs struct {
+	BridgeFilter string `json:"bridge_filter,omitempty" jsonschema:"the name of the bridge to filter by"`
+	CountOnly    bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset       int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type ListNetFlowArgs struct {
+	BridgeFilter string `json:"bridge_filter,omitempty" jsonschema:"the name of the bridge to filter by"`
+	CountOnly    bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset       int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type ListSFlowArgs struct {
+	BridgeFilter string `json:"bridge_filter,omitempty" jsonschema:"the name of the bridge to filter by"`
+	CountOnly    bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset       int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type ListIPFIXArgs struct {
+	BridgeFilter string `json:"bridge_filter,omitempty" jsonschema:"the name of the bridge to filter by"`
+	CountOnly    bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset       int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+// bridgeParent filters rows by the bridge named name, keeping those the
+// bridge references. It returns nil when name is empty.
+func bridgeParent[C any](name string, keep func(vswitch.Bridge, C) bool) *mcp.ParentFilter[vswitch.Bridge, C] {
+	if name == "" {
+		return nil
+	}
+	bridge := &vswitch.Bridge{}
+	return &mcp.ParentFilter[vswitch.Bridge, C]{
+		Model: bridge,
+		Field: &bridge.Name,
+		Value: name,
+		Kind:  "bridge",
+		Keep:  keep,
+	}
+}
+
+// refersTo reports whether the optional reference column ref is uuid
+func refersTo(ref *string, uuid string) bool {
+	return ref != nil && *ref == uuid
+}
+
+func (s *Server) ListMirrors(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListMirrorsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	return mcp.ListWithParentFilter(ctx, client, mcp.ListQuery[vswitch.Bridge, vswitch.Mirror]{
+		Model:   &vswitch.Mirror{},
+		Table:   vswitch.MirrorTable,
+		Schema:  vswitch.DatabaseSchema(),
+		Key:     "mirrors",
+		Context: "Mirrors copy packets to another port for capture, like a SPAN port on a physical switch. A mirror selects the packets entering its select_src_port ports, leaving its select_dst_port ports, on its select_vlan VLANs or all packets with select_all, and sends copies to output_port, or to output_vlan on every port when it is set. Use a mirror to capture full packets, not just statistics about them.",
+		Parent: bridgeParent(args.BridgeFilter, func(bridge vswitch.Bridge, mirror vswitch.Mirror) bool {
+			return slices.Contains(bridge.Mirrors, mirror.UUID)
+		}),
+		Limit:     args.Limit,
+		Offset:    args.Offset,
+		CountOnly: args.CountOnly,
+	})
+}
+
+func (s *Server) ListNetFlow(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListNetFlowArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	return mcp.ListWithParentFilter(ctx, client, mcp.ListQuery[vswitch.Bridge, vswitch.NetFlow]{
+		Model:   &vswitch.NetFlow{},
+		Table:   vswitch.NetFlowTable,
+		Schema:  vswitch.DatabaseSchema(),
+		Key:     "netflow",
+		Context: "NetFlow exports a record of every flow through a bridge to the collectors in targets when the flow expires, or every active_timeout seconds while it is active. Records have per-flow packet and byte counts but no packet contents, so NetFlow suits accounting and traffic analysis of all traffic, not sampling.",
+		Parent: bridgeParent(args.BridgeFilter, func(bridge vswitch.Bridge, netflow vswitch.NetFlow) bool {
+			return refersTo(bridge.Netflow, netflow.UUID)
+		}),
+		Limit:     args.Limit,
+		Offset:    args.Offset,
+		CountOnly: args.CountOnly,
+	})
+}
+
+func (s *Server) ListSFlow(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListSFlowArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	return mcp.ListWithParentFilter(ctx, client, mcp.ListQuery[vswitch.Bridge, vswitch.SFlow]{
+		Model:   &vswitch.SFlow{},
+		Table:   vswitch.SFlowTable,
+		Schema:  vswitch.DatabaseSchema(),
+		Key:     "sflow",
+		Context: "sFlow sends the headers of 1 in sampling packets on a bridge, truncated to header bytes, along with interface counters every polling seconds, to the collectors in targets. Sampling keeps the overhead low on busy bridges at the cost of missing individual flows.",
+		Parent: bridgeParent(args.BridgeFilter, func(bridge vswitch.Bridge, sflow vswitch.SFlow) bool {
+			return refersTo(bridge.Sflow, sflow.UUID)
+		}),
+		Limit:     args.Limit,
+		Offset:    args.Offset,
+		CountOnly: args.CountOnly,
+	})
+}
+
+func (s *Server) ListIPFIX(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListIPFIXArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	return mcp.ListWithParentFilter(ctx, client, mcp.ListQuery[vswitch.Bridge, vswitch.IPFIX]{
+		Model:   &vswitch.IPFIX{},
+		Table:   vswitch.IPFIXTable,
+		Schema:  vswitch.DatabaseSchema(),
+		Key:     "ipfix",
+		Context: "IPFIX samples 1 in sampling packets on a bridge and exports flow records for them to the collectors in targets. It is the standardized successor of NetFlow, with templated records that can carry tunnel metadata. This is per-bridge sampling; per-flow sampling, such as OVN's ACL sampling, is configured in Flow_Sample_Collector_Set.",
+		Parent: bridgeParent(args.BridgeFilter, func(bridge vswitch.Bridge, ipfix vswitch.IPFIX) bool {
+			return refersTo(bridge.IPFIX, ipfix.UUID)
+		}),
+		Limit:     args.Limit,
+		Offset:    args.Offset,
+		CountOnly: args.CountOnly,
+	})
+}
@@ -0,0 +1,194 @@
+package vswitch
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// OtherConfigEntry is a decoded key from the Open_vSwitch other_config column
+type OtherConfigEntry struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Known       bool   `json:"known"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+	NonDefault  bool   `json:"non_default"`
+	Warning     string `json:"warning,omitempty"`
+}
+
+type otherConfigKey struct {
+	description string
+	defaultVal  string
+	// check returns a warning for risky values, or an empty string
+	check func(value string) string
+}
+
+// knownOtherConfig describes the well-known Open_vSwitch other_config keys,
+// see ovs-vswitchd.conf.db(5)
+var knownOtherConfig = map[string]otherConfigKey{
+	"stats-update-interval": {
+		description: "Interval in milliseconds at which statistics are written to the database",
+		defaultVal:  "5000",
+		check:       minInt(5000, "values below 5000ms are clamped to 5000ms"),
+	},
+	"flow-restore-wait": {
+		description: "When true, ovs-vswitchd waits for flows to be restored before processing packets and does not flush or expire datapath flows",
+		defaultVal:  "false",
+		check: func(v string) string {
+			if v == "true" {
+				return "flow-restore-wait is left enabled, the datapath will not be updated until it is removed"
+			}
+			return ""
+		},
+	},
+	"flow-limit": {
+		description: "Maximum number of flows allowed in the datapath flow table",
+		defaultVal:  "200000",
+		check:       minInt(1000, "a low flow limit forces frequent flow eviction and upcalls"),
+	},
+	"max-idle": {
+		description: "Maximum time in milliseconds that idle datapath flows are cached",
+		defaultVal:  "10000",
+		check:       minInt(500, "a very low max-idle causes excessive upcalls"),
+	},
+	"max-revalidator": {
+		description: "Maximum time in milliseconds that revalidators wait before revalidating datapath flows",
+		defaultVal:  "500",
+	},
+	"min-revalidate-pps": {
+		description: "Datapath flows with fewer packets per second than this are deleted on revalidation",
+		defaultVal:  "5",
+	},
+	"n-handler-threads": {
+		description: "Number of threads handling new flow setups",
+		defaultVal:  "",
+	},
+	"n-revalidator-threads": {
+		description: "Number of threads revalidating datapath flows",
+		defaultVal:  "",
+	},
+	"hw-offload": {
+		description: "Enables offloading of datapath flows to network hardware, requires a restart of ovs-vswitchd to take effect",
+		defaultVal:  "false",
+		check: func(v string) string {
+			if v == "true" {
+				return "hardware offload is enabled, flows may be handled by the NIC and not appear in the kernel datapath"
+			}
+			return ""
+		},
+	},
+	"tc-policy": {
+		description: "Policy used when offloading flows with TC: none, skip_sw or skip_hw",
+		defaultVal:  "none",
+		check: func(v string) string {
+			if v == "skip_sw" {
+				return "skip_sw installs flows in hardware only, flows that cannot be offloaded will fail"
+			}
+			return ""
+		},
+	},
+	"n-offload-threads": {
+		description: "Number of threads used for flow offload",
+		defaultVal:  "1",
+	},
+	"offload-rebalance": {
+		description: "Enables dynamic rebalancing of offloaded flows",
+		defaultVal:  "false",
+	},
+	"dpdk-init": {
+		description: "Initializes DPDK at startup: true, false or try",
+		defaultVal:  "false",
+		check: func(v string) string {
+			if v == "try" {
+				return "DPDK initialization failures are ignored, check dpdk_initialized"
+			}
+			return ""
+		},
+	},
+	"dpdk-lcore-mask": {
+		description: "CPU mask of cores on which DPDK lcore threads are spawned",
+	},
+	"pmd-cpu-mask": {
+		description: "CPU mask of cores on which PMD threads are spawned",
+	},
+	"dpdk-socket-mem": {
+		description: "Amount of hugepage memory in megabytes to preallocate per NUMA node",
+	},
+	"dpdk-socket-limit": {
+		description: "Upper limit of hugepage memory in megabytes DPDK may use per NUMA node",
+	},
+	"dpdk-hugepage-dir": {
+		description: "Directory where hugetlbfs is mounted",
+	},
+	"dpdk-extra": {
+		description: "Additional arguments passed to the DPDK EAL",
+	},
+	"vhost-sock-dir": {
+		description: "Directory for vhost-user sockets, relative to the OVS run directory",
+	},
+	"pmd-rxq-assign": {
+		description: "Algorithm used to assign receive queues to PMD threads: cycles, roundrobin or group",
+		defaultVal:  "cycles",
+	},
+	"userspace-tso-enable": {
+		description: "Enables TCP segmentation offload in the userspace datapath",
+		defaultVal:  "false",
+	},
+	"vlan-limit": {
+		description: "Maximum number of VLAN headers matched on, 0 means unlimited",
+		defaultVal:  "1",
+	},
+	"bundle-idle-timeout": {
+		description: "Timeout in seconds for idle OpenFlow bundles",
+		defaultVal:  "10",
+	},
+	"smc-enable": {
+		description: "Enables the signature match cache in the userspace datapath",
+		defaultVal:  "false",
+	},
+	"emc-insert-inv-prob": {
+		description: "Inverse probability of inserting a flow into the exact match cache, 0 disables it",
+		defaultVal:  "100",
+	},
+}
+
+// minInt returns a check that warns when an integer value is below min
+func minInt(min int, warning string) func(string) string {
+	return func(v string) string {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Sprintf("value %q is not an integer", v)
+		}
+		if n < min {
+			return warning
+		}
+		return ""
+	}
+}
+
+// decodeOtherConfig explains the keys of the Open_vSwitch other_config
+// column, unknown keys are returned as-is
+func decodeOtherConfig(otherConfig map[string]string) []OtherConfigEntry {
+	entries := make([]OtherConfigEntry, 0, len(otherConfig))
+	for key, value := range otherConfig {
+		entry := OtherConfigEntry{
+			Key:   key,
+			Value: value,
+		}
+		if known, ok := knownOtherConfig[key]; ok {
+			entry.Known = true
+			entry.Description = known.description
+			entry.Default = known.defaultVal
+			entry.NonDefault = known.defaultVal == "" || value != known.defaultVal
+			if known.check != nil {
+				entry.Warning = known.check(value)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+	return entries
+}
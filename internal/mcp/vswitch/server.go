@@ -2,15 +2,14 @@ package vswitch
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/dave-tucker/ariadne/internal/mcp"
 	"github.com/dave-tucker/ariadne/internal/schema/vswitch"
 	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/ovn-kubernetes/libovsdb/client"
-	"github.com/ovn-kubernetes/libovsdb/mapper"
 	"github.com/ovn-kubernetes/libovsdb/model"
 	"github.com/ovn-kubernetes/libovsdb/ovsdb"
 )
@@ -18,480 +17,1121 @@ import (
 const defaultEndpoint = "unix:/var/run/openvswitch/db.sock"
 
 type Server struct {
-	*mcpsdk.Server
-	dbModel    model.ClientDBModel
-	httpServer *http.Server
+	*mcp.BaseServer
 }
 
 type ListBridgesArgs struct {
 	NameFilter string `json:"name_filter" jsonschema:"the name of the bridge to filter by"`
+	MatchMode  string `json:"match,omitempty" jsonschema:"how name_filter is matched: exact (default), prefix, substring or regex, prefix and substring matches are case-insensitive"`
+	CountOnly  bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset     int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListPortsArgs struct {
+	CountOnly bool `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit     int  `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset    int  `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListInterfacesArgs struct {
 	PortFilter string `json:"port_filter" jsonschema:"the name of the port to filter by"`
+	CountOnly  bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset     int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListManagersArgs struct {
+	CountOnly bool `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit     int  `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset    int  `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListControllersArgs struct {
+	CountOnly bool `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit     int  `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset    int  `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListFlowTablesArgs struct {
 	BridgeFilter string `json:"bridge_filter" jsonschema:"the name of the bridge to filter by"`
+	CountOnly    bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset       int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
 type ListSSLConfigsArgs struct {
+	CountOnly bool `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit     int  `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset    int  `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
-type ListResult struct {
-	Data    map[string]any `json:"data"`
-	Count   int            `json:"count"`
-	Context string         `json:"context"`
+type ListOVSQoSArgs struct {
+	PortFilter string `json:"port_filter,omitempty" jsonschema:"the name of the port whose QoS to return"`
+	CountOnly  bool   `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset     int    `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
 }
 
-func (s *Server) ListBridges(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListBridgesArgs]) (*mcpsdk.CallToolResultFor[ListResult], error) {
+type ListQueuesArgs struct {
+	CountOnly bool `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit     int  `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset    int  `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type OVSInfoArgs struct {
+}
+
+type CreateBridgeArgs struct {
+	Name         string `json:"name" jsonschema:"the name of the bridge to create"`
+	DatapathType string `json:"datapath_type,omitempty" jsonschema:"the datapath type of the bridge, e.g. system or netdev, defaults to system"`
+}
+
+type OVSInfoResult struct {
+	OVSVersion      string             `json:"ovs_version"`
+	DBVersion       string             `json:"db_version"`
+	SystemType      string             `json:"system_type"`
+	SystemVersion   string             `json:"system_version"`
+	DPDKVersion     string             `json:"dpdk_version"`
+	DPDKInitialized bool               `json:"dpdk_initialized"`
+	DatapathTypes   []string           `json:"datapath_types"`
+	IfaceTypes      []string           `json:"iface_types"`
+	ExternalIDs     map[string]string  `json:"external_ids"`
+	OtherConfig     []OtherConfigEntry `json:"other_config"`
+	Context         string             `json:"context"`
+}
+
+func (s *Server) ListBridges(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListBridgesArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	nameFilter := args.NameFilter
+	matcher, err := mcp.NewNameMatcher(args.NameFilter, args.MatchMode)
+	if err != nil {
+		return nil, err
+	}
+
+	bridge := &vswitch.Bridge{}
 	var conditions []model.Condition
-	if nameFilter != "" {
+	if matcher.Exact() {
 		conditions = append(conditions, model.Condition{
-			Field:    &(&vswitch.Bridge{}).Name,
+			Field:    &bridge.Name,
 			Function: ovsdb.ConditionEqual,
-			Value:    nameFilter,
+			Value:    args.NameFilter,
 		})
 	}
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
+	results, err := mcp.ExecuteSelectQuery(ctx, client, bridge, conditions...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+		return nil, err
+	}
+	results = mcp.FilterByName(results, matcher, func(r vswitch.Bridge) string { return r.Name })
+	if args.CountOnly {
+		return mcp.CountResult(len(results))
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, vswitch.Bridge{}, conditions...)
+	results, page := mcp.Paginate(results, args.Limit, args.Offset)
+
+	data, err := mcp.MapRows(vswitch.BridgeTable, vswitch.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
 
-	m := mapper.NewMapper(vswitch.Schema())
-	tableName := vswitch.BridgeTable
-	tableSchema := vswitch.Schema().Table(tableName)
+	return mcp.NewResult(mcp.ListResult{
+		Data:       map[string]any{"bridges": data},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Bridges are the main configuration entities in Open vSwitch that contain ports and interfaces. Each bridge represents a virtual switch that can have multiple ports.",
+	})
+}
 
-	var data []map[string]any
+func (s *Server) ListPorts(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListPortsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
 
-	for _, result := range results {
-		info, err := mapper.NewInfo(tableName, tableSchema, &result)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create info: %w", err)
-		}
-		row, err := m.NewRow(info)
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	if args.CountOnly {
+		count, err := mcp.CountRows(ctx, client, &vswitch.Port{})
 		if err != nil {
-			return nil, fmt.Errorf("failed to create row: %w", err)
+			return nil, err
 		}
+		return mcp.CountResult(count)
+	}
 
-		data = append(data, row)
+	results, page, err := mcp.ExecuteSelectQueryPaged(ctx, client, &vswitch.Port{}, args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
 	}
 
-	var res mcpsdk.CallToolResultFor[ListResult]
-	res.Content = []mcpsdk.Content{
-		&mcpsdk.TextContent{
-			Text: "success",
-		},
+	data, err := mcp.MapRows(vswitch.PortTable, vswitch.DatabaseSchema(), results)
+	if err != nil {
+		return nil, err
 	}
-	res.StructuredContent = ListResult{
-		Data:    map[string]any{"bridges": data},
-		Count:   len(results),
-		Context: "Bridges are the main configuration entities in Open vSwitch that contain ports and interfaces. Each bridge represents a virtual switch that can have multiple ports.",
+
+	return mcp.NewResult(mcp.ListResult{
+		Data:       map[string]any{"ports": data},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Ports are logical entities that group interfaces together within a bridge. Each port can have multiple interfaces and belongs to a specific bridge.",
+	})
+}
+
+func (s *Server) ListInterfaces(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListInterfacesArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	query := mcp.ListQuery[vswitch.Port, vswitch.Interface]{
+		Model:     &vswitch.Interface{},
+		Table:     vswitch.InterfaceTable,
+		Schema:    vswitch.DatabaseSchema(),
+		Key:       "interfaces",
+		Context:   "Interfaces represent the actual network connections and can be physical or virtual. Each interface belongs to a port and can have various configuration options.",
+		Limit:     args.Limit,
+		Offset:    args.Offset,
+		CountOnly: args.CountOnly,
+	}
+	if args.PortFilter != "" {
+		port := &vswitch.Port{}
+		query.Parent = &mcp.ParentFilter[vswitch.Port, vswitch.Interface]{
+			Model: port,
+			Field: &port.Name,
+			Value: args.PortFilter,
+			Kind:  "port",
+			// Only keep the interfaces referenced by the port's interfaces column
+			Keep: func(port vswitch.Port, iface vswitch.Interface) bool {
+				return slices.Contains(port.Interfaces, iface.UUID)
+			},
+		}
 	}
 
-	return &res, nil
+	return mcp.ListWithParentFilter(ctx, client, query)
 }
 
-func (s *Server) ListPorts(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListPortsArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+func (s *Server) ListManagers(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListManagersArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
+	if args.CountOnly {
+		count, err := mcp.CountRows(ctx, client, &vswitch.Manager{})
+		if err != nil {
+			return nil, err
+		}
+		return mcp.CountResult(count)
+	}
+
+	results, page, err := mcp.ExecuteSelectQueryPaged(ctx, client, &vswitch.Manager{}, args.Limit, args.Offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+		return nil, err
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, vswitch.Port{})
+	rows, err := mcp.MapRows(vswitch.ManagerTable, vswitch.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
 
-	var data []map[string]any
+	result := mcp.ListResult{
+		Data:       map[string]any{"managers": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Managers define connections to OpenFlow controllers. Each manager specifies how Open vSwitch connects to external OpenFlow controllers for network control.",
+	}
 
-	m := mapper.NewMapper(vswitch.Schema())
-	tableName := vswitch.PortTable
-	tableSchema := vswitch.Schema().Table(tableName)
+	return mcp.NewResult(result)
+}
 
-	for _, result := range results {
-		info, err := mapper.NewInfo(tableName, tableSchema, &result)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create info: %w", err)
-		}
-		row, err := m.NewRow(info)
+func (s *Server) ListControllers(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListControllersArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	if args.CountOnly {
+		count, err := mcp.CountRows(ctx, client, &vswitch.Controller{})
 		if err != nil {
-			return nil, fmt.Errorf("failed to create row: %w", err)
+			return nil, err
 		}
+		return mcp.CountResult(count)
+	}
 
-		data = append(data, row)
+	results, page, err := mcp.ExecuteSelectQueryPaged(ctx, client, &vswitch.Controller{}, args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
 	}
 
-	var res mcpsdk.CallToolResultFor[map[string]any]
-	res.Content = []mcpsdk.Content{
-		&mcpsdk.TextContent{
-			Text: "success",
-		},
+	rows, err := mcp.MapRows(vswitch.ControllerTable, vswitch.DatabaseSchema(), results)
+	if err != nil {
+		return nil, err
 	}
-	res.StructuredContent = map[string]any{
-		"ports":   data,
-		"count":   len(results),
-		"context": "Ports are logical entities that group interfaces together within a bridge. Each port can have multiple interfaces and belongs to a specific bridge.",
+
+	result := mcp.ListResult{
+		Data:       map[string]any{"controllers": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Controllers define connections to OpenFlow controllers. Each controller specifies how Open vSwitch connects to external OpenFlow controllers for network control.",
 	}
-	return &res, nil
+
+	return mcp.NewResult(result)
 }
 
-func (s *Server) ListInterfaces(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListInterfacesArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) ListFlowTables(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListFlowTablesArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
-	}
-
-	portFilter := args.PortFilter
+	bridgeFilter := args.BridgeFilter
+	flowTable := &vswitch.FlowTable{}
 	var conditions []model.Condition
-	if portFilter != "" {
-		// First, get the port UUID
-		var ports []vswitch.Port
-		portCondition := model.Condition{
-			Field:    &(&vswitch.Port{}).Name,
+	if bridgeFilter != "" {
+		conditions = append(conditions, model.Condition{
+			Field:    &flowTable.ExternalIDs,
 			Function: ovsdb.ConditionEqual,
-			Value:    portFilter,
-		}
-		portSelectOps, portQueryID, portSelectErr := client.WhereAll(&vswitch.Port{}, portCondition).Select()
-		if portSelectErr != nil {
-			return nil, fmt.Errorf("failed to create port select operation: %w", portSelectErr)
-		}
+			Value:    map[string]string{"bridge": bridgeFilter},
+		})
+	}
 
-		portReply, err := client.Transact(ctx, portSelectOps...)
+	if args.CountOnly {
+		count, err := mcp.CountRows(ctx, client, flowTable, conditions...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to execute port transaction: %w", err)
+			return nil, err
 		}
+		return mcp.CountResult(count)
+	}
+
+	results, page, err := mcp.ExecuteSelectQueryPaged(ctx, client, flowTable, args.Limit, args.Offset, conditions...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := mcp.MapRows(vswitch.FlowTableTable, vswitch.DatabaseSchema(), results)
+	if err != nil {
+		return nil, err
+	}
+
+	result := mcp.ListResult{
+		Data:       map[string]any{"flow_tables": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Flow tables contain the forwarding rules for network traffic. Each flow table belongs to a bridge and contains multiple flow entries that define how packets should be processed.",
+	}
+
+	return mcp.NewResult(result)
+}
+
+func (s *Server) ListSSLConfigs(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListSSLConfigsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
 
-		err = client.GetSelectResults(portSelectOps, portReply, map[string]interface{}{portQueryID: &ports})
+	if args.CountOnly {
+		count, err := mcp.CountRows(ctx, client, &vswitch.SSL{})
 		if err != nil {
-			return nil, fmt.Errorf("failed to get port select results: %w", err)
+			return nil, err
 		}
+		return mcp.CountResult(count)
+	}
 
-		if len(ports) == 0 {
-			result := map[string]interface{}{
-				"interfaces": []vswitch.Interface{},
-				"count":      0,
-				"context":    "No port found with the specified filter.",
-			}
-			json, err := json.Marshal(result)
-			if err != nil {
-				return nil, err
-			}
-			return &mcpsdk.CallToolResult{
-				Content: []mcpsdk.Content{
-					&mcpsdk.TextContent{
-						Text: string(json),
-					},
-				},
-			}, nil
-		}
+	results, page, err := mcp.ExecuteSelectQueryPaged(ctx, client, &vswitch.SSL{}, args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, vswitch.Interface{}, conditions...)
+	rows, err := mcp.MapRows(vswitch.SSLTable, vswitch.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
 
-	result := map[string]interface{}{
-		"interfaces": results,
-		"count":      len(results),
-		"context":    "Interfaces represent the actual network connections and can be physical or virtual. Each interface belongs to a port and can have various configuration options.",
+	result := mcp.ListResult{
+		Data:       map[string]any{"ssl_configs": rows},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "SSL configurations define TLS settings for secure connections. These configurations are used for secure communication with OpenFlow controllers and other external services.",
 	}
 
-	json, err := json.Marshal(result)
+	return mcp.NewResult(result)
+}
+
+func (s *Server) ListOVSQoS(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListOVSQoSArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer client.Close()
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
+	query := mcp.ListQuery[vswitch.Port, vswitch.QoS]{
+		Model:     &vswitch.QoS{},
+		Table:     vswitch.QoSTable,
+		Schema:    vswitch.DatabaseSchema(),
+		Key:       "qos",
+		Context:   "QoS rows configure traffic shaping on the ports that reference them in their qos column. type is the Linux traffic control discipline, e.g. linux-htb or linux-hfsc, other_config holds its settings such as max-rate in bit/s, and queues maps queue numbers to Queue rows. Packets are sent to a queue by OpenFlow set_queue actions; queue 0 is the default.",
+		Limit:     args.Limit,
+		Offset:    args.Offset,
+		CountOnly: args.CountOnly,
+	}
+	if args.PortFilter != "" {
+		port := &vswitch.Port{}
+		query.Parent = &mcp.ParentFilter[vswitch.Port, vswitch.QoS]{
+			Model: port,
+			Field: &port.Name,
+			Value: args.PortFilter,
+			Kind:  "port",
+			// Only keep the QoS referenced by the port's qos column
+			Keep: func(port vswitch.Port, qos vswitch.QoS) bool {
+				return port.QOS != nil && *port.QOS == qos.UUID
 			},
-		},
-	}, nil
+		}
+	}
+
+	return mcp.ListWithParentFilter(ctx, client, query)
 }
 
-func (s *Server) ListManagers(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListManagersArgs]) (*mcpsdk.CallToolResult, error) {
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+func (s *Server) ListQueues(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListQueuesArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
-	err = client.Connect(ctx)
+
+	if args.CountOnly {
+		count, err := mcp.CountRows(ctx, client, &vswitch.Queue{})
+		if err != nil {
+			return nil, err
+		}
+		return mcp.CountResult(count)
+	}
+
+	results, page, err := mcp.ExecuteSelectQueryPaged(ctx, client, &vswitch.Queue{}, args.Limit, args.Offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+		return nil, err
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, vswitch.Manager{})
+	data, err := mcp.MapRows(vswitch.QueueTable, vswitch.DatabaseSchema(), results)
 	if err != nil {
 		return nil, err
 	}
 
-	result := map[string]interface{}{
-		"managers": results,
-		"count":    len(results),
-		"context":  "Managers define connections to OpenFlow controllers. Each manager specifies how Open vSwitch connects to external OpenFlow controllers for network control.",
+	return mcp.NewResult(mcp.ListResult{
+		Data:       map[string]any{"queues": data},
+		Count:      len(results),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "Queues are the classes of a QoS, which references them by queue number. other_config holds the queue's min-rate, max-rate, burst and priority, and dscp the DSCP value set on packets sent through it.",
+	})
+}
+
+func (s *Server) OVSInfo(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[OVSInfoArgs]) (*mcpsdk.CallToolResultFor[OVSInfoResult], error) {
+	client, err := s.Connect(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer client.Close()
 
-	json, err := json.Marshal(result)
+	results, err := mcp.ExecuteSelectQuery(ctx, client, &vswitch.OpenvSwitch{})
 	if err != nil {
 		return nil, err
 	}
+	if len(results) != 1 {
+		return nil, fmt.Errorf("expected 1 Open_vSwitch row, found %d", len(results))
+	}
+	root := results[0]
+
+	return mcp.NewResult(OVSInfoResult{
+		OVSVersion:      stringValue(root.OVSVersion),
+		DBVersion:       stringValue(root.DbVersion),
+		SystemType:      stringValue(root.SystemType),
+		SystemVersion:   stringValue(root.SystemVersion),
+		DPDKVersion:     stringValue(root.DpdkVersion),
+		DPDKInitialized: root.DpdkInitialized,
+		DatapathTypes:   root.DatapathTypes,
+		IfaceTypes:      root.IfaceTypes,
+		ExternalIDs:     root.ExternalIDs,
+		OtherConfig:     decodeOtherConfig(root.OtherConfig),
+		Context:         "The Open_vSwitch table holds a single row describing the Open vSwitch installation on this host: the OVS and database schema versions, the host system type, and the datapath and interface types this build supports. The other_config entries are the host-wide data-plane settings (hardware offload, DPDK, flow limits), well-known keys are explained and values that differ from the default or are risky are flagged.",
+	})
+}
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+type CheckOpenFlowVersionsArgs struct {
+	ControllerVersion string `json:"controller_version,omitempty" jsonschema:"the OpenFlow version the controller speaks, e.g. OpenFlow15, bridges that do not advertise it are flagged"`
 }
 
-func (s *Server) ListControllers(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListControllersArgs]) (*mcpsdk.CallToolResult, error) {
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+type ControllerVersionInfo struct {
+	Target      string `json:"target"`
+	IsConnected bool   `json:"is_connected"`
+	Role        string `json:"role,omitempty"`
+	State       string `json:"state,omitempty"`
+	LastError   string `json:"last_error,omitempty"`
+}
+
+type BridgeOpenFlowVersions struct {
+	Bridge      string                  `json:"bridge"`
+	Advertised  []string                `json:"advertised"`
+	Default     bool                    `json:"default"`
+	MaxVersion  string                  `json:"max_version"`
+	Controllers []ControllerVersionInfo `json:"controllers"`
+	Issues      []string                `json:"issues"`
+}
+
+type CheckOpenFlowVersionsResult struct {
+	Bridges []BridgeOpenFlowVersions `json:"bridges"`
+	Count   int                      `json:"count"`
+	Flagged int                      `json:"flagged"`
+	Context string                   `json:"context"`
+}
+
+// openFlowVersions are the OpenFlow versions in ascending order
+var openFlowVersions = []string{
+	vswitch.BridgeProtocolsOpenflow10,
+	vswitch.BridgeProtocolsOpenflow11,
+	vswitch.BridgeProtocolsOpenflow12,
+	vswitch.BridgeProtocolsOpenflow13,
+	vswitch.BridgeProtocolsOpenflow14,
+	vswitch.BridgeProtocolsOpenflow15,
+}
+
+// defaultOpenFlowVersions are the versions enabled when a bridge's protocols
+// column is empty
+var defaultOpenFlowVersions = openFlowVersions
+
+func (s *Server) CheckOpenFlowVersions(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[CheckOpenFlowVersionsArgs]) (*mcpsdk.CallToolResultFor[CheckOpenFlowVersionsResult], error) {
+	args := params.Arguments
+
+	if args.ControllerVersion != "" && !slices.Contains(openFlowVersions, args.ControllerVersion) {
+		return nil, fmt.Errorf("invalid controller_version %q, must be one of %s", args.ControllerVersion, strings.Join(openFlowVersions, ", "))
+	}
+
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
-	err = client.Connect(ctx)
+
+	bridges, err := mcp.ExecuteSelectQuery(ctx, client, &vswitch.Bridge{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+		return nil, err
+	}
+	controllers, err := mcp.ExecuteSelectQuery(ctx, client, &vswitch.Controller{})
+	if err != nil {
+		return nil, err
 	}
+	controllerByUUID := make(map[string]vswitch.Controller, len(controllers))
+	for _, c := range controllers {
+		controllerByUUID[c.UUID] = c
+	}
+
+	var results []BridgeOpenFlowVersions
+	flagged := 0
+	for _, bridge := range bridges {
+		result := BridgeOpenFlowVersions{
+			Bridge:      bridge.Name,
+			Advertised:  bridge.Protocols,
+			Controllers: []ControllerVersionInfo{},
+			Issues:      []string{},
+		}
+		if len(result.Advertised) == 0 {
+			result.Advertised = slices.Clone(defaultOpenFlowVersions)
+			result.Default = true
+		}
+		slices.SortFunc(result.Advertised, func(a, b string) int {
+			return slices.Index(openFlowVersions, a) - slices.Index(openFlowVersions, b)
+		})
+		result.MaxVersion = result.Advertised[len(result.Advertised)-1]
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, vswitch.Controller{})
+		if args.ControllerVersion != "" && !slices.Contains(result.Advertised, args.ControllerVersion) {
+			result.Issues = append(result.Issues, fmt.Sprintf("bridge does not advertise %s, a controller that only speaks %s cannot connect and one that also speaks older versions negotiates down to at most %s", args.ControllerVersion, args.ControllerVersion, result.MaxVersion))
+		}
+
+		for _, uuid := range bridge.Controller {
+			c, ok := controllerByUUID[uuid]
+			if !ok {
+				continue
+			}
+			info := ControllerVersionInfo{
+				Target:      c.Target,
+				IsConnected: c.IsConnected,
+				State:       c.Status["state"],
+				LastError:   c.Status["last_error"],
+			}
+			if c.Role != nil {
+				info.Role = *c.Role
+			}
+			// A failed version negotiation is reported by the rconn as
+			// EPROTO
+			if !c.IsConnected && info.LastError == "Protocol error" {
+				result.Issues = append(result.Issues, fmt.Sprintf("controller %s is disconnected with a protocol error, OpenFlow version negotiation likely failed", c.Target))
+			}
+			result.Controllers = append(result.Controllers, info)
+		}
+
+		if len(result.Issues) > 0 {
+			flagged++
+		}
+		results = append(results, result)
+	}
+
+	return mcp.NewResult(CheckOpenFlowVersionsResult{
+		Bridges: results,
+		Count:   len(results),
+		Flagged: flagged,
+		Context: "A bridge's protocols column lists the OpenFlow versions it advertises to controllers, when empty OpenFlow 1.0 through 1.5 are enabled. A connection uses the highest version both sides advertise and fails when they share none. Open vSwitch does not record the negotiated version, so a connected controller is using at most max_version; a controller disconnected with a protocol error usually failed version negotiation.",
+	})
+}
+
+type DeleteBridgeArgs struct {
+	Name string `json:"name" jsonschema:"the name of the bridge to delete"`
+}
+
+type FindInterfaceForPortArgs struct {
+	LogicalPort string `json:"logical_port" jsonschema:"the name of the OVN logical port, matched against the interface's external_ids:iface-id"`
+}
+
+type ListInterfaceErrorsArgs struct {
+	CountOnly bool `json:"count_only,omitempty" jsonschema:"only return the number of matching rows, not the rows themselves"`
+	Limit     int  `json:"limit,omitempty" jsonschema:"the maximum number of results to return, defaults to 100"`
+	Offset    int  `json:"offset,omitempty" jsonschema:"the number of results to skip, pass next_offset from the previous page to get the next one"`
+}
+
+type BridgeResult struct {
+	UUID    string `json:"uuid"`
+	Name    string `json:"name"`
+	Context string `json:"context"`
+}
+
+func (s *Server) CreateBridge(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[CreateBridgeArgs]) (*mcpsdk.CallToolResultFor[BridgeResult], error) {
+	args := params.Arguments
+
+	if args.Name == "" {
+		return nil, fmt.Errorf("bridge name must not be empty")
+	}
+
+	client, err := s.Connect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer client.Close()
 
-	result := map[string]interface{}{
-		"controllers": results,
-		"count":       len(results),
-		"context":     "Controllers define connections to OpenFlow controllers. Each controller specifies how Open vSwitch connects to external OpenFlow controllers for network control.",
+	bridgeModel := &vswitch.Bridge{}
+	existing, err := mcp.ExecuteSelectQuery(ctx, client, bridgeModel, model.Condition{
+		Field:    &bridgeModel.Name,
+		Function: ovsdb.ConditionEqual,
+		Value:    args.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("bridge %s already exists with UUID %s", args.Name, existing[0].UUID)
 	}
 
-	json, err := json.Marshal(result)
+	roots, err := mcp.ExecuteSelectQuery(ctx, client, &vswitch.OpenvSwitch{})
 	if err != nil {
 		return nil, err
 	}
+	if len(roots) != 1 {
+		return nil, fmt.Errorf("expected 1 Open_vSwitch row, found %d", len(roots))
+	}
+	root := roots[0]
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	if args.DatapathType != "" && len(root.DatapathTypes) > 0 && !slices.Contains(root.DatapathTypes, args.DatapathType) {
+		return nil, fmt.Errorf("datapath type %s is not supported, supported types are %v", args.DatapathType, root.DatapathTypes)
+	}
+
+	bridge := vswitch.Bridge{
+		UUID:         "new_bridge",
+		Name:         args.Name,
+		DatapathType: args.DatapathType,
+	}
+	insertOps, err := client.Create(&bridge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bridge insert operation: %w", err)
+	}
+
+	mutateOps, err := client.Where(&root).Mutate(&root, model.Mutation{
+		Field:   &root.Bridges,
+		Mutator: ovsdb.MutateOperationInsert,
+		Value:   []string{bridge.UUID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Open_vSwitch mutate operation: %w", err)
+	}
+
+	operations := append(insertOps, mutateOps...)
+	reply, err := mcp.TransactWrite(ctx, client, operations...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+	if _, err := ovsdb.CheckOperationResults(reply, operations); err != nil {
+		return nil, fmt.Errorf("failed to create bridge %s: %w", args.Name, err)
+	}
+
+	return mcp.NewResult(BridgeResult{
+		UUID:    reply[0].UUID.GoUUID,
+		Name:    args.Name,
+		Context: "The bridge was created and added to the Open_vSwitch table. Ports can now be added to it.",
+	})
 }
 
-func (s *Server) ListFlowTables(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListFlowTablesArgs]) (*mcpsdk.CallToolResult, error) {
+func (s *Server) DeleteBridge(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[DeleteBridgeArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
 	args := params.Arguments
 
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+	if args.Name == "" {
+		return nil, fmt.Errorf("bridge name must not be empty")
+	}
+
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
-	err = client.Connect(ctx)
+
+	bridgeModel := &vswitch.Bridge{}
+	bridges, err := mcp.ExecuteSelectQuery(ctx, client, bridgeModel, model.Condition{
+		Field:    &bridgeModel.Name,
+		Function: ovsdb.ConditionEqual,
+		Value:    args.Name,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+		return nil, err
 	}
 
-	bridgeFilter := args.BridgeFilter
-	var conditions []model.Condition
-	if bridgeFilter != "" {
-		conditions = append(conditions, model.Condition{
-			Field:    &(&vswitch.FlowTable{}).ExternalIDs,
-			Function: ovsdb.ConditionEqual,
-			Value:    map[string]string{"bridge": bridgeFilter},
-		})
+	if len(bridges) == 0 {
+		return &mcpsdk.CallToolResultFor[mcp.ListResult]{
+			IsError: true,
+			Content: []mcpsdk.Content{
+				&mcpsdk.TextContent{
+					Text: fmt.Sprintf("bridge %s does not exist", args.Name),
+				},
+			},
+			StructuredContent: mcp.ListResult{
+				Data:    map[string]any{"bridges": []map[string]any{}},
+				Count:   0,
+				Context: "No bridge found with the specified name.",
+			},
+		}, nil
 	}
+	bridge := bridges[0]
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, vswitch.FlowTable{}, conditions...)
+	roots, err := mcp.ExecuteSelectQuery(ctx, client, &vswitch.OpenvSwitch{})
 	if err != nil {
 		return nil, err
 	}
+	if len(roots) != 1 {
+		return nil, fmt.Errorf("expected 1 Open_vSwitch row, found %d", len(roots))
+	}
+	root := roots[0]
 
-	result := map[string]interface{}{
-		"flow_tables": results,
-		"count":       len(results),
-		"context":     "Flow tables contain the forwarding rules for network traffic. Each flow table belongs to a bridge and contains multiple flow entries that define how packets should be processed.",
+	deleteOps, err := client.Where(&bridge).Delete()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bridge delete operation: %w", err)
 	}
 
-	json, err := json.Marshal(result)
+	mutateOps, err := client.Where(&root).Mutate(&root, model.Mutation{
+		Field:   &root.Bridges,
+		Mutator: ovsdb.MutateOperationDelete,
+		Value:   []string{bridge.UUID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Open_vSwitch mutate operation: %w", err)
+	}
+
+	operations := append(deleteOps, mutateOps...)
+	reply, err := mcp.TransactWrite(ctx, client, operations...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+	if _, err := ovsdb.CheckOperationResults(reply, operations); err != nil {
+		return nil, fmt.Errorf("failed to delete bridge %s: %w", args.Name, err)
+	}
+
+	return mcp.NewResult(mcp.ListResult{
+		Data:    map[string]any{"bridges": []map[string]any{{"uuid": bridge.UUID, "name": bridge.Name}}},
+		Count:   1,
+		Context: "The bridge was deleted and removed from the Open_vSwitch table. Its ports and interfaces are garbage collected by OVSDB.",
+	})
+}
+
+func (s *Server) FindInterfaceForPort(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[FindInterfaceForPortArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	if args.LogicalPort == "" {
+		return nil, fmt.Errorf("logical_port must not be empty")
+	}
+
+	client, err := s.Connect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer client.Close()
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	iface := &vswitch.Interface{}
+	results, err := mcp.ExecuteSelectQuery(ctx, client, iface, model.Condition{
+		Field:    &iface.ExternalIDs,
+		Function: ovsdb.ConditionIncludes,
+		Value:    map[string]string{"iface-id": args.LogicalPort},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	locations, err := interfaceLocations(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := mcp.MapRows(vswitch.InterfaceTable, vswitch.DatabaseSchema(), results)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []map[string]any
+
+	for i, row := range rows {
+		location := locations[results[i].UUID]
+		data = append(data, map[string]any{
+			"interface": row,
+			"port":      location.Port,
+			"bridge":    location.Bridge,
+		})
+	}
+
+	summary := fmt.Sprintf("Found %d interfaces bound to logical port %s.", len(results), args.LogicalPort)
+	if len(results) == 0 {
+		summary = fmt.Sprintf("No interface on this host has external_ids:iface-id=%s, the logical port is not bound to this chassis.", args.LogicalPort)
+	}
+
+	return mcp.NewResult(mcp.ListResult{
+		Data:    map[string]any{"interfaces": data},
+		Count:   len(results),
+		Context: summary + " OVN binds a logical port to the OVS interface whose external_ids:iface-id matches the logical port name, the port and bridge show where the interface is attached.",
+	})
 }
 
-func (s *Server) ListSSLConfigs(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListSSLConfigsArgs]) (*mcpsdk.CallToolResult, error) {
-	client, err := client.NewOVSDBClient(s.dbModel, client.WithEndpoint(defaultEndpoint))
+func (s *Server) ListInterfaceErrors(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[ListInterfaceErrorsArgs]) (*mcpsdk.CallToolResultFor[mcp.ListResult], error) {
+	args := params.Arguments
+
+	client, err := s.Connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, err
 	}
 	defer client.Close()
 
-	err = client.Connect(ctx)
+	// OVSDB can't match on an optional column being set, so filter here
+	results, err := mcp.ExecuteSelectQuery(ctx, client, &vswitch.Interface{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OVSDB: %w", err)
+		return nil, err
 	}
 
-	results, err := mcp.ExecuteSelectQuery(ctx, client, vswitch.SSL{})
+	locations, err := interfaceLocations(ctx, client)
 	if err != nil {
 		return nil, err
 	}
 
-	result := map[string]interface{}{
-		"ssl_configs": results,
-		"count":       len(results),
-		"context":     "SSL configurations define TLS settings for secure connections. These configurations are used for secure communication with OpenFlow controllers and other external services.",
+	data := []map[string]any{}
+	for _, result := range results {
+		if result.Error == nil || *result.Error == "" {
+			continue
+		}
+		location := locations[result.UUID]
+		data = append(data, map[string]any{
+			"uuid":   result.UUID,
+			"name":   result.Name,
+			"type":   result.Type,
+			"port":   location.Port,
+			"bridge": location.Bridge,
+			"error":  *result.Error,
+			"reason": interfaceErrorReason(*result.Error),
+		})
+	}
+	if args.CountOnly {
+		return mcp.CountResult(len(data))
+	}
+
+	data, page := mcp.Paginate(data, args.Limit, args.Offset)
+
+	return mcp.NewResult(mcp.ListResult{
+		Data:       map[string]any{"interfaces": data},
+		Count:      len(data),
+		Total:      page.Total,
+		NextOffset: page.NextOffset,
+		Context:    "OVS sets the error column of an interface when it fails to configure it, for example when the network device does not exist or can't be added to the datapath. Interfaces with an error are not forwarding traffic.",
+	})
+}
+
+// interfaceErrorReasons explains the errno strings OVS most commonly reports
+// in the Interface error column
+var interfaceErrorReasons = []struct {
+	text   string
+	reason string
+}{
+	{"No such device", "the network device does not exist on the host, check that the veth, tap or physical device has been created"},
+	{"File exists", "the network device is already attached to another bridge or datapath"},
+	{"Operation not supported", "the interface type is not supported by the bridge's datapath"},
+	{"Device or resource busy", "the network device is in use by another driver or process"},
+	{"Permission denied", "ovs-vswitchd lacks the privileges to configure the network device"},
+	{"Invalid argument", "the interface options are invalid for this interface type"},
+	{"Address family not supported", "the kernel module for this interface type is not loaded"},
+}
+
+// interfaceErrorReason returns a human readable explanation for an
+// Interface error
+func interfaceErrorReason(errText string) string {
+	for _, r := range interfaceErrorReasons {
+		if strings.Contains(errText, r.text) {
+			return r.reason
+		}
 	}
+	return "unrecognized error, see the ovs-vswitchd log for details"
+}
 
-	json, err := json.Marshal(result)
+// interfaceLocation is the port and bridge an interface is attached to
+type interfaceLocation struct {
+	Port   string
+	Bridge string
+}
+
+// interfaceLocations returns the location of every interface keyed by the
+// interface UUID
+func interfaceLocations(ctx context.Context, client client.Client) (map[string]interfaceLocation, error) {
+	bridges, err := mcp.ExecuteSelectQuery(ctx, client, &vswitch.Bridge{})
+	if err != nil {
+		return nil, err
+	}
+	ports, err := mcp.ExecuteSelectQuery(ctx, client, &vswitch.Port{})
 	if err != nil {
 		return nil, err
 	}
 
-	return &mcpsdk.CallToolResult{
-		Content: []mcpsdk.Content{
-			&mcpsdk.TextContent{
-				Text: string(json),
-			},
-		},
-	}, nil
+	portBridges := make(map[string]string)
+	for _, bridge := range bridges {
+		for _, port := range bridge.Ports {
+			portBridges[port] = bridge.Name
+		}
+	}
+
+	locations := make(map[string]interfaceLocation)
+	for _, port := range ports {
+		for _, iface := range port.Interfaces {
+			locations[iface] = interfaceLocation{
+				Port:   port.Name,
+				Bridge: portBridges[port.UUID],
+			}
+		}
+	}
+	return locations, nil
+}
+
+// stringValue dereferences an optional OVSDB string column
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
 }
 
 // NewServer creates a new OVS vSwitchd MCP server instance
-func NewServer(host string, port int) (*Server, error) {
+func NewServer(host string, port int, opts ...mcp.Option) (*Server, error) {
 
-	// Create OVSDB client model using generated code
-	dbModel, err := vswitch.FullDatabaseModel()
+	// Use the OVSDB client model shared by every server in the process
+	dbModel, err := vswitch.DatabaseModel()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create database model: %w", err)
 	}
 
-	server := mcpsdk.NewServer(&mcpsdk.Implementation{
+	base, err := mcp.NewBaseServer(&mcpsdk.Implementation{
 		Name:    "ovs-vswitch-mcp",
 		Title:   "OVS vSwitch MCP Server",
 		Version: "0.1.0",
-	}, nil)
+	}, dbModel, defaultEndpoint, opts...)
+	if err != nil {
+		return nil, err
+	}
 
 	s := Server{
-		Server:  server,
-		dbModel: dbModel,
+		BaseServer: base,
 	}
 
 	// Register tools inline
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_bridges",
-		Description: "List all Open vSwitch bridges. Bridges are the main configuration entities in Open vSwitch that contain ports and interfaces.",
+		Description: "List all Open vSwitch bridges. Bridges are the main configuration entities in Open vSwitch that contain ports and interfaces. Set count_only to only get the number of matching rows.",
 	}, s.ListBridges)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_ports",
-		Description: "List all ports in Open vSwitch bridges. Ports are logical entities that group interfaces together within a bridge.",
+		Description: "List all ports in Open vSwitch bridges. Ports are logical entities that group interfaces together within a bridge. Set count_only to only get the number of matching rows.",
 	}, s.ListPorts)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_interfaces",
-		Description: "List all interfaces in Open vSwitch. Interfaces represent the actual network connections and can be physical or virtual.",
+		Description: "List all interfaces in Open vSwitch. Interfaces represent the actual network connections and can be physical or virtual. Set count_only to only get the number of matching rows.",
 	}, s.ListInterfaces)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_managers",
-		Description: "List all OpenFlow managers in Open vSwitch. Managers define connections to OpenFlow controllers.",
+		Description: "List all OpenFlow managers in Open vSwitch. Managers define connections to OpenFlow controllers. Set count_only to only get the number of matching rows.",
 	}, s.ListManagers)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_controllers",
-		Description: "List all OpenFlow controllers in Open vSwitch. Controllers define connections to OpenFlow controllers.",
+		Description: "List all OpenFlow controllers in Open vSwitch. Controllers define connections to OpenFlow controllers. Set count_only to only get the number of matching rows.",
 	}, s.ListControllers)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_flow_tables",
-		Description: "List all flow tables in Open vSwitch. Flow tables contain the forwarding rules for network traffic.",
+		Description: "List all flow tables in Open vSwitch. Flow tables contain the forwarding rules for network traffic. Set count_only to only get the number of matching rows.",
 	}, s.ListFlowTables)
 
-	mcpsdk.AddTool(s.Server, &mcpsdk.Tool{
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
 		Name:        "list_ssl_configs",
-		Description: "List all SSL configurations in Open vSwitch. SSL configurations define TLS settings for secure connections.",
+		Description: "List all SSL configurations in Open vSwitch. SSL configurations define TLS settings for secure connections. Set count_only to only get the number of matching rows.",
 	}, s.ListSSLConfigs)
 
-	return &s, nil
-}
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_qos",
+		Description: "List the QoS traffic shaping configurations in Open vSwitch, optionally only the one of a port. QoS rows set the shaping discipline, rates and queues of the ports that reference them. Set count_only to only get the number of matching rows.",
+	}, s.ListOVSQoS)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_queues",
+		Description: "List the queues in Open vSwitch. Queues are the classes of a QoS configuration, each with its own rate limits and priority. Set count_only to only get the number of matching rows.",
+	}, s.ListQueues)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_mirrors",
+		Description: "List the port mirrors in Open vSwitch, optionally only those of a bridge. Mirrors copy selected packets to an output port or VLAN for full packet capture. Set count_only to only get the number of matching rows.",
+	}, s.ListMirrors)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_netflow",
+		Description: "List the NetFlow configurations in Open vSwitch, optionally only that of a bridge. NetFlow exports a record of every flow to collectors, for traffic accounting. Set count_only to only get the number of matching rows.",
+	}, s.ListNetFlow)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_sflow",
+		Description: "List the sFlow configurations in Open vSwitch, optionally only that of a bridge. sFlow sends sampled packet headers and interface counters to collectors. Set count_only to only get the number of matching rows.",
+	}, s.ListSFlow)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_ipfix",
+		Description: "List the IPFIX configurations in Open vSwitch, optionally only that of a bridge. IPFIX exports flow records for sampled packets to collectors. Set count_only to only get the number of matching rows.",
+	}, s.ListIPFIX)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "ovs_info",
+		Description: "Report the Open vSwitch version and build information for this host, including the database schema version, system type, the supported datapath and interface types, and the decoded global other_config settings such as hw-offload and dpdk-init.",
+	}, s.OVSInfo)
+
+	mcp.AddWriteTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "create_bridge",
+		Description: "Create a new Open vSwitch bridge and attach it to the Open_vSwitch table. Fails if a bridge with the same name already exists. Returns the UUID of the new bridge. Set dry_run to get the OVSDB operations without running them.",
+	}, s.CreateBridge)
+
+	mcp.AddWriteTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "delete_bridge",
+		Description: "Delete an Open vSwitch bridge by name and remove its reference from the Open_vSwitch table. Its ports and interfaces are deleted with it. Set dry_run to get the OVSDB operations without running them.",
+	}, s.DeleteBridge)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "find_interface_for_port",
+		Description: "Find the Open vSwitch interface bound to an OVN logical port by matching external_ids:iface-id. Returns the interface with the port and bridge it is attached to.",
+	}, s.FindInterfaceForPort)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "find_port_by_mac",
+		Description: "Find where a MAC address is in Open vSwitch: the interfaces whose mac_in_use or mac column holds it, each with the port and bridge it is attached to. The MAC can be given in any case and with colons, dashes or dots.",
+	}, s.FindPortByMAC)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "list_interface_errors",
+		Description: "List all Open vSwitch interfaces that failed to attach, i.e. whose error column is set. Returns the interface type, port, bridge, the error text, and a likely reason. Set count_only to only get the number of matching rows.",
+	}, s.ListInterfaceErrors)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "check_openflow_versions",
+		Description: "Check the OpenFlow versions each bridge advertises against its controllers. Lists the advertised protocols (defaulted when the column is empty) and controller connection state, and flags bridges missing the controller's version or controllers that failed version negotiation.",
+	}, s.CheckOpenFlowVersions)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "bridge_l2_features",
+		Description: "Report the layer 2 features of each Open vSwitch bridge in one view: STP, RSTP and multicast snooping state and status, MAC learning tuning, BPDU forwarding and flood VLANs. Unset other_config settings are shown with their defaults.",
+	}, s.BridgeL2Features)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "ovsdb_select",
+		Description: "Run a select on any table of the Open vSwitch database with [column, function, value] conditions, which rows must all match unless match_all is false, optionally returning only some columns. An escape hatch for querying columns the other tools don't filter on; use get_schema to find the tables and columns. Unknown tables and columns are rejected with the valid names.",
+	}, s.OVSDBSelect)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "get_schema",
+		Description: "Get the schema of the Open vSwitch database: its tables, and each table's columns with their types, whether they are sets, maps or references to other tables, and the values allowed for enums. Optionally describe a single table. Useful for finding which columns exist before filtering on them. Needs no database connection.",
+	}, mcp.GetSchema(vswitch.DatabaseSchema()))
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "describe_schema",
+		Description: "List the tables of the Open vSwitch database with the type of each of their columns, as compact JSON keyed by table. A quick overview of what can be queried before choosing a tool; use get_schema for the details of a table. Needs no database connection.",
+	}, mcp.DescribeSchema(vswitch.DatabaseSchema()))
+
+	mcp.AddLongRunningTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "watch_table",
+		Description: "Watch one table of the Open vSwitch database (e.g. Interface) for a while and report its row inserts, updates and deletes as they happen. Each change is streamed to the client as a progress notification when the call has a progress token, and all of them are returned when the watch ends after duration_seconds (30 by default, at most 300) or max_events changes. Optionally only watch rows whose name matches a filter. The monitor is removed when the watch ends or the session closes.",
+	}, s.WatchTable)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "find_by_external_id_key",
+		Description: "Find the rows of every table in the Open vSwitch database that have an external_ids key, whatever its value, grouped by table. Optionally only match rows whose value for the key contains a substring. Useful for finding every object owned or labelled by a controller, e.g. by the k8s.ovn.org/owner key.",
+	}, s.FindByExternalIDKey)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "row_history",
+		Description: "Get the current value of any row in the Open vSwitch database by UUID, with its recent changes as timestamped before and after snapshots when the server is recording row history. OVSDB does not keep history itself, so only changes made since the server started recording are available.",
+	}, s.RowHistory)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "ping_database",
+		Description: "Check that the Open vSwitch database can be reached, with the connection latency and the schema version it serves. Use it before running other tools when the database may be down. Results are cached for a few seconds.",
+	}, s.PingDatabase)
+
+	mcp.AddTool(s.BaseServer, &mcpsdk.Tool{
+		Name:        "server_info",
+		Description: "Get the schema version of the Open vSwitch database and, from the ovsdb-server _Server database, whether it is standalone, clustered or a relay, whether the server is the cluster leader and connected to the cluster, and its cluster and server IDs. Use it to find the leader of a clustered database and the version to quote in bug reports.",
+	}, s.ServerInfo)
 
-// Start starts the MCP server on the specified address
-func (s *Server) Start(ctx context.Context, addr string) error {
-	// Create HTTP server using Streamable HTTP handler
-	streamableHandler := mcpsdk.NewStreamableHTTPHandler(func(request *http.Request) *mcpsdk.Server {
-		return s.Server
-	}, nil)
-
-	s.httpServer = &http.Server{
-		Addr:    addr,
-		Handler: streamableHandler,
-	}
-
-	// Start server in a goroutine
-	go func() {
-		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			// Log error if we had a logger
-		}
-	}()
-
-	return nil
-}
-
-// Stop stops the MCP server
-func (s *Server) Stop(ctx context.Context) error {
-	if s.httpServer != nil {
-		return s.httpServer.Shutdown(ctx)
-	}
-	return nil
+	return &s, nil
 }
@@ -0,0 +1,268 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/mapper"
+	"github.com/ovn-kubernetes/libovsdb/model"
+)
+
+// Defaults and limits for watch_table
+const (
+	defaultWatchDuration = 30 * time.Second
+	maxWatchDuration     = 5 * time.Minute
+	defaultWatchEvents   = 100
+)
+
+type WatchTableArgs struct {
+	Table           string `json:"table" jsonschema:"the table to watch, e.g. Logical_Switch"`
+	NameFilter      string `json:"name_filter,omitempty" jsonschema:"only report rows whose name column matches, for tables with a name column"`
+	MatchMode       string `json:"match,omitempty" jsonschema:"how name_filter is matched: exact (default), prefix, substring or regex, prefix and substring matches are case-insensitive"`
+	DurationSeconds int    `json:"duration_seconds,omitempty" jsonschema:"how long to watch for, defaults to 30 and at most 300"`
+	MaxEvents       int    `json:"max_events,omitempty" jsonschema:"stop watching after this many changes, defaults to 100"`
+}
+
+// tableWatch collects the changes to a monitored table. The inserts of the
+// rows present when the watch started, which the monitor's initial reply
+// delivers, are not changes and are skipped.
+type tableWatch struct {
+	table   string
+	mapper  mapper.Mapper
+	info    func(model.Model) (*mapper.Info, error)
+	matcher *NameMatcher
+
+	mu       sync.Mutex
+	baseline map[string]bool
+	ready    bool
+	changes  []RowChange
+	full     chan struct{}
+	max      int
+	notify   func(change RowChange, count int)
+}
+
+// setBaseline selects the rows of the table from the server and marks them
+// as present before the watch started. It is called before the monitor is
+// started rather than reading the client's cache once it has, as libovsdb
+// delivers the initial reply to the event handlers after Monitor returns
+// and by then the cache may also hold rows inserted since. A row inserted
+// between the select and the monitor is reported, as it is a change made
+// after the watch started.
+func (w *tableWatch) setBaseline(ctx context.Context, c client.Client) error {
+	baseline, err := selectUUIDs(ctx, c, w.table)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	w.baseline = baseline
+	w.ready = true
+	w.mu.Unlock()
+	return nil
+}
+
+func (w *tableWatch) row(m model.Model) (map[string]any, error) {
+	if m == nil {
+		return nil, nil
+	}
+	info, err := w.info(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create info: %w", err)
+	}
+	return w.mapper.NewRow(info)
+}
+
+// matches reports whether the row before or after the change has a
+// matching name
+func (w *tableWatch) matches(change RowChange) bool {
+	if w.matcher == nil {
+		return true
+	}
+	for _, row := range []map[string]any{change.Before, change.After} {
+		if name, ok := row["name"].(string); ok && w.matcher.Match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// record adds an update from the monitor to the watch
+func (w *tableWatch) record(u Update) error {
+	change := RowChange{
+		Time:  u.Queued,
+		Table: u.Table,
+		UUID:  u.UUID,
+	}
+	switch {
+	case u.Old == nil:
+		change.Op = RowInsert
+	case u.New == nil:
+		change.Op = RowDelete
+	default:
+		change.Op = RowUpdate
+	}
+	var err error
+	if change.Before, err = w.row(u.Old); err != nil {
+		return err
+	}
+	if change.After, err = w.row(u.New); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	if !w.ready || len(w.changes) >= w.max {
+		w.mu.Unlock()
+		return nil
+	}
+	if change.Op == RowInsert && w.baseline[u.UUID] {
+		// Part of the initial contents of the monitor
+		delete(w.baseline, u.UUID)
+		w.mu.Unlock()
+		return nil
+	}
+	delete(w.baseline, u.UUID)
+	if !w.matches(change) {
+		w.mu.Unlock()
+		return nil
+	}
+	w.changes = append(w.changes, change)
+	count := len(w.changes)
+	if count == w.max {
+		close(w.full)
+	}
+	w.mu.Unlock()
+
+	w.notify(change, count)
+	return nil
+}
+
+// WatchTable monitors a table for the duration of the call, sending each
+// change to the client as a progress notification when it asked for
+// progress, and returns the changes. The monitor is torn down when the
+// call returns, which is also the case when the session is closed.
+func (s *BaseServer) WatchTable(ctx context.Context, ss *mcpsdk.ServerSession, params *mcpsdk.CallToolParamsFor[WatchTableArgs]) (*mcpsdk.CallToolResultFor[map[string]any], error) {
+	args := params.Arguments
+
+	if args.Table == "" {
+		return nil, fmt.Errorf("table is required")
+	}
+	modelType, ok := s.dbModel.Types()[args.Table]
+	if !ok {
+		return nil, fmt.Errorf("unknown table %q", args.Table)
+	}
+	duration := defaultWatchDuration
+	if args.DurationSeconds < 0 {
+		return nil, fmt.Errorf("duration_seconds must not be negative")
+	} else if args.DurationSeconds > 0 {
+		duration = min(time.Duration(args.DurationSeconds)*time.Second, maxWatchDuration)
+	}
+	maxEvents := defaultWatchEvents
+	if args.MaxEvents > 0 {
+		maxEvents = args.MaxEvents
+	}
+
+	var matcher *NameMatcher
+	if args.NameFilter != "" {
+		var err error
+		if matcher, err = NewNameMatcher(args.NameFilter, args.MatchMode); err != nil {
+			return nil, err
+		}
+	}
+
+	// The watch needs a connection of its own, as its monitor must not
+	// change the table cache's shared connection
+	c, err := s.ConnectTo(ctx, s.dbModel, s.Endpoint(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	schema := c.Schema()
+	tableSchema := schema.Table(args.Table)
+	if matcher != nil {
+		if _, ok := tableSchema.Columns["name"]; !ok {
+			return nil, fmt.Errorf("table %s has no name column to filter by", args.Table)
+		}
+	}
+
+	token := params.GetProgressToken()
+	watch := &tableWatch{
+		table:  args.Table,
+		mapper: mapper.NewMapper(schema),
+		info: func(m model.Model) (*mapper.Info, error) {
+			return mapper.NewInfo(args.Table, tableSchema, m)
+		},
+		matcher: matcher,
+		full:    make(chan struct{}),
+		max:     maxEvents,
+		notify: func(change RowChange, count int) {
+			if ss == nil || token == nil {
+				return
+			}
+			message, err := json.Marshal(change)
+			if err != nil {
+				return
+			}
+			// The progress is the number of changes so far
+			if err := ss.NotifyProgress(ctx, &mcpsdk.ProgressNotificationParams{
+				ProgressToken: token,
+				Message:       string(message),
+				Progress:      float64(count),
+			}); err != nil {
+				s.Logger.Debug("Failed to send watch notification", "table", args.Table, "error", err)
+			}
+		},
+	}
+
+	watchCtx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	processor := NewUpdateProcessor(1, func(u Update) {
+		if u.Table != args.Table {
+			return
+		}
+		if err := watch.record(u); err != nil {
+			s.Logger.Warn("Failed to record watched change", "table", u.Table, "uuid", u.UUID, "error", err)
+		}
+	})
+	if err := watch.setBaseline(ctx, c); err != nil {
+		return nil, err
+	}
+	processor.Start(watchCtx)
+	c.Cache().AddEventHandler(processor)
+
+	m := reflect.New(modelType.Elem()).Interface().(model.Model)
+	if _, err := c.Monitor(ctx, c.NewMonitor(client.WithTable(m))); err != nil {
+		return nil, fmt.Errorf("failed to monitor table %s: %w", args.Table, err)
+	}
+	started := time.Now()
+
+	select {
+	case <-watchCtx.Done():
+	case <-watch.full:
+	}
+
+	watch.mu.Lock()
+	changes := watch.changes
+	watch.ready = false
+	watch.mu.Unlock()
+	if changes == nil {
+		changes = []RowChange{}
+	}
+
+	result := map[string]interface{}{
+		"table":              args.Table,
+		"changes":            changes,
+		"count":              len(changes),
+		"watched_seconds":    time.Since(started).Seconds(),
+		"max_events_reached": len(changes) >= maxEvents,
+		"context":            "The inserts, updates and deletes of the table's rows while it was watched, oldest first, with the row before and after each change in OVSDB notation. When the call was made with a progress token each change was also sent as a progress notification as it happened, with the change as the message. Rows present when the watch started are not reported.",
+	}
+
+	return NewResult(result)
+}
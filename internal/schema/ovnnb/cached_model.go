@@ -0,0 +1,37 @@
+package ovnnb
+
+import (
+	"sync"
+
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+)
+
+var (
+	databaseModelOnce sync.Once
+	databaseModel     model.ClientDBModel
+	databaseModelErr  error
+
+	databaseSchemaOnce sync.Once
+	databaseSchema     ovsdb.DatabaseSchema
+)
+
+// DatabaseModel returns the model built by FullDatabaseModel. The model is
+// never modified once built, so it is built once and shared by every client in
+// the process.
+func DatabaseModel() (model.ClientDBModel, error) {
+	databaseModelOnce.Do(func() {
+		databaseModel, databaseModelErr = FullDatabaseModel()
+	})
+	return databaseModel, databaseModelErr
+}
+
+// DatabaseSchema returns the schema parsed by Schema. Schema parses the
+// embedded JSON on every call, so it is parsed once and shared instead. The
+// schema is shared by every caller and must not be modified.
+func DatabaseSchema() ovsdb.DatabaseSchema {
+	databaseSchemaOnce.Do(func() {
+		databaseSchema = Schema()
+	})
+	return databaseSchema
+}
@@ -0,0 +1,69 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestACLFilterIntegration(t *testing.T) {
+	suite.Run(t, new(ACLFilterIntegrationTestSuite))
+}
+
+// ACLFilterIntegrationTestSuite checks that list_acls filters by direction
+// and action along with the switch
+type ACLFilterIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ACLFilterIntegrationTestSuite) TestDirectionAndAction() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(),
+		&ovnnbSchema.LogicalSwitch{Name: "sw1"},
+		&ovnnbSchema.LogicalSwitch{Name: "sw2"},
+	)
+
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	for _, acl := range []map[string]any{
+		{"switch_or_portgroup": "sw1", "direction": "to-lport", "action": "drop"},
+		{"switch_or_portgroup": "sw1", "direction": "to-lport", "action": "allow-related"},
+		{"switch_or_portgroup": "sw1", "direction": "from-lport", "action": "drop"},
+		{"switch_or_portgroup": "sw2", "direction": "to-lport", "action": "drop"},
+	} {
+		acl["priority"] = 1001
+		acl["match"] = "ip4"
+		callTool(suite.T(), session, "create_acl", acl)
+	}
+
+	count := func(args map[string]any) float64 {
+		args["count_only"] = true
+		return callTool(suite.T(), session, "list_acls", args)["count"].(float64)
+	}
+
+	suite.Equal(float64(3), count(map[string]any{"direction": "to-lport"}))
+	suite.Equal(float64(3), count(map[string]any{"action": "drop"}))
+	suite.Equal(float64(2), count(map[string]any{"direction": "to-lport", "action": "drop"}))
+	suite.Equal(float64(1), count(map[string]any{"switch_filter": "sw1", "direction": "to-lport", "action": "drop"}))
+	suite.Equal(float64(2), count(map[string]any{"switch_filter": "sw1", "action": "drop"}))
+
+	for _, args := range []map[string]any{
+		{"direction": "ingress"},
+		{"action": "deny"},
+	} {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "list_acls", Arguments: args})
+		suite.Require().NoError(err, "Failed to call list_acls")
+		suite.True(result.IsError, "Expected list_acls to reject %v", args)
+	}
+}
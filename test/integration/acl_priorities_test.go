@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestACLPrioritiesIntegration(t *testing.T) {
+	suite.Run(t, new(ACLPrioritiesIntegrationTestSuite))
+}
+
+// ACLPrioritiesIntegrationTestSuite checks that check_acl_priorities flags
+// the ACLs with priorities in reserved ranges
+type ACLPrioritiesIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ACLPrioritiesIntegrationTestSuite) TestReservedRanges() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	acl := func(uuid string, priority int, match string) *ovnnbSchema.ACL {
+		return &ovnnbSchema.ACL{UUID: uuid, Priority: priority, Direction: "to-lport", Match: match, Action: "allow"}
+	}
+	endpoint := seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(),
+		acl("acl1", 1001, "ip4.src == 10.0.0.1"),
+		acl("acl2", 5000, "ip4.src == 10.0.0.2"),
+		acl("acl3", 1500, "ip4.src == 10.0.0.3"),
+		acl("acl4", 0, "ip4.src == 10.0.0.4"),
+		&ovnnbSchema.LogicalSwitch{Name: "sw1", ACLs: []string{"acl1", "acl2", "acl3", "acl4"}},
+	)
+
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	// flagged returns the matches of the flagged ACLs and their violations
+	flagged := func(result map[string]any) map[string][]any {
+		flagged := make(map[string][]any)
+		if result["flagged_acls"] == nil {
+			return flagged
+		}
+		for _, f := range result["flagged_acls"].([]any) {
+			acl := f.(map[string]any)
+			flagged[acl["match"].(string)] = acl["violations"].([]any)
+		}
+		return flagged
+	}
+
+	// The default ranges are those of ovn-kubernetes
+	result := callTool(suite.T(), session, "check_acl_priorities", map[string]any{})
+	suite.Equal(float64(4), result["checked"])
+	suite.Equal(float64(2), result["count"])
+	suite.Equal(map[string][]any{
+		"ip4.src == 10.0.0.1": {"reserved range 1000-1001: ovn-kubernetes network policy default deny and allow"},
+		"ip4.src == 10.0.0.2": {"reserved range 2000-10000: ovn-kubernetes egress firewall"},
+	}, flagged(result))
+
+	result = callTool(suite.T(), session, "check_acl_priorities", map[string]any{
+		"reserved_ranges": []any{map[string]any{"min": 1400, "max": 1600, "reason": "internal"}},
+	})
+	suite.Equal(map[string][]any{
+		"ip4.src == 10.0.0.3": {"reserved range 1400-1600: internal"},
+	}, flagged(result))
+
+	invalid, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "check_acl_priorities",
+		Arguments: map[string]any{"reserved_ranges": []any{map[string]any{"min": 1600, "max": 1400}}},
+	})
+	suite.Require().NoError(err, "Failed to call check_acl_priorities")
+	suite.True(invalid.IsError, "Expected a range with min above max to be rejected")
+}
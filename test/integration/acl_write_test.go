@@ -0,0 +1,162 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestACLWriteIntegration(t *testing.T) {
+	suite.Run(t, new(ACLWriteIntegrationTestSuite))
+}
+
+// ACLWriteIntegrationTestSuite checks that create_acl and delete_acl keep
+// the ACL and the references to it consistent
+type ACLWriteIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ACLWriteIntegrationTestSuite) TestCreateAndDelete() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(),
+		&ovnnbSchema.LogicalSwitch{Name: "sw1"},
+		&ovnnbSchema.PortGroup{Name: "pg1"},
+	)
+
+	c, err := client.NewOVSDBClient(dbModel, client.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create OVSDB client")
+	suite.Require().NoError(c.Connect(ctx), "Failed to connect to OVSDB")
+	defer c.Close()
+
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	// switchACLs and portGroupACLs return the ACLs referenced by the switch
+	// or port group
+	switchACLs := func(name string) []string {
+		switches, err := mcpserver.ExecuteSelectQuery(ctx, c, &ovnnbSchema.LogicalSwitch{})
+		suite.Require().NoError(err, "Failed to select switches")
+		for _, ls := range switches {
+			if ls.Name == name {
+				return ls.ACLs
+			}
+		}
+		return nil
+	}
+	portGroupACLs := func(name string) []string {
+		portGroups, err := mcpserver.ExecuteSelectQuery(ctx, c, &ovnnbSchema.PortGroup{})
+		suite.Require().NoError(err, "Failed to select port groups")
+		for _, pg := range portGroups {
+			if pg.Name == name {
+				return pg.ACLs
+			}
+		}
+		return nil
+	}
+
+	createACL := func(parent string) string {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name: "create_acl",
+			Arguments: map[string]any{
+				"switch_or_portgroup": parent,
+				"direction":           "to-lport",
+				"priority":            1001,
+				"match":               "ip4.src == 10.0.0.0/24",
+				"action":              "drop",
+			},
+		})
+		suite.Require().NoError(err, "Failed to call create_acl")
+		suite.Require().False(result.IsError, "Expected create_acl to succeed: %v", result.Content)
+		structured, ok := result.StructuredContent.(map[string]any)
+		suite.Require().True(ok, "Expected structured content, got %T", result.StructuredContent)
+		uuid, ok := structured["uuid"].(string)
+		suite.Require().True(ok, "Expected uuid, got %T", structured["uuid"])
+		return uuid
+	}
+
+	switchACL := createACL("sw1")
+	suite.Equal([]string{switchACL}, switchACLs("sw1"))
+	portGroupACL := createACL("pg1")
+	suite.Equal([]string{portGroupACL}, portGroupACLs("pg1"))
+
+	acls, err := mcpserver.ExecuteSelectQuery(ctx, c, &ovnnbSchema.ACL{})
+	suite.Require().NoError(err, "Failed to select ACLs")
+	suite.Require().Len(acls, 2)
+	for _, acl := range acls {
+		suite.Equal("to-lport", acl.Direction)
+		suite.Equal(1001, acl.Priority)
+		suite.Equal("drop", acl.Action)
+	}
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "delete_acl",
+		Arguments: map[string]any{"uuid": switchACL},
+	})
+	suite.Require().NoError(err, "Failed to call delete_acl")
+	suite.Require().False(result.IsError, "Expected delete_acl to succeed: %v", result.Content)
+	suite.Empty(switchACLs("sw1"))
+	suite.Equal([]string{portGroupACL}, portGroupACLs("pg1"))
+
+	acls, err = mcpserver.ExecuteSelectQuery(ctx, c, &ovnnbSchema.ACL{})
+	suite.Require().NoError(err, "Failed to select ACLs")
+	suite.Require().Len(acls, 1)
+	suite.Equal(portGroupACL, acls[0].UUID)
+
+	// Deleting it again is reported as not found
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "delete_acl",
+		Arguments: map[string]any{"uuid": switchACL},
+	})
+	suite.Require().NoError(err, "Failed to call delete_acl")
+	suite.True(result.IsError, "Expected deleting a missing ACL to fail")
+}
+
+func (suite *ACLWriteIntegrationTestSuite) TestInvalidArguments() {
+	ctx := context.Background()
+
+	server, err := ovnnb.NewServer("localhost", 0)
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	valid := map[string]any{
+		"switch_or_portgroup": "sw1",
+		"direction":           "to-lport",
+		"priority":            1001,
+		"match":               "ip4",
+		"action":              "drop",
+	}
+	for field, value := range map[string]any{
+		"direction": "ingress",
+		"action":    "deny",
+		"priority":  40000,
+		"match":     "",
+	} {
+		args := make(map[string]any, len(valid))
+		for k, v := range valid {
+			args[k] = v
+		}
+		args[field] = value
+
+		// Validation fails before connecting to the database, which is not
+		// running
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "create_acl",
+			Arguments: args,
+		})
+		suite.Require().NoError(err, "Failed to call create_acl")
+		suite.True(result.IsError, "Expected invalid %s to be rejected", field)
+		suite.NotContains(result.Content[0].(*mcp.TextContent).Text, "connect", "Expected invalid %s to be rejected before connecting", field)
+	}
+}
@@ -0,0 +1,112 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnsb"
+	ovnsbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestBindingChurnIntegration(t *testing.T) {
+	suite.Run(t, new(BindingChurnIntegrationTestSuite))
+}
+
+// BindingChurnIntegrationTestSuite checks that binding_churn counts every
+// move of a port binding between chassis recorded in the row history
+type BindingChurnIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *BindingChurnIntegrationTestSuite) TestFlappingBinding() {
+	ctx := context.Background()
+
+	dbModel, err := ovnsbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := startDatabase(suite.T(), dbModel, ovnsbSchema.Schema())
+	ch1, ch2 := "ch1", "ch2"
+	uuids := insertRows(suite.T(), dbModel, endpoint,
+		&ovnsbSchema.Encap{UUID: "encap1", Type: ovnsbSchema.EncapTypeGeneve, IP: "192.168.0.1", ChassisName: "ch1"},
+		&ovnsbSchema.Encap{UUID: "encap2", Type: ovnsbSchema.EncapTypeGeneve, IP: "192.168.0.2", ChassisName: "ch2"},
+		&ovnsbSchema.Chassis{UUID: ch1, Name: "ch1", Encaps: []string{"encap1"}},
+		&ovnsbSchema.Chassis{UUID: ch2, Name: "ch2", Encaps: []string{"encap2"}},
+		&ovnsbSchema.DatapathBinding{UUID: "dp", TunnelKey: 1, ExternalIDs: map[string]string{"name": "sw1"}},
+		&ovnsbSchema.PortBinding{UUID: "pod1", LogicalPort: "pod1", Datapath: "dp", TunnelKey: 1, Chassis: &ch1},
+		&ovnsbSchema.PortBinding{UUID: "pod2", LogicalPort: "pod2", Datapath: "dp", TunnelKey: 2, Chassis: &ch1},
+	)
+	ch1UUID, ch2UUID := uuids[2], uuids[3]
+
+	server, err := ovnsb.NewServer("localhost", 8099, mcpserver.WithEndpoint(endpoint), mcpserver.WithRowHistory(100))
+	suite.Require().NoError(err, "Failed to create server")
+	suite.Require().NoError(server.Start(ctx, "localhost:8099"), "Failed to start server")
+	defer server.Stop(ctx)
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	c, err := client.NewOVSDBClient(dbModel, client.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create OVSDB client")
+	suite.Require().NoError(c.Connect(ctx), "Failed to connect to OVSDB")
+	defer c.Close()
+
+	// pod1 flaps between the chassis in quick succession, moving six times,
+	// and pod2 is released from ch1
+	pod1 := &ovnsbSchema.PortBinding{UUID: uuids[5]}
+	for i := 0; i < 6; i++ {
+		chassis := ch2UUID
+		if i%2 == 1 {
+			chassis = ch1UUID
+		}
+		pod1.Chassis = &chassis
+		updateRow(suite.T(), c, pod1, &pod1.Chassis)
+	}
+	pod2 := &ovnsbSchema.PortBinding{UUID: uuids[6]}
+	updateRow(suite.T(), c, pod2, &pod2.Chassis)
+
+	var result map[string]any
+	suite.Require().Eventually(func() bool {
+		result = callTool(suite.T(), session, "binding_churn", map[string]any{})
+		return result["changes"] == float64(7)
+	}, 5*time.Second, 10*time.Millisecond, "Expected every move to be recorded")
+	suite.Equal(true, result["complete"])
+	suite.Equal(float64(2), result["chassis_count"])
+
+	chassis := result["chassis"].([]any)
+	suite.Require().Len(chassis, 2)
+	first := chassis[0].(map[string]any)
+	suite.Equal("ch1", first["chassis"], "Expected the chassis with the most churn first")
+	suite.Equal(ch1UUID, first["chassis_uuid"])
+	suite.Equal(float64(3), first["added"])
+	suite.Equal(float64(4), first["removed"])
+	suite.Equal(float64(7), first["total"])
+	second := chassis[1].(map[string]any)
+	suite.Equal("ch2", second["chassis"])
+	suite.Equal(float64(3), second["added"])
+	suite.Equal(float64(3), second["removed"])
+	suite.Equal(float64(6), second["total"])
+
+	result = callTool(suite.T(), session, "binding_churn", map[string]any{"top": 1})
+	suite.Len(result["chassis"], 1)
+	suite.Equal(float64(2), result["chassis_count"])
+}
+
+func (suite *BindingChurnIntegrationTestSuite) TestWithoutHistory() {
+	ctx := context.Background()
+
+	server, err := ovnsb.NewServer("localhost", 0)
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "binding_churn",
+		Arguments: map[string]any{},
+	})
+	suite.Require().NoError(err, "Failed to call binding_churn")
+	suite.True(result.IsError, "Expected binding_churn to fail without row history")
+	suite.Contains(result.Content[0].(*mcp.TextContent).Text, "row history")
+}
@@ -0,0 +1,106 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCacheIntegration(t *testing.T) {
+	suite.Run(t, new(CacheIntegrationTestSuite))
+}
+
+// CacheIntegrationTestSuite checks that list tools are served from the
+// table cache and see changes made after it was populated
+type CacheIntegrationTestSuite struct {
+	suite.Suite
+}
+
+// lockedBuffer is a buffer the server's logger can write to while the test
+// reads it
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (suite *CacheIntegrationTestSuite) TestListFromCache() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(),
+		&ovnnbSchema.LogicalSwitchPort{UUID: "port1", Name: "sw1-port1"},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "port2", Name: "sw1-port2"},
+		&ovnnbSchema.LogicalSwitch{UUID: "sw1", Name: "sw1", Ports: []string{"port1", "port2"}},
+		&ovnnbSchema.LogicalSwitch{Name: "sw2"},
+	)
+
+	logs := &lockedBuffer{}
+	logger := slog.New(slog.NewTextHandler(logs, nil))
+	server, err := ovnnb.NewServer("localhost", 8093,
+		mcpserver.WithEndpoint(endpoint),
+		mcpserver.WithLogger(logger),
+		mcpserver.WithCache("Logical_Switch"))
+	suite.Require().NoError(err, "Failed to create server")
+	suite.Require().NoError(server.Start(ctx, "localhost:8093"), "Failed to start server")
+	defer server.Stop(ctx)
+
+	suite.Eventually(func() bool {
+		return strings.Contains(logs.String(), "Serving tables from the cache")
+	}, 10*time.Second, 10*time.Millisecond, "Expected the cache to be populated")
+
+	mcpClient := mcp.NewClient(&mcp.Implementation{Name: "ovsdb-mcp-test-client", Version: "1.0.0"}, nil)
+	session, err := mcpClient.Connect(ctx, mcp.NewStreamableClientTransport("http://localhost:8093"+mcpserver.MCPPath, nil))
+	suite.Require().NoError(err, "Failed to connect to MCP server")
+	defer session.Close()
+
+	list := func(tool string, args map[string]any) float64 {
+		structured := callTool(suite.T(), session, tool, args)
+		count, _ := structured["count"].(float64)
+		return count
+	}
+
+	suite.Equal(float64(2), list("list_logical_switches", map[string]any{}))
+	suite.Equal(float64(1), list("list_logical_switches", map[string]any{"name_filter": "sw1"}))
+
+	// Ports are not cached, but their parent switch is read from the cache
+	suite.Equal(float64(2), list("list_logical_switch_ports", map[string]any{"switch_filter": "sw1"}))
+
+	// Changes made after the cache was populated are seen through the
+	// monitor
+	insertRows(suite.T(), dbModel, endpoint, &ovnnbSchema.LogicalSwitch{Name: "sw3"})
+	suite.Eventually(func() bool {
+		return list("list_logical_switches", map[string]any{}) == 3
+	}, 5*time.Second, 10*time.Millisecond, "Expected the new switch to be listed")
+
+	suite.Equal(float64(3), list("list_logical_switches", map[string]any{"count_only": true}))
+}
+
+func (suite *CacheIntegrationTestSuite) TestUnknownTable() {
+	_, err := ovnnb.NewServer("localhost", 0, mcpserver.WithCache("Bridge"))
+	suite.Require().Error(err, "Expected a table of another database to be rejected")
+	suite.Contains(err.Error(), fmt.Sprintf("%q", "Bridge"))
+}
@@ -0,0 +1,111 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnsb"
+	ovnsbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestChassisFilterIntegration(t *testing.T) {
+	suite.Run(t, new(ChassisFilterIntegrationTestSuite))
+}
+
+// ChassisFilterIntegrationTestSuite checks that list_chassis finds chassis
+// by the hostname of their node and their tunnel IP, and that their
+// Chassis_Private rows report whether they are up to date
+type ChassisFilterIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ChassisFilterIntegrationTestSuite) TestHostnameAndEncapIP() {
+	ctx := context.Background()
+
+	dbModel, err := ovnsbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := seedDatabase(suite.T(), dbModel, ovnsbSchema.Schema(),
+		&ovnsbSchema.Encap{UUID: "encap1", Type: ovnsbSchema.EncapTypeGeneve, IP: "192.168.0.1", ChassisName: "ch1"},
+		&ovnsbSchema.Encap{UUID: "encap2", Type: ovnsbSchema.EncapTypeGeneve, IP: "192.168.0.2", ChassisName: "ch2"},
+		&ovnsbSchema.Chassis{Name: "ch1", Hostname: "node1", Encaps: []string{"encap1"}},
+		// Only set in external_ids, as some integrations do
+		&ovnsbSchema.Chassis{Name: "ch2", ExternalIDs: map[string]string{"hostname": "node2"}, Encaps: []string{"encap2"}},
+	)
+
+	server, err := ovnsb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	names := func(args map[string]any) []string {
+		structured := callTool(suite.T(), session, "list_chassis", args)
+		data, ok := structured["data"].(map[string]any)
+		suite.Require().True(ok, "Expected data, got %T", structured["data"])
+		chassis, ok := data["chassis"].([]any)
+		suite.Require().True(ok, "Expected chassis, got %T", data["chassis"])
+		names := []string{}
+		for _, ch := range chassis {
+			names = append(names, ch.(map[string]any)["name"].(string))
+		}
+		return names
+	}
+
+	suite.Equal([]string{"ch1"}, names(map[string]any{"hostname": "node1"}))
+	suite.Equal([]string{"ch2"}, names(map[string]any{"hostname": "node2"}))
+	suite.Equal([]string{"ch2"}, names(map[string]any{"encap_ip": "192.168.0.2"}))
+	suite.Equal([]string{"ch1"}, names(map[string]any{"name_filter": "ch1", "encap_ip": "192.168.0.1"}))
+	suite.Empty(names(map[string]any{"name_filter": "ch1", "encap_ip": "192.168.0.2"}))
+	suite.Empty(names(map[string]any{"hostname": "node3"}))
+	suite.Empty(names(map[string]any{"encap_ip": "192.168.0.3"}))
+}
+
+func (suite *ChassisFilterIntegrationTestSuite) TestChassisPrivate() {
+	ctx := context.Background()
+
+	dbModel, err := ovnsbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+
+	ch1 := "ch1-uuid"
+	endpoint := seedDatabase(suite.T(), dbModel, ovnsbSchema.Schema(),
+		&ovnsbSchema.SBGlobal{NbCfg: 7},
+		&ovnsbSchema.Encap{UUID: "encap1", Type: ovnsbSchema.EncapTypeGeneve, IP: "192.168.0.1", ChassisName: "ch1"},
+		&ovnsbSchema.Encap{UUID: "encap2", Type: ovnsbSchema.EncapTypeGeneve, IP: "192.168.0.2", ChassisName: "ch2"},
+		&ovnsbSchema.Encap{UUID: "encap3", Type: ovnsbSchema.EncapTypeGeneve, IP: "192.168.0.3", ChassisName: "ch3"},
+		&ovnsbSchema.Chassis{UUID: ch1, Name: "ch1", Encaps: []string{"encap1"}},
+		&ovnsbSchema.Chassis{Name: "ch2", Encaps: []string{"encap2"}},
+		&ovnsbSchema.Chassis{Name: "ch3", Encaps: []string{"encap3"}},
+		&ovnsbSchema.ChassisPrivate{Name: "ch1", Chassis: &ch1, NbCfg: 7},
+		&ovnsbSchema.ChassisPrivate{Name: "ch2", NbCfg: 5},
+	)
+
+	server, err := ovnsb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	rows := func(name, key string, args map[string]any) map[string]map[string]any {
+		data := callTool(suite.T(), session, name, args)["data"].(map[string]any)
+		byName := map[string]map[string]any{}
+		for _, row := range data[key].([]any) {
+			byName[row.(map[string]any)["name"].(string)] = row.(map[string]any)
+		}
+		return byName
+	}
+
+	private := rows("list_chassis_private", "chassis_private", map[string]any{})
+	suite.Require().Len(private, 2)
+	suite.Equal(true, private["ch1"]["up_to_date"])
+	suite.Equal(false, private["ch2"]["up_to_date"])
+
+	chassis := rows("list_chassis", "chassis", map[string]any{"include_private": true})
+	suite.Require().Len(chassis, 3)
+	suite.Equal(float64(7), chassis["ch1"]["private"].(map[string]any)["nb_cfg"])
+	suite.Equal(true, chassis["ch1"]["private"].(map[string]any)["up_to_date"])
+	suite.Equal(false, chassis["ch2"]["private"].(map[string]any)["up_to_date"])
+	suite.Nil(chassis["ch3"]["private"], "Expected a chassis without a Chassis_Private row to have none")
+
+	chassis = rows("list_chassis", "chassis", map[string]any{})
+	suite.NotContains(chassis["ch1"], "private")
+}
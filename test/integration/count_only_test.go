@@ -0,0 +1,58 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCountOnlyIntegration(t *testing.T) {
+	suite.Run(t, new(CountOnlyIntegrationTestSuite))
+}
+
+// CountOnlyIntegrationTestSuite checks that list tools called with
+// count_only return the number of rows they would list, without the rows
+type CountOnlyIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *CountOnlyIntegrationTestSuite) TestCountOnly() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+
+	// Two switches, one with three ports and one with one
+	endpoint := seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(),
+		&ovnnbSchema.LogicalSwitchPort{UUID: "port11", Name: "sw1-port1"},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "port12", Name: "sw1-port2"},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "port13", Name: "sw1-port3"},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "port21", Name: "sw2-port1"},
+		&ovnnbSchema.LogicalSwitch{UUID: "sw1", Name: "sw1", Ports: []string{"port11", "port12", "port13"}},
+		&ovnnbSchema.LogicalSwitch{UUID: "sw2", Name: "sw2", Ports: []string{"port21"}},
+	)
+
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	count := func(tool string, args map[string]any) float64 {
+		args["count_only"] = true
+		structured := callTool(suite.T(), session, tool, args)
+		suite.NotContains(structured, "data", "Expected %s not to return the rows", tool)
+		n, ok := structured["count"].(float64)
+		suite.Require().True(ok, "Expected count, got %T", structured["count"])
+		return n
+	}
+
+	suite.Equal(float64(2), count("list_logical_switches", map[string]any{}))
+	suite.Equal(float64(1), count("list_logical_switches", map[string]any{"name_filter": "sw1"}))
+	suite.Equal(float64(4), count("list_logical_switch_ports", map[string]any{}))
+	suite.Equal(float64(3), count("list_logical_switch_ports", map[string]any{"switch_filter": "sw1"}))
+	suite.Equal(float64(0), count("list_acls", map[string]any{}))
+}
@@ -0,0 +1,124 @@
+package integration
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/go-logr/logr"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/database/inmemory"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+	"github.com/ovn-kubernetes/libovsdb/server"
+	"github.com/stretchr/testify/require"
+)
+
+// startDatabase serves an empty database for dbModel on a unix socket and
+// returns its endpoint
+func startDatabase(t *testing.T, dbModel model.ClientDBModel, schema ovsdb.DatabaseSchema) string {
+	databaseModel, errs := model.NewDatabaseModel(schema, dbModel)
+	require.Empty(t, errs, "Failed to create database model")
+
+	db := inmemory.NewDatabase(map[string]model.ClientDBModel{schema.Name: dbModel}, nil)
+	logger := logr.Discard()
+	ovsdbServer, err := server.NewOvsdbServer(db, &logger, databaseModel)
+	require.NoError(t, err, "Failed to create OVSDB server")
+
+	socket := filepath.Join(t.TempDir(), "db.sock")
+	go func() {
+		_ = ovsdbServer.Serve("unix", socket)
+	}()
+	t.Cleanup(ovsdbServer.Close)
+	require.Eventually(t, ovsdbServer.Ready, 5*time.Second, 10*time.Millisecond, "OVSDB server did not start")
+
+	return "unix:" + socket
+}
+
+// seedDatabase serves a database for dbModel holding the rows of models,
+// inserted in one transaction, and returns its endpoint
+func seedDatabase(t *testing.T, dbModel model.ClientDBModel, schema ovsdb.DatabaseSchema, models ...model.Model) string {
+	endpoint := startDatabase(t, dbModel, schema)
+	insertRows(t, dbModel, endpoint, models...)
+	return endpoint
+}
+
+// insertRows inserts the rows of models in one transaction into the
+// database at endpoint and returns their UUIDs, in the order of models
+func insertRows(t *testing.T, dbModel model.ClientDBModel, endpoint string, models ...model.Model) []string {
+	if len(models) == 0 {
+		return nil
+	}
+	ctx := context.Background()
+	c, err := client.NewOVSDBClient(dbModel, client.WithEndpoint(endpoint))
+	require.NoError(t, err, "Failed to create OVSDB client")
+	require.NoError(t, c.Connect(ctx), "Failed to connect to OVSDB")
+	defer c.Close()
+
+	var ops []ovsdb.Operation
+	for _, m := range models {
+		createOps, err := c.Create(m)
+		require.NoError(t, err, "Failed to create insert operation")
+		ops = append(ops, createOps...)
+	}
+	reply, err := c.Transact(ctx, ops...)
+	require.NoError(t, err, "Failed to insert rows")
+	_, err = ovsdb.CheckOperationResults(reply, ops)
+	require.NoError(t, err, "Failed to insert rows")
+
+	uuids := make([]string, 0, len(ops))
+	for _, r := range reply[:len(ops)] {
+		uuids = append(uuids, r.UUID.GoUUID)
+	}
+	return uuids
+}
+
+// updateRow updates the columns of the row of m pointed to by fields, which
+// must point into m, to their values in m over c
+func updateRow(t *testing.T, c client.Client, m model.Model, fields ...any) {
+	ctx := context.Background()
+	ops, err := c.Where(m).Update(m, fields...)
+	require.NoError(t, err, "Failed to create update operation")
+	reply, err := c.Transact(ctx, ops...)
+	require.NoError(t, err, "Failed to update row")
+	_, err = ovsdb.CheckOperationResults(reply, ops)
+	require.NoError(t, err, "Failed to update row")
+}
+
+// callTool calls the tool name with args over session and returns its
+// structured content, failing the test unless the call succeeds
+func callTool(t *testing.T, session *mcp.ClientSession, name string, args map[string]any) map[string]any {
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      name,
+		Arguments: args,
+	})
+	require.NoError(t, err, "Failed to call %s", name)
+	require.False(t, result.IsError, "Expected %s to succeed: %v", name, result.Content)
+	structured, ok := result.StructuredContent.(map[string]any)
+	require.True(t, ok, "Expected structured content, got %T", result.StructuredContent)
+	return structured
+}
+
+// connect returns a client session for server over an in-memory transport
+func connect(t *testing.T, ctx context.Context, server *mcpserver.BaseServer) *mcp.ClientSession {
+	return connectServer(t, ctx, server.Server)
+}
+
+// connectServer connects an MCP client to server over an in-memory transport
+func connectServer(t *testing.T, ctx context.Context, server *mcp.Server) *mcp.ClientSession {
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	_, err := server.Connect(ctx, serverTransport)
+	require.NoError(t, err, "Failed to connect server")
+
+	mcpClient := mcp.NewClient(&mcp.Implementation{
+		Name:    "ovsdb-mcp-test-client",
+		Title:   "OVSDB MCP Test Client",
+		Version: "1.0.0",
+	}, nil)
+	session, err := mcpClient.Connect(ctx, clientTransport)
+	require.NoError(t, err, "Failed to connect to MCP server")
+	return session
+}
@@ -0,0 +1,77 @@
+package integration
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestEndpointsIntegration(t *testing.T) {
+	suite.Run(t, new(EndpointsIntegrationTestSuite))
+}
+
+// EndpointsIntegrationTestSuite checks that a server given the endpoints of
+// a clustered database connects to one that can be reached
+type EndpointsIntegrationTestSuite struct {
+	suite.Suite
+	endpoint string
+	down     string
+}
+
+func (suite *EndpointsIntegrationTestSuite) SetupTest() {
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	suite.endpoint = startDatabase(suite.T(), dbModel, ovnnbSchema.Schema())
+	// A server of the cluster that is down
+	suite.down = "unix:" + filepath.Join(suite.T().TempDir(), "down.sock")
+}
+
+func (suite *EndpointsIntegrationTestSuite) listLogicalSwitches(opts ...mcpserver.Option) *mcp.CallToolResult {
+	ctx := context.Background()
+	server, err := ovnnb.NewServer("localhost", 0, opts...)
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "list_logical_switches",
+		Arguments: map[string]any{},
+	})
+	suite.Require().NoError(err, "Failed to call list_logical_switches")
+	return result
+}
+
+func (suite *EndpointsIntegrationTestSuite) TestFailover() {
+	result := suite.listLogicalSwitches(mcpserver.WithEndpoints(suite.down, suite.endpoint))
+	suite.False(result.IsError, "Expected the server to connect to the endpoint that is up: %v", result.Content)
+}
+
+func (suite *EndpointsIntegrationTestSuite) TestCommaSeparatedEndpoint() {
+	result := suite.listLogicalSwitches(mcpserver.WithEndpoint(suite.down + "," + suite.endpoint))
+	suite.False(result.IsError, "Expected the server to connect to the endpoint that is up: %v", result.Content)
+}
+
+func (suite *EndpointsIntegrationTestSuite) TestAllEndpointsDown() {
+	result := suite.listLogicalSwitches(mcpserver.WithEndpoints(suite.down))
+	suite.True(result.IsError, "Expected an error when no endpoint can be reached")
+	text := result.Content[0].(*mcp.TextContent).Text
+	suite.Contains(text, suite.down)
+}
+
+func (suite *EndpointsIntegrationTestSuite) TestLeaderOnly() {
+	// Leadership is read from the _Server database, which the test database
+	// doesn't serve, so no endpoint is found to be the leader
+	result := suite.listLogicalSwitches(mcpserver.WithEndpoints(suite.down, suite.endpoint), mcpserver.WithLeaderOnly(true))
+	suite.True(result.IsError, "Expected an error when no endpoint is the leader")
+	text := result.Content[0].(*mcp.TextContent).Text
+	suite.Contains(text, "_Server")
+
+	result = suite.listLogicalSwitches(mcpserver.WithEndpoints(suite.down, suite.endpoint), mcpserver.WithLeaderOnly(false))
+	suite.False(result.IsError, "Expected the server to connect to any endpoint: %v", result.Content)
+}
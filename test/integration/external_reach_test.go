@@ -0,0 +1,106 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestExternalReachIntegration(t *testing.T) {
+	suite.Run(t, new(ExternalReachIntegrationTestSuite))
+}
+
+// ExternalReachIntegrationTestSuite checks that external_reachable_ports
+// finds the path from each port's switch to a switch with a localnet port,
+// and the gateway on it
+type ExternalReachIntegrationTestSuite struct {
+	suite.Suite
+}
+
+// routerPort returns a router port and the switch port connecting it to a
+// switch
+func routerPort(name, mac, network string) (*ovnnbSchema.LogicalRouterPort, *ovnnbSchema.LogicalSwitchPort) {
+	lrp := &ovnnbSchema.LogicalRouterPort{UUID: name, Name: name, MAC: mac, Networks: []string{network}}
+	lsp := &ovnnbSchema.LogicalSwitchPort{
+		UUID:      "lsp_" + name,
+		Name:      "lsp-" + name,
+		Type:      "router",
+		Addresses: []string{"router"},
+		Options:   map[string]string{"router-port": name},
+	}
+	return lrp, lsp
+}
+
+func (suite *ExternalReachIntegrationTestSuite) TestPaths() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+
+	// ext has a localnet port and reaches the gateway router gw, which is
+	// peered with the distributed router cluster that sw1 is attached to
+	gwExt, lspGwExt := routerPort("gw_ext", "00:00:00:00:01:01", "172.16.0.1/24")
+	gwPeer := "cluster_peer"
+	gwToCluster := &ovnnbSchema.LogicalRouterPort{UUID: "gw_peer", Name: "gw_peer", MAC: "00:00:00:00:01:02", Networks: []string{"100.64.0.1/16"}, Peer: &gwPeer}
+	clusterPeer := "gw_peer"
+	clusterToGw := &ovnnbSchema.LogicalRouterPort{UUID: "cluster_peer", Name: "cluster_peer", MAC: "00:00:00:00:02:01", Networks: []string{"100.64.0.2/16"}, Peer: &clusterPeer}
+	clusterSw1, lspClusterSw1 := routerPort("cluster_sw1", "00:00:00:00:02:02", "10.0.1.1/24")
+
+	// pub has a localnet port and is reached from sw2 through the
+	// distributed gateway port of dr
+	drPub, lspDrPub := routerPort("dr_pub", "00:00:00:00:03:01", "192.0.2.1/24")
+	drPub.Options = map[string]string{"redirect-chassis": "ch1"}
+	drSw2, lspDrSw2 := routerPort("dr_sw2", "00:00:00:00:03:02", "10.0.2.1/24")
+
+	endpoint := seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(),
+		gwExt, lspGwExt, gwToCluster, clusterToGw, clusterSw1, lspClusterSw1, drPub, lspDrPub, drSw2, lspDrSw2,
+		&ovnnbSchema.LogicalRouter{Name: "gw", Ports: []string{"gw_ext", "gw_peer"}, Options: map[string]string{"chassis": "ch1"}},
+		&ovnnbSchema.LogicalRouter{Name: "cluster", Ports: []string{"cluster_peer", "cluster_sw1"}},
+		&ovnnbSchema.LogicalRouter{Name: "dr", Ports: []string{"dr_pub", "dr_sw2"}},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "ln_ext", Name: "ln-ext", Type: "localnet", Addresses: []string{"unknown"}},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "ln_pub", Name: "ln-pub", Type: "localnet", Addresses: []string{"unknown"}},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "pod1", Name: "pod1"},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "pod2", Name: "pod2"},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "pod3", Name: "pod3"},
+		&ovnnbSchema.LogicalSwitch{Name: "ext", Ports: []string{"ln_ext", "lsp_gw_ext"}},
+		&ovnnbSchema.LogicalSwitch{Name: "pub", Ports: []string{"ln_pub", "lsp_dr_pub"}},
+		&ovnnbSchema.LogicalSwitch{Name: "sw1", Ports: []string{"lsp_cluster_sw1", "pod1"}},
+		&ovnnbSchema.LogicalSwitch{Name: "sw2", Ports: []string{"lsp_dr_sw2", "pod2"}},
+		// Not connected to anything
+		&ovnnbSchema.LogicalSwitch{Name: "isolated", Ports: []string{"pod3"}},
+	)
+
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	result := callTool(suite.T(), session, "external_reachable_ports", map[string]any{})
+	suite.Equal([]any{"ext", "pub"}, result["external_switches"])
+	suite.Equal(float64(2), result["reachable_count"])
+	suite.Equal(float64(1), result["isolated_count"])
+
+	reachable := result["reachable"].([]any)
+	suite.Require().Len(reachable, 2)
+	pod1 := reachable[0].(map[string]any)
+	suite.Equal("pod1", pod1["port"])
+	suite.Equal([]any{"sw1", "cluster", "gw", "ext"}, pod1["path"], "Expected the path through the router peering")
+	suite.Equal("ext", pod1["exit_switch"])
+	suite.Equal("gw", pod1["gateway"], "Expected the gateway router")
+	pod2 := reachable[1].(map[string]any)
+	suite.Equal("pod2", pod2["port"])
+	suite.Equal([]any{"sw2", "dr", "pub"}, pod2["path"])
+	suite.Equal("pub", pod2["exit_switch"])
+	suite.Equal("dr_pub", pod2["gateway"], "Expected the distributed gateway port")
+
+	suite.Equal([]any{map[string]any{"port": "pod3", "switch": "isolated"}}, result["isolated"])
+
+	result = callTool(suite.T(), session, "external_reachable_ports", map[string]any{"switch": "sw1", "isolated_only": true})
+	suite.Equal(float64(1), result["reachable_count"])
+	suite.Empty(result["reachable"])
+	suite.Empty(result["isolated"])
+}
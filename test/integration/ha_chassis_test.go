@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnsb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	ovnsbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestHAChassisIntegration(t *testing.T) {
+	suite.Run(t, new(HAChassisIntegrationTestSuite))
+}
+
+// HAChassisIntegrationTestSuite checks list_ha_chassis_groups and the
+// expansion of the groups' HA chassis, in NB and SB, and list_ha_chassis
+type HAChassisIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *HAChassisIntegrationTestSuite) TestListHAChassisGroups() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(),
+		&ovnnbSchema.HAChassis{UUID: "hc1", ChassisName: "chassis-1", Priority: 10},
+		&ovnnbSchema.HAChassis{UUID: "hc2", ChassisName: "chassis-2", Priority: 30},
+		&ovnnbSchema.HAChassis{UUID: "hc3", ChassisName: "chassis-3", Priority: 20},
+		&ovnnbSchema.HAChassisGroup{Name: "gw1", HaChassis: []string{"hc1", "hc2", "hc3"}},
+		&ovnnbSchema.HAChassisGroup{Name: "gw2"},
+	)
+
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	call := func(args map[string]any) map[string]any {
+		return callTool(suite.T(), session, "list_ha_chassis_groups", args)
+	}
+
+	suite.Equal(float64(2), call(map[string]any{})["count"])
+
+	result := call(map[string]any{"name_filter": "gw1", "resolve": true})
+	suite.Require().Equal(float64(1), result["count"])
+	groups := result["data"].(map[string]any)["ha_chassis_groups"].([]any)
+	members, ok := groups[0].(map[string]any)["ha_chassis"].([]any)
+	suite.Require().True(ok, "Expected ha_chassis to be resolved to rows")
+	suite.Require().Len(members, 3)
+	var names []string
+	for _, member := range members {
+		names = append(names, member.(map[string]any)["chassis_name"].(string))
+	}
+	suite.Equal([]string{"chassis-2", "chassis-3", "chassis-1"}, names, "Expected the highest priority chassis first")
+}
+
+func (suite *HAChassisIntegrationTestSuite) TestListSBHAChassis() {
+	ctx := context.Background()
+
+	dbModel, err := ovnsbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+
+	ch1, ch2 := "ch1", "ch2"
+	endpoint := seedDatabase(suite.T(), dbModel, ovnsbSchema.Schema(),
+		&ovnsbSchema.Encap{UUID: "encap1", Type: ovnsbSchema.EncapTypeGeneve, IP: "192.168.0.1", ChassisName: "chassis-1"},
+		&ovnsbSchema.Encap{UUID: "encap2", Type: ovnsbSchema.EncapTypeGeneve, IP: "192.168.0.2", ChassisName: "chassis-2"},
+		&ovnsbSchema.Chassis{UUID: "ch1", Name: "chassis-1", Encaps: []string{"encap1"}},
+		&ovnsbSchema.Chassis{UUID: "ch2", Name: "chassis-2", Encaps: []string{"encap2"}},
+		&ovnsbSchema.HAChassis{UUID: "hc1", Chassis: &ch1, Priority: 10},
+		&ovnsbSchema.HAChassis{UUID: "hc2", Chassis: &ch2, Priority: 30},
+		&ovnsbSchema.HAChassis{UUID: "hc3", Priority: 20},
+		&ovnsbSchema.HAChassisGroup{Name: "gw1", HaChassis: []string{"hc1", "hc2", "hc3"}},
+		&ovnsbSchema.HAChassisGroup{Name: "gw2"},
+	)
+
+	server, err := ovnsb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	call := func(name string, args map[string]any) map[string]any {
+		return callTool(suite.T(), session, name, args)
+	}
+	chassisNames := func(rows []any) []any {
+		var names []any
+		for _, row := range rows {
+			names = append(names, row.(map[string]any)["chassis_name"])
+		}
+		return names
+	}
+
+	result := call("list_ha_chassis", map[string]any{})
+	suite.Require().Equal(float64(3), result["count"])
+	rows := result["data"].(map[string]any)["ha_chassis"].([]any)
+	suite.Equal([]any{"chassis-2", nil, "chassis-1"}, chassisNames(rows), "Expected the highest priority chassis first")
+
+	result = call("list_ha_chassis", map[string]any{"chassis_filter": "chassis-1"})
+	suite.Require().Equal(float64(1), result["count"])
+
+	suite.Equal(float64(2), call("list_ha_chassis_groups", map[string]any{})["count"])
+
+	result = call("list_ha_chassis_groups", map[string]any{"name_filter": "gw1", "resolve": true})
+	suite.Require().Equal(float64(1), result["count"])
+	groups := result["data"].(map[string]any)["ha_chassis_groups"].([]any)
+	members, ok := groups[0].(map[string]any)["ha_chassis"].([]any)
+	suite.Require().True(ok, "Expected ha_chassis to be resolved to rows")
+	suite.Equal([]any{"chassis-2", nil, "chassis-1"}, chassisNames(members), "Expected the highest priority chassis first")
+}
@@ -0,0 +1,92 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestHealthIntegration(t *testing.T) {
+	suite.Run(t, new(HealthIntegrationTestSuite))
+}
+
+// HealthIntegrationTestSuite checks the liveness and readiness endpoints
+// and the ping_database tool
+type HealthIntegrationTestSuite struct {
+	suite.Suite
+}
+
+// getLive returns the status code of the server's liveness endpoint
+func (suite *HealthIntegrationTestSuite) getLive(addr string) int {
+	resp, err := http.Get("http://" + addr + mcpserver.HealthPath)
+	suite.Require().NoError(err, "Failed to get liveness")
+	defer resp.Body.Close()
+	return resp.StatusCode
+}
+
+// getReady returns the status code and body of the server's readiness
+// endpoint
+func (suite *HealthIntegrationTestSuite) getReady(addr string) (int, mcpserver.HealthResult) {
+	resp, err := http.Get("http://" + addr + mcpserver.ReadyPath)
+	suite.Require().NoError(err, "Failed to get readiness")
+	defer resp.Body.Close()
+
+	var result mcpserver.HealthResult
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&result), "Failed to decode readiness")
+	return resp.StatusCode, result
+}
+
+func (suite *HealthIntegrationTestSuite) TestHealthy() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := startDatabase(suite.T(), dbModel, ovnnbSchema.Schema())
+
+	server, err := ovnnb.NewServer("localhost", 8091, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	suite.Require().NoError(server.Start(ctx, "localhost:8091"), "Failed to start server")
+	defer server.Stop(ctx)
+
+	suite.Equal(http.StatusOK, suite.getLive("localhost:8091"))
+	status, health := suite.getReady("localhost:8091")
+	suite.Equal(http.StatusOK, status)
+	suite.True(health.Healthy)
+	suite.Require().Len(health.Databases, 1)
+	suite.Equal(ovnnbSchema.Schema().Version, health.Databases[0].SchemaVersion)
+
+	// MCP is served on /mcp as well as /
+	mcpClient := mcp.NewClient(&mcp.Implementation{Name: "ovsdb-mcp-test-client", Version: "1.0.0"}, nil)
+	session, err := mcpClient.Connect(ctx, mcp.NewStreamableClientTransport("http://localhost:8091"+mcpserver.MCPPath, nil))
+	suite.Require().NoError(err, "Failed to connect to MCP server")
+	defer session.Close()
+	structured := callTool(suite.T(), session, "ping_database", map[string]any{})
+	suite.Equal(true, structured["healthy"])
+}
+
+func (suite *HealthIntegrationTestSuite) TestUnreachable() {
+	ctx := context.Background()
+
+	endpoint := "unix:" + filepath.Join(suite.T().TempDir(), "missing.sock")
+	server, err := ovnnb.NewServer("localhost", 8092, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	suite.Require().NoError(server.Start(ctx, "localhost:8092"), "Failed to start server")
+	defer server.Stop(ctx)
+
+	// The process is up even though the database isn't
+	suite.Equal(http.StatusOK, suite.getLive("localhost:8092"))
+	status, health := suite.getReady("localhost:8092")
+	suite.Equal(http.StatusServiceUnavailable, status)
+	suite.False(health.Healthy)
+	suite.Require().Len(health.Databases, 1)
+	suite.Equal(endpoint, health.Databases[0].Endpoint)
+	suite.NotEmpty(health.Databases[0].Error)
+}
@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestRowHistoryIntegration(t *testing.T) {
+	suite.Run(t, new(RowHistoryIntegrationTestSuite))
+}
+
+// RowHistoryIntegrationTestSuite checks that a server started with row
+// history records every change to a row, and that row_history returns them
+type RowHistoryIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *RowHistoryIntegrationTestSuite) TestRecordsChanges() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := startDatabase(suite.T(), dbModel, ovnnbSchema.Schema())
+	// Present before the server starts, so its insert is not a change
+	existing := insertRows(suite.T(), dbModel, endpoint, &ovnnbSchema.LogicalSwitch{Name: "existing"})[0]
+
+	server, err := ovnnb.NewServer("localhost", 8098, mcpserver.WithEndpoint(endpoint), mcpserver.WithRowHistory(100))
+	suite.Require().NoError(err, "Failed to create server")
+	suite.Require().NoError(server.Start(ctx, "localhost:8098"), "Failed to start server")
+	defer server.Stop(ctx)
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	c, err := client.NewOVSDBClient(dbModel, client.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create OVSDB client")
+	suite.Require().NoError(c.Connect(ctx), "Failed to connect to OVSDB")
+	defer c.Close()
+
+	// Rename the switch in quick succession and delete it, every change
+	// must be recorded
+	sw := &ovnnbSchema.LogicalSwitch{Name: "sw-0"}
+	sw.UUID = insertRows(suite.T(), dbModel, endpoint, sw)[0]
+	for i := 1; i <= 5; i++ {
+		sw.Name = fmt.Sprintf("sw-%d", i)
+		updateRow(suite.T(), c, sw, &sw.Name)
+	}
+	deleteOps, err := c.Where(sw).Delete()
+	suite.Require().NoError(err, "Failed to create delete operation")
+	_, err = c.Transact(ctx, deleteOps...)
+	suite.Require().NoError(err, "Failed to delete switch")
+
+	var result map[string]any
+	suite.Require().Eventually(func() bool {
+		result = callTool(suite.T(), session, "row_history", map[string]any{"uuid": sw.UUID})
+		return result["count"] == float64(7)
+	}, 5*time.Second, 10*time.Millisecond, "Expected the insert, five updates and the delete to be recorded")
+	suite.Equal(true, result["history_available"])
+	suite.Equal(false, result["exists"])
+	suite.Equal(ovnnbSchema.LogicalSwitchTable, result["table"])
+
+	history := result["history"].([]any)
+	var last time.Time
+	for i, h := range history {
+		change := h.(map[string]any)
+		switch i {
+		case 0:
+			suite.Equal(mcpserver.RowInsert, change["op"])
+			suite.NotContains(change, "before")
+			suite.Equal("sw-0", change["after"].(map[string]any)["name"])
+		case 6:
+			suite.Equal(mcpserver.RowDelete, change["op"])
+			suite.Equal("sw-5", change["before"].(map[string]any)["name"])
+			suite.NotContains(change, "after")
+		default:
+			suite.Equal(mcpserver.RowUpdate, change["op"])
+			suite.Equal(fmt.Sprintf("sw-%d", i-1), change["before"].(map[string]any)["name"], "Expected every rename in order")
+			suite.Equal(fmt.Sprintf("sw-%d", i), change["after"].(map[string]any)["name"], "Expected every rename in order")
+		}
+		changed, err := time.Parse(time.RFC3339Nano, change["time"].(string))
+		suite.Require().NoError(err, "Expected the time of the change")
+		suite.False(changed.Before(last), "Expected each change to have its own time, oldest first")
+		last = changed
+	}
+
+	// The row present before the server started has no changes
+	result = callTool(suite.T(), session, "row_history", map[string]any{"uuid": existing})
+	suite.Equal(true, result["history_available"])
+	suite.Equal(true, result["exists"])
+	suite.Equal(float64(0), result["count"])
+}
@@ -0,0 +1,120 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnicnb"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnicsb"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnsb"
+	"github.com/dave-tucker/ariadne/internal/mcp/vswitch"
+	ovnicnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnicnb"
+	ovnicsbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnicsb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	ovnsbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	vswitchSchema "github.com/dave-tucker/ariadne/internal/schema/vswitch"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestListResultIntegration(t *testing.T) {
+	suite.Run(t, new(ListResultIntegrationTestSuite))
+}
+
+// ListResultIntegrationTestSuite runs every list tool of each server against
+// an empty in-memory database
+type ListResultIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ListResultIntegrationTestSuite) TestListToolsReturnCount() {
+	ctx := context.Background()
+
+	type database struct {
+		name      string
+		fullModel func() (model.ClientDBModel, error)
+		schema    func() ovsdb.DatabaseSchema
+		server    func(endpoint string) (*mcpserver.BaseServer, error)
+	}
+	databases := []database{
+		{"ovnnb", ovnnbSchema.FullDatabaseModel, ovnnbSchema.Schema, func(endpoint string) (*mcpserver.BaseServer, error) {
+			s, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+			if err != nil {
+				return nil, err
+			}
+			return s.BaseServer, nil
+		}},
+		{"ovnsb", ovnsbSchema.FullDatabaseModel, ovnsbSchema.Schema, func(endpoint string) (*mcpserver.BaseServer, error) {
+			s, err := ovnsb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+			if err != nil {
+				return nil, err
+			}
+			return s.BaseServer, nil
+		}},
+		{"ovnicnb", ovnicnbSchema.FullDatabaseModel, ovnicnbSchema.Schema, func(endpoint string) (*mcpserver.BaseServer, error) {
+			s, err := ovnicnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+			if err != nil {
+				return nil, err
+			}
+			return s.BaseServer, nil
+		}},
+		{"ovnicsb", ovnicsbSchema.FullDatabaseModel, ovnicsbSchema.Schema, func(endpoint string) (*mcpserver.BaseServer, error) {
+			s, err := ovnicsb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+			if err != nil {
+				return nil, err
+			}
+			return s.BaseServer, nil
+		}},
+		{"vswitch", vswitchSchema.FullDatabaseModel, vswitchSchema.Schema, func(endpoint string) (*mcpserver.BaseServer, error) {
+			s, err := vswitch.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+			if err != nil {
+				return nil, err
+			}
+			return s.BaseServer, nil
+		}},
+	}
+
+	for _, db := range databases {
+		suite.Run(db.name, func() {
+			dbModel, err := db.fullModel()
+			suite.Require().NoError(err, "Failed to create client model")
+			endpoint := startDatabase(suite.T(), dbModel, db.schema())
+
+			server, err := db.server(endpoint)
+			suite.Require().NoError(err, "Failed to create server")
+			session := connect(suite.T(), ctx, server)
+			defer session.Close()
+
+			toolsResult, err := session.ListTools(ctx, &mcp.ListToolsParams{})
+			suite.Require().NoError(err, "Failed to list tools")
+
+			listTools := 0
+			for _, tool := range toolsResult.Tools {
+				if !strings.HasPrefix(tool.Name, "list_") {
+					continue
+				}
+				listTools++
+
+				result, err := session.CallTool(ctx, &mcp.CallToolParams{
+					Name:      tool.Name,
+					Arguments: map[string]any{},
+				})
+				suite.Require().NoError(err, "Failed to call %s", tool.Name)
+				suite.Require().False(result.IsError, "Expected %s to succeed: %v", tool.Name, result.Content)
+
+				structured, ok := result.StructuredContent.(map[string]any)
+				suite.Require().True(ok, "Expected %s to return structured content, got %T", tool.Name, result.StructuredContent)
+				suite.Assert().Contains(structured, "count", "Expected %s to set count", tool.Name)
+				suite.Assert().Equal(float64(0), structured["count"], "Expected %s to return no rows", tool.Name)
+				suite.Assert().Contains(structured, "data", "Expected %s to set data", tool.Name)
+				suite.Assert().Len(result.Content, 2, "Expected %s to return the JSON and a summary", tool.Name)
+			}
+			suite.Assert().NotZero(listTools, "Expected %s to have list tools", db.name)
+		})
+	}
+}
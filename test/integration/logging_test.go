@@ -0,0 +1,69 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestLoggingIntegration(t *testing.T) {
+	suite.Run(t, new(LoggingIntegrationTestSuite))
+}
+
+// LoggingIntegrationTestSuite checks that tool calls are logged with the
+// server's logger
+type LoggingIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *LoggingIntegrationTestSuite) TestToolCallLogged() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(), &ovnnbSchema.LogicalSwitch{Name: "sw1"})
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint), mcpserver.WithLogger(logger))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "list_logical_switches",
+		Arguments: map[string]any{"name_filter": "sw1"},
+	})
+	suite.Require().NoError(err, "Failed to call list_logical_switches")
+	suite.Require().False(result.IsError, "Expected list_logical_switches to succeed: %v", result.Content)
+
+	records := map[string]map[string]any{}
+	decoder := json.NewDecoder(&logs)
+	for decoder.More() {
+		var record map[string]any
+		suite.Require().NoError(decoder.Decode(&record), "Failed to decode log record")
+		if record["tool"] == "list_logical_switches" {
+			records[record["msg"].(string)] = record
+		}
+	}
+
+	started, ok := records["Tool call started"]
+	suite.Require().True(ok, "Expected the call to be logged when it starts")
+	arguments, ok := started["arguments"].(map[string]any)
+	suite.Require().True(ok, "Expected the arguments to be logged, got %T", started["arguments"])
+	suite.Equal("sw1", arguments["name_filter"])
+
+	completed, ok := records["Tool call completed"]
+	suite.Require().True(ok, "Expected the call to be logged when it completes")
+	suite.Equal("INFO", completed["level"])
+	suite.Equal(float64(1), completed["rows"], "Expected the row count to be logged")
+	suite.Contains(completed, "duration")
+}
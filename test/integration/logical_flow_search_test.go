@@ -0,0 +1,61 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnsb"
+	ovnsbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestLogicalFlowSearchIntegration(t *testing.T) {
+	suite.Run(t, new(LogicalFlowSearchIntegrationTestSuite))
+}
+
+// LogicalFlowSearchIntegrationTestSuite checks that search_logical_flows
+// combines the match substring with the pipeline and table filters
+type LogicalFlowSearchIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *LogicalFlowSearchIntegrationTestSuite) TestSearch() {
+	ctx := context.Background()
+
+	dbModel, err := ovnsbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := seedDatabase(suite.T(), dbModel, ovnsbSchema.Schema(),
+		&ovnsbSchema.LogicalFlow{Pipeline: ovnsbSchema.LogicalFlowPipelineIngress, TableID: 8, Priority: 100, Match: "ip4.dst == 10.0.0.5", Actions: "next;"},
+		&ovnsbSchema.LogicalFlow{Pipeline: ovnsbSchema.LogicalFlowPipelineIngress, TableID: 9, Priority: 100, Match: "ip4.src == 10.0.0.5", Actions: "next;"},
+		&ovnsbSchema.LogicalFlow{Pipeline: ovnsbSchema.LogicalFlowPipelineEgress, TableID: 8, Priority: 100, Match: "ip4.dst == 10.0.0.5", Actions: "output;"},
+		&ovnsbSchema.LogicalFlow{Pipeline: ovnsbSchema.LogicalFlowPipelineIngress, TableID: 8, Priority: 50, Match: "arp", Actions: "next;"},
+	)
+
+	server, err := ovnsb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	count := func(args map[string]any) float64 {
+		structured := callTool(suite.T(), session, "search_logical_flows", args)
+		return structured["count"].(float64)
+	}
+
+	suite.Equal(float64(3), count(map[string]any{"match_contains": "10.0.0.5"}))
+	suite.Equal(float64(2), count(map[string]any{"match_contains": "ip4.dst"}))
+	suite.Equal(float64(2), count(map[string]any{"match_contains": "10.0.0.5", "pipeline": "ingress"}))
+	suite.Equal(float64(2), count(map[string]any{"match_contains": "10.0.0.5", "table_id": 8}))
+	suite.Equal(float64(1), count(map[string]any{"match_contains": "10.0.0.5", "pipeline": "ingress", "table_id": 8}))
+	suite.Equal(float64(0), count(map[string]any{"match_contains": "10.0.0.6"}))
+
+	for _, args := range []map[string]any{
+		{"match_contains": ""},
+		{"match_contains": "arp", "pipeline": "forward"},
+	} {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "search_logical_flows", Arguments: args})
+		suite.Require().NoError(err, "Failed to call search_logical_flows")
+		suite.True(result.IsError, "Expected search_logical_flows to reject %v", args)
+	}
+}
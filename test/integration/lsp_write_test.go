@@ -0,0 +1,195 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestLSPWriteIntegration(t *testing.T) {
+	suite.Run(t, new(LSPWriteIntegrationTestSuite))
+}
+
+// LSPWriteIntegrationTestSuite checks that create_logical_switch_port and
+// set_logical_switch_port_addresses write the port and its switch together,
+// and that a dry run writes nothing
+type LSPWriteIntegrationTestSuite struct {
+	suite.Suite
+	client  client.Client
+	session *mcp.ClientSession
+}
+
+func (suite *LSPWriteIntegrationTestSuite) SetupTest() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(), &ovnnbSchema.LogicalSwitch{Name: "sw1"})
+
+	c, err := client.NewOVSDBClient(dbModel, client.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create OVSDB client")
+	suite.Require().NoError(c.Connect(ctx), "Failed to connect to OVSDB")
+	suite.T().Cleanup(c.Close)
+	suite.client = c
+
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	suite.session = connect(suite.T(), ctx, server.BaseServer)
+	suite.T().Cleanup(func() { suite.session.Close() })
+}
+
+func (suite *LSPWriteIntegrationTestSuite) call(name string, args map[string]any) *mcp.CallToolResult {
+	result, err := suite.session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      name,
+		Arguments: args,
+	})
+	suite.Require().NoError(err, "Failed to call %s", name)
+	return result
+}
+
+// port returns the logical switch port named name, and the switches
+// referencing it
+func (suite *LSPWriteIntegrationTestSuite) port(name string) (*ovnnbSchema.LogicalSwitchPort, []string) {
+	ctx := context.Background()
+	ports, err := mcpserver.ExecuteSelectQuery(ctx, suite.client, &ovnnbSchema.LogicalSwitchPort{})
+	suite.Require().NoError(err, "Failed to select ports")
+	for _, lsp := range ports {
+		if lsp.Name != name {
+			continue
+		}
+		switches, err := mcpserver.ExecuteSelectQuery(ctx, suite.client, &ovnnbSchema.LogicalSwitch{})
+		suite.Require().NoError(err, "Failed to select switches")
+		var parents []string
+		for _, ls := range switches {
+			for _, port := range ls.Ports {
+				if port == lsp.UUID {
+					parents = append(parents, ls.Name)
+				}
+			}
+		}
+		return &lsp, parents
+	}
+	return nil, nil
+}
+
+func (suite *LSPWriteIntegrationTestSuite) TestCreate() {
+	result := suite.call("create_logical_switch_port", map[string]any{
+		"switch":    "sw1",
+		"name":      "lsp1",
+		"addresses": []string{"0a:58:0a:00:00:05 10.0.0.5"},
+	})
+	suite.Require().False(result.IsError, "Expected create_logical_switch_port to succeed: %v", result.Content)
+	structured := result.StructuredContent.(map[string]any)
+
+	lsp, parents := suite.port("lsp1")
+	suite.Require().NotNil(lsp, "Expected the port to be created")
+	suite.Equal(lsp.UUID, structured["uuid"])
+	suite.Equal([]string{"0a:58:0a:00:00:05 10.0.0.5"}, lsp.Addresses)
+	suite.Equal([]string{"sw1"}, parents, "Expected the port to be added to its switch")
+
+	// Port names are unique
+	result = suite.call("create_logical_switch_port", map[string]any{"switch": "sw1", "name": "lsp1"})
+	suite.True(result.IsError, "Expected creating a duplicate port to fail")
+
+	result = suite.call("create_logical_switch_port", map[string]any{"switch": "sw1", "name": "lrp1", "type": "router", "addresses": []string{"router"}})
+	suite.Require().False(result.IsError, "Expected create_logical_switch_port to succeed: %v", result.Content)
+	lsp, _ = suite.port("lrp1")
+	suite.Require().NotNil(lsp)
+	suite.Equal("router", lsp.Type)
+}
+
+func (suite *LSPWriteIntegrationTestSuite) TestCreateInvalid() {
+	for _, args := range []map[string]any{
+		{"switch": "missing", "name": "lsp1"},
+		{"switch": "sw1", "name": ""},
+		{"switch": "sw1", "name": "lsp1", "type": "bogus"},
+		{"switch": "sw1", "name": "lsp1", "addresses": []string{"10.0.0.5"}},
+		{"switch": "sw1", "name": "lsp1", "addresses": []string{"0a:58:0a:00:00:05 not-an-ip"}},
+		{"switch": "sw1", "name": "lsp1", "addresses": []string{"router 10.0.0.5"}},
+	} {
+		result := suite.call("create_logical_switch_port", args)
+		suite.True(result.IsError, "Expected an error for %v", args)
+	}
+	lsp, _ := suite.port("lsp1")
+	suite.Nil(lsp, "Expected no port to be created")
+}
+
+func (suite *LSPWriteIntegrationTestSuite) TestSetAddresses() {
+	result := suite.call("create_logical_switch_port", map[string]any{"switch": "sw1", "name": "lsp1", "addresses": []string{"dynamic"}})
+	suite.Require().False(result.IsError, "Expected create_logical_switch_port to succeed: %v", result.Content)
+
+	result = suite.call("set_logical_switch_port_addresses", map[string]any{
+		"name":      "lsp1",
+		"addresses": []string{"0a:58:0a:00:00:06 10.0.0.6 fd00::6", "unknown"},
+	})
+	suite.Require().False(result.IsError, "Expected set_logical_switch_port_addresses to succeed: %v", result.Content)
+	structured := result.StructuredContent.(map[string]any)
+	suite.Equal("sw1", structured["switch"])
+
+	lsp, _ := suite.port("lsp1")
+	suite.Require().NotNil(lsp)
+	suite.Equal(lsp.UUID, structured["uuid"])
+	suite.ElementsMatch([]string{"0a:58:0a:00:00:06 10.0.0.6 fd00::6", "unknown"}, lsp.Addresses)
+
+	// Addresses can be cleared
+	result = suite.call("set_logical_switch_port_addresses", map[string]any{"name": "lsp1", "addresses": []string{}})
+	suite.Require().False(result.IsError, "Expected set_logical_switch_port_addresses to succeed: %v", result.Content)
+	lsp, _ = suite.port("lsp1")
+	suite.Empty(lsp.Addresses)
+
+	result = suite.call("set_logical_switch_port_addresses", map[string]any{"name": "missing", "addresses": []string{"unknown"}})
+	suite.True(result.IsError, "Expected an error for a missing port")
+	result = suite.call("set_logical_switch_port_addresses", map[string]any{"name": "lsp1", "addresses": []string{"zz:zz"}})
+	suite.True(result.IsError, "Expected an error for an invalid address")
+}
+
+func (suite *LSPWriteIntegrationTestSuite) TestDryRun() {
+	result := suite.call("create_logical_switch_port", map[string]any{
+		"switch":    "sw1",
+		"name":      "lsp1",
+		"addresses": []string{"0a:58:0a:00:00:05 10.0.0.5"},
+		"dry_run":   true,
+	})
+	suite.Require().False(result.IsError, "Expected create_logical_switch_port to succeed: %v", result.Content)
+	structured := result.StructuredContent.(map[string]any)
+	suite.Equal(true, structured["dry_run"])
+	suite.Equal("OVN_Northbound", structured["database"])
+	operations := structured["operations"].([]any)
+	suite.Require().Len(operations, 2, "Expected the insert of the port and the mutate of its switch")
+	suite.Equal("insert", operations[0].(map[string]any)["op"])
+	suite.Equal("Logical_Switch_Port", operations[0].(map[string]any)["table"])
+	suite.Equal("mutate", operations[1].(map[string]any)["op"])
+	suite.Equal("Logical_Switch", operations[1].(map[string]any)["table"])
+
+	lsp, _ := suite.port("lsp1")
+	suite.Nil(lsp, "Expected a dry run not to create the port")
+
+	// Arguments are still validated, and missing rows reported
+	result = suite.call("create_logical_switch_port", map[string]any{"switch": "sw1", "name": "lsp1", "type": "bogus", "dry_run": true})
+	suite.True(result.IsError, "Expected an error for an invalid type")
+	result = suite.call("set_logical_switch_port_addresses", map[string]any{"name": "missing", "addresses": []string{"unknown"}, "dry_run": true})
+	suite.True(result.IsError, "Expected an error for a missing port")
+
+	// dry_run false writes as usual
+	result = suite.call("create_logical_switch_port", map[string]any{"switch": "sw1", "name": "lsp1", "dry_run": false})
+	suite.Require().False(result.IsError, "Expected create_logical_switch_port to succeed: %v", result.Content)
+	lsp, _ = suite.port("lsp1")
+	suite.NotNil(lsp, "Expected the port to be created")
+
+	tools, err := suite.session.ListTools(context.Background(), &mcp.ListToolsParams{})
+	suite.Require().NoError(err, "Failed to list tools")
+	for _, tool := range tools.Tools {
+		switch tool.Name {
+		case "create_acl", "delete_acl", "create_logical_switch_port", "set_logical_switch_port_addresses":
+			suite.Contains(tool.InputSchema.Properties, "dry_run", "Expected %s to take dry_run", tool.Name)
+		default:
+			suite.NotContains(tool.InputSchema.Properties, "dry_run", "Expected %s not to take dry_run", tool.Name)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package integration
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestMetricsIntegration(t *testing.T) {
+	suite.Run(t, new(MetricsIntegrationTestSuite))
+}
+
+// MetricsIntegrationTestSuite checks the Prometheus metrics served on
+// /metrics
+type MetricsIntegrationTestSuite struct {
+	suite.Suite
+}
+
+// getMetrics returns the metrics served by the server at addr
+func (suite *MetricsIntegrationTestSuite) getMetrics(addr string) string {
+	resp, err := http.Get("http://" + addr + mcpserver.MetricsPath)
+	suite.Require().NoError(err, "Failed to get metrics")
+	defer resp.Body.Close()
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	suite.Require().NoError(err, "Failed to read metrics")
+	return string(body)
+}
+
+// callTool calls a tool over MCP on the server at addr
+func (suite *MetricsIntegrationTestSuite) callTool(ctx context.Context, addr, name string, args map[string]any) {
+	mcpClient := mcp.NewClient(&mcp.Implementation{Name: "ovsdb-mcp-test-client", Version: "1.0.0"}, nil)
+	session, err := mcpClient.Connect(ctx, mcp.NewStreamableClientTransport("http://"+addr+mcpserver.MCPPath, nil))
+	suite.Require().NoError(err, "Failed to connect to MCP server")
+	defer session.Close()
+	_, err = session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+	suite.Require().NoError(err, "Failed to call %s", name)
+}
+
+func (suite *MetricsIntegrationTestSuite) TestToolCalls() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := startDatabase(suite.T(), dbModel, ovnnbSchema.Schema())
+
+	server, err := ovnnb.NewServer("localhost", 8094, mcpserver.WithEndpoint(endpoint), mcpserver.WithMetrics())
+	suite.Require().NoError(err, "Failed to create server")
+	suite.Require().NoError(server.Start(ctx, "localhost:8094"), "Failed to start server")
+	defer server.Stop(ctx)
+
+	suite.callTool(ctx, "localhost:8094", "list_logical_switches", map[string]any{})
+	suite.callTool(ctx, "localhost:8094", "list_logical_switches", map[string]any{})
+	suite.callTool(ctx, "localhost:8094", "get_logical_switch", map[string]any{"name": "missing"})
+
+	metrics := suite.getMetrics("localhost:8094")
+	suite.Contains(metrics, `tool_calls_total{server="ovn-nb-mcp",status="success",tool="list_logical_switches"} 2`)
+	suite.Contains(metrics, `tool_calls_total{server="ovn-nb-mcp",status="error",tool="get_logical_switch"} 1`)
+	suite.Contains(metrics, `tool_call_duration_seconds_count{server="ovn-nb-mcp",tool="list_logical_switches"} 2`)
+}
+
+func (suite *MetricsIntegrationTestSuite) TestConnectErrors() {
+	ctx := context.Background()
+
+	server, err := ovnnb.NewServer("localhost", 8095, mcpserver.WithEndpoint("unix:/nonexistent/ovnnb_db.sock"), mcpserver.WithMetrics())
+	suite.Require().NoError(err, "Failed to create server")
+	suite.Require().NoError(server.Start(ctx, "localhost:8095"), "Failed to start server")
+	defer server.Stop(ctx)
+
+	suite.callTool(ctx, "localhost:8095", "list_logical_switches", map[string]any{})
+
+	metrics := suite.getMetrics("localhost:8095")
+	suite.Contains(metrics, `ovsdb_connect_errors_total{database="OVN_Northbound",server="ovn-nb-mcp"} 1`)
+	suite.Contains(metrics, `tool_calls_total{server="ovn-nb-mcp",status="error",tool="list_logical_switches"} 1`)
+}
+
+func (suite *MetricsIntegrationTestSuite) TestCacheUpdates() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := startDatabase(suite.T(), dbModel, ovnnbSchema.Schema())
+
+	server, err := ovnnb.NewServer("localhost", 8096,
+		mcpserver.WithEndpoint(endpoint),
+		mcpserver.WithCache("Logical_Switch"),
+		mcpserver.WithMetrics())
+	suite.Require().NoError(err, "Failed to create server")
+	suite.Require().NoError(server.Start(ctx, "localhost:8096"), "Failed to start server")
+	defer server.Stop(ctx)
+
+	insertRows(suite.T(), dbModel, endpoint, &ovnnbSchema.LogicalSwitch{Name: "sw1"}, &ovnnbSchema.LogicalSwitch{Name: "sw2"})
+
+	suite.Eventually(func() bool {
+		return strings.Contains(suite.getMetrics("localhost:8096"), `ovsdb_cache_updates_total{server="ovn-nb-mcp",table="Logical_Switch"} 2`)
+	}, 10*time.Second, 10*time.Millisecond, "Expected the inserts to be applied to the cache")
+	// The cache only counts its updates, so it has no backlog to report
+	suite.NotContains(suite.getMetrics("localhost:8096"), `ovsdb_pending_updates`)
+}
+
+func (suite *MetricsIntegrationTestSuite) TestHistoryUpdates() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := startDatabase(suite.T(), dbModel, ovnnbSchema.Schema())
+
+	server, err := ovnnb.NewServer("localhost", 8097,
+		mcpserver.WithEndpoint(endpoint),
+		mcpserver.WithRowHistory(10),
+		mcpserver.WithMetrics())
+	suite.Require().NoError(err, "Failed to create server")
+	suite.Require().NoError(server.Start(ctx, "localhost:8097"), "Failed to start server")
+	defer server.Stop(ctx)
+
+	insertRows(suite.T(), dbModel, endpoint, &ovnnbSchema.LogicalSwitch{Name: "sw1"})
+
+	suite.Eventually(func() bool {
+		metrics := suite.getMetrics("localhost:8097")
+		return strings.Contains(metrics, `ovsdb_update_lag_seconds{monitor="history",server="ovn-nb-mcp"} 0`) &&
+			strings.Contains(metrics, `ovsdb_pending_updates{monitor="history",server="ovn-nb-mcp"} 0`)
+	}, 10*time.Second, 10*time.Millisecond, "Expected the history's processor to be reported once it has caught up")
+}
@@ -0,0 +1,84 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnsb"
+	ovnsbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestMulticastIntegration(t *testing.T) {
+	suite.Run(t, new(MulticastIntegrationTestSuite))
+}
+
+// MulticastIntegrationTestSuite checks list_multicast_groups and
+// list_igmp_groups, their datapath filter and the names of their ports
+type MulticastIntegrationTestSuite struct {
+	suite.Suite
+	session *mcp.ClientSession
+}
+
+func (suite *MulticastIntegrationTestSuite) SetupTest() {
+	ctx := context.Background()
+
+	dbModel, err := ovnsbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+
+	sw1, sw2 := "sw1", "sw2"
+	endpoint := seedDatabase(suite.T(), dbModel, ovnsbSchema.Schema(),
+		&ovnsbSchema.DatapathBinding{UUID: sw1, TunnelKey: 1, ExternalIDs: map[string]string{"name": "sw1"}},
+		&ovnsbSchema.DatapathBinding{UUID: sw2, TunnelKey: 2, ExternalIDs: map[string]string{"name": "sw2"}},
+		&ovnsbSchema.PortBinding{UUID: "pod1", LogicalPort: "pod1", Datapath: sw1, TunnelKey: 1},
+		&ovnsbSchema.PortBinding{UUID: "pod2", LogicalPort: "pod2", Datapath: sw1, TunnelKey: 2},
+		&ovnsbSchema.PortBinding{UUID: "pod3", LogicalPort: "pod3", Datapath: sw2, TunnelKey: 1},
+		&ovnsbSchema.MulticastGroup{Name: "_MC_flood", Datapath: sw1, TunnelKey: 32768, Ports: []string{"pod2", "pod1"}},
+		&ovnsbSchema.MulticastGroup{Name: "_MC_unknown", Datapath: sw1, TunnelKey: 32769},
+		&ovnsbSchema.MulticastGroup{Name: "_MC_flood", Datapath: sw2, TunnelKey: 32768, Ports: []string{"pod3"}},
+		&ovnsbSchema.IGMPGroup{Address: "239.1.1.1", ChassisName: "ch1", Datapath: &sw1, Ports: []string{"pod1"}},
+		&ovnsbSchema.IGMPGroup{Address: "239.1.1.2", ChassisName: "ch2", Datapath: &sw2, Ports: []string{"pod3"}},
+	)
+
+	server, err := ovnsb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	suite.session = connect(suite.T(), ctx, server.BaseServer)
+	suite.T().Cleanup(func() { suite.session.Close() })
+}
+
+func (suite *MulticastIntegrationTestSuite) list(name string, args map[string]any) map[string]any {
+	return callTool(suite.T(), suite.session, name, args)
+}
+
+func (suite *MulticastIntegrationTestSuite) TestListMulticastGroups() {
+	suite.Equal(float64(3), suite.list("list_multicast_groups", map[string]any{})["count"])
+	suite.Equal(float64(2), suite.list("list_multicast_groups", map[string]any{"name": "_MC_flood"})["count"])
+
+	result := suite.list("list_multicast_groups", map[string]any{"datapath_filter": "sw1", "name": "_MC_flood"})
+	suite.Require().Equal(float64(1), result["count"])
+	group := result["data"].(map[string]any)["multicast_groups"].([]any)[0].(map[string]any)
+	suite.Equal(float64(32768), group["tunnel_key"])
+	suite.Equal([]any{"pod1", "pod2"}, group["logical_ports"])
+
+	result = suite.list("list_multicast_groups", map[string]any{"datapath_filter": "sw1", "name": "_MC_unknown"})
+	group = result["data"].(map[string]any)["multicast_groups"].([]any)[0].(map[string]any)
+	suite.Equal([]any{}, group["logical_ports"])
+
+	result = suite.list("list_multicast_groups", map[string]any{"datapath_filter": "missing"})
+	suite.Equal(float64(0), result["count"])
+}
+
+func (suite *MulticastIntegrationTestSuite) TestListIGMPGroups() {
+	suite.Equal(float64(2), suite.list("list_igmp_groups", map[string]any{})["count"])
+
+	result := suite.list("list_igmp_groups", map[string]any{"datapath_filter": "sw2"})
+	suite.Require().Equal(float64(1), result["count"])
+	group := result["data"].(map[string]any)["igmp_groups"].([]any)[0].(map[string]any)
+	suite.Equal("239.1.1.2", group["address"])
+	suite.Equal("ch2", group["chassis_name"])
+	suite.Equal([]any{"pod3"}, group["logical_ports"])
+
+	suite.Equal(float64(1), suite.list("list_igmp_groups", map[string]any{"address": "239.1.1.1"})["count"])
+}
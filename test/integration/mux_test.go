@@ -0,0 +1,74 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	"github.com/dave-tucker/ariadne/internal/mcp/vswitch"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestMuxIntegration(t *testing.T) {
+	suite.Run(t, new(MuxIntegrationTestSuite))
+}
+
+// MuxIntegrationTestSuite checks that a MuxServer serves the tools of each
+// of its servers under their prefix
+type MuxIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *MuxIntegrationTestSuite) TestPrefixedTools() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(), &ovnnbSchema.LogicalSwitch{Name: "sw1"})
+
+	nb, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create OVN NB server")
+	ovs, err := vswitch.NewServer("localhost", 0)
+	suite.Require().NoError(err, "Failed to create Open vSwitch server")
+
+	server, err := mcpserver.NewMuxServer(&mcp.Implementation{Name: "ariadne-mcp-test"}, nil,
+		mcpserver.MuxEntry{Prefix: "nb", Server: nb.BaseServer},
+		mcpserver.MuxEntry{Prefix: "vswitch", Server: ovs.BaseServer},
+	)
+	suite.Require().NoError(err, "Failed to create mux server")
+	session := connectServer(suite.T(), ctx, server.Server)
+	defer session.Close()
+
+	tools, err := session.ListTools(ctx, &mcp.ListToolsParams{})
+	suite.Require().NoError(err, "Failed to list tools")
+	names := make(map[string]bool, len(tools.Tools))
+	for _, tool := range tools.Tools {
+		names[tool.Name] = true
+	}
+	for _, name := range []string{"nb_list_logical_switches", "nb_watch_table", "vswitch_list_bridges"} {
+		suite.True(names[name], "Expected tool %s", name)
+	}
+	suite.False(names["list_logical_switches"], "Expected tools to be prefixed")
+
+	structured := callTool(suite.T(), session, "nb_list_logical_switches", map[string]any{})
+	data, ok := structured["data"].(map[string]any)
+	suite.Require().True(ok, "Expected data, got %T", structured["data"])
+	switches, ok := data["logical_switches"].([]any)
+	suite.Require().True(ok, "Expected logical_switches, got %T", data["logical_switches"])
+	suite.Require().Len(switches, 1)
+	suite.Equal("sw1", switches[0].(map[string]any)["name"])
+}
+
+func (suite *MuxIntegrationTestSuite) TestDuplicatePrefix() {
+	nb, err := ovnnb.NewServer("localhost", 0)
+	suite.Require().NoError(err, "Failed to create OVN NB server")
+
+	_, err = mcpserver.NewMuxServer(&mcp.Implementation{Name: "ariadne-mcp-test"}, nil,
+		mcpserver.MuxEntry{Prefix: "nb", Server: nb.BaseServer},
+		mcpserver.MuxEntry{Prefix: "nb", Server: nb.BaseServer},
+	)
+	suite.Error(err, "Expected duplicate prefixes to be rejected")
+}
@@ -0,0 +1,125 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovn"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	ovnsbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestOVNTraceIntegration(t *testing.T) {
+	suite.Run(t, new(OVNTraceIntegrationTestSuite))
+}
+
+// OVNTraceIntegrationTestSuite checks that trace_packet of the OVN server
+// ties the SB logical flows a packet matches to the NB rows behind them
+type OVNTraceIntegrationTestSuite struct {
+	suite.Suite
+	session *mcp.ClientSession
+	aclUUID string
+}
+
+func (suite *OVNTraceIntegrationTestSuite) SetupTest() {
+	ctx := context.Background()
+
+	nbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create NB client model")
+	nbEndpoint := startDatabase(suite.T(), nbModel, ovnnbSchema.Schema())
+	sbModel, err := ovnsbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create SB client model")
+	sbEndpoint := startDatabase(suite.T(), sbModel, ovnsbSchema.Schema())
+
+	uuids := insertRows(suite.T(), nbModel, nbEndpoint,
+		&ovnnbSchema.ACL{UUID: "acl", Direction: ovnnbSchema.ACLDirectionToLport, Action: ovnnbSchema.ACLActionDrop, Priority: 1000, Match: "tcp.dst == 22"},
+		&ovnnbSchema.LoadBalancer{UUID: "lb", Name: "svc", Vips: map[string]string{"10.96.0.10:80": "10.0.0.2:8080"}},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "lsp1", Name: "lsp1", Addresses: []string{"0a:00:00:00:00:01 10.0.0.1"}},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "lsp2", Name: "lsp2", Addresses: []string{"0a:00:00:00:00:02 10.0.0.2"}},
+		&ovnnbSchema.LogicalSwitch{Name: "sw1", Ports: []string{"lsp1", "lsp2"}, ACLs: []string{"acl"}, LoadBalancer: []string{"lb"}},
+	)
+	suite.aclUUID = uuids[0]
+
+	sw := "sw"
+	ingress, egress := ovnsbSchema.LogicalFlowPipelineIngress, ovnsbSchema.LogicalFlowPipelineEgress
+	insertRows(suite.T(), sbModel, sbEndpoint,
+		&ovnsbSchema.Chassis{UUID: "ch", Name: "ch1", Hostname: "node1"},
+		&ovnsbSchema.DatapathBinding{UUID: sw, TunnelKey: 1, ExternalIDs: map[string]string{"name": "sw1"}},
+		&ovnsbSchema.PortBinding{LogicalPort: "lsp1", Datapath: sw, TunnelKey: 1, Chassis: &[]string{"ch"}[0]},
+		&ovnsbSchema.PortBinding{LogicalPort: "lsp2", Datapath: sw, TunnelKey: 2},
+		&ovnsbSchema.LogicalFlow{LogicalDatapath: &sw, Pipeline: ingress, TableID: 0, Priority: 0, Match: "1", Actions: "next;"},
+		&ovnsbSchema.LogicalFlow{LogicalDatapath: &sw, Pipeline: ingress, TableID: 1, Priority: 50, Match: "eth.dst == 0a:00:00:00:00:02", Actions: `outport = "lsp2"; output;`},
+		&ovnsbSchema.LogicalFlow{LogicalDatapath: &sw, Pipeline: egress, TableID: 0, Priority: 2000, Match: `outport == "lsp2" && (tcp.dst == 22)`, Actions: "drop;",
+			ExternalIDs: map[string]string{"stage-name": "ls_out_acl_eval", "stage-hint": suite.aclUUID[:8]}},
+		&ovnsbSchema.LogicalFlow{LogicalDatapath: &sw, Pipeline: egress, TableID: 0, Priority: 0, Match: "1", Actions: "output;"},
+	)
+
+	server, err := ovn.NewServer("localhost", 0, sbEndpoint, mcpserver.WithEndpoint(nbEndpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	suite.session = connect(suite.T(), ctx, server.BaseServer)
+	suite.T().Cleanup(func() { suite.session.Close() })
+}
+
+func (suite *OVNTraceIntegrationTestSuite) trace(args map[string]any) map[string]any {
+	structured := callTool(suite.T(), suite.session, "trace_packet", args)
+	suite.NotEmpty(structured["explanation"])
+	return structured
+}
+
+func (suite *OVNTraceIntegrationTestSuite) TestOutput() {
+	result := suite.trace(map[string]any{"source": "lsp1", "destination_ip": "10.0.0.2", "protocol": "tcp", "destination_port": 80})
+	suite.Equal("output", result["verdict"])
+	suite.Equal("lsp2", result["outport"])
+	suite.Equal("eth.src == 0a:00:00:00:00:01 && eth.dst == 0a:00:00:00:00:02 && ip4 && ip4.src == 10.0.0.1 && ip4.dst == 10.0.0.2 && tcp && tcp.dst == 80", result["microflow"])
+	destination := result["destination"].(map[string]any)
+	suite.Equal("logical_switch_port", destination["type"])
+	suite.Len(result["logical_flows"], 3)
+	suite.Len(result["acls"], 1, "Expected the destination's to-lport ACL")
+}
+
+func (suite *OVNTraceIntegrationTestSuite) TestACLDrop() {
+	result := suite.trace(map[string]any{"source": "lsp1", "destination_ip": "10.0.0.2", "protocol": "tcp", "destination_port": 22})
+	suite.Equal("drop", result["verdict"])
+	flows := result["logical_flows"].([]any)
+	last := flows[len(flows)-1].(map[string]any)
+	suite.Equal("ls_out_acl_eval", last["stage"])
+	row, ok := last["nb_row"].(map[string]any)
+	suite.Require().True(ok, "Expected the ACL flow to be tied to its NB row")
+	suite.Equal(ovnnbSchema.ACLTable, row["table"])
+	suite.Equal(suite.aclUUID, row["uuid"])
+}
+
+func (suite *OVNTraceIntegrationTestSuite) TestLoadBalancerVIP() {
+	result := suite.trace(map[string]any{"source": "lsp1", "destination_ip": "10.96.0.10", "protocol": "tcp", "destination_port": 80})
+	destination := result["destination"].(map[string]any)
+	suite.Equal("load_balancer_vip", destination["type"])
+	lbs := result["load_balancers"].([]any)
+	suite.Require().Len(lbs, 1)
+	lb := lbs[0].(map[string]any)
+	suite.Equal("svc", lb["name"])
+	suite.Equal([]any{"10.0.0.2:8080"}, lb["backends"])
+	suite.Equal([]any{"logical_switch sw1"}, lb["applied_to"])
+
+	// The VIP is for port 80 only
+	result = suite.trace(map[string]any{"source": "lsp1", "destination_ip": "10.96.0.10", "protocol": "tcp", "destination_port": 443})
+	suite.Empty(result["load_balancers"])
+}
+
+func (suite *OVNTraceIntegrationTestSuite) TestInvalidArguments() {
+	for _, args := range []map[string]any{
+		{"source": "missing", "destination_ip": "10.0.0.2"},
+		{"source": "lsp1", "destination_ip": "not-an-ip"},
+		{"source": "lsp1", "destination_ip": "10.0.0.2", "protocol": "gre"},
+		{"source": "lsp1", "destination_ip": "10.0.0.2", "destination_port": 80},
+	} {
+		result, err := suite.session.CallTool(context.Background(), &mcp.CallToolParams{
+			Name:      "trace_packet",
+			Arguments: args,
+		})
+		suite.Require().NoError(err, "Failed to call trace_packet")
+		suite.True(result.IsError, "Expected an error for %v", args)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"log/slog"
 	"os"
 	"testing"
-	"time"
 
 	"github.com/dave-tucker/ariadne/internal/mcp/ovnicnb"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -31,9 +30,6 @@ func (suite *OVNICNBIntegrationTestSuite) TestToolsList() {
 	suite.Require().NoError(err, "Failed to start server")
 	defer server.Stop(ctx)
 
-	// Give the server a moment to start
-	time.Sleep(1 * time.Second)
-
 	// Create MCP client implementation
 	impl := &mcp.Implementation{
 		Name:    "ovsdb-mcp-test-client",
@@ -65,6 +61,14 @@ func (suite *OVNICNBIntegrationTestSuite) TestToolsList() {
 		"list_ic_nb_globals",
 		"list_connections",
 		"list_ssl_configs",
+		"ovsdb_select",
+		"get_schema",
+		"describe_schema",
+		"watch_table",
+		"find_by_external_id_key",
+		"row_history",
+		"ping_database",
+		"server_info",
 	}
 
 	// Create a map of returned tool names for easy lookup
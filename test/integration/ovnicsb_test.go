@@ -3,7 +3,6 @@ package integration
 import (
 	"context"
 	"testing"
-	"time"
 
 	"github.com/dave-tucker/ariadne/internal/mcp/ovnicsb"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -29,9 +28,6 @@ func (suite *OVNICSBIntegrationTestSuite) TestToolsList() {
 	suite.Require().NoError(err, "Failed to start server")
 	defer server.Stop(ctx)
 
-	// Give the server a moment to start
-	time.Sleep(1 * time.Second)
-
 	// Create MCP client implementation
 	impl := &mcp.Implementation{
 		Name:    "ovsdb-mcp-test-client",
@@ -66,6 +62,14 @@ func (suite *OVNICSBIntegrationTestSuite) TestToolsList() {
 		"list_routes",
 		"list_encaps",
 		"list_ic_sb_globals",
+		"ovsdb_select",
+		"get_schema",
+		"describe_schema",
+		"watch_table",
+		"find_by_external_id_key",
+		"row_history",
+		"ping_database",
+		"server_info",
 	}
 
 	// Create a map of returned tool names for easy lookup
@@ -3,7 +3,6 @@ package integration
 import (
 	"context"
 	"testing"
-	"time"
 
 	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -30,9 +29,6 @@ func (suite *OVNNBIntegrationTestSuite) TestToolsList() {
 	suite.Require().NoError(err, "Failed to start server")
 	defer server.Stop(ctx)
 
-	// Give the server a moment to start
-	time.Sleep(1 * time.Second)
-
 	// Create MCP client implementation
 	impl := &mcp.Implementation{
 		Name:    "ovsdb-mcp-test-client",
@@ -61,15 +57,50 @@ func (suite *OVNNBIntegrationTestSuite) TestToolsList() {
 	// Define expected tools based on the OVN NB MCP server
 	expectedTools := []string{
 		"list_logical_switches",
+		"get_logical_switch_by_uuid",
+		"get_logical_switch",
 		"list_logical_switch_ports",
 		"list_logical_routers",
+		"list_logical_router_ports",
+		"get_topology",
+		"topology_by_router",
+		"find_distributed_gateway_ports",
+		"gateway_router_services",
+		"external_reachable_ports",
+		"check_router_networks",
+		"validate_router_port_subnets",
 		"list_acls",
 		"list_load_balancers",
 		"list_nat_rules",
+		"list_logical_router_static_routes",
 		"list_port_groups",
 		"list_address_sets",
 		"list_qos_rules",
+		"port_dscp",
 		"list_meters",
+		"list_bfd",
+		"list_static_mac_bindings",
+		"list_gateway_chassis",
+		"list_ha_chassis_groups",
+		"check_acl_priorities",
+		"find_shadowed_acls",
+		"create_acl",
+		"delete_acl",
+		"create_logical_switch_port",
+		"set_logical_switch_port_addresses",
+		"list_dns",
+		"list_dhcp_options",
+		"list_nb_global",
+		"minimal_repro",
+		"ovsdb_select",
+		"count_by",
+		"get_schema",
+		"describe_schema",
+		"watch_table",
+		"find_by_external_id_key",
+		"row_history",
+		"ping_database",
+		"server_info",
 	}
 
 	// Create a map of returned tool names for easy lookup
@@ -3,7 +3,6 @@ package integration
 import (
 	"context"
 	"testing"
-	"time"
 
 	"github.com/dave-tucker/ariadne/internal/mcp/ovnsb"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -29,9 +28,6 @@ func (suite *OVNSBIntegrationTestSuite) TestToolsList() {
 	suite.Require().NoError(err, "Failed to start server")
 	defer server.Stop(ctx)
 
-	// Give the server a moment to start
-	time.Sleep(1 * time.Second)
-
 	// Create MCP client implementation
 	impl := &mcp.Implementation{
 		Name:    "ovsdb-mcp-test-client",
@@ -62,11 +58,34 @@ func (suite *OVNSBIntegrationTestSuite) TestToolsList() {
 		"list_datapath_bindings",
 		"list_port_bindings",
 		"list_chassis",
+		"list_chassis_private",
 		"list_logical_flows",
+		"search_logical_flows",
+		"trace_packet",
 		"list_mac_bindings",
+		"find_stale_mac_bindings",
 		"list_encaps",
+		"list_gateway_chassis",
+		"list_ha_chassis",
+		"list_ha_chassis_groups",
 		"list_meters",
 		"list_fdb_entries",
+		"list_multicast_groups",
+		"list_igmp_groups",
+		"list_service_monitors",
+		"list_sb_global",
+		"binding_churn",
+		"resolve_tunnel_key",
+		"resolve_port_binding",
+		"ovsdb_select",
+		"count_by",
+		"get_schema",
+		"describe_schema",
+		"watch_table",
+		"find_by_external_id_key",
+		"row_history",
+		"ping_database",
+		"server_info",
 	}
 
 	// Create a map of returned tool names for easy lookup
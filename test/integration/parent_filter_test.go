@@ -0,0 +1,114 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestParentFilterIntegration(t *testing.T) {
+	suite.Run(t, new(ParentFilterIntegrationTestSuite))
+}
+
+// ParentFilterIntegrationTestSuite checks that list tools filtered by a
+// parent only return the parent's rows
+type ParentFilterIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ParentFilterIntegrationTestSuite) TestSwitchFilter() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+
+	// Two switches with two ports each, one of which connects to a router
+	endpoint := seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(),
+		&ovnnbSchema.LogicalSwitchPort{UUID: "port11", Name: "sw1-port1", Type: "router"},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "port12", Name: "sw1-port2"},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "port21", Name: "sw2-port1", Type: "router"},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "port22", Name: "sw2-port2", Type: "localnet"},
+		&ovnnbSchema.LogicalSwitch{UUID: "sw1", Name: "sw1", Ports: []string{"port11", "port12"}},
+		&ovnnbSchema.LogicalSwitch{UUID: "sw2", Name: "sw2", Ports: []string{"port21", "port22"}},
+	)
+
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	portNames := func(args map[string]any) []string {
+		structured := callTool(suite.T(), session, "list_logical_switch_ports", args)
+		data, ok := structured["data"].(map[string]any)
+		suite.Require().True(ok, "Expected data, got %T", structured["data"])
+		ports, ok := data["logical_switch_ports"].([]any)
+		suite.Require().True(ok, "Expected logical_switch_ports, got %T", data["logical_switch_ports"])
+
+		var names []string
+		for _, port := range ports {
+			names = append(names, port.(map[string]any)["name"].(string))
+		}
+		return names
+	}
+
+	suite.Assert().ElementsMatch([]string{"sw1-port1", "sw1-port2"}, portNames(map[string]any{"switch_filter": "sw1"}))
+	suite.Assert().ElementsMatch([]string{"sw2-port1", "sw2-port2"}, portNames(map[string]any{"switch_filter": "sw2"}))
+	suite.Assert().Len(portNames(map[string]any{}), 4, "Expected all ports without a filter")
+	suite.Assert().Empty(portNames(map[string]any{"switch_filter": "sw3"}), "Expected no ports for an unknown switch")
+
+	suite.Assert().ElementsMatch([]string{"sw1-port1", "sw2-port1"}, portNames(map[string]any{"type": "router"}))
+	suite.Assert().ElementsMatch([]string{"sw2-port2"}, portNames(map[string]any{"type": "localnet"}))
+	suite.Assert().ElementsMatch([]string{"sw1-port1"}, portNames(map[string]any{"switch_filter": "sw1", "type": "router"}))
+	suite.Assert().Empty(portNames(map[string]any{"switch_filter": "sw1", "type": "localnet"}), "Expected no localnet ports on sw1")
+}
+
+func (suite *ParentFilterIntegrationTestSuite) TestDNSSwitchFilter() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+
+	// sw1 and sw2 share a DNS row, and sw1 has one of its own
+	endpoint := seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(),
+		&ovnnbSchema.DNS{UUID: "shared", Records: map[string]string{"db": "10.0.0.5"}},
+		&ovnnbSchema.DNS{UUID: "own", Records: map[string]string{"Web": "10.0.0.10 fd00::10"}},
+		&ovnnbSchema.LogicalSwitch{UUID: "sw1", Name: "sw1", DNSRecords: []string{"shared", "own"}},
+		&ovnnbSchema.LogicalSwitch{UUID: "sw2", Name: "sw2", DNSRecords: []string{"shared"}},
+		&ovnnbSchema.LogicalSwitch{UUID: "sw3", Name: "sw3"},
+	)
+
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	list := func(args map[string]any) []map[string]any {
+		data := callTool(suite.T(), session, "list_dns", args)["data"].(map[string]any)
+		entries, ok := data["dns"].([]any)
+		suite.Require().True(ok, "Expected dns, got %T", data["dns"])
+		var rows []map[string]any
+		for _, entry := range entries {
+			rows = append(rows, entry.(map[string]any))
+		}
+		return rows
+	}
+
+	suite.Len(list(map[string]any{}), 2, "Expected every DNS row without a filter")
+	suite.Len(list(map[string]any{"switch_filter": "sw1"}), 2)
+
+	rows := list(map[string]any{"switch_filter": "sw2"})
+	suite.Require().Len(rows, 1)
+	suite.Equal(map[string]any{"db": []any{"10.0.0.5"}}, rows[0]["records"])
+	suite.Len(rows[0]["switches"], 2, "Expected every switch referencing the row to be listed")
+
+	rows = list(map[string]any{"switch_filter": "sw1", "hostname": "WEB"})
+	suite.Require().Len(rows, 1)
+	suite.Equal([]any{"10.0.0.10", "fd00::10"}, rows[0]["addresses"])
+
+	suite.Empty(list(map[string]any{"switch_filter": "sw3"}), "Expected no DNS rows for a switch without any")
+	suite.Empty(list(map[string]any{"switch_filter": "missing"}), "Expected no DNS rows for an unknown switch")
+}
@@ -0,0 +1,69 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnsb"
+	ovnsbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestPortBindingIntegration(t *testing.T) {
+	suite.Run(t, new(PortBindingIntegrationTestSuite))
+}
+
+// PortBindingIntegrationTestSuite checks that resolve_port_binding reports
+// the chassis of bound ports and unbound ports
+type PortBindingIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *PortBindingIntegrationTestSuite) TestResolve() {
+	ctx := context.Background()
+
+	dbModel, err := ovnsbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+
+	chassis, datapath := "chassis", "datapath"
+	endpoint := seedDatabase(suite.T(), dbModel, ovnsbSchema.Schema(),
+		&ovnsbSchema.Encap{UUID: "encap", Type: ovnsbSchema.EncapTypeGeneve, IP: "192.168.0.1", ChassisName: "ch1"},
+		&ovnsbSchema.Chassis{UUID: chassis, Name: "ch1", Hostname: "node1", Encaps: []string{"encap"}},
+		&ovnsbSchema.DatapathBinding{UUID: datapath, TunnelKey: 5, ExternalIDs: map[string]string{"name": "sw1", "logical-switch": "ls-uuid"}},
+		&ovnsbSchema.PortBinding{LogicalPort: "pod1", Datapath: datapath, TunnelKey: 1, Chassis: &chassis, MAC: []string{"0a:58:0a:00:00:05 10.0.0.5"}},
+		&ovnsbSchema.PortBinding{LogicalPort: "pod2", Datapath: datapath, TunnelKey: 2},
+	)
+
+	server, err := ovnsb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	resolve := func(port string) *mcp.CallToolResult {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "resolve_port_binding",
+			Arguments: map[string]any{"logical_port": port},
+		})
+		suite.Require().NoError(err, "Failed to call resolve_port_binding")
+		return result
+	}
+
+	structured := callTool(suite.T(), session, "resolve_port_binding", map[string]any{"logical_port": "pod1"})
+	suite.Equal(true, structured["bound"])
+	suite.Equal(float64(1), structured["tunnel_key"])
+	suite.Equal(map[string]any{"uuid": structured["datapath"].(map[string]any)["uuid"], "name": "sw1", "type": "switch", "tunnel_key": float64(5)}, structured["datapath"])
+	ch := structured["chassis"].(map[string]any)
+	suite.Equal("ch1", ch["name"])
+	suite.Equal("node1", ch["hostname"])
+	suite.Equal([]any{"192.168.0.1"}, ch["encap_ips"])
+
+	structured = callTool(suite.T(), session, "resolve_port_binding", map[string]any{"logical_port": "pod2"})
+	suite.Equal(false, structured["bound"])
+	suite.NotContains(structured, "chassis")
+	suite.Contains(structured["context"], "not bound to any chassis")
+
+	result := resolve("pod3")
+	suite.True(result.IsError, "Expected a missing port to be reported")
+}
@@ -0,0 +1,71 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovn"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	ovnsbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestReportIntegration(t *testing.T) {
+	suite.Run(t, new(ReportIntegrationTestSuite))
+}
+
+// ReportIntegrationTestSuite checks which ACLs pod_to_pod_report blames for
+// dropping traffic between two ports of a switch
+type ReportIntegrationTestSuite struct {
+	suite.Suite
+}
+
+// aclStage returns the acls_and_policies stage of the report from lsp1
+// (10.0.0.1) to lsp2 (10.0.0.2) when lsp2's switch has a to-lport drop ACL
+// with match
+func (suite *ReportIntegrationTestSuite) aclStage(match string) map[string]any {
+	ctx := context.Background()
+
+	nbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create NB client model")
+	nbEndpoint := seedDatabase(suite.T(), nbModel, ovnnbSchema.Schema(),
+		&ovnnbSchema.ACL{UUID: "acl", Direction: ovnnbSchema.ACLDirectionToLport, Action: ovnnbSchema.ACLActionDrop, Priority: 1000, Match: match},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "lsp1", Name: "lsp1", Addresses: []string{"0a:00:00:00:00:01 10.0.0.1"}},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "lsp2", Name: "lsp2", Addresses: []string{"0a:00:00:00:00:02 10.0.0.2"}},
+		&ovnnbSchema.LogicalSwitch{Name: "sw1", Ports: []string{"lsp1", "lsp2"}, ACLs: []string{"acl"}},
+	)
+	sbModel, err := ovnsbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create SB client model")
+	sbEndpoint := startDatabase(suite.T(), sbModel, ovnsbSchema.Schema())
+
+	server, err := ovn.NewServer("localhost", 0, sbEndpoint, mcpserver.WithEndpoint(nbEndpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	structured := callTool(suite.T(), session, "pod_to_pod_report", map[string]any{"source": "lsp1", "destination": "lsp2"})
+	for _, s := range structured["stages"].([]any) {
+		stage := s.(map[string]any)
+		if stage["stage"] == "acls_and_policies" {
+			return stage
+		}
+	}
+	suite.FailNow("Expected an acls_and_policies stage")
+	return nil
+}
+
+func (suite *ReportIntegrationTestSuite) TestACLNamingSource() {
+	stage := suite.aclStage("ip4.src == 10.0.0.1")
+	suite.Equal("fail", stage["status"])
+	suite.Contains(stage["findings"].([]any)[0], "drops this traffic")
+}
+
+func (suite *ReportIntegrationTestSuite) TestACLNamingPrefixCollidingAddress() {
+	// 10.0.0.10 starts with the source's address, but is another host
+	for _, match := range []string{"ip4.src == 10.0.0.10", "ip4.src == {10.0.0.10, 10.0.0.11}", "ip4.src == 110.0.0.1"} {
+		stage := suite.aclStage(match)
+		suite.Equal("warn", stage["status"], "Expected %q not to be blamed for the traffic", match)
+		suite.Contains(stage["findings"].([]any)[0], "drops traffic it matches", match)
+	}
+}
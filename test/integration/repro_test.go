@@ -0,0 +1,120 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestMinimalReproIntegration(t *testing.T) {
+	suite.Run(t, new(MinimalReproIntegrationTestSuite))
+}
+
+// MinimalReproIntegrationTestSuite checks that minimal_repro returns a
+// transaction inserting the rows around its target, which can be replayed
+// against an empty database
+type MinimalReproIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *MinimalReproIntegrationTestSuite) TestReplay() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(),
+		&ovnnbSchema.AddressSet{Name: "as1", Addresses: []string{"10.0.0.1"}},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "p1", Name: "p1"},
+		&ovnnbSchema.PortGroup{Name: "pg1", Ports: []string{"p1"}},
+		&ovnnbSchema.ACL{UUID: "acl1", Priority: 1001, Direction: "to-lport", Match: "outport == @pg1 && ip4.src == $as1", Action: "allow"},
+		&ovnnbSchema.LogicalSwitch{Name: "sw1", Ports: []string{"p1"}, ACLs: []string{"acl1"}},
+		// Unrelated to sw1
+		&ovnnbSchema.LogicalSwitchPort{UUID: "p2", Name: "p2"},
+		&ovnnbSchema.LogicalSwitch{Name: "sw2", Ports: []string{"p2"}},
+	)
+
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	// The switch's port and ACL are one reference away
+	result := callTool(suite.T(), session, "minimal_repro", map[string]any{"table": "Logical_Switch", "name": "sw1", "depth": 1})
+	suite.Equal(map[string]any{"Logical_Switch": float64(1), "Logical_Switch_Port": float64(1), "ACL": float64(1)}, result["rows"])
+
+	// The port group and address set are named in the ACL's match, and
+	// the port group also refers to the port
+	result = callTool(suite.T(), session, "minimal_repro", map[string]any{"table": "Logical_Switch", "name": "sw1"})
+	suite.Equal(float64(3), result["depth"])
+	suite.Equal(map[string]any{
+		"Logical_Switch":      float64(1),
+		"Logical_Switch_Port": float64(1),
+		"ACL":                 float64(1),
+		"Port_Group":          float64(1),
+		"Address_Set":         float64(1),
+	}, result["rows"])
+	suite.Equal(float64(5), result["count"])
+	suite.Empty(result["warnings"])
+
+	transaction := result["transaction"].([]any)
+	suite.Require().Len(transaction, 6)
+	suite.Equal("OVN_Northbound", transaction[0])
+
+	// Replay it against an empty database
+	raw, err := json.Marshal(transaction[1:])
+	suite.Require().NoError(err)
+	var ops []ovsdb.Operation
+	suite.Require().NoError(json.Unmarshal(raw, &ops), "Expected OVSDB operations")
+	replayed := startDatabase(suite.T(), dbModel, ovnnbSchema.Schema())
+	c, err := client.NewOVSDBClient(dbModel, client.WithEndpoint(replayed))
+	suite.Require().NoError(err, "Failed to create OVSDB client")
+	suite.Require().NoError(c.Connect(ctx), "Failed to connect to OVSDB")
+	defer c.Close()
+	reply, err := c.Transact(ctx, ops...)
+	suite.Require().NoError(err, "Failed to replay the transaction")
+	_, err = ovsdb.CheckOperationResults(reply, ops)
+	suite.Require().NoError(err, "Failed to replay the transaction")
+
+	switches, err := mcpserver.ExecuteSelectQuery(ctx, c, &ovnnbSchema.LogicalSwitch{})
+	suite.Require().NoError(err, "Failed to select switches")
+	suite.Require().Len(switches, 1)
+	suite.Equal("sw1", switches[0].Name)
+	suite.Len(switches[0].Ports, 1)
+	suite.Len(switches[0].ACLs, 1)
+	acls, err := mcpserver.ExecuteSelectQuery(ctx, c, &ovnnbSchema.ACL{})
+	suite.Require().NoError(err, "Failed to select ACLs")
+	suite.Require().Len(acls, 1)
+	suite.Equal("outport == @pg1 && ip4.src == $as1", acls[0].Match)
+	portGroups, err := mcpserver.ExecuteSelectQuery(ctx, c, &ovnnbSchema.PortGroup{})
+	suite.Require().NoError(err, "Failed to select port groups")
+	suite.Require().Len(portGroups, 1)
+	suite.Equal(switches[0].Ports, portGroups[0].Ports)
+}
+
+func (suite *MinimalReproIntegrationTestSuite) TestNotFound() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := startDatabase(suite.T(), dbModel, ovnnbSchema.Schema())
+
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "minimal_repro",
+		Arguments: map[string]any{"table": "Logical_Switch", "name": "missing"},
+	})
+	suite.Require().NoError(err, "Failed to call minimal_repro")
+	suite.True(result.IsError, "Expected a missing switch to be reported")
+}
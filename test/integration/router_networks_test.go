@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestRouterNetworksIntegration(t *testing.T) {
+	suite.Run(t, new(RouterNetworksIntegrationTestSuite))
+}
+
+// RouterNetworksIntegrationTestSuite checks that check_router_networks
+// reports the router ports whose subnets overlap
+type RouterNetworksIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *RouterNetworksIntegrationTestSuite) TestConflicts() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	port := func(name string, networks ...string) *ovnnbSchema.LogicalRouterPort {
+		return &ovnnbSchema.LogicalRouterPort{UUID: name, Name: name, MAC: "00:00:00:00:00:01", Networks: networks}
+	}
+	toLR2, toLR1 := port("to_lr2", "100.64.0.1/16"), port("to_lr1", "100.64.0.2/16")
+	toLR2.Peer = &toLR1.Name
+	toLR1.Peer = &toLR2.Name
+	endpoint := seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(),
+		port("a", "10.0.0.1/24"),
+		// The same subnet as a
+		port("b", "10.0.0.254/24"),
+		// Contains the subnets of a and b
+		port("c", "10.0.0.1/16"),
+		port("d", "10.1.0.1/24"),
+		port("e", "bogus"),
+		toLR2,
+		&ovnnbSchema.LogicalRouter{Name: "lr1", Ports: []string{"a", "b", "c", "d", "e", "to_lr2"}},
+		// Reuses d's subnet
+		port("f", "10.1.0.2/24"),
+		toLR1,
+		&ovnnbSchema.LogicalRouter{Name: "lr2", Ports: []string{"f", "to_lr1"}},
+	)
+
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	type conflict struct{ kind, port, otherRouter, otherPort string }
+	conflicts := func(result map[string]any) []conflict {
+		conflicts := []conflict{}
+		for _, c := range result["conflicts"].([]any) {
+			c := c.(map[string]any)
+			conflicts = append(conflicts, conflict{c["kind"].(string), c["port"].(string), c["other_router"].(string), c["other_port"].(string)})
+		}
+		return conflicts
+	}
+
+	result := callTool(suite.T(), session, "check_router_networks", map[string]any{"router": "lr1"})
+	suite.Equal([]conflict{
+		{"duplicate", "a", "lr1", "b"},
+		{"overlap", "a", "lr1", "c"},
+		{"overlap", "b", "lr1", "c"},
+	}, conflicts(result))
+	suite.Equal(float64(3), result["count"])
+	suite.Len(result["invalid"], 1)
+	suite.Contains(result["invalid"].([]any)[0], "port e")
+
+	// The subnet shared with the peer port of lr2 is not a conflict
+	result = callTool(suite.T(), session, "check_router_networks", map[string]any{"router": "lr1", "cross_check": true})
+	suite.Equal([]conflict{
+		{"duplicate", "a", "lr1", "b"},
+		{"overlap", "a", "lr1", "c"},
+		{"overlap", "b", "lr1", "c"},
+		{"duplicate", "d", "lr2", "f"},
+	}, conflicts(result))
+}
@@ -0,0 +1,106 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestRouterPortSubnetsIntegration(t *testing.T) {
+	suite.Run(t, new(RouterPortSubnetsIntegrationTestSuite))
+}
+
+// RouterPortSubnetsIntegrationTestSuite checks that
+// validate_router_port_subnets reports each way a router port's networks
+// can disagree with the subnets of the switches it is attached to
+type RouterPortSubnetsIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *RouterPortSubnetsIntegrationTestSuite) TestFindings() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+
+	var rows []model.Model
+	var ports []string
+	// attach adds a router port with networks attached to a switch with
+	// other_config
+	attach := func(name string, networks []string, otherConfig map[string]string) {
+		lrp, lsp := routerPort(name, "00:00:00:00:00:01", "")
+		lrp.Networks = networks
+		rows = append(rows, lrp, lsp, &ovnnbSchema.LogicalSwitch{
+			Name:        "sw_" + name,
+			Ports:       []string{lsp.UUID},
+			OtherConfig: otherConfig,
+		})
+		ports = append(ports, name)
+	}
+	attach("p_ok", []string{"10.0.1.1/24"}, map[string]string{"subnet": "10.0.1.0/24"})
+	attach("p_mismatch", []string{"10.0.9.1/24"}, map[string]string{"subnet": "10.0.2.0/24"})
+	attach("p_prefix", []string{"10.0.3.1/16"}, map[string]string{"subnet": "10.0.3.0/24"})
+	// The /16 is in the subnet too, but the /24 matches it exactly
+	attach("p_exact", []string{"10.0.4.1/16", "10.0.4.1/24"}, map[string]string{"subnet": "10.0.4.0/24"})
+	attach("p_missing", []string{"10.0.5.1/24"}, map[string]string{"ipv6_prefix": "fd00:5::"})
+	// ipv6_prefix is a /64 when it has no length
+	attach("p_ipv6", []string{"fd00:6::1/64"}, map[string]string{"ipv6_prefix": "fd00:6::"})
+	attach("p_invalid", []string{"10.0.7.1/24", "bogus"}, map[string]string{"subnet": "not-a-subnet"})
+	rows = append(rows, &ovnnbSchema.LogicalRouterPort{UUID: "p_peer", Name: "p_peer", MAC: "00:00:00:00:00:02", Networks: []string{"100.64.0.1/16"}})
+	ports = append(ports, "p_peer")
+	rows = append(rows, &ovnnbSchema.LogicalRouter{Name: "lr1", Ports: ports})
+	endpoint := seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(), rows...)
+
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	result := callTool(suite.T(), session, "validate_router_port_subnets", map[string]any{"router": "lr1"})
+	suite.Equal(float64(8), result["count"])
+	suite.Equal(float64(4), result["mismatches"])
+
+	byPort := make(map[string]map[string]any)
+	for _, p := range result["ports"].([]any) {
+		port := p.(map[string]any)
+		byPort[port["port"].(string)] = port
+	}
+	// kinds returns the kinds of the findings of port
+	kinds := func(port string) []string {
+		kinds := []string{}
+		for _, f := range byPort[port]["findings"].([]any) {
+			kinds = append(kinds, f.(map[string]any)["kind"].(string))
+		}
+		return kinds
+	}
+
+	suite.Equal("ok", byPort["p_ok"]["status"])
+	suite.Empty(kinds("p_ok"))
+
+	suite.Equal("mismatch", byPort["p_mismatch"]["status"])
+	suite.Equal([]string{"subnet_mismatch"}, kinds("p_mismatch"))
+
+	suite.Equal([]string{"prefix_length_mismatch"}, kinds("p_prefix"))
+	finding := byPort["p_prefix"]["findings"].([]any)[0].(map[string]any)
+	suite.Equal("10.0.3.0/24", finding["subnet"])
+	suite.Equal("10.0.3.1/16", finding["network"])
+
+	suite.Equal("ok", byPort["p_exact"]["status"], "Expected the network matching the subnet exactly to be found")
+
+	suite.Equal([]string{"missing_gateway_ip"}, kinds("p_missing"))
+	finding = byPort["p_missing"]["findings"].([]any)[0].(map[string]any)
+	suite.Equal("fd00:5::/64", finding["subnet"])
+
+	suite.Equal("ok", byPort["p_ipv6"]["status"])
+	suite.Equal([]any{"fd00:6::"}, byPort["p_ipv6"]["subnets"])
+
+	suite.Equal([]string{"invalid", "invalid"}, kinds("p_invalid"))
+
+	suite.Equal("not_checked", byPort["p_peer"]["status"])
+	suite.Empty(byPort["p_peer"]["switches"])
+}
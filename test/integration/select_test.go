@@ -0,0 +1,111 @@
+package integration
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSelectIntegration(t *testing.T) {
+	suite.Run(t, new(SelectIntegrationTestSuite))
+}
+
+// SelectIntegrationTestSuite checks that conditions are combined with AND
+// or OR as requested
+type SelectIntegrationTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	endpoint string
+	client   client.Client
+}
+
+func (suite *SelectIntegrationTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	suite.endpoint = seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(),
+		&ovnnbSchema.LogicalSwitchPort{UUID: "port_a", Name: "a", Type: "router"},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "port_b", Name: "b"},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "port_c", Name: "c"},
+		&ovnnbSchema.LogicalSwitch{Name: "sw", Ports: []string{"port_a", "port_b", "port_c"}},
+	)
+
+	c, err := client.NewOVSDBClient(dbModel, client.WithEndpoint(suite.endpoint))
+	suite.Require().NoError(err, "Failed to create OVSDB client")
+	suite.Require().NoError(c.Connect(suite.ctx), "Failed to connect to OVSDB")
+	suite.client = c
+}
+
+func (suite *SelectIntegrationTestSuite) TearDownTest() {
+	suite.client.Close()
+}
+
+// portNames returns the sorted names of ports
+func portNames(ports []ovnnbSchema.LogicalSwitchPort) []string {
+	names := []string{}
+	for _, p := range ports {
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (suite *SelectIntegrationTestSuite) TestCombinators() {
+	lsp := &ovnnbSchema.LogicalSwitchPort{}
+	named := func(name string) model.Condition {
+		return model.Condition{Field: &lsp.Name, Function: ovsdb.ConditionEqual, Value: name}
+	}
+	router := model.Condition{Field: &lsp.Type, Function: ovsdb.ConditionEqual, Value: "router"}
+
+	tests := []struct {
+		name       string
+		any        bool
+		conditions []model.Condition
+		expected   []string
+	}{
+		{"all of different names", false, []model.Condition{named("a"), named("b")}, []string{}},
+		{"any of different names", true, []model.Condition{named("a"), named("b")}, []string{"a", "b"}},
+		{"all of name and type", false, []model.Condition{named("a"), router}, []string{"a"}},
+		{"any of overlapping conditions", true, []model.Condition{named("a"), router}, []string{"a"}},
+		{"all without conditions", false, nil, []string{"a", "b", "c"}},
+		{"any without conditions", true, nil, []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		var ports []ovnnbSchema.LogicalSwitchPort
+		var err error
+		if tt.any {
+			ports, err = mcpserver.ExecuteSelectQueryAny(suite.ctx, suite.client, lsp, tt.conditions...)
+		} else {
+			ports, err = mcpserver.ExecuteSelectQuery(suite.ctx, suite.client, lsp, tt.conditions...)
+		}
+		suite.Require().NoError(err, tt.name)
+		suite.Equal(tt.expected, portNames(ports), tt.name)
+	}
+}
+
+func (suite *SelectIntegrationTestSuite) TestOVSDBSelectMatchAll() {
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(suite.endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), suite.ctx, server.BaseServer)
+	defer session.Close()
+
+	count := func(args map[string]any) float64 {
+		args["table"] = "Logical_Switch_Port"
+		args["conditions"] = [][]any{{"name", "==", "a"}, {"name", "==", "b"}}
+		return callTool(suite.T(), session, "ovsdb_select", args)["count"].(float64)
+	}
+
+	suite.Equal(float64(0), count(map[string]any{}), "Expected conditions to all be matched by default")
+	suite.Equal(float64(0), count(map[string]any{"match_all": true}))
+	suite.Equal(float64(2), count(map[string]any{"match_all": false}))
+	suite.Equal(float64(2), count(map[string]any{"match_all": false, "columns": []string{"name"}}))
+}
@@ -0,0 +1,114 @@
+package integration
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/go-logr/logr"
+	"github.com/ovn-kubernetes/libovsdb/database/inmemory"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb/serverdb"
+	"github.com/ovn-kubernetes/libovsdb/server"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestServerInfoIntegration(t *testing.T) {
+	suite.Run(t, new(ServerInfoIntegrationTestSuite))
+}
+
+// ServerInfoIntegrationTestSuite checks that server_info reports the schema
+// version and, when the server serves _Server, the database's cluster role
+type ServerInfoIntegrationTestSuite struct {
+	suite.Suite
+}
+
+// startClusteredDatabase serves an NB database and a _Server database
+// describing it as the leader of a cluster, and returns its endpoint
+func (suite *ServerInfoIntegrationTestSuite) startClusteredDatabase() string {
+	nbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	serverModel, err := serverdb.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create _Server client model")
+	nbDatabaseModel, errs := model.NewDatabaseModel(ovnnbSchema.Schema(), nbModel)
+	suite.Require().Empty(errs, "Failed to create database model")
+	serverDatabaseModel, errs := model.NewDatabaseModel(serverdb.Schema(), serverModel)
+	suite.Require().Empty(errs, "Failed to create _Server database model")
+
+	db := inmemory.NewDatabase(map[string]model.ClientDBModel{
+		ovnnbSchema.Schema().Name: nbModel,
+		serverdb.Schema().Name:    serverModel,
+	}, nil)
+	logger := logr.Discard()
+	ovsdbServer, err := server.NewOvsdbServer(db, &logger, nbDatabaseModel, serverDatabaseModel)
+	suite.Require().NoError(err, "Failed to create OVSDB server")
+
+	socket := filepath.Join(suite.T().TempDir(), "db.sock")
+	go func() {
+		_ = ovsdbServer.Serve("unix", socket)
+	}()
+	suite.T().Cleanup(ovsdbServer.Close)
+	suite.Require().Eventually(ovsdbServer.Ready, 5*time.Second, 10*time.Millisecond, "OVSDB server did not start")
+	endpoint := "unix:" + socket
+
+	cid, sid, index := "0b5b2ad6-1f5b-4a1c-9b1e-0d1f3a1c2b3d", "5d6e7f80-9a0b-4c1d-8e2f-3a4b5c6d7e8f", 42
+	insertRows(suite.T(), serverModel, endpoint, &serverdb.Database{
+		Name:      ovnnbSchema.Schema().Name,
+		Model:     serverdb.DatabaseModelClustered,
+		Leader:    true,
+		Connected: true,
+		Cid:       &cid,
+		Sid:       &sid,
+		Index:     &index,
+	})
+
+	return endpoint
+}
+
+// serverInfo calls server_info and returns the one database it reports
+func (suite *ServerInfoIntegrationTestSuite) serverInfo(endpoint string) map[string]any {
+	ctx := context.Background()
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	databases := callTool(suite.T(), session, "server_info", map[string]any{})["databases"].([]any)
+	suite.Require().Len(databases, 1)
+	return databases[0].(map[string]any)
+}
+
+func (suite *ServerInfoIntegrationTestSuite) TestStandalone() {
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := startDatabase(suite.T(), dbModel, ovnnbSchema.Schema())
+
+	info := suite.serverInfo(endpoint)
+	suite.Equal("OVN_Northbound", info["database"])
+	suite.Equal(ovnnbSchema.Schema().Version, info["schema_version"])
+	suite.NotContains(info, "error")
+	// The server doesn't serve _Server, so its role is unknown
+	suite.NotContains(info, "leader")
+	suite.NotContains(info, "model")
+}
+
+func (suite *ServerInfoIntegrationTestSuite) TestClustered() {
+	info := suite.serverInfo(suite.startClusteredDatabase())
+	suite.Equal(ovnnbSchema.Schema().Version, info["schema_version"])
+	suite.Equal("clustered", info["model"])
+	suite.Equal(true, info["leader"])
+	suite.Equal(true, info["connected"])
+	suite.Equal("0b5b2ad6-1f5b-4a1c-9b1e-0d1f3a1c2b3d", info["cluster_id"])
+	suite.Equal("5d6e7f80-9a0b-4c1d-8e2f-3a4b5c6d7e8f", info["server_id"])
+	suite.Equal(float64(42), info["index"])
+}
+
+func (suite *ServerInfoIntegrationTestSuite) TestUnreachable() {
+	info := suite.serverInfo("unix:" + filepath.Join(suite.T().TempDir(), "missing.sock"))
+	suite.Contains(info, "error")
+	suite.NotContains(info, "schema_version")
+}
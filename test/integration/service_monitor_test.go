@@ -0,0 +1,71 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnsb"
+	ovnsbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestServiceMonitorIntegration(t *testing.T) {
+	suite.Run(t, new(ServiceMonitorIntegrationTestSuite))
+}
+
+// ServiceMonitorIntegrationTestSuite checks the filters of
+// list_service_monitors
+type ServiceMonitorIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ServiceMonitorIntegrationTestSuite) TestListServiceMonitors() {
+	ctx := context.Background()
+
+	dbModel, err := ovnsbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+
+	tcp, online, offline := ovnsbSchema.ServiceMonitorProtocolTCP, ovnsbSchema.ServiceMonitorStatusOnline, ovnsbSchema.ServiceMonitorStatusOffline
+	endpoint := seedDatabase(suite.T(), dbModel, ovnsbSchema.Schema(),
+		&ovnsbSchema.Encap{UUID: "encap1", Type: ovnsbSchema.EncapTypeGeneve, IP: "192.168.0.1", ChassisName: "ch1"},
+		&ovnsbSchema.Chassis{Name: "ch1", Encaps: []string{"encap1"}},
+		&ovnsbSchema.ServiceMonitor{LogicalPort: "pod1", IP: "10.0.0.5", Port: 80, Protocol: &tcp, Status: &online, ChassisName: "ch1", SrcIP: "10.0.0.254", SrcMAC: "0a:58:0a:00:00:fe"},
+		&ovnsbSchema.ServiceMonitor{LogicalPort: "pod2", IP: "10.0.0.6", Port: 80, Protocol: &tcp, Status: &offline, ChassisName: "ch1", SrcIP: "10.0.0.254", SrcMAC: "0a:58:0a:00:00:fe"},
+		&ovnsbSchema.ServiceMonitor{LogicalPort: "pod3", IP: "10.0.0.7", Port: 80, Protocol: &tcp, ChassisName: "ch2", SrcIP: "10.0.0.254", SrcMAC: "0a:58:0a:00:00:fe"},
+	)
+
+	server, err := ovnsb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	list := func(args map[string]any) *mcp.CallToolResult {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "list_service_monitors",
+			Arguments: args,
+		})
+		suite.Require().NoError(err, "Failed to call list_service_monitors")
+		return result
+	}
+	count := func(args map[string]any) any {
+		return callTool(suite.T(), session, "list_service_monitors", args)["count"]
+	}
+
+	suite.Equal(float64(3), count(map[string]any{}))
+	suite.Equal(float64(1), count(map[string]any{"logical_port": "pod2"}))
+	suite.Equal(float64(1), count(map[string]any{"ip": "10.0.0.5"}))
+	suite.Equal(float64(1), count(map[string]any{"status": "offline"}))
+	suite.Equal(float64(1), count(map[string]any{"status": "online", "count_only": true}))
+	suite.Equal(float64(2), count(map[string]any{"chassis_filter": "ch1"}))
+
+	result := list(map[string]any{"status": "offline"})
+	rows := result.StructuredContent.(map[string]any)["data"].(map[string]any)["service_monitors"].([]any)
+	monitor := rows[0].(map[string]any)
+	suite.Equal("pod2", monitor["logical_port"])
+	suite.Equal("tcp", monitor["protocol"])
+	suite.Equal(float64(80), monitor["port"])
+
+	suite.True(list(map[string]any{"status": "down"}).IsError, "Expected an error for an invalid status")
+}
@@ -0,0 +1,91 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestShadowedACLsIntegration(t *testing.T) {
+	suite.Run(t, new(ShadowedACLsIntegrationTestSuite))
+}
+
+// ShadowedACLsIntegrationTestSuite checks that find_shadowed_acls reports
+// the ACLs a higher priority ACL matches every packet of, and which of them
+// are redundant
+type ShadowedACLsIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ShadowedACLsIntegrationTestSuite) TestShadowedACLs() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	acl := func(uuid string, priority int, direction, match, action string) *ovnnbSchema.ACL {
+		return &ovnnbSchema.ACL{UUID: uuid, Priority: priority, Direction: direction, Match: match, Action: action}
+	}
+	endpoint := seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(),
+		acl("drop_net", 2000, "to-lport", "ip4.src == 10.0.0.0/8", "drop"),
+		// Never applied, the drop of the whole network matches first
+		acl("allow_subnet", 1000, "to-lport", "ip4.src == 10.1.0.0/16 && tcp", "allow"),
+		// Drops what the drop of the whole network already drops
+		acl("drop_host", 1500, "to-lport", "ip4.src == 10.0.0.5", "drop"),
+		// Not comparable, and so not reported whatever they match
+		acl("drop_either", 1800, "to-lport", "ip4.src == 10.0.0.0/8 || ip6", "drop"),
+		acl("allow_not_host", 900, "to-lport", "ip4.src != 10.0.0.1", "allow"),
+		// In another direction, or matching packets the drop doesn't
+		acl("allow_from_host", 100, "from-lport", "ip4.src == 10.0.0.1", "allow"),
+		acl("allow_ip6", 500, "to-lport", "ip6", "allow"),
+		&ovnnbSchema.LogicalSwitch{Name: "sw1", ACLs: []string{
+			"drop_net", "allow_subnet", "drop_host", "drop_either", "allow_not_host", "allow_from_host", "allow_ip6",
+		}},
+		acl("pg_drop_ip4", 200, "from-lport", "ip4", "drop"),
+		acl("pg_drop_udp", 100, "from-lport", "ip4 && udp", "drop"),
+		&ovnnbSchema.PortGroup{Name: "pg1", ACLs: []string{"pg_drop_ip4", "pg_drop_udp"}},
+	)
+
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	result := callTool(suite.T(), session, "find_shadowed_acls", map[string]any{"switch_filter": "sw1"})
+	suite.Equal(float64(7), result["checked"])
+	suite.Equal(float64(2), result["not_comparable"])
+	suite.Equal(float64(2), result["count"])
+	shadowed := result["shadowed_acls"].([]any)
+	suite.Require().Len(shadowed, 2)
+
+	redundant := shadowed[0].(map[string]any)
+	suite.Equal("sw1", redundant["parent"])
+	suite.Equal("logical_switch", redundant["parent_type"])
+	suite.Equal("ip4.src == 10.0.0.5", redundant["shadowed"].(map[string]any)["match"])
+	suite.Equal("ip4.src == 10.0.0.0/8", redundant["shadowing"].(map[string]any)["match"])
+	suite.Equal(true, redundant["redundant"], "Expected the drop of a host in a dropped network to be redundant")
+
+	conflicting := shadowed[1].(map[string]any)
+	suite.Equal("ip4.src == 10.1.0.0/16 && tcp", conflicting["shadowed"].(map[string]any)["match"])
+	suite.Equal(float64(1000), conflicting["shadowed"].(map[string]any)["priority"])
+	suite.Equal("ip4.src == 10.0.0.0/8", conflicting["shadowing"].(map[string]any)["match"])
+	suite.Equal(false, conflicting["redundant"], "Expected the allow never applied not to be redundant")
+
+	result = callTool(suite.T(), session, "find_shadowed_acls", map[string]any{"port_group_filter": "pg1"})
+	suite.Equal(float64(2), result["checked"])
+	shadowed = result["shadowed_acls"].([]any)
+	suite.Require().Len(shadowed, 1)
+	pg := shadowed[0].(map[string]any)
+	suite.Equal("pg1", pg["parent"])
+	suite.Equal("port_group", pg["parent_type"])
+	suite.Equal("ip4 && udp", pg["shadowed"].(map[string]any)["match"])
+	suite.Equal(true, pg["redundant"])
+
+	// Without a filter both are checked
+	result = callTool(suite.T(), session, "find_shadowed_acls", map[string]any{})
+	suite.Equal(float64(9), result["checked"])
+	suite.Equal(float64(3), result["count"])
+}
@@ -0,0 +1,114 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovn"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	ovnsbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestStaleChassisIntegration(t *testing.T) {
+	suite.Run(t, new(StaleChassisIntegrationTestSuite))
+}
+
+// StaleChassisIntegrationTestSuite checks that find_stale_chassis_refs
+// reports the NB and SB rows that refer to a removed chassis, and not those
+// that refer to another chassis
+type StaleChassisIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *StaleChassisIntegrationTestSuite) TestStaleRefs() {
+	ctx := context.Background()
+
+	nbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create NB client model")
+	nbEndpoint := seedDatabase(suite.T(), nbModel, ovnnbSchema.Schema(),
+		&ovnnbSchema.GatewayChassis{UUID: "gc1", Name: "lrp1-ch1", ChassisName: "ch1", Priority: 20},
+		&ovnnbSchema.GatewayChassis{UUID: "gc2", Name: "lrp1-ch2", ChassisName: "ch2", Priority: 10},
+		&ovnnbSchema.LogicalRouterPort{UUID: "lrp1", Name: "lrp1", MAC: "0a:00:00:00:00:ff", Networks: []string{"172.16.0.1/24"}, GatewayChassis: []string{"gc1", "gc2"}},
+		&ovnnbSchema.LogicalRouter{Name: "lr1", Ports: []string{"lrp1"}},
+		&ovnnbSchema.LogicalRouter{Name: "gw1", Options: map[string]string{"chassis": "ch1"}},
+		&ovnnbSchema.LogicalRouter{Name: "gw2", Options: map[string]string{"chassis": "ch2"}},
+		&ovnnbSchema.HAChassis{UUID: "ha1", ChassisName: "ch1", Priority: 20},
+		&ovnnbSchema.HAChassis{UUID: "ha2", ChassisName: "ch2", Priority: 10},
+		&ovnnbSchema.HAChassisGroup{Name: "grp", HaChassis: []string{"ha1", "ha2"}},
+		// pod1 names ch1 by its hostname
+		&ovnnbSchema.LogicalSwitchPort{UUID: "pod1", Name: "pod1", Options: map[string]string{"requested-chassis": "node1"}},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "pod2", Name: "pod2", Options: map[string]string{"requested-chassis": "ch2"}},
+		&ovnnbSchema.LogicalSwitchPort{UUID: "pod3", Name: "pod3", Options: map[string]string{"requested-chassis": "gone"}},
+		&ovnnbSchema.LogicalSwitch{Name: "sw1", Ports: []string{"pod1", "pod2", "pod3"}},
+	)
+
+	sbModel, err := ovnsbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create SB client model")
+	ch1, ch2 := "ch1", "ch2"
+	sbEndpoint := seedDatabase(suite.T(), sbModel, ovnsbSchema.Schema(),
+		&ovnsbSchema.Encap{UUID: "encap1", Type: ovnsbSchema.EncapTypeGeneve, IP: "192.168.0.1", ChassisName: "ch1"},
+		&ovnsbSchema.Encap{UUID: "encap2", Type: ovnsbSchema.EncapTypeGeneve, IP: "192.168.0.2", ChassisName: "ch2"},
+		&ovnsbSchema.Chassis{UUID: ch1, Name: "ch1", Hostname: "node1", Encaps: []string{"encap1"}},
+		&ovnsbSchema.Chassis{UUID: ch2, Name: "ch2", Hostname: "node2", Encaps: []string{"encap2"}},
+		&ovnsbSchema.ChassisPrivate{Name: "ch1", Chassis: &ch1},
+		&ovnsbSchema.ChassisPrivate{Name: "ch2", Chassis: &ch2},
+		&ovnsbSchema.DatapathBinding{UUID: "dp", TunnelKey: 1, ExternalIDs: map[string]string{"name": "sw1"}},
+		&ovnsbSchema.PortBinding{LogicalPort: "pod1", Datapath: "dp", TunnelKey: 1, Chassis: &ch1, RequestedChassis: &ch1},
+		&ovnsbSchema.PortBinding{LogicalPort: "pod2", Datapath: "dp", TunnelKey: 2, Chassis: &ch2, RequestedChassis: &ch2},
+		&ovnsbSchema.HAChassis{UUID: "hc1", Chassis: &ch1, Priority: 20},
+		&ovnsbSchema.HAChassis{UUID: "hc2", Chassis: &ch2, Priority: 10},
+		&ovnsbSchema.HAChassisGroup{Name: "grp", HaChassis: []string{"hc1", "hc2"}, RefChassis: []string{ch1}},
+	)
+
+	server, err := ovn.NewServer("localhost", 0, sbEndpoint, mcpserver.WithEndpoint(nbEndpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	// owners returns the owner of each reference to a table, failing if a
+	// reference isn't in column
+	owners := func(refs map[string]any, table, column string) []any {
+		var owners []any
+		for _, r := range refs[table].([]any) {
+			ref := r.(map[string]any)
+			suite.Equal(column, ref["column"], table)
+			owners = append(owners, ref["owner"])
+		}
+		return owners
+	}
+
+	result := callTool(suite.T(), session, "find_stale_chassis_refs", map[string]any{"chassis": "ch1"})
+	suite.Equal(true, result["chassis_exists"])
+	suite.Equal(float64(11), result["count"])
+
+	nb := result["nb"].(map[string]any)
+	suite.Len(nb, 4)
+	suite.Equal([]any{"Logical_Router_Port lrp1"}, owners(nb, ovnnbSchema.GatewayChassisTable, "chassis_name"))
+	suite.Equal([]any{"HA_Chassis_Group grp"}, owners(nb, ovnnbSchema.HAChassisTable, "chassis_name"))
+	suite.Equal([]any{"pod1"}, owners(nb, ovnnbSchema.LogicalSwitchPortTable, "options:requested-chassis"))
+	suite.Equal([]any{"gw1"}, owners(nb, ovnnbSchema.LogicalRouterTable, "options:chassis"))
+
+	sb := result["sb"].(map[string]any)
+	suite.Len(sb, 6)
+	suite.Len(sb[ovnsbSchema.ChassisTable], 1)
+	suite.Len(sb[ovnsbSchema.ChassisPrivateTable], 1)
+	suite.Equal([]any{"geneve 192.168.0.1"}, owners(sb, ovnsbSchema.EncapTable, "chassis_name"))
+	suite.Len(sb[ovnsbSchema.HAChassisTable], 1)
+	suite.Equal([]any{"grp"}, owners(sb, ovnsbSchema.HAChassisGroupTable, "ref_chassis"))
+	var columns []any
+	for _, r := range sb[ovnsbSchema.PortBindingTable].([]any) {
+		ref := r.(map[string]any)
+		suite.Equal("pod1", ref["owner"])
+		columns = append(columns, ref["column"])
+	}
+	suite.Equal([]any{"chassis", "requested_chassis"}, columns)
+
+	// A chassis that has already been deleted from SB is only found by name
+	result = callTool(suite.T(), session, "find_stale_chassis_refs", map[string]any{"chassis": "gone"})
+	suite.Equal(false, result["chassis_exists"])
+	suite.Equal(float64(1), result["count"])
+	suite.Equal([]any{"pod3"}, owners(result["nb"].(map[string]any), ovnnbSchema.LogicalSwitchPortTable, "options:requested-chassis"))
+	suite.Empty(result["sb"])
+}
@@ -0,0 +1,74 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnsb"
+	ovnsbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestStaleMACBindingsIntegration(t *testing.T) {
+	suite.Run(t, new(StaleMACBindingsIntegrationTestSuite))
+}
+
+// StaleMACBindingsIntegrationTestSuite checks that find_stale_mac_bindings
+// reports the MAC bindings that no longer match the ports of the network
+// they were learned on, and only those
+type StaleMACBindingsIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *StaleMACBindingsIntegrationTestSuite) TestStaleBindings() {
+	ctx := context.Background()
+
+	dbModel, err := ovnsbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	macBinding := func(ip, mac string) *ovnsbSchema.MACBinding {
+		return &ovnsbSchema.MACBinding{IP: ip, MAC: mac, LogicalPort: "lr1-sw1", Datapath: "dp_lr1"}
+	}
+	endpoint := seedDatabase(suite.T(), dbModel, ovnsbSchema.Schema(),
+		&ovnsbSchema.DatapathBinding{UUID: "dp_lr1", TunnelKey: 1, ExternalIDs: map[string]string{"name": "lr1"}},
+		&ovnsbSchema.DatapathBinding{UUID: "dp_sw1", TunnelKey: 2, ExternalIDs: map[string]string{"name": "sw1"}},
+		&ovnsbSchema.PortBinding{LogicalPort: "lr1-sw1", Datapath: "dp_lr1", TunnelKey: 1, Type: "patch",
+			MAC: []string{"00:00:00:00:00:01 10.0.0.1/24"}, Options: map[string]string{"peer": "sw1-lr1"}},
+		&ovnsbSchema.PortBinding{LogicalPort: "sw1-lr1", Datapath: "dp_sw1", TunnelKey: 1, Type: "patch",
+			MAC: []string{"router"}, Options: map[string]string{"peer": "lr1-sw1"}},
+		&ovnsbSchema.PortBinding{LogicalPort: "pod1", Datapath: "dp_sw1", TunnelKey: 2, MAC: []string{"0a:00:00:00:00:01 10.0.0.10"}},
+		&ovnsbSchema.PortBinding{LogicalPort: "pod2", Datapath: "dp_sw1", TunnelKey: 3, MAC: []string{"0a:00:00:00:00:02 10.0.0.20"}},
+		// Matches pod1
+		macBinding("10.0.0.10", "0A:00:00:00:00:01"),
+		// No port has the MAC
+		macBinding("10.0.0.30", "0a:00:00:00:00:99"),
+		// pod2's IP with pod1's MAC
+		macBinding("10.0.0.20", "0a:00:00:00:00:01"),
+	)
+
+	server, err := ovnsb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	result := callTool(suite.T(), session, "find_stale_mac_bindings", map[string]any{})
+	suite.Equal(float64(3), result["checked"])
+	suite.Equal(float64(2), result["count"])
+	stale := result["stale_mac_bindings"].([]any)
+	suite.Require().Len(stale, 2)
+
+	reassigned := stale[0].(map[string]any)
+	suite.Equal("10.0.0.20", reassigned["ip"])
+	suite.Equal("ip_reassigned", reassigned["reason"])
+	suite.Equal("lr1", reassigned["datapath"])
+	suite.Equal("sw1", reassigned["network"])
+	suite.Contains(reassigned["detail"], "pod2")
+
+	unknown := stale[1].(map[string]any)
+	suite.Equal("10.0.0.30", unknown["ip"])
+	suite.Equal("mac_not_assigned", unknown["reason"])
+	suite.Equal(false, unknown["external_possible"], "Expected no external hosts on a network without localnet ports")
+
+	result = callTool(suite.T(), session, "find_stale_mac_bindings", map[string]any{"datapath_filter": "sw1"})
+	suite.Equal(float64(0), result["checked"], "Expected no bindings learned on the switch")
+}
@@ -0,0 +1,121 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestTargetIntegration(t *testing.T) {
+	suite.Run(t, new(TargetIntegrationTestSuite))
+}
+
+// TargetIntegrationTestSuite checks that tool calls query the database of
+// the target they name
+type TargetIntegrationTestSuite struct {
+	suite.Suite
+	lab1, lab2 string
+}
+
+// startSwitchDatabase starts an NB database holding one logical switch
+func (suite *TargetIntegrationTestSuite) startSwitchDatabase(name string) string {
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	return seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(), &ovnnbSchema.LogicalSwitch{Name: name})
+}
+
+func (suite *TargetIntegrationTestSuite) SetupTest() {
+	suite.lab1 = suite.startSwitchDatabase("sw-lab1")
+	suite.lab2 = suite.startSwitchDatabase("sw-lab2")
+}
+
+// switchNames lists the logical switches, with the target when it is set
+func (suite *TargetIntegrationTestSuite) switchNames(session *mcp.ClientSession, target string) []string {
+	args := map[string]any{}
+	if target != "" {
+		args["target"] = target
+	}
+	data := callTool(suite.T(), session, "list_logical_switches", args)["data"].(map[string]any)
+	names := []string{}
+	for _, row := range data["logical_switches"].([]any) {
+		names = append(names, row.(map[string]any)["name"].(string))
+	}
+	return names
+}
+
+func (suite *TargetIntegrationTestSuite) TestSelectTarget() {
+	ctx := context.Background()
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithTarget("lab1", suite.lab1), mcpserver.WithTarget("lab2", suite.lab2))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	// Calls without a target query the first one
+	suite.Equal([]string{"sw-lab1"}, suite.switchNames(session, ""))
+	suite.Equal([]string{"sw-lab1"}, suite.switchNames(session, "lab1"))
+	suite.Equal([]string{"sw-lab2"}, suite.switchNames(session, "lab2"))
+
+	// Arguments are still validated
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "list_logical_switches",
+		Arguments: map[string]any{"target": "lab2", "bogus": true},
+	})
+	if err == nil {
+		suite.True(result.IsError, "Expected an error for an unknown argument")
+	}
+
+	tools, err := session.ListTools(ctx, &mcp.ListToolsParams{})
+	suite.Require().NoError(err, "Failed to list tools")
+	for _, tool := range tools.Tools {
+		suite.Contains(tool.InputSchema.Properties, "target", "Expected %s to take a target", tool.Name)
+	}
+}
+
+func (suite *TargetIntegrationTestSuite) TestUnknownTarget() {
+	ctx := context.Background()
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithTarget("lab1", suite.lab1), mcpserver.WithTarget("lab2", suite.lab2))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	// An unknown target is not replaced by the default one
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "list_logical_switches",
+		Arguments: map[string]any{"target": "lab3"},
+	})
+	suite.Require().NoError(err, "Failed to call list_logical_switches")
+	suite.Require().True(result.IsError, "Expected an error result for an unknown target")
+	suite.Require().Len(result.Content, 1)
+	text, ok := result.Content[0].(*mcp.TextContent)
+	suite.Require().True(ok, "Expected text content, got %T", result.Content[0])
+	suite.Contains(text.Text, `"lab3"`)
+	suite.Contains(text.Text, "lab1, lab2", "Expected the valid targets to be listed")
+}
+
+func (suite *TargetIntegrationTestSuite) TestStatus() {
+	ctx := context.Background()
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithTarget("lab1", suite.lab1), mcpserver.WithTarget("lab2", suite.lab2))
+	suite.Require().NoError(err, "Failed to create server")
+
+	health := server.CheckHealth(ctx)
+	suite.True(health.Healthy)
+	suite.Require().Len(health.Databases, 2)
+	suite.Equal("lab1", health.Databases[0].Target)
+	suite.Equal("lab2", health.Databases[1].Target)
+}
+
+func (suite *TargetIntegrationTestSuite) TestInvalidTargets() {
+	for _, opts := range [][]mcpserver.Option{
+		{mcpserver.WithEndpoint(suite.lab1), mcpserver.WithTarget("lab2", suite.lab2)},
+		{mcpserver.WithTarget("lab1", suite.lab1), mcpserver.WithTarget("lab1", suite.lab2)},
+		{mcpserver.WithTarget("", suite.lab1)},
+	} {
+		_, err := ovnnb.NewServer("localhost", 0, opts...)
+		suite.Error(err)
+	}
+}
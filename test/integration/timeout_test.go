@@ -0,0 +1,67 @@
+package integration
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCallTimeoutIntegration(t *testing.T) {
+	suite.Run(t, new(CallTimeoutIntegrationTestSuite))
+}
+
+// CallTimeoutIntegrationTestSuite checks that tool calls to an unresponsive
+// database fail once the call timeout expires
+type CallTimeoutIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *CallTimeoutIntegrationTestSuite) TestUnresponsiveDatabase() {
+	ctx := context.Background()
+
+	// A database that accepts connections but never replies
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err, "Failed to listen")
+	defer listener.Close()
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+	endpoint := "tcp:" + listener.Addr().String()
+
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint), mcpserver.WithCallTimeout(500*time.Millisecond))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	start := time.Now()
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "list_logical_switches",
+		Arguments: map[string]any{},
+	})
+	suite.Require().NoError(err, "Failed to call list_logical_switches")
+	suite.Assert().Less(time.Since(start), 5*time.Second, "Expected the call to fail once the timeout expired")
+	suite.Require().True(result.IsError, "Expected list_logical_switches to fail")
+
+	text, ok := result.Content[0].(*mcp.TextContent)
+	suite.Require().True(ok, "Expected text content, got %T", result.Content[0])
+	suite.Assert().Contains(text.Text, "timed out after 500ms")
+	suite.Assert().Contains(text.Text, endpoint)
+}
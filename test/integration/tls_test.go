@@ -0,0 +1,82 @@
+package integration
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestTLSIntegration(t *testing.T) {
+	suite.Run(t, new(TLSIntegrationTestSuite))
+}
+
+// TLSIntegrationTestSuite checks that servers load the certificates for
+// ssl: endpoints when they are created
+type TLSIntegrationTestSuite struct {
+	suite.Suite
+}
+
+// writeCertificate writes a self-signed certificate and its private key to
+// dir and returns their paths
+func (suite *TLSIntegrationTestSuite) writeCertificate(dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Require().NoError(err, "Failed to generate key")
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ovsdb"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	suite.Require().NoError(err, "Failed to create certificate")
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	suite.Require().NoError(err, "Failed to marshal key")
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	suite.Require().NoError(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	suite.Require().NoError(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func (suite *TLSIntegrationTestSuite) TestCertPaths() {
+	dir := suite.T().TempDir()
+	certFile, keyFile := suite.writeCertificate(dir)
+
+	_, err := ovnnb.NewServer("localhost", 0,
+		mcpserver.WithEndpoint("ssl:127.0.0.1:6641"),
+		mcpserver.WithCertPaths(certFile, keyFile, certFile))
+	suite.Require().NoError(err, "Expected the certificates to load")
+}
+
+func (suite *TLSIntegrationTestSuite) TestUnreadableCertPaths() {
+	dir := suite.T().TempDir()
+	certFile, keyFile := suite.writeCertificate(dir)
+	missing := filepath.Join(dir, "missing.pem")
+
+	_, err := ovnnb.NewServer("localhost", 0, mcpserver.WithCertPaths(missing, keyFile, ""))
+	suite.Require().Error(err, "Expected a missing certificate to fail")
+	suite.Assert().Contains(err.Error(), missing)
+
+	_, err = ovnnb.NewServer("localhost", 0, mcpserver.WithCertPaths(certFile, keyFile, missing))
+	suite.Require().Error(err, "Expected a missing CA certificate to fail")
+	suite.Assert().Contains(err.Error(), missing)
+
+	_, err = ovnnb.NewServer("localhost", 0, mcpserver.WithCertPaths(certFile, "", ""))
+	suite.Require().Error(err, "Expected a certificate without a key to fail")
+}
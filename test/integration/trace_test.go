@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnsb"
+	ovnsbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestTraceIntegration(t *testing.T) {
+	suite.Run(t, new(TraceIntegrationTestSuite))
+}
+
+// TraceIntegrationTestSuite checks that the built-in tracer of trace_packet
+// follows the logical flows of a switch connected to a router
+type TraceIntegrationTestSuite struct {
+	suite.Suite
+	session *mcp.ClientSession
+}
+
+func (suite *TraceIntegrationTestSuite) SetupTest() {
+	ctx := context.Background()
+
+	dbModel, err := ovnsbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+
+	sw, lr := "sw", "lr"
+	flow := func(dp *string, pipeline string, table, priority int, match, actions string) model.Model {
+		return &ovnsbSchema.LogicalFlow{LogicalDatapath: dp, Pipeline: pipeline, TableID: table, Priority: priority, Match: match, Actions: actions}
+	}
+	ingress, egress := ovnsbSchema.LogicalFlowPipelineIngress, ovnsbSchema.LogicalFlowPipelineEgress
+	endpoint := seedDatabase(suite.T(), dbModel, ovnsbSchema.Schema(),
+		&ovnsbSchema.DatapathBinding{UUID: sw, TunnelKey: 1, ExternalIDs: map[string]string{"name": "sw1"}},
+		&ovnsbSchema.DatapathBinding{UUID: lr, TunnelKey: 2, ExternalIDs: map[string]string{"name": "lr1"}},
+		&ovnsbSchema.PortBinding{LogicalPort: "sw1-lr1", Datapath: sw, TunnelKey: 3, Type: "patch", Options: map[string]string{"peer": "lr1-sw1"}},
+		&ovnsbSchema.PortBinding{LogicalPort: "lr1-sw1", Datapath: lr, TunnelKey: 1, Type: "patch", Options: map[string]string{"peer": "sw1-lr1"}},
+		&ovnsbSchema.AddressSet{Name: "blocked", Addresses: []string{"10.0.0.3", "10.0.0.4"}},
+		// Port security, then forwarding by destination
+		flow(&sw, ingress, 0, 50, `inport == {"lsp1", "lsp2", "sw1-lr1"}`, "reg0[15] = check_in_port_sec(); next;"),
+		flow(&sw, ingress, 1, 100, "reg0[15] == 1", "drop;"),
+		flow(&sw, ingress, 1, 0, "1", "next;"),
+		flow(&sw, ingress, 2, 100, "ip4.dst == $blocked", "drop;"),
+		flow(&sw, ingress, 2, 90, "ip4 && ip4.dst == 10.0.0.2", `outport = "lsp2"; output;`),
+		flow(&sw, ingress, 2, 80, "ip4 && ip4.dst == 192.168.0.0/16", `outport = "sw1-lr1"; output;`),
+		flow(&sw, ingress, 2, 70, `is_chassis_resident("cr-lrp")`, "drop;"),
+		flow(&sw, egress, 0, 0, "1", "output;"),
+		flow(&lr, ingress, 0, 100, "ip.ttl == {0, 1}", "drop;"),
+		flow(&lr, ingress, 0, 50, `inport == "lr1-sw1" && tcp.dst == 22`, "drop;"),
+		flow(&lr, ingress, 0, 0, "1", "ip.ttl--; next;"),
+	)
+
+	server, err := ovnsb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	suite.session = connect(suite.T(), ctx, server.BaseServer)
+	suite.T().Cleanup(func() { suite.session.Close() })
+}
+
+// trace traces flow from inport of sw1 with the built-in tracer
+func (suite *TraceIntegrationTestSuite) trace(inport, flow string) map[string]any {
+	structured := callTool(suite.T(), suite.session, "trace_packet", map[string]any{
+		"datapath": "sw1",
+		"inport":   inport,
+		"flow":     flow,
+		"mode":     "builtin",
+	})
+	suite.Equal("builtin", structured["mode"])
+	return structured
+}
+
+func stepTables(result map[string]any) []string {
+	tables := []string{}
+	steps, _ := result["steps"].([]any)
+	for _, s := range steps {
+		step := s.(map[string]any)
+		tables = append(tables, step["datapath"].(string)+"/"+step["pipeline"].(string)+"/"+fmt.Sprint(step["table"]))
+	}
+	return tables
+}
+
+func (suite *TraceIntegrationTestSuite) TestOutput() {
+	result := suite.trace("lsp1", "tcp,ip_src=10.0.0.1,ip_dst=10.0.0.2,tcp_dst=80")
+	suite.Equal("output", result["verdict"])
+	suite.Equal("lsp2", result["outport"])
+	suite.Equal([]string{"sw1/ingress/0", "sw1/ingress/1", "sw1/ingress/2", "sw1/egress/0"}, stepTables(result))
+	suite.Equal(`inport == "lsp1" && ip4 && tcp && ip4.src == 10.0.0.1 && ip4.dst == 10.0.0.2 && tcp.dst == 80`, result["microflow"])
+}
+
+func (suite *TraceIntegrationTestSuite) TestAddressSetDrop() {
+	result := suite.trace("lsp1", "ip4.src == 10.0.0.1 && ip4.dst == 10.0.0.3")
+	suite.Equal("drop", result["verdict"])
+	suite.Equal([]string{"sw1/ingress/0", "sw1/ingress/1", "sw1/ingress/2"}, stepTables(result))
+}
+
+func (suite *TraceIntegrationTestSuite) TestNoMatchingFlow() {
+	// Only the known ports pass port security
+	result := suite.trace("lsp9", "ip4.dst == 10.0.0.2")
+	suite.Equal("drop", result["verdict"])
+	suite.Empty(stepTables(result))
+}
+
+func (suite *TraceIntegrationTestSuite) TestUncertainMatch() {
+	// is_chassis_resident() can't be evaluated, so a packet that reaches it
+	// may or may not be dropped
+	result := suite.trace("lsp1", "ip4.dst == 172.16.0.1")
+	suite.Equal("unknown", result["verdict"])
+	suite.Contains(result["reason"], "is_chassis_resident() is not simulated")
+}
+
+func (suite *TraceIntegrationTestSuite) TestPatchPort() {
+	// The router's first table drops SSH from the switch
+	result := suite.trace("lsp1", "tcp,ip_dst=192.168.1.1,tcp_dst=22")
+	suite.Equal("drop", result["verdict"])
+	suite.Equal([]string{"sw1/ingress/0", "sw1/ingress/1", "sw1/ingress/2", "sw1/egress/0", "lr1/ingress/0"}, stepTables(result))
+	suite.Contains(result["reason"], "drop the packet")
+
+	// Other traffic has its TTL decremented and reaches the router's second
+	// table, which has no flows
+	result = suite.trace("lsp1", "tcp,ip_dst=192.168.1.1,tcp_dst=80")
+	suite.Equal("drop", result["verdict"])
+	suite.Equal([]string{"sw1/ingress/0", "sw1/ingress/1", "sw1/ingress/2", "sw1/egress/0", "lr1/ingress/0"}, stepTables(result))
+	suite.Contains(result["reason"], "no logical flow of table 1 of the ingress pipeline of lr1 matched")
+	packet := result["packet"].(map[string]any)
+	suite.Equal("lr1-sw1", packet["inport"])
+	suite.Equal("63", packet["ip.ttl"])
+}
+
+func (suite *TraceIntegrationTestSuite) TestInvalidArguments() {
+	for _, args := range []map[string]any{
+		{"datapath": "sw1", "inport": "lsp1", "flow": "tcp,tp_dst=80,bogus=1"},
+		{"datapath": "sw1", "inport": "lsp1", "flow": "ip4.dst == 10.0.0.2 || ip4.dst == 10.0.0.3"},
+		{"datapath": "sw1", "inport": "lsp1", "flow": "ip4.dst == nowhere"},
+		{"datapath": "sw1", "inport": "lsp1", "flow": "ip", "mode": "fast"},
+		{"datapath": "sw9", "inport": "lsp1", "flow": "ip", "mode": "builtin"},
+		{"datapath": "--ovnsb=tcp:10.0.0.1:6642", "inport": "lsp1", "flow": "ip"},
+	} {
+		result, err := suite.session.CallTool(context.Background(), &mcp.CallToolParams{Name: "trace_packet", Arguments: args})
+		suite.Require().NoError(err, "Failed to call trace_packet")
+		suite.True(result.IsError, "Expected trace_packet to reject %v", args)
+	}
+}
@@ -0,0 +1,88 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnsb"
+	ovnsbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnsb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestTunnelKeyIntegration(t *testing.T) {
+	suite.Run(t, new(TunnelKeyIntegrationTestSuite))
+}
+
+// TunnelKeyIntegrationTestSuite checks which datapaths, ports and multicast
+// groups resolve_tunnel_key finds for a key, as port keys are reused on
+// every datapath
+type TunnelKeyIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TunnelKeyIntegrationTestSuite) TestResolve() {
+	ctx := context.Background()
+
+	dbModel, err := ovnsbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := seedDatabase(suite.T(), dbModel, ovnsbSchema.Schema(),
+		&ovnsbSchema.DatapathBinding{UUID: "dp_sw1", TunnelKey: 1, ExternalIDs: map[string]string{"name": "sw1", "logical-switch": "ls-uuid"}},
+		&ovnsbSchema.DatapathBinding{UUID: "dp_lr1", TunnelKey: 2, ExternalIDs: map[string]string{"name": "lr1", "logical-router": "lr-uuid"}},
+		&ovnsbSchema.PortBinding{UUID: "sw1_lr1", LogicalPort: "sw1-lr1", Type: "patch", Datapath: "dp_sw1", TunnelKey: 1},
+		&ovnsbSchema.PortBinding{UUID: "pod1", LogicalPort: "pod1", Datapath: "dp_sw1", TunnelKey: 2},
+		&ovnsbSchema.PortBinding{LogicalPort: "lr1-sw1", Type: "patch", Datapath: "dp_lr1", TunnelKey: 1},
+		&ovnsbSchema.MulticastGroup{Name: "_MC_flood", Datapath: "dp_sw1", TunnelKey: 32768, Ports: []string{"sw1_lr1", "pod1"}},
+	)
+
+	server, err := ovnsb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	// Without a scope, key 2 is both lr1 and pod1
+	result := callTool(suite.T(), session, "resolve_tunnel_key", map[string]any{"key": "2"})
+	suite.Equal(float64(2), result["count"])
+	suite.Equal("0x2", result["hex"])
+	datapaths := result["datapaths"].([]any)
+	suite.Require().Len(datapaths, 1)
+	suite.Equal("lr1", datapaths[0].(map[string]any)["name"])
+	suite.Equal("router", datapaths[0].(map[string]any)["type"])
+	ports := result["ports"].([]any)
+	suite.Require().Len(ports, 1)
+	suite.Equal("pod1", ports[0].(map[string]any)["logical_port"])
+	suite.Equal("sw1", ports[0].(map[string]any)["datapath"])
+	suite.Empty(result["multicast_groups"])
+
+	// Port key 1 is used on both datapaths, ordered by datapath key
+	result = callTool(suite.T(), session, "resolve_tunnel_key", map[string]any{"key": "0x1", "scope": "port"})
+	suite.Equal(float64(2), result["count"])
+	suite.NotContains(result, "datapaths")
+	var names []any
+	for _, p := range result["ports"].([]any) {
+		names = append(names, p.(map[string]any)["logical_port"])
+	}
+	suite.Equal([]any{"sw1-lr1", "lr1-sw1"}, names)
+
+	result = callTool(suite.T(), session, "resolve_tunnel_key", map[string]any{"key": "1", "scope": "port", "datapath_key": "0x2"})
+	suite.Equal(float64(1), result["count"])
+	ports = result["ports"].([]any)
+	suite.Require().Len(ports, 1)
+	suite.Equal("lr1-sw1", ports[0].(map[string]any)["logical_port"])
+	suite.Equal(float64(2), ports[0].(map[string]any)["datapath_tunnel_key"])
+
+	result = callTool(suite.T(), session, "resolve_tunnel_key", map[string]any{"key": "32768", "scope": "port"})
+	suite.Equal(float64(1), result["count"])
+	groups := result["multicast_groups"].([]any)
+	suite.Require().Len(groups, 1)
+	suite.Equal("_MC_flood", groups[0].(map[string]any)["name"])
+	suite.Equal("sw1", groups[0].(map[string]any)["datapath"])
+
+	missing, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "resolve_tunnel_key",
+		Arguments: map[string]any{"key": "3", "scope": "datapath"},
+	})
+	suite.Require().NoError(err, "Failed to call resolve_tunnel_key")
+	suite.True(missing.IsError, "Expected an unused key to be reported as an error")
+}
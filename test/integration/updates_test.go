@@ -0,0 +1,160 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestUpdateProcessor(t *testing.T) {
+	suite.Run(t, new(UpdateProcessorTestSuite))
+}
+
+// UpdateProcessorTestSuite checks that every monitor update is processed in
+// order for each row, by a bounded number of workers, and that the lag of
+// the backlog is reported
+type UpdateProcessorTestSuite struct {
+	suite.Suite
+}
+
+func (suite *UpdateProcessorTestSuite) TestOrder() {
+	var mu sync.Mutex
+	var processed []mcpserver.Update
+	p := mcpserver.NewUpdateProcessor(2, func(u mcpserver.Update) {
+		mu.Lock()
+		defer mu.Unlock()
+		processed = append(processed, u)
+	})
+
+	// A burst of updates queued before the workers start: sw1 is inserted
+	// and renamed 100 times, sw2 is inserted and deleted, and sw3 is updated
+	first := &ovnnbSchema.LogicalSwitch{UUID: "sw1", Name: "sw1-0"}
+	p.OnAdd(ovnnbSchema.LogicalSwitchTable, first)
+	last := first
+	for i := 1; i <= 100; i++ {
+		next := &ovnnbSchema.LogicalSwitch{UUID: "sw1", Name: fmt.Sprintf("sw1-%d", i)}
+		p.OnUpdate(ovnnbSchema.LogicalSwitchTable, last, next)
+		last = next
+	}
+	sw2 := &ovnnbSchema.LogicalSwitch{UUID: "sw2", Name: "sw2"}
+	p.OnAdd(ovnnbSchema.LogicalSwitchTable, sw2)
+	p.OnDelete(ovnnbSchema.LogicalSwitchTable, sw2)
+	p.OnUpdate(ovnnbSchema.LogicalSwitchTable,
+		&ovnnbSchema.LogicalSwitch{UUID: "sw3", Name: "sw3"},
+		&ovnnbSchema.LogicalSwitch{UUID: "sw3", Name: "sw3-renamed"})
+
+	suite.Equal(104, p.Pending(), "Expected every update to be pending")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.Start(ctx)
+
+	suite.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(processed) == 104
+	}, 5*time.Second, 10*time.Millisecond, "Expected every update to be processed")
+	suite.Equal(0, p.Pending())
+
+	mu.Lock()
+	defer mu.Unlock()
+	byUUID := make(map[string][]mcpserver.Update)
+	for _, u := range processed {
+		byUUID[u.UUID] = append(byUUID[u.UUID], u)
+	}
+	sw1 := byUUID["sw1"]
+	suite.Require().Len(sw1, 101)
+	suite.Nil(sw1[0].Old, "Expected the insert first")
+	for i, u := range sw1 {
+		suite.Equal(fmt.Sprintf("sw1-%d", i), u.New.(*ovnnbSchema.LogicalSwitch).Name, "Expected the updates to sw1 in order")
+		if i > 0 {
+			suite.Equal(fmt.Sprintf("sw1-%d", i-1), u.Old.(*ovnnbSchema.LogicalSwitch).Name)
+			suite.False(u.Queued.Before(sw1[i-1].Queued), "Expected each update to keep the time it was queued")
+		}
+	}
+	suite.Require().Len(byUUID["sw2"], 2)
+	suite.Nil(byUUID["sw2"][0].Old, "Expected the insert of sw2 before its delete")
+	suite.Nil(byUUID["sw2"][1].New)
+	suite.Require().Len(byUUID["sw3"], 1)
+	suite.Equal("sw3", byUUID["sw3"][0].Old.(*ovnnbSchema.LogicalSwitch).Name)
+	suite.Equal("sw3-renamed", byUUID["sw3"][0].New.(*ovnnbSchema.LogicalSwitch).Name)
+}
+
+func (suite *UpdateProcessorTestSuite) TestWorkerBound() {
+	const workers = 3
+	var mu sync.Mutex
+	running, maxRunning, done := 0, 0, 0
+	release := make(chan struct{})
+	p := mcpserver.NewUpdateProcessor(workers, func(u mcpserver.Update) {
+		mu.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		mu.Unlock()
+		<-release
+		mu.Lock()
+		running--
+		done++
+		mu.Unlock()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.Start(ctx)
+
+	for i := 0; i < 20; i++ {
+		p.OnAdd(ovnnbSchema.LogicalSwitchTable, &ovnnbSchema.LogicalSwitch{UUID: fmt.Sprintf("sw%d", i)})
+	}
+
+	suite.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return running == workers
+	}, 5*time.Second, 10*time.Millisecond, "Expected every worker to be busy")
+	// The workers are blocked, so the rest of the burst waits
+	suite.Equal(20-workers, p.Pending())
+
+	close(release)
+	suite.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return done == 20
+	}, 5*time.Second, 10*time.Millisecond, "Expected every update to be processed")
+
+	mu.Lock()
+	defer mu.Unlock()
+	suite.Equal(workers, maxRunning, "Expected no more updates to be processed at once than there are workers")
+}
+
+func (suite *UpdateProcessorTestSuite) TestLag() {
+	release := make(chan struct{})
+	p := mcpserver.NewUpdateProcessor(1, func(u mcpserver.Update) {
+		<-release
+	})
+	suite.Equal(time.Duration(0), p.Lag(), "Expected no lag without updates")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.Start(ctx)
+
+	// The first update occupies the only worker, so the second waits
+	p.OnAdd(ovnnbSchema.LogicalSwitchTable, &ovnnbSchema.LogicalSwitch{UUID: "sw1"})
+	suite.Eventually(func() bool {
+		return p.Pending() == 0
+	}, 5*time.Second, 10*time.Millisecond, "Expected the first update to be taken by the worker")
+	p.OnAdd(ovnnbSchema.LogicalSwitchTable, &ovnnbSchema.LogicalSwitch{UUID: "sw2"})
+
+	time.Sleep(100 * time.Millisecond)
+	suite.GreaterOrEqual(p.Lag(), 100*time.Millisecond, "Expected the lag to be the age of the waiting update")
+	suite.Equal(1, p.Pending())
+
+	close(release)
+	suite.Eventually(func() bool {
+		return p.Pending() == 0 && p.Lag() == 0
+	}, 5*time.Second, 10*time.Millisecond, "Expected the lag to be zero once the processor has caught up")
+}
@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"log"
 	"testing"
-	"time"
 
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
 	"github.com/dave-tucker/ariadne/internal/mcp/vswitch"
 	vswitchSchema "github.com/dave-tucker/ariadne/internal/schema/vswitch"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -38,9 +38,6 @@ func (suite *VSwitchIntegrationTestSuite) TestToolsList() {
 	suite.Require().NoError(err, "Failed to start server")
 	defer server.Stop(ctx)
 
-	// Give the server a moment to start
-	time.Sleep(1 * time.Second)
-
 	// Create MCP client implementation
 	impl := &mcp.Implementation{
 		Name:    "ovsdb-mcp-test-client",
@@ -75,6 +72,28 @@ func (suite *VSwitchIntegrationTestSuite) TestToolsList() {
 		"list_controllers",
 		"list_flow_tables",
 		"list_ssl_configs",
+		"list_qos",
+		"list_queues",
+		"list_mirrors",
+		"list_netflow",
+		"list_sflow",
+		"list_ipfix",
+		"ovs_info",
+		"create_bridge",
+		"delete_bridge",
+		"find_interface_for_port",
+		"find_port_by_mac",
+		"list_interface_errors",
+		"check_openflow_versions",
+		"bridge_l2_features",
+		"ovsdb_select",
+		"get_schema",
+		"describe_schema",
+		"watch_table",
+		"find_by_external_id_key",
+		"row_history",
+		"ping_database",
+		"server_info",
 	}
 
 	// Create a map of returned tool names for easy lookup
@@ -100,6 +119,93 @@ func (suite *VSwitchIntegrationTestSuite) TestToolsList() {
 	}
 }
 
+// TestStartAddressInUse tests that Start reports an error when the address is already bound
+func (suite *VSwitchIntegrationTestSuite) TestStartAddressInUse() {
+	ctx := context.Background()
+
+	server, err := vswitch.NewServer("localhost", 8087)
+	suite.Require().NoError(err, "Failed to create OVS vSwitchd server")
+
+	err = server.Start(ctx, "localhost:8087")
+	suite.Require().NoError(err, "Failed to start server")
+	defer server.Stop(ctx)
+
+	// Start a second server on the same address
+	second, err := vswitch.NewServer("localhost", 8087)
+	suite.Require().NoError(err, "Failed to create OVS vSwitchd server")
+
+	err = second.Start(ctx, "localhost:8087")
+	suite.Assert().Error(err, "Expected starting a second server on the same address to fail")
+}
+
+// TestListQoS tests that list_qos returns the QoS of a port and list_queues its queues
+func (suite *VSwitchIntegrationTestSuite) TestListQoS() {
+	ctx := context.Background()
+
+	dbModel, err := vswitchSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := startDatabase(suite.T(), dbModel, vswitchSchema.Schema())
+
+	ovs, err := client.NewOVSDBClient(dbModel, client.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create OVSDB client")
+	suite.Require().NoError(ovs.Connect(ctx), "Failed to connect to OVSDB")
+	defer ovs.Close()
+
+	// Two ports, only one of which has a QoS with a queue
+	dscp := 10
+	qos := "qos"
+	var ops []ovsdb.Operation
+	for _, m := range []model.Model{
+		&vswitchSchema.Queue{UUID: "queue", DSCP: &dscp, OtherConfig: map[string]string{"max-rate": "1000000"}},
+		&vswitchSchema.QoS{UUID: qos, Type: "linux-htb", Queues: map[int]string{0: "queue"}},
+		&vswitchSchema.QoS{UUID: "unused", Type: "linux-hfsc"},
+		&vswitchSchema.Port{UUID: "port1", Name: "port1", QOS: &qos},
+		&vswitchSchema.Port{UUID: "port2", Name: "port2"},
+		&vswitchSchema.Bridge{UUID: "bridge", Name: "br0", Ports: []string{"port1", "port2"}},
+		&vswitchSchema.OpenvSwitch{UUID: "root", Bridges: []string{"bridge"}},
+	} {
+		createOps, err := ovs.Create(m)
+		suite.Require().NoError(err, "Failed to create insert operation")
+		ops = append(ops, createOps...)
+	}
+	reply, err := ovs.Transact(ctx, ops...)
+	suite.Require().NoError(err, "Failed to insert QoS")
+	_, err = ovsdb.CheckOperationResults(reply, ops)
+	suite.Require().NoError(err, "Failed to insert QoS")
+
+	server, err := vswitch.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create OVS vSwitchd server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	rows := func(tool, key string, args map[string]any) []any {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      tool,
+			Arguments: args,
+		})
+		suite.Require().NoError(err, "Failed to call %s", tool)
+		suite.Require().False(result.IsError, "Expected %s to succeed: %v", tool, result.Content)
+
+		structured, ok := result.StructuredContent.(map[string]any)
+		suite.Require().True(ok, "Expected structured content, got %T", result.StructuredContent)
+		data, ok := structured["data"].(map[string]any)
+		suite.Require().True(ok, "Expected data, got %T", structured["data"])
+		rows, ok := data[key].([]any)
+		suite.Require().True(ok, "Expected %s, got %T", key, data[key])
+		return rows
+	}
+
+	suite.Assert().Len(rows("list_qos", "qos", map[string]any{}), 2, "Expected all QoS without a filter")
+	portQoS := rows("list_qos", "qos", map[string]any{"port_filter": "port1"})
+	suite.Require().Len(portQoS, 1, "Expected the QoS of port1")
+	suite.Assert().Equal("linux-htb", portQoS[0].(map[string]any)["type"])
+	suite.Assert().Empty(rows("list_qos", "qos", map[string]any{"port_filter": "port2"}), "Expected no QoS for port2")
+
+	queues := rows("list_queues", "queues", map[string]any{})
+	suite.Require().Len(queues, 1, "Expected the queue")
+	suite.Assert().EqualValues(dscp, queues[0].(map[string]any)["dscp"])
+}
+
 // TestOVSTools tests OVS tools against a real OVS container
 func (suite *VSwitchIntegrationTestSuite) TestListBridges() {
 	ctx := context.Background()
@@ -113,52 +219,15 @@ func (suite *VSwitchIntegrationTestSuite) TestListBridges() {
 	suite.Require().NoError(err, "Failed to start server")
 	defer server.Stop(ctx)
 
-	// Give the server a moment to start
-	time.Sleep(1 * time.Second)
-
-	// Start a container using the libovsdb/ovs:3.5.0 image, exposing port TCP 6640
-	req := testcontainers.ContainerRequest{
-		Image:        "libovsdb/ovs:3.5.0",
-		ExposedPorts: []string{"6640/tcp"},
-		WaitingFor: wait.ForAll(
-			wait.ForListeningPort("6640/tcp"),
-			wait.ForLog("ovsdb-server --remote=punix:/usr/local/var/run/openvswitch/db.sock --remote=ptcp:6640 --pidfile=ovsdb-server.pid"),
-		),
-	}
-
-	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
-		ContainerRequest: req,
-		Started:          true,
-	})
-	suite.Require().NoError(err, "Failed to start OVS container")
+	container, endpoint := suite.startOVSContainer(ctx)
 	defer container.Terminate(ctx)
 
-	dbModel, err := vswitchSchema.FullDatabaseModel()
-	suite.Require().NoError(err, "Failed to create database model")
-
-	port, err := container.MappedPort(ctx, "6640/tcp")
-	suite.Require().NoError(err, "Failed to get port")
-	endpoint := fmt.Sprintf("tcp:127.0.0.1:%s", port.Port())
-	suite.T().Logf("Endpoint: %s", endpoint)
-
-	ovs, err := client.NewOVSDBClient(dbModel, client.WithEndpoint(endpoint))
-	suite.Require().NoError(err, "Failed to create OVS client")
-	err = ovs.Connect(ctx)
-	suite.Require().NoError(err, "Failed to connect to OVS")
+	ovs := suite.connectOVS(ctx, endpoint)
 	defer ovs.Disconnect()
 
-	selectOps, queryID, selectErr := ovs.Where(&vswitchSchema.OpenvSwitch{}).Select()
-	suite.Require().NoError(selectErr, "Failed to select OpenvSwitch")
-
-	reply, err := ovs.Transact(ctx, selectOps...)
-	suite.Require().NoError(err, "Failed to execute transaction")
-
-	var results []vswitchSchema.OpenvSwitch
-	err = ovs.GetSelectResults(selectOps, reply, map[string]interface{}{queryID: &results})
-	suite.Require().NoError(err, "Failed to get select results")
-	suite.Assert().Equal(1, len(results), "Expected 1 OpenvSwitch to be returned")
-	fmt.Println(results[0])
-	rootUUID := results[0].UUID
+	root := suite.getRoot(ctx, ovs)
+	fmt.Println(root)
+	rootUUID := root.UUID
 
 	createBridge(ovs, rootUUID, "br-test-listbr1")
 	createBridge(ovs, rootUUID, "br-test-listbr2")
@@ -197,6 +266,124 @@ func (suite *VSwitchIntegrationTestSuite) TestListBridges() {
 	// TODO: Add assertions
 }
 
+// TestDeleteBridge tests that delete_bridge removes the bridge and its reference from the root row
+func (suite *VSwitchIntegrationTestSuite) TestDeleteBridge() {
+	ctx := context.Background()
+
+	container, endpoint := suite.startOVSContainer(ctx)
+	defer container.Terminate(ctx)
+
+	ovs := suite.connectOVS(ctx, endpoint)
+	defer ovs.Disconnect()
+
+	createBridge(ovs, suite.getRoot(ctx, ovs).UUID, "br-test-delete")
+
+	server, err := vswitch.NewServer("localhost", 8088, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create OVS vSwitchd server")
+
+	err = server.Start(ctx, "localhost:8088")
+	suite.Require().NoError(err, "Failed to start server")
+	defer server.Stop(ctx)
+
+	mcpClient := mcp.NewClient(&mcp.Implementation{
+		Name:    "ovsdb-mcp-test-client",
+		Title:   "OVSDB MCP Test Client",
+		Version: "1.0.0",
+	}, nil)
+
+	transport := mcp.NewStreamableClientTransport("http://localhost:8088/", nil)
+	session, err := mcpClient.Connect(ctx, transport)
+	suite.Require().NoError(err, "Failed to connect to MCP server")
+	defer session.Close()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "delete_bridge",
+		Arguments: map[string]interface{}{"name": "br-test-delete"},
+	})
+	suite.Require().NoError(err, "Failed to delete bridge")
+	suite.Require().False(result.IsError, "Expected delete_bridge to succeed: %v", result.Content)
+
+	// The bridge row and the root reference must both be gone
+	var bridges []vswitchSchema.Bridge
+	selectOps, queryID, err := ovs.Where(&vswitchSchema.Bridge{}).Select()
+	suite.Require().NoError(err, "Failed to select bridges")
+	reply, err := ovs.Transact(ctx, selectOps...)
+	suite.Require().NoError(err, "Failed to execute transaction")
+	err = ovs.GetSelectResults(selectOps, reply, map[string]interface{}{queryID: &bridges})
+	suite.Require().NoError(err, "Failed to get select results")
+
+	bridgeUUIDs := make(map[string]bool)
+	for _, bridge := range bridges {
+		suite.Assert().NotEqual("br-test-delete", bridge.Name, "Expected bridge to be deleted")
+		bridgeUUIDs[bridge.UUID] = true
+	}
+	for _, uuid := range suite.getRoot(ctx, ovs).Bridges {
+		suite.Assert().True(bridgeUUIDs[uuid], "Open_vSwitch references a bridge that does not exist: %s", uuid)
+	}
+
+	// Deleting it again reports an error
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "delete_bridge",
+		Arguments: map[string]interface{}{"name": "br-test-delete"},
+	})
+	suite.Require().NoError(err, "Failed to call delete_bridge")
+	suite.Assert().True(result.IsError, "Expected deleting a missing bridge to fail")
+}
+
+// startOVSContainer starts a container using the libovsdb/ovs:3.5.0 image and returns its OVSDB endpoint
+func (suite *VSwitchIntegrationTestSuite) startOVSContainer(ctx context.Context) (testcontainers.Container, string) {
+	req := testcontainers.ContainerRequest{
+		Image:        "libovsdb/ovs:3.5.0",
+		ExposedPorts: []string{"6640/tcp"},
+		WaitingFor: wait.ForAll(
+			wait.ForListeningPort("6640/tcp"),
+			wait.ForLog("ovsdb-server --remote=punix:/usr/local/var/run/openvswitch/db.sock --remote=ptcp:6640 --pidfile=ovsdb-server.pid"),
+		),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	suite.Require().NoError(err, "Failed to start OVS container")
+
+	port, err := container.MappedPort(ctx, "6640/tcp")
+	suite.Require().NoError(err, "Failed to get port")
+	endpoint := fmt.Sprintf("tcp:127.0.0.1:%s", port.Port())
+	suite.T().Logf("Endpoint: %s", endpoint)
+
+	return container, endpoint
+}
+
+// connectOVS returns a libovsdb client connected to endpoint
+func (suite *VSwitchIntegrationTestSuite) connectOVS(ctx context.Context, endpoint string) client.Client {
+	dbModel, err := vswitchSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create database model")
+
+	ovs, err := client.NewOVSDBClient(dbModel, client.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create OVS client")
+	err = ovs.Connect(ctx)
+	suite.Require().NoError(err, "Failed to connect to OVS")
+
+	return ovs
+}
+
+// getRoot returns the Open_vSwitch root row
+func (suite *VSwitchIntegrationTestSuite) getRoot(ctx context.Context, ovs client.Client) vswitchSchema.OpenvSwitch {
+	selectOps, queryID, selectErr := ovs.Where(&vswitchSchema.OpenvSwitch{}).Select()
+	suite.Require().NoError(selectErr, "Failed to select OpenvSwitch")
+
+	reply, err := ovs.Transact(ctx, selectOps...)
+	suite.Require().NoError(err, "Failed to execute transaction")
+
+	var results []vswitchSchema.OpenvSwitch
+	err = ovs.GetSelectResults(selectOps, reply, map[string]interface{}{queryID: &results})
+	suite.Require().NoError(err, "Failed to get select results")
+	suite.Require().Equal(1, len(results), "Expected 1 OpenvSwitch to be returned")
+
+	return results[0]
+}
+
 func createBridge(ovs client.Client, rootUUID string, bridgeName string) {
 	bridge := vswitchSchema.Bridge{
 		UUID: "gopher",
@@ -0,0 +1,191 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	mcpserver "github.com/dave-tucker/ariadne/internal/mcp"
+	"github.com/dave-tucker/ariadne/internal/mcp/ovnnb"
+	ovnnbSchema "github.com/dave-tucker/ariadne/internal/schema/ovnnb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ovn-kubernetes/libovsdb/model"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestWatchIntegration(t *testing.T) {
+	suite.Run(t, new(WatchIntegrationTestSuite))
+}
+
+// WatchIntegrationTestSuite checks that watch_table reports the changes
+// made while it watches, as progress notifications and in its result
+type WatchIntegrationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *WatchIntegrationTestSuite) TestWatchTable() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	endpoint := startDatabase(suite.T(), dbModel, ovnnbSchema.Schema())
+
+	insert := func(name string) {
+		insertRows(suite.T(), dbModel, endpoint, &ovnnbSchema.LogicalSwitch{Name: name})
+	}
+	// Present before the watch starts, so not a change
+	insert("watched-existing")
+
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+
+	var mu sync.Mutex
+	var notifications []*mcp.ProgressNotificationParams
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	_, err = server.Server.Connect(ctx, serverTransport)
+	suite.Require().NoError(err, "Failed to connect server")
+	mcpClient := mcp.NewClient(&mcp.Implementation{
+		Name:    "ovsdb-mcp-test-client",
+		Title:   "OVSDB MCP Test Client",
+		Version: "1.0.0",
+	}, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, _ *mcp.ClientSession, params *mcp.ProgressNotificationParams) {
+			mu.Lock()
+			defer mu.Unlock()
+			notifications = append(notifications, params)
+		},
+	})
+	session, err := mcpClient.Connect(ctx, clientTransport)
+	suite.Require().NoError(err, "Failed to connect to MCP server")
+	defer session.Close()
+
+	params := &mcp.CallToolParams{
+		// SetProgressToken needs Meta to be set
+		Meta: mcp.Meta{},
+		Name: "watch_table",
+		Arguments: map[string]any{
+			"table":            ovnnbSchema.LogicalSwitchTable,
+			"name_filter":      "watched-",
+			"match":            "prefix",
+			"duration_seconds": 10,
+			"max_events":       1,
+		},
+	}
+	params.SetProgressToken("watch")
+
+	type callResult struct {
+		result *mcp.CallToolResult
+		err    error
+	}
+	done := make(chan callResult, 1)
+	go func() {
+		result, err := session.CallTool(ctx, params)
+		done <- callResult{result, err}
+	}()
+
+	// The watch starts asynchronously, so keep making changes until it has
+	// seen one
+	var call callResult
+	for i := 0; ; i++ {
+		insert(fmt.Sprintf("ignored-%d", i))
+		insert(fmt.Sprintf("watched-%d", i))
+		select {
+		case call = <-done:
+		case <-time.After(100 * time.Millisecond):
+			continue
+		}
+		break
+	}
+	suite.Require().NoError(call.err, "Failed to call watch_table")
+	suite.Require().False(call.result.IsError, "Expected watch_table to succeed: %v", call.result.Content)
+
+	structured, ok := call.result.StructuredContent.(map[string]any)
+	suite.Require().True(ok, "Expected structured content, got %T", call.result.StructuredContent)
+	suite.Assert().Equal(true, structured["max_events_reached"])
+	changes, ok := structured["changes"].([]any)
+	suite.Require().True(ok, "Expected changes, got %T", structured["changes"])
+	suite.Require().Len(changes, 1)
+	change := changes[0].(map[string]any)
+	suite.Assert().Equal(mcpserver.RowInsert, change["op"])
+	after := change["after"].(map[string]any)
+	suite.Assert().Regexp(`^watched-\d+$`, after["name"], "Expected a switch inserted during the watch")
+
+	// Notifications are handled concurrently with the result
+	suite.Require().Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(notifications) > 0
+	}, 5*time.Second, 10*time.Millisecond, "Expected the change as a progress notification")
+	mu.Lock()
+	defer mu.Unlock()
+	suite.Require().Len(notifications, 1, "Expected one progress notification")
+	suite.Assert().Equal("watch", notifications[0].ProgressToken)
+	suite.Assert().Equal(float64(1), notifications[0].Progress)
+	var notified mcpserver.RowChange
+	suite.Require().NoError(json.Unmarshal([]byte(notifications[0].Message), &notified), "Expected the change as the message")
+	suite.Assert().Equal(change["uuid"], notified.UUID)
+}
+
+func (suite *WatchIntegrationTestSuite) TestInsertsWhileStarting() {
+	ctx := context.Background()
+
+	dbModel, err := ovnnbSchema.FullDatabaseModel()
+	suite.Require().NoError(err, "Failed to create client model")
+	// Present before the watch starts, so not changes
+	existing := make([]model.Model, 0, 50)
+	for i := range 50 {
+		existing = append(existing, &ovnnbSchema.LogicalSwitch{Name: fmt.Sprintf("existing-%d", i)})
+	}
+	endpoint := seedDatabase(suite.T(), dbModel, ovnnbSchema.Schema(), existing...)
+
+	server, err := ovnnb.NewServer("localhost", 0, mcpserver.WithEndpoint(endpoint))
+	suite.Require().NoError(err, "Failed to create server")
+	session := connect(suite.T(), ctx, server.BaseServer)
+	defer session.Close()
+
+	done := make(chan *mcp.CallToolResult, 1)
+	go func() {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name: "watch_table",
+			Arguments: map[string]any{
+				"table":            ovnnbSchema.LogicalSwitchTable,
+				"duration_seconds": 2,
+				"max_events":       1000,
+			},
+		})
+		suite.NoError(err, "Failed to call watch_table")
+		done <- result
+	}()
+
+	// Keep inserting while the watch starts and runs. Once the watch has
+	// seen an insert it must see every later one, without a gap where the
+	// monitor was starting.
+	var inserted []string
+	var result *mcp.CallToolResult
+	for i := 0; result == nil; i++ {
+		inserted = append(inserted, insertRows(suite.T(), dbModel, endpoint, &ovnnbSchema.LogicalSwitch{Name: fmt.Sprintf("new-%d", i)})...)
+		select {
+		case result = <-done:
+		default:
+		}
+	}
+
+	suite.Require().False(result.IsError, "Expected watch_table to succeed: %v", result.Content)
+	changes := result.StructuredContent.(map[string]any)["changes"].([]any)
+	suite.Require().NotEmpty(changes, "Expected the inserts made during the watch")
+	reported := make([]string, 0, len(changes))
+	for _, c := range changes {
+		change := c.(map[string]any)
+		suite.Require().Equal(mcpserver.RowInsert, change["op"])
+		suite.Require().Regexp(`^new-\d+$`, change["after"].(map[string]any)["name"], "Expected only switches inserted during the watch")
+		reported = append(reported, change["uuid"].(string))
+	}
+	first := slices.Index(inserted, reported[0])
+	suite.Require().GreaterOrEqual(first, 0, "Expected a reported insert to be one made by the test")
+	suite.Require().LessOrEqual(first+len(reported), len(inserted))
+	suite.Equal(inserted[first:first+len(reported)], reported, "Expected every insert after the first reported one")
+}